@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dyluth/holt/internal/agentrun"
+	"github.com/dyluth/holt/internal/config"
+	dockerpkg "github.com/dyluth/holt/internal/docker"
+	"github.com/dyluth/holt/internal/instance"
+	"github.com/dyluth/holt/internal/printer"
+	"github.com/dyluth/holt/internal/pup"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/spf13/cobra"
+)
+
+var (
+	agentRunArtefact  string
+	agentRunContext   string
+	agentRunLocal     bool
+	agentRunShowInput bool
+)
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Agent development tools",
+}
+
+var agentRunCmd = &cobra.Command{
+	Use:   "run <role>",
+	Short: "Run one claim for an agent offline",
+	Long: `Run a single claim for an agent without Redis or an orchestrator.
+
+The target artefact (--artefact) and any context artefacts (--context, a
+directory of *.json files) are loaded into an in-memory blackboard. The
+ToolInput is then built exactly as the pup would build it, the agent's
+command is run, and its output is validated. The artefacts that would be
+created are printed; nothing is written to a real blackboard.
+
+Artefact files use the JSON format printed by 'holt hoard <id>'. Missing
+fields are defaulted: id (new UUID), logical_id (= id), version (1),
+structural_type (Standard) and produced_by_role (user).
+
+By default the command runs in a throwaway container from the agent's
+image, with the workspace mounted as configured. Use --local to run the
+command directly on the host from the current directory.
+
+Examples:
+  # Run the coder agent against a goal
+  holt agent run coder --artefact goal.json
+
+  # Provide ancestors for context assembly and show the ToolInput
+  holt agent run reviewer --artefact code.json --context fixtures/ --show-input
+
+  # Run the tool script on the host instead of in its image
+  holt agent run coder --artefact goal.json --local`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentRun,
+}
+
+func init() {
+	agentRunCmd.Flags().StringVarP(&agentRunArtefact, "artefact", "a", "", "Target artefact JSON file (required)")
+	agentRunCmd.Flags().StringVarP(&agentRunContext, "context", "c", "", "Directory of context artefact JSON files")
+	agentRunCmd.Flags().BoolVar(&agentRunLocal, "local", false, "Run the agent command on the host instead of in its image")
+	agentRunCmd.Flags().BoolVar(&agentRunShowInput, "show-input", false, "Print the ToolInput sent to the agent")
+	agentRunCmd.MarkFlagRequired("artefact")
+	agentCmd.AddCommand(agentRunCmd)
+	rootCmd.AddCommand(agentCmd)
+}
+
+func runAgentRun(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	role := args[0]
+
+	cfg, err := config.Load("holt.yml")
+	if err != nil {
+		return printer.Error(
+			"holt.yml not found or invalid",
+			err.Error(),
+			[]string{"Run from the project root containing holt.yml"},
+		)
+	}
+
+	agent, ok := cfg.Agents[role]
+	if !ok {
+		return printer.Error(
+			fmt.Sprintf("agent '%s' not found in holt.yml", role),
+			fmt.Sprintf("Configured agents: %s", strings.Join(sortedAgentRoles(cfg), ", ")),
+			nil,
+		)
+	}
+	if agent.Mode == "controller" && agent.Worker != nil {
+		// Controllers only bid; the work runs in their worker image
+		agent.Image = agent.Worker.Image
+		agent.Command = agent.Worker.Command
+		agent.Workspace = agent.Worker.Workspace
+	}
+
+	target, err := agentrun.LoadArtefact(agentRunArtefact)
+	if err != nil {
+		return err
+	}
+	var contextArtefacts []*blackboard.Artefact
+	if agentRunContext != "" {
+		contextArtefacts, err = agentrun.LoadContextDir(agentRunContext)
+		if err != nil {
+			return err
+		}
+	}
+	agentrun.OrderFixtures(contextArtefacts, target)
+
+	opts := agentrun.Options{
+		Role:    role,
+		Agent:   agent,
+		Target:  target,
+		Context: contextArtefacts,
+		Verbose: printer.IsDebug(),
+	}
+
+	if agentRunLocal {
+		workDir, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+		opts.Workspace = workDir
+		opts.Runner = agentrun.LocalRunner(workDir)
+		printer.Step("Running agent '%s' locally: %v\n", role, agent.Command)
+	} else {
+		cli, err := dockerpkg.NewClient(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create Docker client: %w", err)
+		}
+		defer cli.Close()
+
+		workspacePath, err := instance.GetCanonicalWorkspacePath()
+		if err != nil {
+			return fmt.Errorf("failed to get workspace path: %w", err)
+		}
+		opts.Workspace = workspacePath
+		opts.Runner = agentrun.DockerRunner(cli, role, agent, workspacePath)
+		printer.Step("Running agent '%s' in %s: %v\n", role, agent.Image, agent.Command)
+	}
+
+	result, runErr := agentrun.Run(ctx, opts)
+	if result == nil {
+		result = &pup.OfflineResult{}
+	}
+	agentrun.WriteReport(os.Stdout, result, agentRunShowInput)
+
+	if runErr != nil {
+		return printer.Error(
+			"agent run failed",
+			runErr.Error(),
+			[]string{"Re-run with --show-input to inspect the ToolInput the agent received"},
+		)
+	}
+
+	printer.Success("Tool output is valid\n")
+	return nil
+}
+
+// sortedAgentRoles returns the configured agent roles in name order
+func sortedAgentRoles(cfg *config.HoltConfig) []string {
+	roles := make([]string, 0, len(cfg.Agents))
+	for role := range cfg.Agents {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/dyluth/holt/internal/config"
+	"github.com/dyluth/holt/internal/printer"
+	"github.com/spf13/cobra"
+)
+
+var configShowProfile string
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect holt.yml",
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective configuration",
+	Long: `Print the configuration 'holt up' would run with, as YAML.
+
+With --profile, holt.<profile>.yml is merged over holt.yml first:
+  • Mappings, including the agents map, merge key by key
+  • Agent environment entries merge by variable name
+  • Any other value in the overlay (scalars, lists) replaces the base value
+  • A value tagged !reset deletes the key, e.g. 'Reviewer: !reset'
+  • A value tagged !override replaces a mapping instead of merging it
+
+The output has ${VAR} references expanded from the current environment and
+defaults such as orchestrator.max_review_iterations filled in. Comments and
+anchors from the source files are not preserved.
+
+Examples:
+  # Effective config for CI
+  holt config show --profile ci
+
+  # Compare local and CI configuration
+  diff <(holt config show) <(holt config show --profile ci)`,
+	Args: cobra.NoArgs,
+	RunE: runConfigShow,
+}
+
+func init() {
+	configShowCmd.Flags().StringVar(&configShowProfile, "profile", "", "Merge holt.<profile>.yml over holt.yml")
+	configCmd.AddCommand(configShowCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	configPath := "holt.yml"
+	if GetGlobalConfigPath() != "" {
+		configPath = GetGlobalConfigPath()
+	}
+
+	cfg, err := config.LoadProfile(configPath, configShowProfile)
+	if err != nil {
+		suggestions := []string{"Run 'holt validate' for line-by-line diagnostics"}
+		if configShowProfile != "" {
+			suggestions = append(suggestions, fmt.Sprintf("Check that %s exists next to %s", config.ProfilePath(configPath, configShowProfile), configPath))
+		}
+		return printer.Error(
+			fmt.Sprintf("cannot load %s", configPath),
+			err.Error(),
+			suggestions,
+		)
+	}
+
+	return writeEffectiveConfig(cmd.OutOrStdout(), cfg)
+}
+
+// writeEffectiveConfig encodes the loaded configuration as YAML
+func writeEffectiveConfig(w io.Writer, cfg *config.HoltConfig) error {
+	data, err := cfg.Marshal()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dyluth/holt/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestWriteEffectiveConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "holt.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`version: "1.0"
+agents:
+  Coder:
+    image: coder:latest
+    command: [/app/run.sh]
+    bidding_strategy: exclusive
+  Reviewer:
+    image: reviewer:latest
+    command: [/app/review.sh]
+    bidding_strategy: review
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "holt.ci.yml"), []byte(`agents:
+  Coder:
+    image: coder:ci
+  Reviewer: !reset
+`), 0644))
+
+	cfg, err := config.LoadProfile(path, "ci")
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, writeEffectiveConfig(&out, cfg))
+
+	// The printed config is itself a valid holt.yml with the overlay applied
+	shown := filepath.Join(dir, "shown.yml")
+	require.NoError(t, os.WriteFile(shown, out.Bytes(), 0644))
+	roundTrip, err := config.Load(shown)
+	require.NoError(t, err)
+	assert.Equal(t, "coder:ci", roundTrip.Agents["Coder"].Image)
+	assert.NotContains(t, roundTrip.Agents, "Reviewer")
+
+	var raw map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(out.Bytes(), &raw))
+	assert.Contains(t, raw, "orchestrator", "defaults are shown")
+}
@@ -2,12 +2,17 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	dockerpkg "github.com/dyluth/holt/internal/docker"
 	"github.com/dyluth/holt/internal/git"
+	"github.com/dyluth/holt/internal/goal"
 	"github.com/dyluth/holt/internal/instance"
 	"github.com/dyluth/holt/internal/printer"
 	"github.com/dyluth/holt/internal/watch"
@@ -21,6 +26,10 @@ var (
 	forageInstanceName string
 	forageWatch        bool
 	forageGoal         string
+	forageFile         string
+	forageType         string
+	forageTemplate     string
+	forageSet          []string
 )
 
 var forageCmd = &cobra.Command{
@@ -31,6 +40,16 @@ var forageCmd = &cobra.Command{
 The forage command creates a GoalDefined artefact on the blackboard,
 which the orchestrator will process to begin coordinating agents.
 
+The goal comes from exactly one of:
+  --goal      an inline description
+  --file      a file holding a multi-line or structured goal ('-' reads stdin)
+  --template  a goal template at .holt/templates/<name>.yml, filled with --set
+
+--type sets the root artefact type (default GoalDefined, or the template's type).
+A template can declare a JSON Schema for its payload; otherwise the schema at
+.holt/schemas/<type>.json is used if present. Payloads that have a schema must be
+JSON and must match it before anything is submitted.
+
 Prerequisites:
   • Git repository with clean workspace (no uncommitted changes)
   • Running Holt instance (start with 'holt up')
@@ -43,15 +62,24 @@ Examples:
   holt forage --name prod --goal "Refactor authentication module"
 
   # Validate orchestrator response (Phase 1)
-  holt forage --watch --goal "Add logging to all endpoints"`,
+  holt forage --watch --goal "Add logging to all endpoints"
+
+  # Multi-line goal from a file, as a custom root artefact type
+  holt forage --file spec.json --type FeatureRequest
+
+  # Standardised workflow from .holt/templates/new-module.yml
+  holt forage --template new-module --set name=billing --set owner=payments`,
 	RunE: runForage,
 }
 
 func init() {
 	forageCmd.Flags().StringVarP(&forageInstanceName, "name", "n", "", "Target instance name (auto-inferred if omitted)")
 	forageCmd.Flags().BoolVarP(&forageWatch, "watch", "w", false, "Wait for orchestrator to create claim (Phase 1 validation)")
-	forageCmd.Flags().StringVarP(&forageGoal, "goal", "g", "", "Goal description (required)")
-	forageCmd.MarkFlagRequired("goal")
+	forageCmd.Flags().StringVarP(&forageGoal, "goal", "g", "", "Goal description")
+	forageCmd.Flags().StringVar(&forageFile, "file", "", "Read the goal payload from a file ('-' for stdin)")
+	forageCmd.Flags().StringVar(&forageType, "type", "", "Root artefact type (default GoalDefined, or the template's type)")
+	forageCmd.Flags().StringVar(&forageTemplate, "template", "", "Goal template name from .holt/templates")
+	forageCmd.Flags().StringArrayVar(&forageSet, "set", nil, "Template parameter as key=value (repeatable)")
 	rootCmd.AddCommand(forageCmd)
 }
 
@@ -59,12 +87,8 @@ func runForage(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
 	// Phase 1: Validate goal input
-	if forageGoal == "" {
-		return printer.Error(
-			"required flag --goal not provided",
-			"Usage:\n  holt forage --goal \"description of what you want to build\"\n\nExample:\n  holt forage --goal \"Create a REST API for user management\"",
-			[]string{"For immediate validation:\n  holt forage --watch --goal \"your goal\""},
-		)
+	if err := validateForageFlags(); err != nil {
+		return err
 	}
 
 	// Phase 2: Git workspace validation
@@ -102,7 +126,18 @@ func runForage(cmd *cobra.Command, args []string) error {
 		)
 	}
 
-	// Phase 3: Instance discovery
+	// Phase 3: Build and validate the goal payload before touching the instance
+	projectRoot, err := checker.GetGitRoot()
+	if err != nil {
+		return fmt.Errorf("failed to get Git root: %w", err)
+	}
+
+	artefactType, payload, err := buildForageGoal(projectRoot, os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	// Phase 4: Instance discovery
 	cli, err := dockerpkg.NewClient(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to create Docker client: %w", err)
@@ -120,7 +155,7 @@ func runForage(cmd *cobra.Command, args []string) error {
 					map[string]string{"Workspace": mustGetGitRoot()},
 					[]string{
 						"Start an instance first:\n  holt up",
-						fmt.Sprintf("Then retry:\n  holt forage --goal \"%s\"", forageGoal),
+						"Then retry:\n  " + forageCommandLine(""),
 					},
 				)
 			}
@@ -129,7 +164,7 @@ func runForage(cmd *cobra.Command, args []string) error {
 					"multiple instances found",
 					"Found multiple running instances for this workspace.",
 					[]string{
-						fmt.Sprintf("Specify which instance to use:\n  holt forage --name <instance-name> --goal \"%s\"", forageGoal),
+						"Specify which instance to use:\n  " + forageCommandLine("<instance-name>"),
 						"List instances:\n  holt list",
 					},
 				)
@@ -138,7 +173,7 @@ func runForage(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Phase 4: Verify instance is running
+	// Phase 5: Verify instance is running
 	if err := instance.VerifyInstanceRunning(ctx, cli, targetInstanceName); err != nil {
 		return printer.Error(
 			fmt.Sprintf("instance '%s' is not running", targetInstanceName),
@@ -150,7 +185,7 @@ func runForage(cmd *cobra.Command, args []string) error {
 		)
 	}
 
-	// Phase 5: Get Redis port
+	// Phase 6: Get Redis port
 	redisPort, err := instance.GetInstanceRedisPort(ctx, cli, targetInstanceName)
 	if err != nil {
 		return printer.ErrorWithContext(
@@ -165,7 +200,7 @@ func runForage(cmd *cobra.Command, args []string) error {
 		)
 	}
 
-	// Phase 6: Connect to blackboard
+	// Phase 7: Connect to blackboard
 	redisURL := instance.GetRedisURL(redisPort)
 	redisOpts, err := redis.ParseURL(redisURL)
 	if err != nil {
@@ -191,7 +226,7 @@ func runForage(cmd *cobra.Command, args []string) error {
 		)
 	}
 
-	// Phase 7: If --watch mode, start streaming BEFORE creating artefact to catch all events
+	// Phase 8: If --watch mode, start streaming BEFORE creating artefact to catch all events
 	if forageWatch {
 		printer.Info("Starting watch mode...\n")
 
@@ -206,20 +241,7 @@ func runForage(cmd *cobra.Command, args []string) error {
 		time.Sleep(100 * time.Millisecond)
 
 		// Now create the artefact - all subsequent events will be captured
-		artefactID := uuid.New().String()
-		logicalID := uuid.New().String()
-
-		artefact := &blackboard.Artefact{
-			ID:              artefactID,
-			LogicalID:       logicalID,
-			Version:         1,
-			StructuralType:  blackboard.StructuralTypeStandard,
-			Type:            "GoalDefined",
-			Payload:         forageGoal,
-			SourceArtefacts: []string{},
-			ProducedByRole:  "user",
-		}
-
+		artefact := newGoalArtefact(artefactType, payload)
 		if err := bbClient.CreateArtefact(ctx, artefact); err != nil {
 			return fmt.Errorf("failed to create artefact: %w", err)
 		}
@@ -229,25 +251,16 @@ func runForage(cmd *cobra.Command, args []string) error {
 	}
 
 	// Non-watch mode: create artefact and return
-	artefactID := uuid.New().String()
-	logicalID := uuid.New().String()
-
-	artefact := &blackboard.Artefact{
-		ID:              artefactID,
-		LogicalID:       logicalID,
-		Version:         1,
-		StructuralType:  blackboard.StructuralTypeStandard,
-		Type:            "GoalDefined",
-		Payload:         forageGoal,
-		SourceArtefacts: []string{},
-		ProducedByRole:  "user",
-	}
-
+	artefact := newGoalArtefact(artefactType, payload)
 	if err := bbClient.CreateArtefact(ctx, artefact); err != nil {
 		return fmt.Errorf("failed to create artefact: %w", err)
 	}
 
-	printer.Success("Goal artefact created: %s\n", artefactID)
+	if artefactType == goal.DefaultType {
+		printer.Success("Goal artefact created: %s\n", artefact.ID)
+	} else {
+		printer.Success("Goal artefact created: %s (type %s)\n", artefact.ID, artefactType)
+	}
 
 	printer.Info("\nNext steps:\n")
 	printer.Info("  • Agents will process this goal in Phase 2+\n")
@@ -257,6 +270,187 @@ func runForage(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// validateForageFlags checks that exactly one goal source was given.
+func validateForageFlags() error {
+	sources := 0
+	for _, set := range []bool{forageGoal != "", forageFile != "", forageTemplate != ""} {
+		if set {
+			sources++
+		}
+	}
+
+	if sources == 0 {
+		return printer.Error(
+			"no goal provided",
+			"Usage:\n  holt forage --goal \"description of what you want to build\"\n\nExample:\n  holt forage --goal \"Create a REST API for user management\"",
+			[]string{
+				"For immediate validation:\n  holt forage --watch --goal \"your goal\"",
+				"For a multi-line goal:\n  holt forage --file goal.md",
+				"From a goal template:\n  holt forage --template <name> --set key=value",
+			},
+		)
+	}
+	if sources > 1 {
+		return printer.Error(
+			"conflicting goal flags",
+			"Use only one of --goal, --file and --template.",
+			nil,
+		)
+	}
+	if len(forageSet) > 0 && forageTemplate == "" {
+		return printer.Error(
+			"--set requires --template",
+			"Parameters fill in a goal template; there is nothing to set them on.",
+			[]string{"Use a template:\n  holt forage --template <name> --set key=value"},
+		)
+	}
+	return nil
+}
+
+// buildForageGoal resolves the root artefact type and payload from the goal flags,
+// then checks the payload against the template's schema or .holt/schemas/<type>.json.
+func buildForageGoal(projectRoot string, stdin io.Reader) (string, string, error) {
+	artefactType := forageType
+	var payload string
+	var schema map[string]interface{}
+
+	switch {
+	case forageTemplate != "":
+		tmpl, err := goal.LoadTemplate(projectRoot, forageTemplate)
+		if err != nil {
+			return "", "", templateLoadError(projectRoot, err)
+		}
+		values, err := goal.ParseSet(forageSet)
+		if err != nil {
+			return "", "", printer.Error("invalid template parameter", err.Error(), nil)
+		}
+		payload, err = tmpl.Render(values)
+		if err != nil {
+			return "", "", printer.Error(
+				fmt.Sprintf("cannot render template '%s'", tmpl.Name),
+				err.Error(),
+				[]string{fmt.Sprintf("Check the parameters declared in:\n  %s", tmpl.Path)},
+			)
+		}
+		if artefactType == "" {
+			artefactType = tmpl.Type
+		}
+		schema, err = tmpl.LoadSchema()
+		if err != nil {
+			return "", "", printer.Error(fmt.Sprintf("cannot load schema for template '%s'", tmpl.Name), err.Error(), nil)
+		}
+
+	case forageFile != "":
+		var data []byte
+		var err error
+		if forageFile == "-" {
+			data, err = io.ReadAll(stdin)
+		} else {
+			data, err = os.ReadFile(forageFile)
+		}
+		if err != nil {
+			return "", "", printer.Error("cannot read goal file", err.Error(), []string{"Check the path passed to --file"})
+		}
+		payload = string(data)
+
+	default:
+		payload = forageGoal
+	}
+
+	if strings.TrimSpace(payload) == "" {
+		return "", "", printer.Error("goal is empty", "The goal payload has no content.", nil)
+	}
+	if artefactType == "" {
+		artefactType = goal.DefaultType
+	}
+
+	if schema == nil {
+		var err error
+		schema, err = goal.TypeSchema(projectRoot, artefactType)
+		if err != nil {
+			return "", "", printer.Error(fmt.Sprintf("cannot load schema for type '%s'", artefactType), err.Error(), nil)
+		}
+	}
+	if schema != nil {
+		violations, err := goal.ValidatePayload(payload, schema)
+		if err != nil {
+			return "", "", printer.Error(
+				"goal payload is not valid JSON",
+				fmt.Sprintf("A schema applies to %s payloads: %v", artefactType, err),
+				nil,
+			)
+		}
+		if len(violations) > 0 {
+			lines := make([]string, len(violations))
+			for i, v := range violations {
+				lines[i] = "  • " + v.String()
+			}
+			return "", "", printer.Error(
+				"goal payload does not match schema",
+				strings.Join(lines, "\n"),
+				[]string{"Fix the payload and retry; nothing was submitted"},
+			)
+		}
+	}
+
+	return artefactType, payload, nil
+}
+
+// templateLoadError explains a failed template lookup, listing what is available.
+func templateLoadError(projectRoot string, err error) error {
+	if !errors.Is(err, goal.ErrTemplateNotFound) {
+		return printer.Error(fmt.Sprintf("cannot load template '%s'", forageTemplate), err.Error(), nil)
+	}
+
+	names, _ := goal.ListTemplates(projectRoot)
+	available := "No templates found in " + goal.TemplatesDir(projectRoot)
+	if len(names) > 0 {
+		available = "Available templates: " + strings.Join(names, ", ")
+	}
+	return printer.Error(
+		fmt.Sprintf("template '%s' not found", forageTemplate),
+		available,
+		[]string{fmt.Sprintf("Create one at:\n  %s", filepath.Join(goal.TemplatesDir(projectRoot), forageTemplate+".yml"))},
+	)
+}
+
+// newGoalArtefact builds the root artefact that starts a workflow.
+func newGoalArtefact(artefactType, payload string) *blackboard.Artefact {
+	return &blackboard.Artefact{
+		ID:              uuid.New().String(),
+		LogicalID:       uuid.New().String(),
+		Version:         1,
+		StructuralType:  blackboard.StructuralTypeStandard,
+		Type:            artefactType,
+		Payload:         payload,
+		SourceArtefacts: []string{},
+		ProducedByRole:  "user",
+	}
+}
+
+// forageCommandLine rebuilds the invocation for retry suggestions.
+func forageCommandLine(instanceName string) string {
+	parts := []string{"holt forage"}
+	if instanceName != "" {
+		parts = append(parts, "--name "+instanceName)
+	}
+	switch {
+	case forageTemplate != "":
+		parts = append(parts, "--template "+forageTemplate)
+		for _, set := range forageSet {
+			parts = append(parts, fmt.Sprintf("--set %q", set))
+		}
+	case forageFile != "":
+		parts = append(parts, "--file "+forageFile)
+	default:
+		parts = append(parts, fmt.Sprintf("--goal %q", forageGoal))
+	}
+	if forageType != "" {
+		parts = append(parts, "--type "+forageType)
+	}
+	return strings.Join(parts, " ")
+}
+
 func mustGetGitRoot() string {
 	checker := git.NewChecker()
 	root, err := checker.GetGitRoot()
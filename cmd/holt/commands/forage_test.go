@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dyluth/holt/internal/goal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setForageFlags(t *testing.T, goalText, file, artefactType, template string, set ...string) {
+	t.Helper()
+	forageGoal, forageFile, forageType, forageTemplate, forageSet = goalText, file, artefactType, template, set
+	t.Cleanup(func() {
+		forageGoal, forageFile, forageType, forageTemplate, forageSet = "", "", "", "", nil
+	})
+}
+
+func writeProjectFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestValidateForageFlags(t *testing.T) {
+	setForageFlags(t, "", "", "", "")
+	assert.EqualError(t, validateForageFlags(), "no goal provided")
+
+	setForageFlags(t, "build it", "goal.md", "", "")
+	assert.EqualError(t, validateForageFlags(), "conflicting goal flags")
+
+	setForageFlags(t, "build it", "", "", "", "name=x")
+	assert.EqualError(t, validateForageFlags(), "--set requires --template")
+
+	setForageFlags(t, "", "", "", "new-module", "name=x")
+	assert.NoError(t, validateForageFlags())
+}
+
+func TestBuildForageGoal_GoalAndFile(t *testing.T) {
+	root := t.TempDir()
+
+	setForageFlags(t, "Build a REST API", "", "", "")
+	artefactType, payload, err := buildForageGoal(root, nil)
+	require.NoError(t, err)
+	assert.Equal(t, goal.DefaultType, artefactType)
+	assert.Equal(t, "Build a REST API", payload)
+
+	writeProjectFile(t, root, "goal.md", "# Goal\n\nTwo lines\n")
+	setForageFlags(t, "", filepath.Join(root, "goal.md"), "FeatureRequest", "")
+	artefactType, payload, err = buildForageGoal(root, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "FeatureRequest", artefactType)
+	assert.Equal(t, "# Goal\n\nTwo lines\n", payload)
+
+	setForageFlags(t, "", "-", "", "")
+	_, payload, err = buildForageGoal(root, strings.NewReader("from stdin"))
+	require.NoError(t, err)
+	assert.Equal(t, "from stdin", payload)
+
+	setForageFlags(t, "", filepath.Join(root, "missing.md"), "", "")
+	_, _, err = buildForageGoal(root, nil)
+	assert.EqualError(t, err, "cannot read goal file")
+
+	setForageFlags(t, "   ", "", "", "")
+	_, _, err = buildForageGoal(root, nil)
+	assert.EqualError(t, err, "goal is empty")
+}
+
+func TestBuildForageGoal_Template(t *testing.T) {
+	root := t.TempDir()
+	writeProjectFile(t, root, ".holt/templates/new-module.yml", `type: NewModuleRequest
+params:
+  name: {required: true}
+  owner: {default: platform}
+payload: |
+  {"module": {{json .name}}, "owner": {{json .owner}}}
+schema:
+  type: object
+  properties:
+    module: {type: string, pattern: "^[a-z]+$"}
+`)
+
+	setForageFlags(t, "", "", "", "new-module", "name=billing")
+	artefactType, payload, err := buildForageGoal(root, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "NewModuleRequest", artefactType)
+	assert.JSONEq(t, `{"module": "billing", "owner": "platform"}`, payload)
+
+	setForageFlags(t, "", "", "CustomGoal", "new-module", "name=billing")
+	artefactType, _, err = buildForageGoal(root, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "CustomGoal", artefactType, "--type overrides the template's type")
+
+	setForageFlags(t, "", "", "", "new-module", "name=Billing")
+	_, _, err = buildForageGoal(root, nil)
+	assert.EqualError(t, err, "goal payload does not match schema")
+
+	setForageFlags(t, "", "", "", "new-module")
+	_, _, err = buildForageGoal(root, nil)
+	assert.EqualError(t, err, "cannot render template 'new-module'")
+
+	setForageFlags(t, "", "", "", "release")
+	_, _, err = buildForageGoal(root, nil)
+	assert.EqualError(t, err, "template 'release' not found")
+}
+
+func TestBuildForageGoal_TypeSchema(t *testing.T) {
+	root := t.TempDir()
+	writeProjectFile(t, root, ".holt/schemas/FeatureRequest.json", `{"type": "object", "required": ["title"]}`)
+
+	setForageFlags(t, `{"title": "Search"}`, "", "FeatureRequest", "")
+	_, _, err := buildForageGoal(root, nil)
+	require.NoError(t, err)
+
+	setForageFlags(t, `{"summary": "Search"}`, "", "FeatureRequest", "")
+	_, _, err = buildForageGoal(root, nil)
+	assert.EqualError(t, err, "goal payload does not match schema")
+
+	setForageFlags(t, "Add search", "", "FeatureRequest", "")
+	_, _, err = buildForageGoal(root, nil)
+	assert.EqualError(t, err, "goal payload is not valid JSON")
+
+	setForageFlags(t, "Add search", "", "", "")
+	_, _, err = buildForageGoal(root, nil)
+	assert.NoError(t, err, "schemas only apply to their own type")
+}
+
+func TestNewGoalArtefact(t *testing.T) {
+	artefact := newGoalArtefact("FeatureRequest", "payload")
+	require.NoError(t, artefact.Validate())
+	assert.Equal(t, "FeatureRequest", artefact.Type)
+	assert.Equal(t, "user", artefact.ProducedByRole)
+}
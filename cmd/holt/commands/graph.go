@@ -0,0 +1,209 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	dockerpkg "github.com/dyluth/holt/internal/docker"
+	"github.com/dyluth/holt/internal/graph"
+	"github.com/dyluth/holt/internal/instance"
+	"github.com/dyluth/holt/internal/printer"
+	"github.com/dyluth/holt/internal/resolver"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/cobra"
+)
+
+var (
+	graphInstanceName string
+	graphOutputFormat string
+	graphFrom         string
+	graphWorkflow     string
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export artefact lineage as a DOT, Mermaid or JSON graph",
+	Long: `Export the lineage of blackboard artefacts as a graph.
+
+Edges point from earlier to later work:
+  source          - an artefact was derived from another (source_artefacts)
+  next version    - a new version of the same logical artefact (logical_id thread)
+  child workflow  - a claim requested a sub-workflow with this goal
+
+Nodes show type, version, producing role, structural type and short ID.
+Failures, rejected and approved reviews, Terminal artefacts and
+questions/answers are coloured.
+
+Scope:
+  (default)         - every artefact on the blackboard
+  --from ID         - the artefact's lineage: everything it was derived from
+                      and everything derived from it
+  --workflow ID     - the whole workflow the artefact belongs to, including
+                      sibling branches outside its lineage
+
+Output Formats:
+  dot      - Graphviz (render with: dot -Tsvg)
+  mermaid  - Mermaid flowchart (paste into Markdown)
+  json     - Nodes and edges as JSON
+
+Examples:
+  # Render the whole blackboard with Graphviz
+  holt graph | dot -Tsvg > workflow.svg
+
+  # Lineage of a failure, for an incident write-up
+  holt graph --from abc123 --output mermaid
+
+  # Everything in the workflow containing an artefact, as JSON
+  holt graph --workflow abc123 --output json`,
+	RunE: runGraph,
+}
+
+func init() {
+	graphCmd.Flags().StringVarP(&graphInstanceName, "name", "n", "", "Target instance name (auto-inferred if omitted)")
+	graphCmd.Flags().StringVarP(&graphOutputFormat, "output", "o", "dot", "Output format (dot, mermaid, or json)")
+	graphCmd.Flags().StringVar(&graphFrom, "from", "", "Only show this artefact's lineage (ancestors and descendants)")
+	graphCmd.Flags().StringVar(&graphWorkflow, "workflow", "", "Only show the workflow containing this artefact")
+
+	rootCmd.AddCommand(graphCmd)
+}
+
+func runGraph(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	// Validate flags
+	format := graph.Format(graphOutputFormat)
+	switch format {
+	case graph.FormatDOT, graph.FormatMermaid, graph.FormatJSON:
+	default:
+		return printer.Error(
+			"invalid output format",
+			fmt.Sprintf("Unknown format: %s", graphOutputFormat),
+			[]string{"Valid formats: dot, mermaid, json"},
+		)
+	}
+	if graphFrom != "" && graphWorkflow != "" {
+		return printer.Error(
+			"conflicting scope flags",
+			"--from and --workflow cannot be used together.",
+			[]string{
+				"Show an artefact's lineage:\n  holt graph --from <artefact-id>",
+				"Show its whole workflow:\n  holt graph --workflow <artefact-id>",
+			},
+		)
+	}
+
+	// Phase 1: Instance discovery
+	cli, err := dockerpkg.NewClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer cli.Close()
+
+	targetInstanceName := graphInstanceName
+	if targetInstanceName == "" {
+		targetInstanceName, err = instance.InferInstanceFromWorkspace(ctx, cli)
+		if err != nil {
+			if err.Error() == "no Holt instances found for this workspace" {
+				return printer.Error(
+					"no Holt instances found",
+					"No running instances found for this workspace.",
+					[]string{"Start an instance first:\n  holt up"},
+				)
+			}
+			if err.Error() == "multiple instances found for this workspace, use --name to specify which one" {
+				return printer.Error(
+					"multiple instances found",
+					"Found multiple running instances for this workspace.",
+					[]string{
+						"Specify which instance to graph:\n  holt graph --name <instance-name>",
+						"List instances:\n  holt list",
+					},
+				)
+			}
+			return fmt.Errorf("failed to infer instance: %w", err)
+		}
+	}
+
+	// Phase 2: Verify instance is running
+	if err := instance.VerifyInstanceRunning(ctx, cli, targetInstanceName); err != nil {
+		return printer.Error(
+			fmt.Sprintf("instance '%s' is not running", targetInstanceName),
+			fmt.Sprintf("Error: %v", err),
+			[]string{fmt.Sprintf("Start the instance:\n  holt up --name %s", targetInstanceName)},
+		)
+	}
+
+	// Phase 3: Get Redis port
+	redisPort, err := instance.GetInstanceRedisPort(ctx, cli, targetInstanceName)
+	if err != nil {
+		return printer.ErrorWithContext(
+			"Redis port not found",
+			fmt.Sprintf("Instance '%s' exists but Redis port label is missing.", targetInstanceName),
+			nil,
+			[]string{fmt.Sprintf("Restart the instance:\n  holt down --name %s\n  holt up --name %s", targetInstanceName, targetInstanceName)},
+		)
+	}
+
+	// Phase 4: Connect to blackboard
+	redisURL := instance.GetRedisURL(redisPort)
+	redisOpts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse Redis URL: %w", err)
+	}
+
+	bbClient, err := blackboard.NewClient(redisOpts, targetInstanceName)
+	if err != nil {
+		return fmt.Errorf("failed to create blackboard client: %w", err)
+	}
+	defer bbClient.Close()
+
+	if err := bbClient.Ping(ctx); err != nil {
+		return printer.ErrorWithContext(
+			"Redis connection failed",
+			fmt.Sprintf("Could not connect to Redis at %s", redisURL),
+			nil,
+			[]string{
+				fmt.Sprintf("Check Redis container status:\n  docker logs holt-redis-%s", targetInstanceName),
+				fmt.Sprintf("Restart if needed:\n  holt down --name %s\n  holt up --name %s", targetInstanceName, targetInstanceName),
+			},
+		)
+	}
+
+	// Phase 5: Resolve the scope artefact
+	var scopeID string
+	if shortID := graphFrom + graphWorkflow; shortID != "" {
+		scopeID, err = resolver.ResolveArtefactID(ctx, bbClient, shortID)
+		if err != nil {
+			if resolver.IsNotFoundError(err) {
+				return printer.Error(
+					fmt.Sprintf("artefact with ID '%s' not found", shortID),
+					"The specified artefact does not exist on the blackboard.",
+					[]string{"List all artefacts:\n  holt hoard"},
+				)
+			}
+			if resolver.IsAmbiguousError(err) {
+				ambigErr := err.(*resolver.AmbiguousError)
+				fmt.Fprintln(os.Stderr, resolver.FormatAmbiguousError(ambigErr))
+				return fmt.Errorf("ambiguous short ID")
+			}
+			return fmt.Errorf("failed to resolve artefact ID: %w", err)
+		}
+	}
+
+	// Phase 6: Build and write the graph
+	artefacts, childParents, err := graph.LoadArtefacts(ctx, bbClient)
+	if err != nil {
+		return err
+	}
+	g := graph.Build(artefacts, childParents)
+	switch {
+	case graphFrom != "":
+		g = g.Lineage(scopeID)
+	case graphWorkflow != "":
+		g = g.Workflow(scopeID)
+	}
+
+	return graph.Write(os.Stdout, g, format)
+}
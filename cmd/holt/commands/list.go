@@ -13,6 +13,8 @@ import (
 	dockerpkg "github.com/dyluth/holt/internal/docker"
 	"github.com/dyluth/holt/internal/instance"
 	"github.com/dyluth/holt/internal/printer"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/redis/go-redis/v9"
 	"github.com/spf13/cobra"
 )
 
@@ -30,6 +32,7 @@ For each instance, displays:
   • Status (Running/Degraded/Stopped)
   • Workspace path
   • Uptime (for running instances)
+  • Leader (the orchestrator replica currently leading, for running instances)
 
 Use --json for machine-readable output.`,
 	RunE: runList,
@@ -87,11 +90,18 @@ func runList(cmd *cobra.Command, args []string) error {
 			uptime = "-"
 		}
 
+		// HA: Ask Redis which orchestrator replica holds the leader lease
+		leader := ""
+		if status != instance.StatusStopped {
+			leader = instanceLeader(ctx, name, containers)
+		}
+
 		infos = append(infos, instance.InstanceInfo{
 			Name:      name,
 			Status:    status,
 			Workspace: workspacePath,
 			Uptime:    uptime,
+			Leader:    leader,
 		})
 	}
 
@@ -151,7 +161,7 @@ func outputJSON(infos []instance.InstanceInfo) {
 
 func outputTable(infos []instance.InstanceInfo) {
 	// Print header
-	printer.Printf("%-15s %-10s %-30s %s\n", "INSTANCE", "STATUS", "WORKSPACE", "UPTIME")
+	printer.Printf("%-15s %-10s %-30s %-10s %s\n", "INSTANCE", "STATUS", "WORKSPACE", "UPTIME", "LEADER")
 
 	// Print rows
 	for _, info := range infos {
@@ -161,6 +171,49 @@ func outputTable(infos []instance.InstanceInfo) {
 			workspace = "..." + workspace[len(workspace)-27:]
 		}
 
-		printer.Printf("%-15s %-10s %-30s %s\n", info.Name, info.Status, workspace, info.Uptime)
+		leader := info.Leader
+		if leader == "" {
+			leader = "-"
+		}
+
+		printer.Printf("%-15s %-10s %-30s %-10s %s\n", info.Name, info.Status, workspace, info.Uptime, leader)
+	}
+}
+
+// instanceLeader returns the orchestrator replica leading an instance, "none" if no replica
+// holds the lease, or "" if the instance's Redis cannot be reached
+func instanceLeader(ctx context.Context, instanceName string, containers []types.Container) string {
+	for _, c := range containers {
+		if c.Labels[dockerpkg.LabelComponent] != "redis" || c.State != "running" {
+			continue
+		}
+		port := c.Labels[dockerpkg.LabelRedisPort]
+		if port == "" {
+			return ""
+		}
+		return queryLeader(ctx, fmt.Sprintf("localhost:%s", port), instanceName)
+	}
+	return ""
+}
+
+// queryLeader reads the leader lease from the blackboard at addr
+func queryLeader(ctx context.Context, addr, instanceName string) string {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	bbClient, err := blackboard.NewClient(&redis.Options{Addr: addr}, instanceName)
+	if err != nil {
+		return ""
+	}
+	defer bbClient.Close()
+
+	lease, err := bbClient.GetLeader(ctx)
+	if err != nil {
+		if blackboard.IsNotFound(err) {
+			return "none"
+		}
+		printer.Debug("Failed to read orchestrator leader for '%s': %v\n", instanceName, err)
+		return ""
 	}
+	return lease.HolderID
 }
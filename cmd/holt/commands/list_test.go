@@ -1,11 +1,16 @@
 package commands
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/dyluth/holt/internal/instance"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFormatDuration(t *testing.T) {
@@ -78,6 +83,7 @@ func TestOutputTable(t *testing.T) {
 			Status:    instance.StatusRunning,
 			Workspace: "/home/user/project",
 			Uptime:    "2h 15m",
+			Leader:    "holt-orchestrator-test1-2",
 		},
 		{
 			Name:      "test2",
@@ -92,3 +98,24 @@ func TestOutputTable(t *testing.T) {
 		outputTable(infos)
 	})
 }
+
+func TestQueryLeader(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+
+	assert.Equal(t, "none", queryLeader(ctx, mr.Addr(), "prod"))
+
+	client, err := blackboard.NewClient(&redis.Options{Addr: mr.Addr()}, "prod")
+	require.NoError(t, err)
+	defer client.Close()
+	_, err = client.AcquireLeadership(ctx, "holt-orchestrator-prod-2", 10*time.Second)
+	require.NoError(t, err)
+
+	assert.Equal(t, "holt-orchestrator-prod-2", queryLeader(ctx, mr.Addr(), "prod"))
+	assert.Equal(t, "none", queryLeader(ctx, mr.Addr(), "other"))
+
+	// Unreachable Redis: leader unknown
+	addr := mr.Addr()
+	mr.Close()
+	assert.Equal(t, "", queryLeader(ctx, addr, "prod"))
+}
@@ -0,0 +1,167 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/client"
+	dockerpkg "github.com/dyluth/holt/internal/docker"
+	"github.com/dyluth/holt/internal/instance"
+	"github.com/dyluth/holt/internal/logs"
+	"github.com/dyluth/holt/internal/printer"
+	"github.com/dyluth/holt/internal/timespec"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsInstanceName string
+	logsAgent        string
+	logsClaim        string
+	logsFollow       bool
+	logsSince        string
+	logsNoColor      bool
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Show interleaved logs from the orchestrator, agents and workers",
+	Long: `Show the container logs of a Holt instance in a single stream.
+
+Output from the orchestrator, every agent and every worker is interleaved by
+timestamp, each line prefixed with its (colour-coded) source. Worker containers
+are removed once they exit, so their output is persisted to the blackboard and
+remains available here afterwards.
+
+Filters:
+  --agent  - Only show the agent with this role and its workers
+  --claim  - Only show the claim's worker, plus lines from other containers
+             that mention the claim ID
+  --since  - Only show lines after this time
+             Duration: 1h, 30m, 1h30m, 2h45m30s
+             Absolute: 2025-10-29T13:00:00Z (RFC3339)
+
+Examples:
+  # Show all logs so far
+  holt logs
+
+  # Follow live output, including workers as they start
+  holt logs --follow
+
+  # Everything that happened for one claim
+  holt logs --claim 1a2b3c4d-...
+
+  # Last 10 minutes of the coder agent and its workers
+  holt logs --agent coder --since 10m`,
+	RunE: runLogs,
+}
+
+func init() {
+	logsCmd.Flags().StringVarP(&logsInstanceName, "name", "n", "", "Target instance name (auto-inferred if omitted)")
+	logsCmd.Flags().StringVar(&logsAgent, "agent", "", "Only show logs for this agent role (exact match)")
+	logsCmd.Flags().StringVar(&logsClaim, "claim", "", "Only show logs for this claim ID")
+	logsCmd.Flags().BoolVar(&logsFollow, "follow", false, "Stream new output until interrupted")
+	logsCmd.Flags().StringVar(&logsSince, "since", "", "Show logs after time (duration or RFC3339)")
+	logsCmd.Flags().BoolVar(&logsNoColor, "no-color", false, "Disable colour-coded prefixes")
+
+	rootCmd.AddCommand(logsCmd)
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	filter := logs.Filter{
+		Role:    logsAgent,
+		ClaimID: logsClaim,
+	}
+	if logsSince != "" {
+		sinceMS, err := timespec.Parse(logsSince)
+		if err != nil {
+			return printer.Error(
+				"invalid time filter",
+				err.Error(),
+				[]string{"Use duration format like '1h30m' or RFC3339 like '2025-10-29T13:00:00Z'"},
+			)
+		}
+		filter.Since = time.UnixMilli(sinceMS)
+	}
+
+	// Phase 1: Instance discovery
+	cli, err := dockerpkg.NewClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer cli.Close()
+
+	targetInstanceName := logsInstanceName
+	if targetInstanceName == "" {
+		targetInstanceName, err = instance.InferInstanceFromWorkspace(ctx, cli)
+		if err != nil {
+			if err.Error() == "no Holt instances found for this workspace" {
+				return printer.Error(
+					"no Holt instances found",
+					"No running instances found for this workspace.",
+					[]string{"Start an instance first:\n  holt up"},
+				)
+			}
+			if err.Error() == "multiple instances found for this workspace, use --name to specify which one" {
+				return printer.Error(
+					"multiple instances found",
+					"Found multiple running instances for this workspace.",
+					[]string{
+						"Specify which instance to show logs for:\n  holt logs --name <instance-name>",
+						"List instances:\n  holt list",
+					},
+				)
+			}
+			return fmt.Errorf("failed to infer instance: %w", err)
+		}
+	}
+
+	// Phase 2: Connect to the blackboard for persisted worker logs.
+	// Container logs are still shown if the blackboard is unavailable.
+	var store logs.WorkerLogStore
+	if bbClient, err := connectLogsBlackboard(ctx, cli, targetInstanceName); err != nil {
+		printer.Warning("worker logs persisted to the blackboard are unavailable: %v\n", err)
+	} else {
+		defer bbClient.Close()
+		store = bbClient
+	}
+
+	// Phase 3: Show logs
+	return logs.Run(ctx, cli, store, logs.Options{
+		InstanceName: targetInstanceName,
+		Filter:       filter,
+		Follow:       logsFollow,
+		NoColor:      logsNoColor || os.Getenv("NO_COLOR") != "",
+	}, os.Stdout)
+}
+
+// connectLogsBlackboard connects to the instance's Redis, if it is running
+func connectLogsBlackboard(ctx context.Context, cli *client.Client, instanceName string) (*blackboard.Client, error) {
+	redisPort, err := instance.GetInstanceRedisPort(ctx, cli, instanceName)
+	if err != nil {
+		return nil, err
+	}
+
+	redisOpts, err := redis.ParseURL(instance.GetRedisURL(redisPort))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Redis URL: %w", err)
+	}
+
+	bbClient, err := blackboard.NewClient(redisOpts, instanceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blackboard client: %w", err)
+	}
+	if err := bbClient.Ping(ctx); err != nil {
+		bbClient.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	return bbClient, nil
+}
@@ -0,0 +1,420 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	dockerpkg "github.com/dyluth/holt/internal/docker"
+	"github.com/dyluth/holt/internal/instance"
+	"github.com/dyluth/holt/internal/printer"
+	"github.com/dyluth/holt/internal/resolver"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/cobra"
+)
+
+// drainPollInterval is how often holt drain re-counts active claims
+const drainPollInterval = 2 * time.Second
+
+var (
+	pauseInstanceName  string
+	pauseWorkflow      string
+	resumeInstanceName string
+	resumeWorkflow     string
+	drainInstanceName  string
+	drainTimeout       time.Duration
+)
+
+var pauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Stop the orchestrator creating and granting claims",
+	Long: `Stop the orchestrator creating and granting claims, so a running instance
+can be inspected or fixed without new work starting.
+
+Agents finish the work they already hold. The artefacts they produce are
+kept on the blackboard but not acted on - no claims are created and no
+review, parallel or exclusive phase is granted - until 'holt resume'.
+Claims queued for a free controller worker also stay queued. The pause is stored on the blackboard, so it survives
+orchestrator restarts.
+
+With --workflow, only the workflow containing that artefact (including its
+sub-workflows) is paused; other workflows, and the worker queue, carry on.
+
+Examples:
+  # Pause the whole instance
+  holt pause
+
+  # Pause one workflow
+  holt pause --workflow abc123`,
+	Args: cobra.NoArgs,
+	RunE: runPause,
+}
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Continue after holt pause or holt drain",
+	Long: `Continue after 'holt pause' or 'holt drain'.
+
+The orchestrator processes the artefacts it held back, in the order they
+arrived, then carries on as normal.
+
+Without --workflow, every pause and drain is lifted. With --workflow, only
+that workflow's pause is lifted; an instance-wide pause or drain stays.
+
+Examples:
+  # Resume everything
+  holt resume
+
+  # Resume one paused workflow
+  holt resume --workflow abc123`,
+	Args: cobra.NoArgs,
+	RunE: runResume,
+}
+
+var drainCmd = &cobra.Command{
+	Use:   "drain",
+	Short: "Wait for active claims to finish before holt down",
+	Long: `Stop new claims being created and wait for the active ones to finish, so
+'holt down' does not interrupt work in progress.
+
+Claims already on the blackboard keep moving through their review,
+parallel and exclusive phases. Artefacts that would start new claims are
+held back until 'holt resume'. Claims parked waiting for a sub-workflow are
+not waited for.
+
+Examples:
+  # Drain, then stop the instance
+  holt drain && holt down
+
+  # Give up after ten minutes
+  holt drain --timeout 10m`,
+	Args: cobra.NoArgs,
+	RunE: runDrain,
+}
+
+func init() {
+	pauseCmd.Flags().StringVarP(&pauseInstanceName, "name", "n", "", "Target instance name (auto-inferred if omitted)")
+	pauseCmd.Flags().StringVar(&pauseWorkflow, "workflow", "", "Only pause the workflow containing this artefact")
+	resumeCmd.Flags().StringVarP(&resumeInstanceName, "name", "n", "", "Target instance name (auto-inferred if omitted)")
+	resumeCmd.Flags().StringVar(&resumeWorkflow, "workflow", "", "Only resume the workflow containing this artefact")
+	drainCmd.Flags().StringVarP(&drainInstanceName, "name", "n", "", "Target instance name (auto-inferred if omitted)")
+	drainCmd.Flags().DurationVar(&drainTimeout, "timeout", 30*time.Minute, "How long to wait for active claims (0 waits forever)")
+
+	rootCmd.AddCommand(pauseCmd)
+	rootCmd.AddCommand(resumeCmd)
+	rootCmd.AddCommand(drainCmd)
+}
+
+func runPause(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	bbClient, instanceName, err := connectInstanceBlackboard(ctx, pauseInstanceName, "pause")
+	if err != nil {
+		return err
+	}
+	defer bbClient.Close()
+
+	if pauseWorkflow != "" {
+		root, err := resolveWorkflowRoot(ctx, bbClient, pauseWorkflow)
+		if err != nil {
+			return err
+		}
+		if err := bbClient.PauseWorkflow(ctx, root); err != nil {
+			return err
+		}
+		printer.Success("Paused workflow %s on instance '%s'\n", shortArtefactID(root), instanceName)
+	} else {
+		if err := bbClient.PauseInstance(ctx); err != nil {
+			return err
+		}
+		printer.Success("Paused instance '%s'\n", instanceName)
+	}
+
+	active, _, err := countDrainClaims(ctx, bbClient)
+	if err != nil {
+		return err
+	}
+	if active > 0 {
+		printer.Info("%d active claims: agents finish their current work, the results wait for 'holt resume'\n", active)
+	}
+	printer.Info("Continue with:\n  holt resume%s\n", workflowFlag(pauseWorkflow))
+	return nil
+}
+
+func runResume(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	bbClient, instanceName, err := connectInstanceBlackboard(ctx, resumeInstanceName, "resume")
+	if err != nil {
+		return err
+	}
+	defer bbClient.Close()
+
+	state, err := bbClient.GetPauseState(ctx)
+	if err != nil {
+		return err
+	}
+	if !state.IsActive() {
+		printer.Info("Instance '%s' is not paused\n", instanceName)
+		return nil
+	}
+
+	if resumeWorkflow != "" {
+		root, err := resolveWorkflowRoot(ctx, bbClient, resumeWorkflow)
+		if err != nil {
+			return err
+		}
+		if _, paused := state.Workflows[root]; !paused {
+			return printer.Error(
+				fmt.Sprintf("workflow %s is not paused", shortArtefactID(root)),
+				"Only workflows paused with 'holt pause --workflow' can be resumed individually.",
+				[]string{"Resume everything:\n  holt resume"},
+			)
+		}
+		if err := bbClient.ResumeWorkflow(ctx, root); err != nil {
+			return err
+		}
+		printer.Success("Resumed workflow %s on instance '%s'\n", shortArtefactID(root), instanceName)
+		if state.InstanceSinceMs != 0 || state.DrainSinceMs != 0 {
+			printer.Warning("the instance itself is still paused or draining; 'holt resume' lifts that\n")
+		}
+		return nil
+	}
+
+	if err := bbClient.ResumeAll(ctx); err != nil {
+		return err
+	}
+
+	deferred, err := bbClient.GetDeferredArtefacts(ctx)
+	if err != nil {
+		return err
+	}
+	printer.Success("Resumed instance '%s'\n", instanceName)
+	if len(deferred) > 0 {
+		printer.Info("The orchestrator is processing %d held artefacts\n", len(deferred))
+	}
+	return nil
+}
+
+func runDrain(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	bbClient, instanceName, err := connectInstanceBlackboard(ctx, drainInstanceName, "drain")
+	if err != nil {
+		return err
+	}
+	defer bbClient.Close()
+
+	if err := bbClient.StartDrain(ctx); err != nil {
+		return err
+	}
+	printer.Info("Draining instance '%s': no new claims will be created\n", instanceName)
+
+	var deadline <-chan time.Time
+	if drainTimeout > 0 {
+		timer := time.NewTimer(drainTimeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	lastActive := -1
+	for {
+		active, parked, err := countDrainClaims(ctx, bbClient)
+		if err != nil {
+			return err
+		}
+		if active == 0 {
+			printer.Success("Instance '%s' drained: no active claims\n", instanceName)
+			if parked > 0 {
+				printer.Warning("%d claims are parked waiting for sub-workflows and will not finish\n", parked)
+			}
+			printer.Info("New work is held until 'holt resume'. Stop the instance with:\n  holt down\n")
+			return nil
+		}
+		if active != lastActive {
+			printer.Step("Waiting for %d active claims...\n", active)
+			lastActive = active
+		}
+
+		select {
+		case <-ticker.C:
+		case <-deadline:
+			return printer.Error(
+				"drain timed out",
+				fmt.Sprintf("%d claims were still active after %s. The instance is still draining.", active, drainTimeout),
+				[]string{
+					"See what is still running:\n  holt top",
+					"Keep waiting:\n  holt drain --timeout 0",
+					"Cancel the drain:\n  holt resume",
+				},
+			)
+		case <-ctx.Done():
+			printer.Warning("drain interrupted; the instance is still draining ('holt resume' cancels it)\n")
+			return nil
+		}
+	}
+}
+
+// countDrainClaims counts the claims holt drain waits for, and those parked awaiting
+// a child workflow, which it does not.
+func countDrainClaims(ctx context.Context, bbClient *blackboard.Client) (active, parked int, err error) {
+	claims, err := bbClient.GetClaimsByStatus(ctx, []string{
+		string(blackboard.ClaimStatusPendingReview),
+		string(blackboard.ClaimStatusPendingParallel),
+		string(blackboard.ClaimStatusPendingExclusive),
+		string(blackboard.ClaimStatusPendingAssignment),
+		string(blackboard.ClaimStatusAwaitingChild),
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read active claims: %w", err)
+	}
+
+	for _, claim := range claims {
+		if claim.Status == blackboard.ClaimStatusAwaitingChild {
+			parked++
+		} else {
+			active++
+		}
+	}
+	return active, parked, nil
+}
+
+// resolveWorkflowRoot resolves a (short) artefact ID to the root of its workflow
+func resolveWorkflowRoot(ctx context.Context, bbClient *blackboard.Client, shortID string) (string, error) {
+	artefactID, err := resolveArtefactArg(ctx, bbClient, shortID)
+	if err != nil {
+		return "", err
+	}
+
+	root, err := bbClient.WorkflowRoot(ctx, artefactID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find the workflow of artefact %s: %w", shortArtefactID(artefactID), err)
+	}
+	return root, nil
+}
+
+// resolveArtefactArg resolves a (short) artefact ID given on the command line
+func resolveArtefactArg(ctx context.Context, bbClient *blackboard.Client, shortID string) (string, error) {
+	artefactID, err := resolver.ResolveArtefactID(ctx, bbClient, shortID)
+	if err != nil {
+		if resolver.IsNotFoundError(err) {
+			return "", printer.Error(
+				fmt.Sprintf("artefact with ID '%s' not found", shortID),
+				"The specified artefact does not exist on the blackboard.",
+				[]string{"List all artefacts:\n  holt hoard"},
+			)
+		}
+		if resolver.IsAmbiguousError(err) {
+			ambigErr := err.(*resolver.AmbiguousError)
+			fmt.Fprintln(os.Stderr, resolver.FormatAmbiguousError(ambigErr))
+			return "", fmt.Errorf("ambiguous short ID")
+		}
+		return "", fmt.Errorf("failed to resolve artefact ID: %w", err)
+	}
+	return artefactID, nil
+}
+
+func workflowFlag(workflow string) string {
+	if workflow == "" {
+		return ""
+	}
+	return " --workflow " + workflow
+}
+
+func shortArtefactID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// connectInstanceBlackboard finds the target instance (inferring it from the workspace
+// if instanceName is empty), checks it is running and connects to its blackboard.
+// command names the holt subcommand in suggestions.
+func connectInstanceBlackboard(ctx context.Context, instanceName, command string) (*blackboard.Client, string, error) {
+	// Phase 1: Instance discovery
+	cli, err := dockerpkg.NewClient(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer cli.Close()
+
+	targetInstanceName := instanceName
+	if targetInstanceName == "" {
+		targetInstanceName, err = instance.InferInstanceFromWorkspace(ctx, cli)
+		if err != nil {
+			if err.Error() == "no Holt instances found for this workspace" {
+				return nil, "", printer.Error(
+					"no Holt instances found",
+					"No running instances found for this workspace.",
+					[]string{"Start an instance first:\n  holt up"},
+				)
+			}
+			if err.Error() == "multiple instances found for this workspace, use --name to specify which one" {
+				return nil, "", printer.Error(
+					"multiple instances found",
+					"Found multiple running instances for this workspace.",
+					[]string{
+						fmt.Sprintf("Specify which instance to %s:\n  holt %s --name <instance-name>", command, command),
+						"List instances:\n  holt list",
+					},
+				)
+			}
+			return nil, "", fmt.Errorf("failed to infer instance: %w", err)
+		}
+	}
+
+	// Phase 2: Verify instance is running
+	if err := instance.VerifyInstanceRunning(ctx, cli, targetInstanceName); err != nil {
+		return nil, "", printer.Error(
+			fmt.Sprintf("instance '%s' is not running", targetInstanceName),
+			fmt.Sprintf("Error: %v", err),
+			[]string{fmt.Sprintf("Start the instance:\n  holt up --name %s", targetInstanceName)},
+		)
+	}
+
+	// Phase 3: Get Redis port
+	redisPort, err := instance.GetInstanceRedisPort(ctx, cli, targetInstanceName)
+	if err != nil {
+		return nil, "", printer.ErrorWithContext(
+			"Redis port not found",
+			fmt.Sprintf("Instance '%s' exists but Redis port label is missing.", targetInstanceName),
+			nil,
+			[]string{fmt.Sprintf("Restart the instance:\n  holt down --name %s\n  holt up --name %s", targetInstanceName, targetInstanceName)},
+		)
+	}
+
+	// Phase 4: Connect to blackboard
+	redisURL := instance.GetRedisURL(redisPort)
+	redisOpts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse Redis URL: %w", err)
+	}
+
+	bbClient, err := blackboard.NewClient(redisOpts, targetInstanceName)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create blackboard client: %w", err)
+	}
+
+	if err := bbClient.Ping(ctx); err != nil {
+		bbClient.Close()
+		return nil, "", printer.ErrorWithContext(
+			"Redis connection failed",
+			fmt.Sprintf("Could not connect to Redis at %s", redisURL),
+			nil,
+			[]string{
+				fmt.Sprintf("Check Redis container status:\n  docker logs holt-redis-%s", targetInstanceName),
+				fmt.Sprintf("Restart if needed:\n  holt down --name %s\n  holt up --name %s", targetInstanceName, targetInstanceName),
+			},
+		)
+	}
+
+	return bbClient, targetInstanceName, nil
+}
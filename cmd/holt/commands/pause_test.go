@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountDrainClaims(t *testing.T) {
+	mr := miniredis.RunT(t)
+	bbClient, err := blackboard.NewClient(&redis.Options{Addr: mr.Addr()}, "test-instance")
+	require.NoError(t, err)
+	defer bbClient.Close()
+	ctx := context.Background()
+
+	for _, status := range []blackboard.ClaimStatus{
+		blackboard.ClaimStatusPendingReview,
+		blackboard.ClaimStatusPendingExclusive,
+		blackboard.ClaimStatusPendingAssignment,
+		blackboard.ClaimStatusAwaitingChild,
+		blackboard.ClaimStatusComplete,
+		blackboard.ClaimStatusTerminated,
+	} {
+		require.NoError(t, bbClient.CreateClaim(ctx, &blackboard.Claim{
+			ID:                    uuid.New().String(),
+			ArtefactID:            uuid.New().String(),
+			Status:                status,
+			GrantedReviewAgents:   []string{},
+			GrantedParallelAgents: []string{},
+		}))
+	}
+
+	active, parked, err := countDrainClaims(ctx, bbClient)
+	require.NoError(t, err)
+	assert.Equal(t, 3, active)
+	assert.Equal(t, 1, parked)
+}
+
+func TestWorkflowFlag(t *testing.T) {
+	assert.Equal(t, "", workflowFlag(""))
+	assert.Equal(t, " --workflow abc123", workflowFlag("abc123"))
+}
@@ -0,0 +1,513 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/dyluth/holt/internal/config"
+	dockerpkg "github.com/dyluth/holt/internal/docker"
+	"github.com/dyluth/holt/internal/instance"
+	"github.com/dyluth/holt/internal/orchestrator"
+	"github.com/dyluth/holt/internal/printer"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/cobra"
+)
+
+// reloadAckTimeout bounds how long holt reload waits for the orchestrator to apply
+// a revision. It applies revisions between claims, so a claim in consensus delays it.
+const reloadAckTimeout = 30 * time.Second
+
+var (
+	reloadInstanceName string
+	reloadProfile      string
+	reloadForce        bool
+	reloadDryRun       bool
+)
+
+var reloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Apply holt.yml changes to a running instance",
+	Long: `Apply holt.yml changes to a running instance without 'holt down' and
+'holt up', keeping the blackboard and in-flight work of unchanged agents.
+
+Compares holt.yml with the running instance and:
+  • Starts containers for new agents
+  • Recreates agents whose configuration, resolved environment or image changed
+  • Removes agents no longer in holt.yml
+  • Updates the orchestrator's agent registry, workflows and review settings
+
+The orchestrator switches to the new configuration between claims. The reload
+is refused if an agent it would recreate or remove is granted on an active
+claim, since that work would be lost; use --force to reload anyway.
+
+The profile the instance was started or last reloaded with is reused unless
+--profile is given. orchestrator.replicas, orchestrator.lease_ttl and services
+cannot change on a running instance and need 'holt down' and 'holt up'.
+
+Examples:
+  # See what would change
+  holt reload --dry-run
+
+  # Apply the changes
+  holt reload
+
+  # Switch the running instance to the CI profile
+  holt reload --profile ci`,
+	Args: cobra.NoArgs,
+	RunE: runReload,
+}
+
+func init() {
+	reloadCmd.Flags().StringVarP(&reloadInstanceName, "name", "n", "", "Target instance name (auto-inferred if omitted)")
+	reloadCmd.Flags().StringVar(&reloadProfile, "profile", "", "Merge holt.<profile>.yml over holt.yml (default: the instance's current profile)")
+	reloadCmd.Flags().BoolVar(&reloadForce, "force", false, "Reload even if agents with active claims would be recreated or removed")
+	reloadCmd.Flags().BoolVar(&reloadDryRun, "dry-run", false, "Show what would change without applying it")
+	rootCmd.AddCommand(reloadCmd)
+}
+
+// agentState identifies what a running agent container was created from
+type agentState struct {
+	ConfigHash string // holt.agent.config_hash label
+	ImageID    string // Image the container runs
+}
+
+// reloadPlan is the set of agent container changes a reload makes
+type reloadPlan struct {
+	Added     []string          // Agents to start
+	Recreated []string          // Agents to stop and start again
+	Removed   []string          // Agents to stop
+	Reasons   map[string]string // Why each recreated agent changed
+}
+
+// Empty reports whether no agent containers change
+func (p *reloadPlan) Empty() bool {
+	return len(p.Added) == 0 && len(p.Recreated) == 0 && len(p.Removed) == 0
+}
+
+// Affected returns the agents whose current containers go away (recreated or removed)
+func (p *reloadPlan) Affected() []string {
+	affected := append(append([]string{}, p.Recreated...), p.Removed...)
+	sort.Strings(affected)
+	return affected
+}
+
+// planReload compares the desired agents with the running ones
+func planReload(desired, running map[string]agentState) *reloadPlan {
+	plan := &reloadPlan{Reasons: map[string]string{}}
+	for role, want := range desired {
+		have, exists := running[role]
+		switch {
+		case !exists:
+			plan.Added = append(plan.Added, role)
+		case have.ConfigHash != want.ConfigHash:
+			plan.Recreated = append(plan.Recreated, role)
+			plan.Reasons[role] = "configuration changed"
+		case have.ImageID != want.ImageID:
+			plan.Recreated = append(plan.Recreated, role)
+			plan.Reasons[role] = "image updated"
+		}
+	}
+	for role := range running {
+		if _, exists := desired[role]; !exists {
+			plan.Removed = append(plan.Removed, role)
+		}
+	}
+	sort.Strings(plan.Added)
+	sort.Strings(plan.Recreated)
+	sort.Strings(plan.Removed)
+	return plan
+}
+
+// orphanedClaims describes the active claims granted to any of the given agents
+func orphanedClaims(claims []*blackboard.Claim, agents []string) []string {
+	affected := make(map[string]bool, len(agents))
+	for _, agent := range agents {
+		affected[agent] = true
+	}
+
+	var orphaned []string
+	for _, claim := range claims {
+		granted := append(append([]string{claim.GrantedExclusiveAgent}, claim.GrantedReviewAgents...), claim.GrantedParallelAgents...)
+		var hit []string
+		for _, agent := range granted {
+			if affected[agent] && !containsAgent(hit, agent) {
+				hit = append(hit, agent)
+			}
+		}
+		if len(hit) > 0 {
+			orphaned = append(orphaned, fmt.Sprintf("claim %s (%s) granted to %s", claim.ID, claim.Status, strings.Join(hit, ", ")))
+		}
+	}
+	sort.Strings(orphaned)
+	return orphaned
+}
+
+func containsAgent(agents []string, agent string) bool {
+	for _, a := range agents {
+		if a == agent {
+			return true
+		}
+	}
+	return false
+}
+
+// restartRequiredChanges lists changes a running instance cannot apply
+func restartRequiredChanges(current, next *config.HoltConfig) []string {
+	var changes []string
+	if current.Orchestrator.ReplicaCount() != next.Orchestrator.ReplicaCount() {
+		changes = append(changes, "orchestrator.replicas")
+	}
+	if current.Orchestrator.LeaseDuration() != next.Orchestrator.LeaseDuration() {
+		changes = append(changes, "orchestrator.lease_ttl")
+	}
+	if !reflect.DeepEqual(current.Services, next.Services) {
+		changes = append(changes, "services")
+	}
+	return changes
+}
+
+func runReload(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	cli, err := dockerpkg.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	// Phase 1: Instance discovery
+	targetInstanceName := reloadInstanceName
+	if targetInstanceName == "" {
+		targetInstanceName, err = instance.InferInstanceFromWorkspace(ctx, cli)
+		if err != nil {
+			if err.Error() == "no Holt instances found for this workspace" {
+				return printer.Error(
+					"no Holt instances found",
+					"No running instances found for this workspace.",
+					[]string{"Start an instance first:\n  holt up"},
+				)
+			}
+			if err.Error() == "multiple instances found for this workspace, use --name to specify which one" {
+				return printer.Error(
+					"multiple instances found",
+					"Found multiple running instances for this workspace.",
+					[]string{
+						"Specify which instance to reload:\n  holt reload --name <instance-name>",
+						"List instances:\n  holt list",
+					},
+				)
+			}
+			return fmt.Errorf("failed to infer instance: %w", err)
+		}
+	}
+
+	if err := instance.VerifyInstanceRunning(ctx, cli, targetInstanceName); err != nil {
+		return printer.Error(
+			fmt.Sprintf("instance '%s' is not running", targetInstanceName),
+			fmt.Sprintf("Error: %v", err),
+			[]string{fmt.Sprintf("Start the instance:\n  holt up --name %s", targetInstanceName)},
+		)
+	}
+
+	// Phase 2: Connect to blackboard
+	redisPort, err := instance.GetInstanceRedisPort(ctx, cli, targetInstanceName)
+	if err != nil {
+		return fmt.Errorf("failed to get Redis port: %w", err)
+	}
+	redisOpts, err := redis.ParseURL(instance.GetRedisURL(redisPort))
+	if err != nil {
+		return fmt.Errorf("failed to parse Redis URL: %w", err)
+	}
+	bbClient, err := blackboard.NewClient(redisOpts, targetInstanceName)
+	if err != nil {
+		return fmt.Errorf("failed to create blackboard client: %w", err)
+	}
+	defer bbClient.Close()
+	if err := bbClient.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	// Phase 3: What is running
+	orchestratorContainer, runningAgents, err := listInstanceContainers(ctx, cli, targetInstanceName)
+	if err != nil {
+		return err
+	}
+
+	var current *config.HoltConfig
+	profile := orchestratorContainer.Labels[dockerpkg.LabelProfile]
+	stored, err := bbClient.GetInstanceConfig(ctx)
+	if err != nil && !blackboard.IsNotFound(err) {
+		return err
+	}
+	if stored != nil {
+		profile = stored.Profile
+		if current, err = config.Decode([]byte(stored.Config)); err != nil {
+			printer.Warning("stored configuration revision %d is unreadable: %v\n", stored.Revision, err)
+		}
+	}
+	if cmd.Flags().Changed("profile") {
+		profile = reloadProfile
+	}
+
+	workspacePath := orchestratorContainer.Labels[dockerpkg.LabelWorkspacePath]
+	runID := orchestratorContainer.Labels[dockerpkg.LabelInstanceRunID]
+
+	// Phase 4: What holt.yml asks for
+	cfg, err := config.LoadProfile("holt.yml", profile)
+	if err != nil {
+		return printer.Error(
+			"holt.yml not found or invalid",
+			err.Error(),
+			[]string{"Run 'holt validate' for line-by-line diagnostics"},
+		)
+	}
+	agentEnv, err := resolveAgentEnvironments(cfg, workspacePath)
+	if err != nil {
+		return err
+	}
+	if err := validateAgentImages(ctx, cli, cfg); err != nil {
+		return err
+	}
+
+	desired := make(map[string]agentState, len(cfg.Agents))
+	for role, agent := range cfg.Agents {
+		image, _, err := cli.ImageInspectWithRaw(ctx, agent.Image)
+		if err != nil {
+			return fmt.Errorf("failed to inspect image %s for agent '%s': %w", agent.Image, role, err)
+		}
+		desired[role] = agentState{ConfigHash: agentConfigHash(agent, agentEnv[role]), ImageID: image.ID}
+	}
+
+	plan := planReload(desired, runningAgents)
+	nextConfig, err := instanceConfig(cfg, agentEnv)
+	if err != nil {
+		return err
+	}
+	configChanged := stored == nil || stored.Config != nextConfig.Config || !reflect.DeepEqual(stored.WorkerEnv, nextConfig.WorkerEnv)
+
+	if plan.Empty() && !configChanged {
+		printer.Success("Instance '%s' is already up to date\n", targetInstanceName)
+		return nil
+	}
+
+	printReloadPlan(targetInstanceName, profile, plan, configChanged)
+	if current != nil {
+		for _, change := range restartRequiredChanges(current, cfg) {
+			printer.Warning("%s changed; it takes effect after 'holt down' and 'holt up'\n", change)
+		}
+	}
+
+	if reloadDryRun {
+		printer.Info("\nDry run: no changes made\n")
+		return nil
+	}
+
+	// Phase 5: Refuse to strand work held by agents that are going away
+	if affected := plan.Affected(); len(affected) > 0 {
+		activeClaims, err := bbClient.GetClaimsByStatus(ctx, []string{
+			string(blackboard.ClaimStatusPendingReview),
+			string(blackboard.ClaimStatusPendingParallel),
+			string(blackboard.ClaimStatusPendingExclusive),
+			string(blackboard.ClaimStatusPendingAssignment),
+			string(blackboard.ClaimStatusAwaitingChild),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to check active claims: %w", err)
+		}
+
+		if orphaned := orphanedClaims(activeClaims, affected); len(orphaned) > 0 {
+			if !reloadForce {
+				return printer.Error(
+					"reload would orphan active claims",
+					fmt.Sprintf("These claims are held by agents the reload recreates or removes:\n  %s", strings.Join(orphaned, "\n  ")),
+					[]string{
+						"Wait for the claims to finish:\n  holt watch",
+						"Reload anyway, losing those agents' in-flight work:\n  holt reload --force",
+					},
+				)
+			}
+			for _, claim := range orphaned {
+				printer.Warning("--force: %s will lose its in-flight work\n", claim)
+			}
+		}
+	}
+
+	// Phase 6: Switch the orchestrator over, then change the containers
+	if err := applyOrchestratorConfig(ctx, bbClient, nextConfig); err != nil {
+		return err
+	}
+
+	networkName := dockerpkg.NetworkName(targetInstanceName)
+	redisName := dockerpkg.RedisContainerName(targetInstanceName)
+
+	for _, role := range plan.Affected() {
+		if err := removeAgentContainer(ctx, cli, dockerpkg.AgentContainerName(targetInstanceName, role)); err != nil {
+			return err
+		}
+	}
+
+	started := append(append([]string{}, plan.Recreated...), plan.Added...)
+	var containerNames []string
+	for _, role := range started {
+		if err := launchAgentContainer(ctx, cli, targetInstanceName, runID, workspacePath, networkName, redisName, role, cfg.Agents[role], agentEnv[role]); err != nil {
+			return fmt.Errorf("failed to launch agent '%s': %w", role, err)
+		}
+		containerNames = append(containerNames, dockerpkg.AgentContainerName(targetInstanceName, role))
+	}
+	if len(containerNames) > 0 {
+		if err := validateAllAgentsHealthy(ctx, cli, containerNames); err != nil {
+			return fmt.Errorf("agent health check failed: %w", err)
+		}
+		if err := registerAgentImages(ctx, cli, bbClient, targetInstanceName, started); err != nil {
+			return err
+		}
+	}
+
+	printer.Success("\nInstance '%s' reloaded (revision %d): %d started, %d recreated, %d removed\n",
+		targetInstanceName, nextConfig.Revision, len(plan.Added), len(plan.Recreated), len(plan.Removed))
+	return nil
+}
+
+// listInstanceContainers returns one of the instance's orchestrator containers,
+// whose labels describe the instance, and the state of each agent container by role
+func listInstanceContainers(ctx context.Context, cli *client.Client, instanceName string) (types.Container, map[string]agentState, error) {
+	containerFilters := filters.NewArgs()
+	containerFilters.Add("label", fmt.Sprintf("%s=%s", dockerpkg.LabelInstanceName, instanceName))
+
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: true, Filters: containerFilters})
+	if err != nil {
+		return types.Container{}, nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	var orchestratorContainer types.Container
+	foundOrchestrator := false
+	agents := make(map[string]agentState)
+	for _, c := range containers {
+		switch c.Labels[dockerpkg.LabelComponent] {
+		case "orchestrator":
+			if !foundOrchestrator {
+				orchestratorContainer = c
+				foundOrchestrator = true
+			}
+		case "agent":
+			agents[c.Labels[dockerpkg.LabelAgentRole]] = agentState{
+				ConfigHash: c.Labels[dockerpkg.LabelConfigHash],
+				ImageID:    c.ImageID,
+			}
+		}
+	}
+
+	if !foundOrchestrator {
+		return types.Container{}, nil, fmt.Errorf("no orchestrator container found for instance '%s'", instanceName)
+	}
+	return orchestratorContainer, agents, nil
+}
+
+// printReloadPlan shows the changes a reload makes
+func printReloadPlan(instanceName, profile string, plan *reloadPlan, configChanged bool) {
+	if profile != "" {
+		printer.Info("Reloading instance '%s' (profile '%s'):\n", instanceName, profile)
+	} else {
+		printer.Info("Reloading instance '%s':\n", instanceName)
+	}
+	for _, role := range plan.Added {
+		printer.Info("  + %s (new agent)\n", role)
+	}
+	for _, role := range plan.Recreated {
+		printer.Info("  ~ %s (%s)\n", role, plan.Reasons[role])
+	}
+	for _, role := range plan.Removed {
+		printer.Info("  - %s (removed)\n", role)
+	}
+	if configChanged {
+		printer.Info("  ~ orchestrator configuration\n")
+	}
+}
+
+// applyOrchestratorConfig stores the next configuration revision and waits for the
+// orchestrator to apply it. If the orchestrator is still busy after reloadAckTimeout
+// the reload carries on: the orchestrator applies the stored revision when it is free.
+func applyOrchestratorConfig(ctx context.Context, bbClient *blackboard.Client, next *blackboard.InstanceConfig) error {
+	// Subscribe before publishing so the acknowledgement cannot be missed
+	sub, err := bbClient.SubscribeWorkflowEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to workflow events: %w", err)
+	}
+	defer sub.Close()
+
+	if err := bbClient.SaveInstanceConfig(ctx, next); err != nil {
+		return err
+	}
+	if err := bbClient.PublishConfigRevision(ctx, next.Revision); err != nil {
+		return err
+	}
+
+	timeout := time.After(reloadAckTimeout)
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return fmt.Errorf("workflow event subscription closed")
+			}
+			revision, _ := event.Data["revision"].(float64)
+			if int(revision) != next.Revision {
+				continue
+			}
+			switch event.Event {
+			case orchestrator.EventConfigReloaded:
+				printer.Step("Orchestrator switched to configuration revision %d\n", next.Revision)
+				return nil
+			case orchestrator.EventConfigReloadFailed:
+				return printer.Error(
+					"orchestrator rejected the configuration",
+					fmt.Sprintf("%v", event.Data["error"]),
+					[]string{"Check the orchestrator logs:\n  holt logs --since 5m"},
+				)
+			}
+		case <-timeout:
+			printer.Warning("orchestrator has not applied revision %d yet (a claim is probably awaiting bids); it will switch when free\n", next.Revision)
+			return nil
+		}
+	}
+}
+
+// removeAgentContainer stops and removes an agent container
+func removeAgentContainer(ctx context.Context, cli *client.Client, containerName string) error {
+	timeout := 10
+	printer.Step("Stopping %s...\n", containerName)
+	if err := cli.ContainerStop(ctx, containerName, container.StopOptions{Timeout: &timeout}); err != nil {
+		printer.Warning("failed to stop %s: %v\n", containerName, err)
+	}
+	if err := cli.ContainerRemove(ctx, containerName, container.RemoveOptions{Force: true}); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", containerName, err)
+	}
+	return nil
+}
+
+// registerAgentImages records the image ID of each started agent in the
+// agent_images audit hash (M3.9)
+func registerAgentImages(ctx context.Context, cli *client.Client, bbClient *blackboard.Client, instanceName string, roles []string) error {
+	for _, role := range roles {
+		containerInfo, err := cli.ContainerInspect(ctx, dockerpkg.AgentContainerName(instanceName, role))
+		if err != nil {
+			return fmt.Errorf("failed to inspect agent '%s': %w", role, err)
+		}
+		imageID, err := getImageDigest(ctx, cli, containerInfo.Image)
+		if err != nil {
+			return fmt.Errorf("failed to resolve image ID for agent '%s': %w", role, err)
+		}
+		if err := bbClient.RedisClient().HSet(ctx, blackboard.AgentImagesKey(instanceName), role, imageID).Err(); err != nil {
+			return fmt.Errorf("failed to store image ID for agent '%s': %w", role, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/dyluth/holt/internal/config"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanReload(t *testing.T) {
+	running := map[string]agentState{
+		"Coder":    {ConfigHash: "aaa", ImageID: "sha256:1"},
+		"Reviewer": {ConfigHash: "bbb", ImageID: "sha256:2"},
+		"Tester":   {ConfigHash: "ccc", ImageID: "sha256:3"},
+		"Linter":   {ConfigHash: "ddd", ImageID: "sha256:4"},
+	}
+	desired := map[string]agentState{
+		"Coder":    {ConfigHash: "aaa", ImageID: "sha256:1"},   // unchanged
+		"Reviewer": {ConfigHash: "b2b", ImageID: "sha256:2"},   // bid script edited
+		"Tester":   {ConfigHash: "ccc", ImageID: "sha256:new"}, // image rebuilt under the same tag
+		"Planner":  {ConfigHash: "eee", ImageID: "sha256:5"},
+	}
+
+	plan := planReload(desired, running)
+	assert.Equal(t, []string{"Planner"}, plan.Added)
+	assert.Equal(t, []string{"Reviewer", "Tester"}, plan.Recreated)
+	assert.Equal(t, []string{"Linter"}, plan.Removed)
+	assert.Equal(t, "configuration changed", plan.Reasons["Reviewer"])
+	assert.Equal(t, "image updated", plan.Reasons["Tester"])
+	assert.Equal(t, []string{"Linter", "Reviewer", "Tester"}, plan.Affected())
+	assert.False(t, plan.Empty())
+
+	assert.True(t, planReload(running, running).Empty())
+}
+
+func TestOrphanedClaims(t *testing.T) {
+	claims := []*blackboard.Claim{
+		{ID: "c1", Status: blackboard.ClaimStatusPendingExclusive, GrantedExclusiveAgent: "Coder"},
+		{ID: "c2", Status: blackboard.ClaimStatusPendingReview, GrantedReviewAgents: []string{"Reviewer", "Security"}},
+		{ID: "c3", Status: blackboard.ClaimStatusPendingParallel, GrantedParallelAgents: []string{"Tester"}},
+	}
+
+	orphaned := orphanedClaims(claims, []string{"Coder", "Reviewer", "Security"})
+	assert.Equal(t, []string{
+		"claim c1 (pending_exclusive) granted to Coder",
+		"claim c2 (pending_review) granted to Reviewer, Security",
+	}, orphaned)
+
+	assert.Empty(t, orphanedClaims(claims, []string{"Planner"}))
+}
+
+func TestRestartRequiredChanges(t *testing.T) {
+	current := &config.HoltConfig{}
+	next := &config.HoltConfig{
+		Orchestrator: &config.OrchestratorConfig{Replicas: 2},
+		Services:     &config.ServicesConfig{Redis: &config.ServiceOverride{Image: "redis:7"}},
+	}
+
+	assert.Equal(t, []string{"orchestrator.replicas", "services"}, restartRequiredChanges(current, next))
+	assert.Empty(t, restartRequiredChanges(next, next))
+}
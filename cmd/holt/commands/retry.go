@@ -0,0 +1,275 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dyluth/holt/internal/orchestrator"
+	"github.com/dyluth/holt/internal/printer"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+// claimRequestAckTimeout bounds how long holt retry and holt skip wait for the
+// orchestrator. It handles requests between claims, so a claim in consensus delays it.
+const claimRequestAckTimeout = 30 * time.Second
+
+var (
+	retryInstanceName string
+	retryAgent        string
+	retryNoContext    bool
+	skipInstanceName  string
+	skipType          string
+	skipPayload       string
+	skipFile          string
+	skipTerminal      bool
+)
+
+var retryCmd = &cobra.Command{
+	Use:   "retry <claim-or-artefact-id>",
+	Short: "Re-run a terminated claim",
+	Long: `Re-run a terminated claim without re-foraging the whole goal.
+
+Creates a fresh claim on the terminated claim's artefact. By default agents
+bid on it as usual; with --agent it is granted straight to that agent. The
+Failure artefacts recorded against the artefact (tool failures, exceeded
+review iterations, missing agents) are passed to the agent as context, unless
+--no-context is given.
+
+The argument is a claim ID, or the ID (or unique prefix) of the artefact whose
+latest claim should be retried. A claim already superseded by a feedback claim
+or an earlier retry cannot be retried.
+
+Examples:
+  # Let agents bid again
+  holt retry abc123
+
+  # Hand the work straight to the Coder, with the earlier failure as context
+  holt retry abc123 --agent Coder`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRetry,
+}
+
+var skipCmd = &cobra.Command{
+	Use:   "skip <claim-or-artefact-id>",
+	Short: "Resolve a terminated claim with a human-supplied artefact",
+	Long: `Resolve a terminated claim by supplying the artefact its agent should
+have produced.
+
+The artefact is created as the user's work, derived from the claim's
+artefact, and the claim is marked complete. The orchestrator then claims the
+new artefact as usual, so the workflow carries on from it.
+
+Examples:
+  # Provide the commit the Coder failed to make
+  holt skip abc123 --type CodeCommit --payload 4f2c9e1
+
+  # Provide a design document from a file
+  holt skip abc123 --type DesignSpec --file design.md
+
+  # End the workflow here
+  holt skip abc123 --type Report --payload "Done by hand" --terminal`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSkip,
+}
+
+func init() {
+	retryCmd.Flags().StringVarP(&retryInstanceName, "name", "n", "", "Target instance name (auto-inferred if omitted)")
+	retryCmd.Flags().StringVar(&retryAgent, "agent", "", "Grant the retry straight to this agent instead of bidding")
+	retryCmd.Flags().BoolVar(&retryNoContext, "no-context", false, "Do not pass earlier Failure artefacts to the agent")
+	skipCmd.Flags().StringVarP(&skipInstanceName, "name", "n", "", "Target instance name (auto-inferred if omitted)")
+	skipCmd.Flags().StringVar(&skipType, "type", "", "Type of the supplied artefact (required)")
+	skipCmd.Flags().StringVar(&skipPayload, "payload", "", "Payload of the supplied artefact")
+	skipCmd.Flags().StringVar(&skipFile, "file", "", "Read the payload from a file")
+	skipCmd.Flags().BoolVar(&skipTerminal, "terminal", false, "The supplied artefact ends the workflow")
+
+	rootCmd.AddCommand(retryCmd)
+	rootCmd.AddCommand(skipCmd)
+}
+
+func runRetry(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	bbClient, _, err := connectInstanceBlackboard(ctx, retryInstanceName, "retry")
+	if err != nil {
+		return err
+	}
+	defer bbClient.Close()
+
+	claim, err := resolveTerminatedClaim(ctx, bbClient, args[0], "retry")
+	if err != nil {
+		return err
+	}
+
+	event, err := sendClaimRequest(ctx, bbClient, &blackboard.ClaimRequest{
+		ID:        uuid.New().String(),
+		Action:    blackboard.ClaimActionRetry,
+		ClaimID:   claim.ID,
+		AgentRole: retryAgent,
+		NoContext: retryNoContext,
+	})
+	if err != nil || event == nil {
+		return err
+	}
+
+	newClaimID, _ := event.Data["claim_id"].(string)
+	printer.Success("Claim %s retried as claim %s\n", claim.ID, newClaimID)
+	if contextIDs, _ := event.Data["context_ids"].([]interface{}); len(contextIDs) > 0 {
+		printer.Info("Passing %d earlier failures to the agent as context\n", len(contextIDs))
+	}
+	printer.Info("Follow progress with:\n  holt watch\n")
+	return nil
+}
+
+func runSkip(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if skipType == "" {
+		return printer.Error(
+			"artefact type required",
+			"holt skip needs the type of the artefact that resolves the claim.",
+			[]string{"Set it with --type:\n  holt skip <claim-id> --type CodeCommit --payload <hash>"},
+		)
+	}
+	payload, err := skipArtefactPayload(skipPayload, skipFile)
+	if err != nil {
+		return err
+	}
+
+	bbClient, _, err := connectInstanceBlackboard(ctx, skipInstanceName, "skip")
+	if err != nil {
+		return err
+	}
+	defer bbClient.Close()
+
+	claim, err := resolveTerminatedClaim(ctx, bbClient, args[0], "skip")
+	if err != nil {
+		return err
+	}
+
+	event, err := sendClaimRequest(ctx, bbClient, &blackboard.ClaimRequest{
+		ID:           uuid.New().String(),
+		Action:       blackboard.ClaimActionSkip,
+		ClaimID:      claim.ID,
+		ArtefactType: skipType,
+		Payload:      payload,
+		Terminal:     skipTerminal,
+	})
+	if err != nil || event == nil {
+		return err
+	}
+
+	artefactID, _ := event.Data["artefact_id"].(string)
+	printer.Success("Claim %s resolved with artefact %s\n", claim.ID, artefactID)
+	return nil
+}
+
+// skipArtefactPayload returns the payload given with --payload or read from --file.
+func skipArtefactPayload(payload, file string) (string, error) {
+	switch {
+	case payload != "" && file != "":
+		return "", printer.Error(
+			"conflicting payload flags",
+			"--payload and --file cannot be used together.",
+			[]string{"Pass the payload inline:\n  holt skip <claim-id> --type <type> --payload <text>", "Or read it from a file:\n  holt skip <claim-id> --type <type> --file <path>"},
+		)
+	case file != "":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", printer.Error(
+				"cannot read payload file",
+				err.Error(),
+				[]string{"Check the path passed to --file"},
+			)
+		}
+		return string(data), nil
+	case payload != "":
+		return payload, nil
+	default:
+		return "", printer.Error(
+			"payload required",
+			"holt skip needs the content of the artefact that resolves the claim.",
+			[]string{"Pass it with --payload or --file"},
+		)
+	}
+}
+
+// resolveTerminatedClaim finds the claim named by a claim ID or by an artefact ID
+// (or prefix), and checks it is terminated. The orchestrator re-checks when it
+// handles the request.
+func resolveTerminatedClaim(ctx context.Context, bbClient *blackboard.Client, id, command string) (*blackboard.Claim, error) {
+	claim, err := bbClient.GetClaim(ctx, id)
+	if err != nil && !blackboard.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to fetch claim: %w", err)
+	}
+
+	if claim == nil {
+		artefactID, err := resolveArtefactArg(ctx, bbClient, id)
+		if err != nil {
+			return nil, err
+		}
+		claim, err = bbClient.GetClaimByArtefactID(ctx, artefactID)
+		if err != nil {
+			if blackboard.IsNotFound(err) {
+				return nil, printer.Error(
+					fmt.Sprintf("no claim for artefact '%s'", id),
+					"The orchestrator has not created a claim for this artefact.",
+					[]string{"List artefacts and their claims:\n  holt hoard"},
+				)
+			}
+			return nil, fmt.Errorf("failed to fetch claim: %w", err)
+		}
+	}
+
+	if claim.Status != blackboard.ClaimStatusTerminated {
+		return nil, printer.Error(
+			fmt.Sprintf("claim %s is %s", claim.ID, claim.Status),
+			fmt.Sprintf("Only terminated claims can be passed to holt %s.", command),
+			[]string{"Watch the claim's progress:\n  holt watch"},
+		)
+	}
+	return claim, nil
+}
+
+// sendClaimRequest publishes a claim request and waits for the orchestrator's outcome.
+// Returns a nil event (and no error) if the orchestrator has not answered in time;
+// it still handles the request once it is free.
+func sendClaimRequest(ctx context.Context, bbClient *blackboard.Client, request *blackboard.ClaimRequest) (*blackboard.WorkflowEvent, error) {
+	// Subscribe before publishing so the outcome cannot be missed
+	sub, err := bbClient.SubscribeWorkflowEvents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to workflow events: %w", err)
+	}
+	defer sub.Close()
+
+	if err := bbClient.PublishClaimRequest(ctx, request); err != nil {
+		return nil, err
+	}
+
+	timeout := time.After(claimRequestAckTimeout)
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return nil, fmt.Errorf("workflow event subscription closed")
+			}
+			if requestID, _ := event.Data["request_id"].(string); requestID != request.ID {
+				continue
+			}
+			if event.Event == orchestrator.EventClaimRequestFailed {
+				return nil, printer.Error(
+					fmt.Sprintf("orchestrator refused to %s the claim", request.Action),
+					fmt.Sprintf("%v", event.Data["error"]),
+					[]string{"Check the orchestrator logs:\n  holt logs --since 5m"},
+				)
+			}
+			return event, nil
+		case <-timeout:
+			printer.Warning("orchestrator has not handled the request yet (a claim is probably awaiting bids); it will when free\n")
+			return nil, nil
+		}
+	}
+}
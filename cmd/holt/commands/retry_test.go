@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkipArtefactPayload(t *testing.T) {
+	payload, err := skipArtefactPayload("abc123", "")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", payload)
+
+	file := filepath.Join(t.TempDir(), "design.md")
+	require.NoError(t, os.WriteFile(file, []byte("# Design\n\nline two\n"), 0o644))
+	payload, err = skipArtefactPayload("", file)
+	require.NoError(t, err)
+	assert.Equal(t, "# Design\n\nline two\n", payload)
+
+	_, err = skipArtefactPayload("abc123", file)
+	assert.EqualError(t, err, "conflicting payload flags")
+
+	_, err = skipArtefactPayload("", "")
+	assert.EqualError(t, err, "payload required")
+}
+
+func TestResolveTerminatedClaim(t *testing.T) {
+	mr := miniredis.RunT(t)
+	bbClient, err := blackboard.NewClient(&redis.Options{Addr: mr.Addr()}, "test-instance")
+	require.NoError(t, err)
+	defer bbClient.Close()
+	ctx := context.Background()
+
+	artefact := &blackboard.Artefact{
+		ID:              uuid.New().String(),
+		LogicalID:       uuid.New().String(),
+		Version:         1,
+		StructuralType:  blackboard.StructuralTypeStandard,
+		Type:            "GoalDefined",
+		Payload:         "goal",
+		SourceArtefacts: []string{},
+		ProducedByRole:  "user",
+	}
+	require.NoError(t, bbClient.CreateArtefact(ctx, artefact))
+	claim := &blackboard.Claim{
+		ID:                    uuid.New().String(),
+		ArtefactID:            artefact.ID,
+		Status:                blackboard.ClaimStatusTerminated,
+		GrantedReviewAgents:   []string{},
+		GrantedParallelAgents: []string{},
+	}
+	require.NoError(t, bbClient.CreateClaim(ctx, claim))
+
+	// By claim ID, or by artefact ID prefix
+	for _, id := range []string{claim.ID, artefact.ID[:8]} {
+		resolved, err := resolveTerminatedClaim(ctx, bbClient, id, "retry")
+		require.NoError(t, err)
+		assert.Equal(t, claim.ID, resolved.ID)
+	}
+
+	claim.Status = blackboard.ClaimStatusComplete
+	require.NoError(t, bbClient.UpdateClaim(ctx, claim))
+	_, err = resolveTerminatedClaim(ctx, bbClient, claim.ID, "retry")
+	assert.EqualError(t, err, "claim "+claim.ID+" is complete")
+}
@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/dyluth/holt/internal/config"
+	"github.com/dyluth/holt/internal/printer"
+	"github.com/dyluth/holt/internal/scenario"
+	"github.com/spf13/cobra"
+)
+
+var testCmd = &cobra.Command{
+	Use:   "test <scenario>...",
+	Short: "Run workflow scenarios against holt.yml with scripted agents",
+	Long: `Run workflow scenarios without Docker or Redis.
+
+Each scenario file submits a goal to the real orchestrator engine running
+in-process against an in-memory blackboard. Every agent in holt.yml runs
+as a real pup (bidding, claim phases, context assembly) but its tool
+output is scripted by the scenario, keyed by role and target artefact
+type. Once the blackboard is idle the created artefacts are checked
+against the scenario's expectations.
+
+Arguments may be scenario files or directories (every *.yml and *.yaml
+file in a directory is run). The command exits non-zero if any scenario
+fails, so it can gate CI.
+
+Scenario format:
+  name: design then build
+  config: ../holt.yml          # relative to this file (default: --config, or ./holt.yml)
+  goal: "Build a CLI"
+  timeout: 30s                 # default: 30s
+  settle: 500ms                # idle period that ends the run (default: 500ms)
+  responses:
+    Designer:
+      GoalDefined:
+        - artefact_type: DesignSpec
+          artefact_payload: "# Spec"
+          summary: Designed the CLI
+    Coder:
+      "*":                     # globs match any target type
+        - exit_code: 1         # simulate a tool failure
+          stderr: compile error
+  expect:
+    exhaustive: false          # true: no other artefacts may be created
+    artefacts:
+      - type: DesignSpec
+        produced_by: Designer
+    terminal:
+      structural_type: Failure
+
+Examples:
+  # Run one scenario
+  holt test tests/happy-path.yml
+
+  # Run every scenario in a directory
+  holt test tests/`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runTest,
+}
+
+func init() {
+	rootCmd.AddCommand(testCmd)
+}
+
+func runTest(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	paths, err := scenarioPaths(args)
+	if err != nil {
+		return printer.Error("failed to find scenarios", err.Error(), nil)
+	}
+
+	configs := make(map[string]*config.HoltConfig)
+	var failed []string
+
+	for _, path := range paths {
+		s, err := scenario.Load(path)
+		if err != nil {
+			return printer.Error("invalid scenario", err.Error(), nil)
+		}
+
+		configPath := s.ConfigPath()
+		if s.Config == "" && GetGlobalConfigPath() != "" {
+			configPath = GetGlobalConfigPath()
+		}
+		cfg, ok := configs[configPath]
+		if !ok {
+			cfg, err = config.Load(configPath)
+			if err != nil {
+				return printer.Error(
+					fmt.Sprintf("failed to load %s for scenario '%s'", configPath, s.Name),
+					err.Error(),
+					[]string{"Run from the project root, or set 'config:' in the scenario"},
+				)
+			}
+			configs[configPath] = cfg
+		}
+
+		printer.Step("Running scenario '%s'...\n", s.Name)
+		result, err := scenario.Run(ctx, s, cfg, scenario.Options{Verbose: printer.IsDebug()})
+		if err != nil {
+			return printer.Error(fmt.Sprintf("scenario '%s' could not run", s.Name), err.Error(), nil)
+		}
+
+		if result.Passed() {
+			printer.Success("PASS %s (%d artefacts, %s)\n", s.Name, len(result.Artefacts), result.Duration.Round(time.Millisecond))
+			continue
+		}
+
+		failed = append(failed, s.Name)
+		printer.Warning("FAIL %s\n", s.Name)
+		for _, failure := range result.Failures {
+			printer.Printf("    %s\n", failure)
+		}
+	}
+
+	if len(failed) > 0 {
+		return printer.Error(
+			fmt.Sprintf("%d of %d scenarios failed", len(failed), len(paths)),
+			fmt.Sprintf("Failed: %v", failed),
+			[]string{"Re-run with --debug to see orchestrator and agent logs"},
+		)
+	}
+
+	printer.Info("\n%d scenarios passed\n", len(paths))
+	return nil
+}
+
+// scenarioPaths expands the arguments into scenario files, in name order within directories
+func scenarioPaths(args []string) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			paths = append(paths, arg)
+			continue
+		}
+
+		var found []string
+		for _, pattern := range []string{"*.yml", "*.yaml"} {
+			matches, err := filepath.Glob(filepath.Join(arg, pattern))
+			if err != nil {
+				return nil, err
+			}
+			found = append(found, matches...)
+		}
+		if len(found) == 0 {
+			return nil, fmt.Errorf("no *.yml or *.yaml scenarios in %s", arg)
+		}
+		sort.Strings(found)
+		paths = append(paths, found...)
+	}
+	return paths, nil
+}
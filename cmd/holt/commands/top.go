@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	dockerpkg "github.com/dyluth/holt/internal/docker"
+	"github.com/dyluth/holt/internal/instance"
+	"github.com/dyluth/holt/internal/printer"
+	"github.com/dyluth/holt/internal/top"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/cobra"
+)
+
+var (
+	topInstanceName string
+	topInterval     time.Duration
+)
+
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Interactive dashboard of agents, claims, queues and artefacts",
+	Long: `Show a live, full-screen dashboard for a Holt instance.
+
+Panels:
+  AGENTS         - Agent containers with /healthz status, in-flight claims and worker counts
+  ACTIVE CLAIMS  - Claims by phase with time spent in the current phase (longest first)
+  GRANT QUEUE    - Claims paused waiting for a worker slot (max_concurrent)
+  ARTEFACTS      - Live artefact feed, newest first
+
+Keys:
+  tab            Switch between the claims and artefacts panes
+  up/down, k/j   Move the selection
+  enter          Show the selected claim (with bids) or artefact (as holt hoard <id>)
+  esc, b         Back to the dashboard
+  r              Refresh now
+  q, Ctrl+C      Quit
+
+Examples:
+  # Dashboard for the workspace's instance
+  holt top
+
+  # Specific instance, refreshing every 5 seconds
+  holt top --name prod --interval 5s`,
+	RunE: runTop,
+}
+
+func init() {
+	topCmd.Flags().StringVarP(&topInstanceName, "name", "n", "", "Target instance name (auto-inferred if omitted)")
+	topCmd.Flags().DurationVar(&topInterval, "interval", top.DefaultInterval, "Refresh interval")
+
+	rootCmd.AddCommand(topCmd)
+}
+
+func runTop(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	// Phase 1: Instance discovery
+	cli, err := dockerpkg.NewClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create Docker client: %w", err)
+	}
+	defer cli.Close()
+
+	targetInstanceName := topInstanceName
+	if targetInstanceName == "" {
+		targetInstanceName, err = instance.InferInstanceFromWorkspace(ctx, cli)
+		if err != nil {
+			if err.Error() == "no Holt instances found for this workspace" {
+				return printer.Error(
+					"no Holt instances found",
+					"No running instances found for this workspace.",
+					[]string{"Start an instance first:\n  holt up"},
+				)
+			}
+			if err.Error() == "multiple instances found for this workspace, use --name to specify which one" {
+				return printer.Error(
+					"multiple instances found",
+					"Found multiple running instances for this workspace.",
+					[]string{
+						"Specify which instance to show:\n  holt top --name <instance-name>",
+						"List instances:\n  holt list",
+					},
+				)
+			}
+			return fmt.Errorf("failed to infer instance: %w", err)
+		}
+	}
+
+	// Phase 2: Verify instance is running
+	if err := instance.VerifyInstanceRunning(ctx, cli, targetInstanceName); err != nil {
+		return printer.Error(
+			fmt.Sprintf("instance '%s' is not running", targetInstanceName),
+			fmt.Sprintf("Error: %v", err),
+			[]string{fmt.Sprintf("Start the instance:\n  holt up --name %s", targetInstanceName)},
+		)
+	}
+
+	// Phase 3: Get Redis port
+	redisPort, err := instance.GetInstanceRedisPort(ctx, cli, targetInstanceName)
+	if err != nil {
+		return printer.ErrorWithContext(
+			"Redis port not found",
+			fmt.Sprintf("Instance '%s' exists but Redis port label is missing.", targetInstanceName),
+			nil,
+			[]string{fmt.Sprintf("Restart the instance:\n  holt down --name %s\n  holt up --name %s", targetInstanceName, targetInstanceName)},
+		)
+	}
+
+	// Phase 4: Connect to blackboard
+	redisURL := instance.GetRedisURL(redisPort)
+	redisOpts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse Redis URL: %w", err)
+	}
+
+	bbClient, err := blackboard.NewClient(redisOpts, targetInstanceName)
+	if err != nil {
+		return fmt.Errorf("failed to create blackboard client: %w", err)
+	}
+	defer bbClient.Close()
+
+	if err := bbClient.Ping(ctx); err != nil {
+		return printer.ErrorWithContext(
+			"Redis connection failed",
+			fmt.Sprintf("Could not connect to Redis at %s", redisURL),
+			nil,
+			[]string{
+				fmt.Sprintf("Check Redis container status:\n  docker logs holt-redis-%s", targetInstanceName),
+				fmt.Sprintf("Restart if needed:\n  holt down --name %s\n  holt up --name %s", targetInstanceName, targetInstanceName),
+			},
+		)
+	}
+
+	// Phase 5: Run the dashboard
+	collector := top.NewCollector(cli, bbClient, targetInstanceName, top.DockerHealthProbe(cli))
+	if err := top.Run(ctx, collector, bbClient, topInterval); err != nil {
+		if errors.Is(err, top.ErrNotTerminal) {
+			return printer.Error(
+				"not an interactive terminal",
+				"holt top draws a full-screen dashboard and needs a terminal on stdin and stdout.",
+				[]string{
+					fmt.Sprintf("Stream events instead:\n  holt watch --name %s", targetInstanceName),
+					fmt.Sprintf("List artefacts:\n  holt hoard --name %s", targetInstanceName),
+				},
+			)
+		}
+		return err
+	}
+	return nil
+}
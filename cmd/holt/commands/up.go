@@ -2,6 +2,8 @@ package commands
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -19,6 +21,7 @@ import (
 	"github.com/dyluth/holt/internal/git"
 	"github.com/dyluth/holt/internal/instance"
 	"github.com/dyluth/holt/internal/printer"
+	"github.com/dyluth/holt/pkg/blackboard"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/spf13/cobra"
@@ -27,6 +30,7 @@ import (
 var (
 	upInstanceName string
 	upForce        bool
+	upProfile      string
 )
 
 var upCmd = &cobra.Command{
@@ -40,7 +44,11 @@ Creates and starts:
   • Orchestrator container (claim coordinator)
 
 The instance name is auto-generated (default-N) unless specified with --name.
-Workspace safety checks prevent multiple instances on the same directory unless --force is used.`,
+Workspace safety checks prevent multiple instances on the same directory unless --force is used.
+
+--profile merges holt.<profile>.yml over holt.yml, so variants such as CI or
+local runs only list what differs. Run 'holt config show --profile <name>'
+to see the merged result.`,
 	RunE: runUp,
 }
 
@@ -48,6 +56,7 @@ func init() {
 	upCmd.Flags().StringVarP(&upInstanceName, "name", "n", "", "Instance name (auto-generated if omitted)")
 	// Note: Cannot use -f shorthand because it conflicts with global --config flag
 	upCmd.Flags().BoolVar(&upForce, "force", false, "Bypass workspace collision check")
+	upCmd.Flags().StringVar(&upProfile, "profile", "", "Merge holt.<profile>.yml over holt.yml")
 	rootCmd.AddCommand(upCmd)
 }
 
@@ -60,16 +69,17 @@ func runUp(cmd *cobra.Command, args []string) error {
 	}
 
 	// Phase 2: Configuration Validation
-	cfg, err := config.Load("holt.yml")
+	cfg, err := loadUpConfig()
 	if err != nil {
-		return printer.Error(
-			"holt.yml not found or invalid",
-			"No configuration file found in the current directory.",
-			[]string{
-				"Initialize your project first:\n  holt init",
-				"Then retry: holt up",
-			},
-		)
+		return err
+	}
+
+	// Warn about produced artefact types that nothing consumes
+	for _, warning := range cfg.PipelineWarnings() {
+		printer.Warning("%s\n", warning)
+	}
+	for _, issue := range cfg.WorkflowIssues() {
+		printer.Warning("%s (run 'holt validate' for details)\n", issue)
 	}
 
 	// Create Docker client
@@ -174,6 +184,12 @@ func validateEnvironment() error {
 }
 
 func createInstance(ctx context.Context, cli *client.Client, cfg *config.HoltConfig, instanceName, runID, workspacePath string) error {
+	// Step 0: Read env files and check secret files before creating anything
+	agentEnv, err := resolveAgentEnvironments(cfg, workspacePath)
+	if err != nil {
+		return err
+	}
+
 	// Step 1: Validate all agent images exist
 	if err := validateAgentImages(ctx, cli, cfg); err != nil {
 		return err
@@ -245,9 +261,12 @@ func createInstance(ctx context.Context, cli *client.Client, cfg *config.HoltCon
 		return err
 	}
 
-	// Step 5: Start Orchestrator container with pre-built image
-	orchestratorName := dockerpkg.OrchestratorContainerName(instanceName)
+	// Step 5: Start Orchestrator container(s) with pre-built image
+	// HA: with orchestrator.replicas > 1 the replicas elect a leader; the rest stand by
 	orchestratorLabels := dockerpkg.BuildLabels(instanceName, runID, workspacePath, "orchestrator")
+	if cfg.Profile() != "" {
+		orchestratorLabels[dockerpkg.LabelProfile] = cfg.Profile()
+	}
 
 	// Use Redis container name as hostname (Docker DNS)
 	redisURL := fmt.Sprintf("redis://%s:6379", redisName)
@@ -255,38 +274,51 @@ func createInstance(ctx context.Context, cli *client.Client, cfg *config.HoltCon
 	// M3.4: Get Docker socket GID for worker management permissions
 	dockerGroups := getDockerSocketGroups()
 
-	orchestratorResp, err := cli.ContainerCreate(ctx, &container.Config{
-		Image:  orchestratorImage,
-		Labels: orchestratorLabels,
-		Env: []string{
-			fmt.Sprintf("HOLT_INSTANCE_NAME=%s", instanceName),
-			fmt.Sprintf("REDIS_URL=%s", redisURL),
-			// M3.4: Pass host workspace path for worker mounts
-			fmt.Sprintf("HOST_WORKSPACE_PATH=%s", workspacePath),
-		},
-	}, &container.HostConfig{
-		NetworkMode: container.NetworkMode(networkName),
-		Binds: []string{
-			fmt.Sprintf("%s:/workspace:ro", workspacePath),
-			// M3.4: Mount Docker socket for worker management
-			"/var/run/docker.sock:/var/run/docker.sock",
-		},
-		// M3.4: Grant Docker socket access (required for worker launching)
-		// Only add group if we successfully detected the socket's GID
-		GroupAdd: dockerGroups,
-	}, nil, nil, orchestratorName)
+	// The orchestrator loads holt.yml itself, so it needs the host variables the file
+	// interpolates, and the resolved environment for the workers it launches
+	orchestratorEnv, err := orchestratorConfigEnv(cfg, agentEnv)
 	if err != nil {
-		return fmt.Errorf("failed to create orchestrator container: %w", err)
+		return err
 	}
 
-	if err := cli.ContainerStart(ctx, orchestratorResp.ID, container.StartOptions{}); err != nil {
-		return fmt.Errorf("failed to start orchestrator container: %w", err)
-	}
+	for replica := 1; replica <= cfg.Orchestrator.ReplicaCount(); replica++ {
+		orchestratorName := dockerpkg.OrchestratorReplicaContainerName(instanceName, replica)
+
+		orchestratorResp, err := cli.ContainerCreate(ctx, &container.Config{
+			Image:  orchestratorImage,
+			Labels: orchestratorLabels,
+			Env: append([]string{
+				fmt.Sprintf("HOLT_INSTANCE_NAME=%s", instanceName),
+				fmt.Sprintf("REDIS_URL=%s", redisURL),
+				// M3.4: Pass host workspace path for worker mounts
+				fmt.Sprintf("HOST_WORKSPACE_PATH=%s", workspacePath),
+				// HA: Replica identity recorded in the leader lease
+				fmt.Sprintf("HOLT_ORCHESTRATOR_ID=%s", orchestratorName),
+			}, orchestratorEnv...),
+		}, &container.HostConfig{
+			NetworkMode: container.NetworkMode(networkName),
+			Binds: []string{
+				fmt.Sprintf("%s:/workspace:ro", workspacePath),
+				// M3.4: Mount Docker socket for worker management
+				"/var/run/docker.sock:/var/run/docker.sock",
+			},
+			// M3.4: Grant Docker socket access (required for worker launching)
+			// Only add group if we successfully detected the socket's GID
+			GroupAdd: dockerGroups,
+		}, nil, nil, orchestratorName)
+		if err != nil {
+			return fmt.Errorf("failed to create orchestrator container: %w", err)
+		}
 
-	printer.Debug("Started orchestrator container: %s\n", orchestratorName)
+		if err := cli.ContainerStart(ctx, orchestratorResp.ID, container.StartOptions{}); err != nil {
+			return fmt.Errorf("failed to start orchestrator container: %w", err)
+		}
+
+		printer.Debug("Started orchestrator container: %s\n", orchestratorName)
+	}
 
 	// Step 6: Launch agent containers
-	if err := launchAgentContainers(ctx, cli, cfg, instanceName, runID, workspacePath, networkName, redisName); err != nil {
+	if err := launchAgentContainers(ctx, cli, cfg, agentEnv, instanceName, runID, workspacePath, networkName, redisName); err != nil {
 		return fmt.Errorf("failed to launch agent containers: %w", err)
 	}
 
@@ -295,6 +327,11 @@ func createInstance(ctx context.Context, cli *client.Client, cfg *config.HoltCon
 		return fmt.Errorf("failed to populate agent images: %w", err)
 	}
 
+	// Step 8: Store the effective config for the orchestrator and holt reload
+	if err := storeInstanceConfig(ctx, cfg, agentEnv, instanceName, redisPort); err != nil {
+		return fmt.Errorf("failed to store instance config: %w", err)
+	}
+
 	return nil
 }
 
@@ -343,6 +380,39 @@ func rollbackInstance(ctx context.Context, cli *client.Client, instanceName stri
 	return nil
 }
 
+// loadUpConfig loads holt.yml, merging the --profile overlay when one is given
+func loadUpConfig() (*config.HoltConfig, error) {
+	if upProfile == "" {
+		cfg, err := config.Load("holt.yml")
+		if err != nil {
+			return nil, printer.Error(
+				"holt.yml not found or invalid",
+				"No configuration file found in the current directory.",
+				[]string{
+					"Initialize your project first:\n  holt init",
+					"Then retry: holt up",
+				},
+			)
+		}
+		return cfg, nil
+	}
+
+	overlayPath := config.ProfilePath("holt.yml", upProfile)
+	cfg, err := config.LoadProfile("holt.yml", upProfile)
+	if err != nil {
+		return nil, printer.Error(
+			fmt.Sprintf("profile '%s' could not be applied", upProfile),
+			err.Error(),
+			[]string{
+				fmt.Sprintf("Check that %s exists next to holt.yml", overlayPath),
+				fmt.Sprintf("Inspect the merged config: holt config show --profile %s", upProfile),
+			},
+		)
+	}
+	printer.Info("Using profile '%s' (%s)\n", upProfile, overlayPath)
+	return cfg, nil
+}
+
 func printUpSuccess(instanceName, workspacePath string, cfg *config.HoltConfig) {
 	agentCount := len(cfg.Agents)
 	printer.Success("\nInstance '%s' started successfully (%d agents ready)\n\n", instanceName, agentCount)
@@ -350,7 +420,9 @@ func printUpSuccess(instanceName, workspacePath string, cfg *config.HoltConfig)
 	// Debug-level container/network details
 	printer.Debug("Containers:\n")
 	printer.Debug("  • %s (running)\n", dockerpkg.RedisContainerName(instanceName))
-	printer.Debug("  • %s (running)\n", dockerpkg.OrchestratorContainerName(instanceName))
+	for replica := 1; replica <= cfg.Orchestrator.ReplicaCount(); replica++ {
+		printer.Debug("  • %s (running)\n", dockerpkg.OrchestratorReplicaContainerName(instanceName, replica))
+	}
 
 	// List agent containers (M3.7: agent key IS the role)
 	for agentRole, agent := range cfg.Agents {
@@ -458,7 +530,7 @@ func validateAgentImages(ctx context.Context, cli *client.Client, cfg *config.Ho
 // Uses goroutines + WaitGroup for concurrent startup.
 // Validates health checks before reporting success.
 // Returns error immediately on first failure (fail-fast) and triggers rollback.
-func launchAgentContainers(ctx context.Context, cli *client.Client, cfg *config.HoltConfig, instanceName, runID, workspacePath, networkName, redisName string) error {
+func launchAgentContainers(ctx context.Context, cli *client.Client, cfg *config.HoltConfig, agentEnv map[string][]string, instanceName, runID, workspacePath, networkName, redisName string) error {
 	if len(cfg.Agents) == 0 {
 		return nil
 	}
@@ -480,7 +552,7 @@ func launchAgentContainers(ctx context.Context, cli *client.Client, cfg *config.
 		agentCount++
 		// Launch each agent in a goroutine
 		go func(role string, agentCfg config.Agent) {
-			err := launchAgentContainer(launchCtx, cli, instanceName, runID, workspacePath, networkName, redisName, role, agentCfg)
+			err := launchAgentContainer(launchCtx, cli, instanceName, runID, workspacePath, networkName, redisName, role, agentCfg, agentEnv[role])
 			resultChan <- launchResult{agentName: role, err: err}
 		}(agentRole, agent)
 	}
@@ -510,11 +582,14 @@ func launchAgentContainers(ctx context.Context, cli *client.Client, cfg *config.
 }
 
 // M3.7: agentRole parameter is the agent key from holt.yml (which IS the role)
-func launchAgentContainer(ctx context.Context, cli *client.Client, instanceName, runID, workspacePath, networkName, redisName, agentRole string, agent config.Agent) error {
+// agentEnv is the agent's resolved env_file and environment entries.
+func launchAgentContainer(ctx context.Context, cli *client.Client, instanceName, runID, workspacePath, networkName, redisName, agentRole string, agent config.Agent, agentEnv []string) error {
 	containerName := dockerpkg.AgentContainerName(instanceName, agentRole)
 	labels := dockerpkg.BuildLabels(instanceName, runID, workspacePath, "agent")
 	labels[dockerpkg.LabelAgentName] = agentRole // M3.7: Agent name = role
 	labels[dockerpkg.LabelAgentRole] = agentRole // M3.7: Same value (kept for label consistency)
+	// holt reload recreates the agent when its config hash changes
+	labels[dockerpkg.LabelConfigHash] = agentConfigHash(agent, agentEnv)
 
 	// Determine workspace mode (default to ro)
 	workspaceMode := "ro"
@@ -530,6 +605,43 @@ func launchAgentContainer(ctx context.Context, cli *client.Client, instanceName,
 		fmt.Sprintf("HOLT_AGENT_NAME=%s", agentRole),
 		fmt.Sprintf("REDIS_URL=%s", redisURL),
 		fmt.Sprintf("HOLT_BIDDING_STRATEGY=%s", agent.BiddingStrategy), // M3.1
+		fmt.Sprintf("HOLT_WORKSPACE_MODE=%s", workspaceMode),
+	}
+
+	// Per-claim worktree isolation (pup also isolates implicitly when concurrency > 1)
+	if agent.Workspace != nil && agent.Workspace.Isolation != "" {
+		env = append(env, fmt.Sprintf("HOLT_WORKSPACE_ISOLATION=%s", agent.Workspace.Isolation))
+	}
+
+	// Context assembly settings as JSON
+	if agent.Context != nil {
+		contextJSON, err := json.Marshal(agent.Context)
+		if err != nil {
+			return fmt.Errorf("failed to marshal context settings to JSON: %w", err)
+		}
+		env = append(env, fmt.Sprintf("HOLT_AGENT_CONTEXT=%s", contextJSON))
+	}
+
+	// Long-running HTTP agent protocol
+	if agent.Protocol != "" {
+		env = append(env, fmt.Sprintf("HOLT_AGENT_PROTOCOL=%s", agent.Protocol))
+	}
+	if agent.HTTP != nil {
+		httpJSON, err := json.Marshal(agent.HTTP)
+		if err != nil {
+			return fmt.Errorf("failed to marshal http settings to JSON: %w", err)
+		}
+		env = append(env, fmt.Sprintf("HOLT_AGENT_HTTP=%s", httpJSON))
+	}
+
+	// Pup-managed commit capture of workspace changes
+	if agent.AutoCommit {
+		env = append(env, "HOLT_AUTO_COMMIT=true")
+	}
+
+	// Concurrent claim execution (pup defaults to 1)
+	if agent.Concurrency > 1 {
+		env = append(env, fmt.Sprintf("HOLT_AGENT_CONCURRENCY=%d", agent.Concurrency))
 	}
 
 	// M3.4: Set HOLT_MODE for controller agents
@@ -555,13 +667,23 @@ func launchAgentContainer(ctx context.Context, cli *client.Client, instanceName,
 		env = append(env, fmt.Sprintf("HOLT_AGENT_BID_SCRIPT=%s", bidScriptJSON))
 	}
 
-	// Add custom environment variables from config (with expansion)
-	if len(agent.Environment) > 0 {
-		for _, envVar := range agent.Environment {
-			env = append(env, os.ExpandEnv(envVar))
+	// Add HOLT_AGENT_CONSUMES as JSON array (declarative type matching)
+	if len(agent.Consumes) > 0 {
+		consumesJSON, err := json.Marshal(agent.Consumes)
+		if err != nil {
+			return fmt.Errorf("failed to marshal agent consumes to JSON: %w", err)
 		}
+		env = append(env, fmt.Sprintf("HOLT_AGENT_CONSUMES=%s", consumesJSON))
+	}
+
+	// Add HOLT_AGENT_WHEN (content-based routing expression)
+	if agent.When != "" {
+		env = append(env, fmt.Sprintf("HOLT_AGENT_WHEN=%s", agent.When))
 	}
 
+	// Add custom environment variables from env_file and environment (interpolated at load)
+	env = append(env, agentEnv...)
+
 	// Create container
 	resp, err := cli.ContainerCreate(ctx, &container.Config{
 		Image:  agent.Image,
@@ -570,9 +692,9 @@ func launchAgentContainer(ctx context.Context, cli *client.Client, instanceName,
 		Cmd:    agent.Command,
 	}, &container.HostConfig{
 		NetworkMode: container.NetworkMode(networkName),
-		Binds: []string{
+		Binds: append([]string{
 			fmt.Sprintf("%s:/workspace:%s", workspacePath, workspaceMode),
-		},
+		}, secretBinds(agent, workspacePath)...),
 	}, nil, nil, containerName)
 	if err != nil {
 		return fmt.Errorf("failed to create container: %w", err)
@@ -587,6 +709,127 @@ func launchAgentContainer(ctx context.Context, cli *client.Client, instanceName,
 	return nil
 }
 
+// resolveAgentEnvironments reads every agent's env files and checks its secret
+// files exist, returning each agent's environment by role. Paths are relative
+// to the workspace, where holt.yml lives.
+func resolveAgentEnvironments(cfg *config.HoltConfig, workspacePath string) (map[string][]string, error) {
+	agentEnv := make(map[string][]string, len(cfg.Agents))
+	for role, agent := range cfg.Agents {
+		env, err := agent.ResolveEnvironment(workspacePath)
+		if err != nil {
+			return nil, fmt.Errorf("agent '%s': %w", role, err)
+		}
+		agentEnv[role] = env
+
+		for _, secret := range agent.Secrets {
+			// Docker would create a missing bind source as an empty directory
+			info, err := os.Stat(secret.HostPath(workspacePath))
+			if err != nil {
+				return nil, fmt.Errorf("agent '%s': secret '%s': %w", role, secret.MountName(), err)
+			}
+			if info.IsDir() {
+				return nil, fmt.Errorf("agent '%s': secret '%s': %s is a directory", role, secret.MountName(), secret.File)
+			}
+		}
+	}
+	return agentEnv, nil
+}
+
+// orchestratorConfigEnv returns the host variables holt.yml interpolates, so the
+// orchestrator's own config load sees the same values, HOLT_PROFILE when a
+// profile overlay is in use, and HOLT_WORKER_ENV with the resolved environment
+// of each controller's workers
+func orchestratorConfigEnv(cfg *config.HoltConfig, agentEnv map[string][]string) ([]string, error) {
+	var env []string
+	if cfg.Profile() != "" {
+		env = append(env, fmt.Sprintf("HOLT_PROFILE=%s", cfg.Profile()))
+	}
+	for _, name := range cfg.Variables() {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, fmt.Sprintf("%s=%s", name, value))
+		}
+	}
+
+	if workerEnv := workerEnvironment(cfg, agentEnv); len(workerEnv) > 0 {
+		workerEnvJSON, err := json.Marshal(workerEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal worker environment to JSON: %w", err)
+		}
+		env = append(env, fmt.Sprintf("HOLT_WORKER_ENV=%s", workerEnvJSON))
+	}
+	return env, nil
+}
+
+// workerEnvironment returns the resolved environment of each controller, which
+// the orchestrator passes to the controller's workers
+func workerEnvironment(cfg *config.HoltConfig, agentEnv map[string][]string) map[string][]string {
+	workerEnv := map[string][]string{}
+	for role, agent := range cfg.Agents {
+		if agent.Mode == "controller" && len(agentEnv[role]) > 0 {
+			workerEnv[role] = agentEnv[role]
+		}
+	}
+	return workerEnv
+}
+
+// instanceConfig returns the effective configuration as stored for a running instance
+func instanceConfig(cfg *config.HoltConfig, agentEnv map[string][]string) (*blackboard.InstanceConfig, error) {
+	data, err := cfg.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return &blackboard.InstanceConfig{
+		Profile:   cfg.Profile(),
+		Config:    string(data),
+		WorkerEnv: workerEnvironment(cfg, agentEnv),
+	}, nil
+}
+
+// storeInstanceConfig saves the effective configuration to the instance's
+// blackboard. The orchestrator adopts it when it starts leading, and holt reload
+// replaces it.
+func storeInstanceConfig(ctx context.Context, cfg *config.HoltConfig, agentEnv map[string][]string, instanceName string, redisPort int) error {
+	redisOpts, err := redis.ParseURL(instance.GetRedisURL(redisPort))
+	if err != nil {
+		return fmt.Errorf("failed to parse Redis URL: %w", err)
+	}
+	bbClient, err := blackboard.NewClient(redisOpts, instanceName)
+	if err != nil {
+		return fmt.Errorf("failed to create blackboard client: %w", err)
+	}
+	defer bbClient.Close()
+
+	stored, err := instanceConfig(cfg, agentEnv)
+	if err != nil {
+		return err
+	}
+	return bbClient.SaveInstanceConfig(ctx, stored)
+}
+
+// agentConfigHash fingerprints everything an agent container is created from:
+// its holt.yml entry and its resolved environment
+func agentConfigHash(agent config.Agent, agentEnv []string) string {
+	data, err := json.Marshal(struct {
+		Agent config.Agent
+		Env   []string
+	}{agent, agentEnv})
+	if err != nil {
+		// Unhashable config: an empty hash never matches, so reload recreates the agent
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:12])
+}
+
+// secretBinds returns read-only bind mounts of the agent's secrets at /run/secrets/<name>
+func secretBinds(agent config.Agent, workspacePath string) []string {
+	binds := make([]string, 0, len(agent.Secrets))
+	for _, secret := range agent.Secrets {
+		binds = append(binds, fmt.Sprintf("%s:%s:ro", secret.HostPath(workspacePath), secret.MountPath()))
+	}
+	return binds
+}
+
 // validateAllAgentsHealthy validates that all agent containers pass health checks.
 // M3.1: Uses docker exec to check /healthz endpoint inside each container.
 // Implements retry logic with exponential backoff (5 attempts over ~10s).
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dyluth/holt/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const upEnvTestConfig = `version: "1.0"
+agents:
+  Coder:
+    image: ${HOLT_UP_TEST_REGISTRY}/coder:latest
+    command: [/app/run.sh]
+    bidding_strategy: exclusive
+    env_file: [.env]
+    environment: ["MODEL=large"]
+    secrets:
+      - file: keys/openai.key
+  Builder:
+    image: builder:latest
+    command: [/app/run.sh]
+    bidding_strategy: exclusive
+    mode: controller
+    env_file: [.env]
+    worker:
+      image: builder:latest
+      command: [/app/run.sh]
+`
+
+func loadUpEnvTestConfig(t *testing.T) (*config.HoltConfig, string) {
+	t.Helper()
+	t.Setenv("HOLT_UP_TEST_REGISTRY", "ghcr.io/acme")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "holt.yml"), []byte(upEnvTestConfig), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("MODEL=small\nREGION=eu\n"), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "keys"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "keys", "openai.key"), []byte("sk-test\n"), 0600))
+
+	cfg, err := config.Load(filepath.Join(dir, "holt.yml"))
+	require.NoError(t, err)
+	return cfg, dir
+}
+
+func TestResolveAgentEnvironments(t *testing.T) {
+	cfg, dir := loadUpEnvTestConfig(t)
+
+	agentEnv, err := resolveAgentEnvironments(cfg, dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"MODEL=small", "REGION=eu", "MODEL=large"}, agentEnv["Coder"])
+	assert.Equal(t, []string{"MODEL=small", "REGION=eu"}, agentEnv["Builder"])
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "keys", "openai.key")))
+	_, err = resolveAgentEnvironments(cfg, dir)
+	assert.ErrorContains(t, err, "agent 'Coder': secret 'openai.key'")
+}
+
+func TestOrchestratorConfigEnv(t *testing.T) {
+	cfg, dir := loadUpEnvTestConfig(t)
+	agentEnv, err := resolveAgentEnvironments(cfg, dir)
+	require.NoError(t, err)
+
+	env, err := orchestratorConfigEnv(cfg, agentEnv)
+	require.NoError(t, err)
+
+	require.Len(t, env, 2)
+	assert.Equal(t, "HOLT_UP_TEST_REGISTRY=ghcr.io/acme", env[0])
+	// Only controllers launch workers
+	assert.Equal(t, `HOLT_WORKER_ENV={"Builder":["MODEL=small","REGION=eu"]}`, env[1])
+}
+
+func TestSecretBinds(t *testing.T) {
+	agent := config.Agent{Secrets: []config.Secret{
+		{File: "keys/openai.key"},
+		{Name: "db", File: "/etc/holt/db-password"},
+	}}
+
+	binds := secretBinds(agent, "/repo")
+
+	assert.Equal(t, []string{
+		"/repo/keys/openai.key:/run/secrets/openai.key:ro",
+		"/etc/holt/db-password:/run/secrets/db:ro",
+	}, binds)
+}
+
+func TestOrchestratorConfigEnv_Profile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "holt.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`version: "1.0"
+agents:
+  Coder:
+    image: coder:latest
+    command: [/app/run.sh]
+    bidding_strategy: exclusive
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "holt.ci.yml"), []byte("agents:\n  Coder:\n    image: coder:ci\n"), 0644))
+
+	cfg, err := config.LoadProfile(path, "ci")
+	require.NoError(t, err)
+
+	env, err := orchestratorConfigEnv(cfg, nil)
+	require.NoError(t, err)
+	// The orchestrator reloads holt.yml itself and must apply the same overlay
+	assert.Equal(t, []string{"HOLT_PROFILE=ci"}, env)
+}
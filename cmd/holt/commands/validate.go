@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/dyluth/holt/internal/config"
+	"github.com/dyluth/holt/internal/printer"
+	"github.com/spf13/cobra"
+)
+
+var validateSchema bool
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Validate holt.yml and its workflow definitions",
+	Long: `Validate holt.yml offline, without Docker or a running instance.
+
+Lints the file and reports every problem with its line and column:
+  • YAML syntax errors
+  • Unknown fields (with a suggestion for likely typos) and wrongly typed values
+  • The same rules 'holt up' enforces, such as required fields and valid enums
+  • Fields holt accepts but ignores (replicas, strategy, prompts, resources)
+  • Build contexts that do not exist or have no Dockerfile
+  • Health-check intervals and timeouts that are not positive durations
+
+It then analyses every workflow declared under 'workflows:':
+  • Unreachable stages (nothing from the trigger leads to them)
+  • Cycles between stages
+  • Stages whose agent's own 'consumes' filter excludes the stage's types
+
+Each workflow's stages are printed in dependency order. Produced artefact
+types that nothing consumes, and ignored fields, are reported as warnings.
+The command exits non-zero if the configuration is invalid or any workflow
+has issues.
+
+--schema prints a JSON Schema for holt.yml instead, for editor validation
+and autocompletion (e.g. yaml-language-server's '# yaml-language-server:
+$schema=holt.schema.json' modeline).
+
+Examples:
+  # Validate ./holt.yml
+  holt validate
+
+  # Validate another file
+  holt validate ci/holt.yml
+
+  # Write a schema for your editor
+  holt validate --schema > holt.schema.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runValidate,
+}
+
+func init() {
+	validateCmd.Flags().BoolVar(&validateSchema, "schema", false, "Print a JSON Schema for holt.yml and exit")
+	rootCmd.AddCommand(validateCmd)
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	if validateSchema {
+		schema, err := config.JSONSchema()
+		if err != nil {
+			return fmt.Errorf("failed to generate schema: %w", err)
+		}
+		fmt.Println(string(schema))
+		return nil
+	}
+
+	configPath := "holt.yml"
+	if len(args) > 0 {
+		configPath = args[0]
+	} else if GetGlobalConfigPath() != "" {
+		configPath = GetGlobalConfigPath()
+	}
+
+	// Lint reports role naming itself; silence the duplicate log lines from validation
+	logWriter := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(logWriter)
+
+	issues, err := config.LintFile(configPath)
+	if err != nil {
+		return printer.Error(
+			fmt.Sprintf("cannot read %s", configPath),
+			err.Error(),
+			[]string{"Check the path, or pass the file explicitly: holt validate path/to/holt.yml"},
+		)
+	}
+
+	var failures []string
+	for _, issue := range issues {
+		if issue.Severity == config.SeverityWarning {
+			printer.Warning("%s\n", formatIssue(configPath, issue))
+			continue
+		}
+		failures = append(failures, formatIssue(configPath, issue))
+	}
+	if len(failures) > 0 {
+		return printer.Error(
+			fmt.Sprintf("%s is invalid", configPath),
+			strings.Join(failures, "\n"),
+			[]string{"Fix the reported fields and run 'holt validate' again"},
+		)
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return printer.Error(
+			fmt.Sprintf("%s is invalid", configPath),
+			err.Error(),
+			[]string{"Fix the reported field and run 'holt validate' again"},
+		)
+	}
+
+	for _, warning := range cfg.PipelineWarnings() {
+		printer.Warning("%s\n", warning)
+	}
+
+	for _, name := range workflowNames(cfg) {
+		workflow := cfg.Workflows[name]
+		printer.Info("Workflow %s:\n", name)
+		for _, stage := range workflow.Order() {
+			printer.Info("  %s\n", formatStage(stage))
+		}
+	}
+
+	workflowIssues := cfg.WorkflowIssues()
+	if len(workflowIssues) > 0 {
+		return printer.Error(
+			fmt.Sprintf("%d workflow issue(s) in %s", len(workflowIssues), configPath),
+			strings.Join(workflowIssues, "\n"),
+			[]string{"Check each stage's consumes, produces and after fields"},
+		)
+	}
+
+	printer.Success("%s is valid (%d agents, %d workflows)\n", configPath, len(cfg.Agents), len(cfg.Workflows))
+	return nil
+}
+
+// formatIssue renders a lint issue as "file:line:col: path: message"
+func formatIssue(configPath string, issue config.Issue) string {
+	if issue.Line > 0 {
+		return fmt.Sprintf("%s:%s", configPath, issue)
+	}
+	return fmt.Sprintf("%s: %s", configPath, issue)
+}
+
+// workflowNames returns the config's workflow names in name order
+func workflowNames(cfg *config.HoltConfig) []string {
+	names := make([]string, 0, len(cfg.Workflows))
+	for name := range cfg.Workflows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatStage renders a stage as "name: Role [phase] Consumes -> Produces (after ...)"
+func formatStage(stage config.WorkflowStage) string {
+	phase := stage.Phase
+	if phase == "" {
+		phase = config.PhaseExclusive
+	}
+
+	line := fmt.Sprintf("%s: %s [%s] %s", stage.Name, stage.Role, phase, strings.Join(stage.Consumes, ","))
+	if len(stage.Produces) > 0 {
+		line += " -> " + strings.Join(stage.Produces, ",")
+	}
+	if len(stage.After) > 0 {
+		line += fmt.Sprintf(" (after %s)", strings.Join(stage.After, ", "))
+	}
+	return line
+}
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dyluth/holt/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validateTestConfig = `version: "1.0"
+agents:
+  Designer:
+    image: test:latest
+    command: [/app/run.sh]
+    bidding_strategy: exclusive
+  Coder:
+    image: test:latest
+    command: [/app/run.sh]
+    bidding_strategy: exclusive
+workflows:
+  feature:
+    stages:
+      - name: design
+        role: Designer
+        consumes: [GoalDefined]
+        produces: [DesignSpec]
+      - name: build
+        role: Coder
+        consumes: [DesignSpec]
+        after: [design]
+`
+
+func runValidateWith(t *testing.T, content string) error {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "holt.yml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	previous := globalConfigPath
+	globalConfigPath = path
+	t.Cleanup(func() { globalConfigPath = previous })
+
+	return runValidate(validateCmd, nil)
+}
+
+func TestValidate_ValidWorkflow(t *testing.T) {
+	assert.NoError(t, runValidateWith(t, validateTestConfig))
+}
+
+func TestValidate_ReportsWorkflowIssues(t *testing.T) {
+	content := validateTestConfig + `      - name: package
+        role: Coder
+        consumes: [TestReport]
+`
+	err := runValidateWith(t, content)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 workflow issue(s)")
+}
+
+func TestValidate_InvalidConfig(t *testing.T) {
+	content := validateTestConfig + `      - name: ship
+        role: Shipper
+        consumes: [DesignSpec]
+`
+	err := runValidateWith(t, content)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "holt.yml is invalid")
+}
+
+func TestValidate_PositionalFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "custom.yml")
+	require.NoError(t, os.WriteFile(path, []byte(validateTestConfig), 0644))
+
+	assert.NoError(t, runValidate(validateCmd, []string{path}))
+}
+
+func TestValidate_ReportsLintErrors(t *testing.T) {
+	content := strings.Replace(validateTestConfig, "bidding_strategy: exclusive\n  Coder:", "bidding_strategy: exclusive\n    concurency: 2\n  Coder:", 1)
+
+	err := runValidateWith(t, content)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "holt.yml is invalid")
+}
+
+func TestValidate_MissingFile(t *testing.T) {
+	err := runValidate(validateCmd, []string{filepath.Join(t.TempDir(), "missing.yml")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot read")
+}
+
+func TestFormatIssue(t *testing.T) {
+	assert.Equal(t, "holt.yml:3:5: agents.Coder: bad",
+		formatIssue("holt.yml", config.Issue{Line: 3, Column: 5, Path: "agents.Coder", Message: "bad"}))
+	assert.Equal(t, "holt.yml: bad", formatIssue("holt.yml", config.Issue{Message: "bad"}))
+}
+
+func TestFormatStage(t *testing.T) {
+	assert.Equal(t, "design: Designer [exclusive] GoalDefined -> DesignSpec",
+		formatStage(config.WorkflowStage{Name: "design", Role: "Designer", Consumes: []string{"GoalDefined"}, Produces: []string{"DesignSpec"}}))
+	assert.Equal(t, "review: Reviewer [review] *Spec (after design, plan)",
+		formatStage(config.WorkflowStage{Name: "review", Role: "Reviewer", Phase: "review", Consumes: []string{"*Spec"}, After: []string{"design", "plan"}}))
+}
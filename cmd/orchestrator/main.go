@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
@@ -46,8 +47,9 @@ func main() {
 		os.Exit(1)
 	}
 
-	// 5. Load holt.yml configuration from workspace
-	cfg, err := config.Load("/workspace/holt.yml")
+	// 5. Load holt.yml configuration from workspace, with the profile overlay
+	// 'holt up --profile' was started with
+	cfg, err := config.LoadProfile("/workspace/holt.yml", os.Getenv("HOLT_PROFILE"))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: Failed to load holt.yml: %v\n", err)
 		os.Exit(1)
@@ -109,31 +111,60 @@ func main() {
 			fmt.Println("Warning: HOST_WORKSPACE_PATH not set, using /workspace (may fail in containerized environment)")
 		}
 		workerManager = orchestrator.NewWorkerManager(dockerClient, instanceName, hostWorkspacePath)
+
+		// Worker environment resolved by holt up from env_file and environment entries
+		if workerEnvJSON := os.Getenv("HOLT_WORKER_ENV"); workerEnvJSON != "" {
+			var workerEnv map[string][]string
+			if err := json.Unmarshal([]byte(workerEnvJSON), &workerEnv); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Invalid HOLT_WORKER_ENV: %v\n", err)
+				os.Exit(1)
+			}
+			workerManager.SetWorkerEnvironment(workerEnv)
+		}
 		fmt.Println("Worker manager initialized for controller-worker pattern")
 	}
 
-	// 8. Create orchestrator engine with config
-	engine := orchestrator.NewEngine(bbClient, instanceName, cfg, workerManager)
+	// 8. Identify this replica for leader election (HA: several replicas may share an instance)
+	replicaID := os.Getenv("HOLT_ORCHESTRATOR_ID")
+	if replicaID == "" {
+		replicaID, _ = os.Hostname()
+	}
+
+	// 9. Start health check server (served by leader and standbys alike)
+	healthServer := orchestrator.NewHealthServer(bbClient)
+	if err := healthServer.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to start health server: %v\n", err)
+		os.Exit(1)
+	}
+	defer healthServer.Shutdown(context.Background())
 
-	// 9. Setup graceful shutdown
+	// 10. Setup graceful shutdown
 	runCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
 
-	// 10. Start orchestrator in goroutine
+	// 11. Campaign for leadership; each time this replica becomes leader a fresh engine
+	// runs (recovering state from Redis) until leadership is lost or we shut down
+	election := orchestrator.NewLeaderElection(bbClient, replicaID, cfg.Orchestrator.LeaseDuration())
+	fmt.Printf("Orchestrator replica '%s' waiting for leadership\n", replicaID)
+
 	errCh := make(chan error, 1)
 	go func() {
-		errCh <- engine.Run(runCtx)
+		errCh <- election.Run(runCtx, func(leaderCtx context.Context) error {
+			engine := orchestrator.NewEngine(bbClient, instanceName, cfg, workerManager)
+			engine.DisableHealthServer()
+			return engine.Run(leaderCtx)
+		})
 	}()
 
-	// 11. Wait for shutdown signal or error
+	// 12. Wait for shutdown signal or error
 	select {
 	case sig := <-sigCh:
 		fmt.Printf("Received signal %v, shutting down gracefully...\n", sig)
 		cancel()
-		// Wait for engine to finish
+		// Wait for engine to finish and leadership to be released
 		<-errCh
 	case runErr := <-errCh:
 		if runErr != nil {
@@ -142,7 +173,7 @@ func main() {
 		}
 	}
 
-	// 12. Cleanup Docker client if initialized
+	// 13. Cleanup Docker client if initialized
 	if dockerClient != nil {
 		dockerClient.Close()
 	}
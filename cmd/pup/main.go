@@ -33,6 +33,9 @@ func run() int {
 		return 1
 	}
 
+	// Keep mounted secret values out of container logs, and so out of holt logs
+	log.SetOutput(config.Secrets.Writer(os.Stderr))
+
 	// M3.4: Mode decision tree
 	// 1. If HOLT_MODE=controller → controller mode (bidder-only)
 	// 2. Else if --execute-claim <id> → worker mode (execute-only)
@@ -86,8 +89,12 @@ func runTraditionalMode(config *pup.Config) int {
 	cancel()
 	log.Printf("[INFO] Connected to Redis")
 
-	// Create health server
+	// Create engine
+	engine := pup.New(config, bbClient)
+
+	// Create health server (reports the engine's in-flight claims)
 	healthServer := pup.NewHealthServer(bbClient, 8080)
+	healthServer.SetInFlightReporter(engine)
 
 	// Start health server
 	if err := healthServer.Start(); err != nil {
@@ -96,9 +103,6 @@ func runTraditionalMode(config *pup.Config) int {
 	}
 	log.Printf("[INFO] Health server started on :8080")
 
-	// Create engine
-	engine := pup.New(config, bbClient)
-
 	// Set up context for graceful shutdown
 	engineCtx, engineCancel := context.WithCancel(context.Background())
 	defer engineCancel()
@@ -0,0 +1,120 @@
+// Package agentrun executes a single claim for one agent offline, without Redis or an
+// orchestrator. The target and context artefacts come from JSON fixtures and are loaded
+// into an in-memory blackboard, so the pup's own context assembly, output validation
+// and artefact construction run unchanged.
+package agentrun
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/dyluth/holt/internal/config"
+	"github.com/dyluth/holt/internal/pup"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// offlineInstanceName namespaces the in-memory blackboard
+const offlineInstanceName = "agent-run"
+
+// Options configures an offline run.
+type Options struct {
+	// Role is the agent's key in holt.yml
+	Role string
+
+	// Agent is the agent's configuration from holt.yml
+	Agent config.Agent
+
+	// Target is the artefact the claim is for
+	Target *blackboard.Artefact
+
+	// Context holds artefacts available to context assembly (ancestors, siblings, reviews)
+	Context []*blackboard.Artefact
+
+	// Workspace is the repository the tool works in; CodeCommit outputs are checked
+	// against it. Empty means the pup's /workspace mount.
+	Workspace string
+
+	// Runner executes the tool (see LocalRunner and DockerRunner)
+	Runner func(engine *pup.Engine) pup.ToolRunner
+
+	// Verbose keeps the pup's logs (context assembly, validation) on stderr
+	Verbose bool
+}
+
+// Run seeds an in-memory blackboard with the fixtures, grants an exclusive claim on the
+// target to the agent, and executes it. The returned result holds the exact ToolInput,
+// the raw tool output, and the artefacts the pup would have created.
+func Run(ctx context.Context, opts Options) (*pup.OfflineResult, error) {
+	server, err := miniredis.Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start in-memory blackboard: %w", err)
+	}
+	defer server.Close()
+
+	bbClient, err := blackboard.NewClient(&redis.Options{Addr: server.Addr()}, offlineInstanceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blackboard client: %w", err)
+	}
+	defer bbClient.Close()
+
+	for _, artefact := range append(append([]*blackboard.Artefact{}, opts.Context...), opts.Target) {
+		if err := seedArtefact(ctx, bbClient, artefact); err != nil {
+			return nil, err
+		}
+	}
+
+	claim := &blackboard.Claim{
+		ID:                    uuid.New().String(),
+		ArtefactID:            opts.Target.ID,
+		Status:                blackboard.ClaimStatusPendingExclusive,
+		GrantedExclusiveAgent: opts.Role,
+	}
+
+	engine := pup.New(pupConfig(opts.Role, opts.Agent), bbClient)
+	if opts.Workspace != "" {
+		engine.SetWorkspace(opts.Workspace)
+	}
+
+	// The pup logs context assembly at debug level; keep the command output readable
+	if !opts.Verbose {
+		logWriter := log.Writer()
+		log.SetOutput(io.Discard)
+		defer log.SetOutput(logWriter)
+	}
+
+	return engine.RunOffline(ctx, claim, opts.Target, opts.Runner(engine))
+}
+
+// seedArtefact stores a fixture artefact and registers it in its logical thread
+func seedArtefact(ctx context.Context, bbClient *blackboard.Client, artefact *blackboard.Artefact) error {
+	if err := bbClient.CreateArtefact(ctx, artefact); err != nil {
+		return fmt.Errorf("artefact %s (%s): %w", artefact.ID, artefact.Type, err)
+	}
+	if err := bbClient.AddVersionToThread(ctx, artefact.LogicalID, artefact.ID, artefact.Version); err != nil {
+		return fmt.Errorf("failed to add artefact %s to thread: %w", artefact.ID, err)
+	}
+	return nil
+}
+
+// pupConfig builds the pup configuration `holt up` would pass to the agent's container
+func pupConfig(role string, agent config.Agent) *pup.Config {
+	workspaceMode := "ro"
+	if agent.Workspace != nil && agent.Workspace.Mode != "" {
+		workspaceMode = agent.Workspace.Mode
+	}
+
+	return &pup.Config{
+		InstanceName:    offlineInstanceName,
+		AgentName:       role,
+		RedisURL:        "offline",
+		Command:         agent.Command,
+		BiddingStrategy: blackboard.BidType(agent.BiddingStrategy),
+		WorkspaceMode:   workspaceMode,
+		Context:         agent.Context,
+	}
+}
@@ -0,0 +1,181 @@
+package agentrun
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dyluth/holt/internal/config"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFixture writes an artefact JSON fixture and returns its path
+func writeFixture(t *testing.T, dir, name string, artefact map[string]interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(artefact)
+	require.NoError(t, err)
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	return path
+}
+
+func TestLoadArtefact_Defaults(t *testing.T) {
+	path := writeFixture(t, t.TempDir(), "goal.json", map[string]interface{}{"type": "GoalDefined", "payload": "build it"})
+
+	artefact, err := LoadArtefact(path)
+	require.NoError(t, err)
+	assert.NotEmpty(t, artefact.ID)
+	assert.Equal(t, artefact.ID, artefact.LogicalID)
+	assert.Equal(t, 1, artefact.Version)
+	assert.Equal(t, blackboard.StructuralTypeStandard, artefact.StructuralType)
+	assert.Equal(t, "user", artefact.ProducedByRole)
+
+	bad := writeFixture(t, t.TempDir(), "bad.json", map[string]interface{}{"id": "not-a-uuid", "type": "GoalDefined"})
+	_, err = LoadArtefact(bad)
+	assert.ErrorContains(t, err, "invalid artefact")
+}
+
+func TestRun_Local(t *testing.T) {
+	fixtures := t.TempDir()
+	goalID := uuid.New().String()
+	writeFixture(t, fixtures, "01-goal.json", map[string]interface{}{"id": goalID, "type": "GoalDefined", "payload": "build it"})
+	targetPath := writeFixture(t, t.TempDir(), "spec.json", map[string]interface{}{
+		"type": "DesignSpec", "payload": "the spec", "produced_by_role": "designer", "source_artefacts": []string{goalID},
+	})
+
+	target, err := LoadArtefact(targetPath)
+	require.NoError(t, err)
+	contextArtefacts, err := LoadContextDir(fixtures)
+	require.NoError(t, err)
+	require.Len(t, contextArtefacts, 1)
+	OrderFixtures(contextArtefacts, target)
+
+	// The tool echoes its input to stderr and emits a fixed output
+	agent := config.Agent{
+		Command: []string{"sh", "-c", `cat >&2; echo '{"artefact_type":"CodePlan","artefact_payload":"plan","summary":"planned"}'`},
+	}
+
+	workDir := t.TempDir()
+	result, err := Run(context.Background(), Options{
+		Role:    "planner",
+		Agent:   agent,
+		Target:  target,
+		Context: contextArtefacts,
+		Runner:  LocalRunner(workDir),
+	})
+	require.NoError(t, err)
+
+	// ToolInput is built by the pup's context assembly
+	var input map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(result.Input), &input))
+	assert.Equal(t, "exclusive", input["claim_type"])
+	chain := input["context_chain"].([]interface{})
+	require.Len(t, chain, 1)
+	assert.Equal(t, goalID, chain[0].(map[string]interface{})["id"])
+	assert.JSONEq(t, result.Input, result.Stderr)
+
+	require.Len(t, result.Artefacts, 1)
+	created := result.Artefacts[0]
+	assert.Equal(t, "CodePlan", created.Type)
+	assert.Equal(t, "planner", created.ProducedByRole)
+	assert.Equal(t, []string{target.ID}, created.SourceArtefacts)
+	assert.Equal(t, "planned", created.Summary)
+
+	var report bytes.Buffer
+	WriteReport(&report, result, true)
+	assert.Contains(t, report.String(), "Tool input:")
+	assert.Contains(t, report.String(), "Artefact that would be created:")
+	assert.Contains(t, report.String(), `"type": "CodePlan"`)
+}
+
+func TestRun_InvalidOutput(t *testing.T) {
+	target, err := LoadArtefact(writeFixture(t, t.TempDir(), "goal.json", map[string]interface{}{"type": "GoalDefined"}))
+	require.NoError(t, err)
+
+	result, err := Run(context.Background(), Options{
+		Role:   "coder",
+		Agent:  config.Agent{Command: []string{"sh", "-c", `cat >/dev/null; echo '{"artefact_type":"X"}'`}},
+		Target: target,
+		Runner: LocalRunner(t.TempDir()),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "summary is required")
+	assert.Nil(t, result.Output)
+	assert.Contains(t, result.Stdout, "artefact_type")
+
+	var report bytes.Buffer
+	WriteReport(&report, result, false)
+	assert.Contains(t, report.String(), "Tool stdout:")
+	assert.NotContains(t, report.String(), "Tool input:")
+}
+
+func TestRun_ToolFailure(t *testing.T) {
+	target, err := LoadArtefact(writeFixture(t, t.TempDir(), "goal.json", map[string]interface{}{"type": "GoalDefined"}))
+	require.NoError(t, err)
+
+	result, err := Run(context.Background(), Options{
+		Role:   "coder",
+		Agent:  config.Agent{Command: []string{"sh", "-c", "echo broken >&2; exit 2"}},
+		Target: target,
+		Runner: LocalRunner(t.TempDir()),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exited with code 2")
+	assert.Equal(t, 2, result.ExitCode)
+	assert.Contains(t, result.Stderr, "broken")
+}
+
+func TestRun_LocalCodeCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	// A host checkout, not /workspace: the commit must be validated where the tool made it
+	repoDir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+		{"commit", "-q", "--allow-empty", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, output)
+	}
+
+	target, err := LoadArtefact(writeFixture(t, t.TempDir(), "goal.json", map[string]interface{}{"type": "GoalDefined"}))
+	require.NoError(t, err)
+
+	script := `cat >/dev/null; printf '{"artefact_type":"CodeCommit","artefact_payload":"%s","summary":"committed"}' "$(git rev-parse HEAD)"`
+	result, err := Run(context.Background(), Options{
+		Role:      "coder",
+		Agent:     config.Agent{Command: []string{"sh", "-c", script}},
+		Target:    target,
+		Workspace: repoDir,
+		Runner:    LocalRunner(repoDir),
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Artefacts, 1)
+	assert.Equal(t, "CodeCommit", result.Artefacts[0].Type)
+
+	// An unknown hash is still rejected
+	result, err = Run(context.Background(), Options{
+		Role:      "coder",
+		Agent:     config.Agent{Command: []string{"sh", "-c", `cat >/dev/null; echo '{"artefact_type":"CodeCommit","artefact_payload":"` + strings.Repeat("0", 40) + `","summary":"s"}'`}},
+		Target:    target,
+		Workspace: repoDir,
+		Runner:    LocalRunner(repoDir),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "git commit validation failed")
+	assert.Empty(t, result.Artefacts)
+}
@@ -0,0 +1,93 @@
+package agentrun
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+)
+
+// LoadArtefact reads an artefact fixture from a JSON file, as printed by `holt hoard <id>`.
+// Missing fields are defaulted (see applyDefaults).
+func LoadArtefact(path string) (*blackboard.Artefact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artefact file: %w", err)
+	}
+
+	var artefact blackboard.Artefact
+	if err := json.Unmarshal(data, &artefact); err != nil {
+		return nil, fmt.Errorf("failed to parse artefact file %s: %w", path, err)
+	}
+
+	applyDefaults(&artefact)
+	if err := artefact.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid artefact in %s: %w", path, err)
+	}
+
+	return &artefact, nil
+}
+
+// LoadContextDir reads every *.json artefact fixture in dir, in file name order.
+func LoadContextDir(dir string) ([]*blackboard.Artefact, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list context directory: %w", err)
+	}
+	if paths == nil {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("context directory not found: %w", err)
+		}
+	}
+	sort.Strings(paths)
+
+	artefacts := make([]*blackboard.Artefact, 0, len(paths))
+	for _, path := range paths {
+		artefact, err := LoadArtefact(path)
+		if err != nil {
+			return nil, err
+		}
+		artefacts = append(artefacts, artefact)
+	}
+
+	return artefacts, nil
+}
+
+// OrderFixtures gives fixtures without created_at_ms increasing timestamps in load order
+// (context files first, then the target), so context is chronological as on a real blackboard.
+func OrderFixtures(context []*blackboard.Artefact, target *blackboard.Artefact) {
+	now := time.Now().UnixMilli()
+	all := append(append([]*blackboard.Artefact{}, context...), target)
+	for i, artefact := range all {
+		if artefact.CreatedAtMs == 0 {
+			artefact.CreatedAtMs = now - int64(len(all)-i)
+		}
+	}
+}
+
+// applyDefaults fills fields that hand-written fixtures commonly omit
+func applyDefaults(artefact *blackboard.Artefact) {
+	if artefact.ID == "" {
+		artefact.ID = uuid.New().String()
+	}
+	if artefact.LogicalID == "" {
+		artefact.LogicalID = artefact.ID
+	}
+	if artefact.Version == 0 {
+		artefact.Version = 1
+	}
+	if artefact.StructuralType == "" {
+		artefact.StructuralType = blackboard.StructuralTypeStandard
+	}
+	if artefact.ProducedByRole == "" {
+		artefact.ProducedByRole = "user"
+	}
+	if artefact.SourceArtefacts == nil {
+		artefact.SourceArtefacts = []string{}
+	}
+}
@@ -0,0 +1,59 @@
+package agentrun
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dyluth/holt/internal/pup"
+)
+
+// WriteReport writes a human-readable account of an offline run: optionally the ToolInput,
+// the tool's stderr, and the artefacts that would have been created (as JSON).
+func WriteReport(w io.Writer, result *pup.OfflineResult, showInput bool) {
+	if showInput && result.Input != "" {
+		fmt.Fprintln(w, "Tool input:")
+		fmt.Fprintln(w, indentJSON(result.Input))
+		fmt.Fprintln(w)
+	}
+
+	if stderr := strings.TrimSpace(result.Stderr); stderr != "" {
+		fmt.Fprintln(w, "Tool stderr:")
+		fmt.Fprintln(w, stderr)
+		fmt.Fprintln(w)
+	}
+
+	if result.Output == nil {
+		if stdout := strings.TrimSpace(result.Stdout); stdout != "" {
+			fmt.Fprintln(w, "Tool stdout:")
+			fmt.Fprintln(w, stdout)
+			fmt.Fprintln(w)
+		}
+		return
+	}
+
+	if len(result.Artefacts) == 1 {
+		fmt.Fprintln(w, "Artefact that would be created:")
+	} else {
+		fmt.Fprintf(w, "%d artefacts that would be created:\n", len(result.Artefacts))
+	}
+	for _, artefact := range result.Artefacts {
+		data, err := json.MarshalIndent(artefact, "", "  ")
+		if err != nil {
+			fmt.Fprintf(w, "failed to format artefact %s: %v\n", artefact.ID, err)
+			continue
+		}
+		fmt.Fprintln(w, string(data))
+	}
+}
+
+// indentJSON pretty-prints a JSON document, returning it unchanged if it isn't valid JSON
+func indentJSON(raw string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(raw), "", "  "); err != nil {
+		return raw
+	}
+	return buf.String()
+}
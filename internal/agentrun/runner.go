@@ -0,0 +1,133 @@
+package agentrun
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/dyluth/holt/internal/config"
+	"github.com/dyluth/holt/internal/pup"
+)
+
+// Limits matching the pup's subprocess execution
+const (
+	toolTimeout   = 5 * time.Minute
+	maxOutputSize = 10 * 1024 * 1024
+)
+
+// LocalRunner runs the agent command on the host in workDir, exactly as the pup runs
+// subprocess tools. The command inherits the caller's environment.
+func LocalRunner(workDir string) func(engine *pup.Engine) pup.ToolRunner {
+	return func(engine *pup.Engine) pup.ToolRunner {
+		return engine.SubprocessRunner(workDir)
+	}
+}
+
+// DockerRunner runs the agent command in a throwaway container from the agent's image,
+// with the workspace mounted at /workspace in the agent's workspace mode. The image's
+// entrypoint (normally the pup) is bypassed so only the tool runs.
+func DockerRunner(cli *client.Client, role string, agent config.Agent, workspacePath string) func(engine *pup.Engine) pup.ToolRunner {
+	return func(engine *pup.Engine) pup.ToolRunner {
+		return func(ctx context.Context, inputJSON string) (int, string, string, error) {
+			return runInContainer(ctx, cli, role, agent, workspacePath, inputJSON)
+		}
+	}
+}
+
+// runInContainer creates the container, streams inputJSON to its stdin and collects its output
+func runInContainer(ctx context.Context, cli *client.Client, role string, agent config.Agent, workspacePath, inputJSON string) (int, string, string, error) {
+	if len(agent.Command) == 0 {
+		return -1, "", "", fmt.Errorf("command array is empty")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, toolTimeout)
+	defer cancel()
+
+	workspaceMode := "ro"
+	if agent.Workspace != nil && agent.Workspace.Mode != "" {
+		workspaceMode = agent.Workspace.Mode
+	}
+
+	env := []string{
+		fmt.Sprintf("HOLT_AGENT_NAME=%s", role),
+		fmt.Sprintf("HOLT_WORKSPACE_MODE=%s", workspaceMode),
+	}
+	agentEnv, err := agent.ResolveEnvironment(workspacePath)
+	if err != nil {
+		return -1, "", "", err
+	}
+	env = append(env, agentEnv...)
+
+	binds := []string{fmt.Sprintf("%s:/workspace:%s", workspacePath, workspaceMode)}
+	for _, secret := range agent.Secrets {
+		binds = append(binds, fmt.Sprintf("%s:%s:ro", secret.HostPath(workspacePath), secret.MountPath()))
+	}
+
+	resp, err := cli.ContainerCreate(ctx, &container.Config{
+		Image:        agent.Image,
+		Entrypoint:   agent.Command[:1],
+		Cmd:          agent.Command[1:],
+		Env:          env,
+		WorkingDir:   "/workspace",
+		OpenStdin:    true,
+		StdinOnce:    true,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+	}, &container.HostConfig{
+		Binds: binds,
+	}, nil, nil, "")
+	if err != nil {
+		return -1, "", "", fmt.Errorf("failed to create container: %w", err)
+	}
+	defer cli.ContainerRemove(context.Background(), resp.ID, container.RemoveOptions{Force: true})
+
+	attach, err := cli.ContainerAttach(ctx, resp.ID, container.AttachOptions{
+		Stream: true,
+		Stdin:  true,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		return -1, "", "", fmt.Errorf("failed to attach to container: %w", err)
+	}
+	defer attach.Close()
+
+	if err := cli.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		return -1, "", "", fmt.Errorf("failed to start container: %w", err)
+	}
+
+	go func() {
+		_, _ = io.WriteString(attach.Conn, inputJSON)
+		_ = attach.CloseWrite()
+	}()
+
+	var stdout, stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &stderr, attach.Reader); err != nil && ctx.Err() == nil {
+		return -1, stdout.String(), stderr.String(), fmt.Errorf("failed to read container output: %w", err)
+	}
+
+	if stdout.Len() > maxOutputSize || stderr.Len() > maxOutputSize {
+		return -1, "", "", fmt.Errorf("tool output exceeded 10MB limit")
+	}
+
+	statusCh, errCh := cli.ContainerWait(ctx, resp.ID, container.WaitConditionNotRunning)
+	select {
+	case status := <-statusCh:
+		exitCode := int(status.StatusCode)
+		if exitCode != 0 {
+			return exitCode, stdout.String(), stderr.String(), fmt.Errorf("process exited with code %d", exitCode)
+		}
+		return 0, stdout.String(), stderr.String(), nil
+	case err := <-errCh:
+		if ctx.Err() == context.DeadlineExceeded {
+			return -1, stdout.String(), stderr.String(), fmt.Errorf("tool execution timeout (5 minutes)")
+		}
+		return -1, stdout.String(), stderr.String(), fmt.Errorf("failed waiting for container: %w", err)
+	}
+}
@@ -1,16 +1,50 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"log"
 	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/dyluth/holt/internal/expr"
+	"github.com/dyluth/holt/pkg/blackboard"
 	"gopkg.in/yaml.v3"
 )
 
 // OrchestratorConfig specifies orchestrator behavior holtings (M3.3)
 type OrchestratorConfig struct {
 	MaxReviewIterations *int `yaml:"max_review_iterations,omitempty"` // How many times an artefact can be rejected and reworked (0 = unlimited, default = 3)
+
+	// High availability: replicas elect a leader through a Redis lease; the rest stand by
+	Replicas int    `yaml:"replicas,omitempty"`  // Orchestrator containers to run (default: 1)
+	LeaseTTL string `yaml:"lease_ttl,omitempty"` // Leader lease TTL, bounding failover time (default: 10s)
+}
+
+// DefaultOrchestratorLeaseTTL is the leader lease TTL when lease_ttl is not set
+const DefaultOrchestratorLeaseTTL = 10 * time.Second
+
+// ReplicaCount returns the number of orchestrator containers to run (at least 1)
+func (o *OrchestratorConfig) ReplicaCount() int {
+	if o == nil || o.Replicas < 1 {
+		return 1
+	}
+	return o.Replicas
+}
+
+// LeaseDuration returns the parsed leader lease TTL, or the default if unset or invalid
+func (o *OrchestratorConfig) LeaseDuration() time.Duration {
+	if o == nil || o.LeaseTTL == "" {
+		return DefaultOrchestratorLeaseTTL
+	}
+	d, err := time.ParseDuration(o.LeaseTTL)
+	if err != nil || d <= 0 {
+		return DefaultOrchestratorLeaseTTL
+	}
+	return d
 }
 
 // HoltConfig represents the top-level holt.yml configuration
@@ -19,6 +53,10 @@ type HoltConfig struct {
 	Orchestrator *OrchestratorConfig `yaml:"orchestrator,omitempty"` // M3.3: Orchestrator holtings
 	Agents       map[string]Agent    `yaml:"agents"`
 	Services     *ServicesConfig     `yaml:"services,omitempty"`
+	Workflows    Workflows           `yaml:"workflows,omitempty"` // Declarative stage DAGs enforced on top of bidding
+
+	variables []string // Host variables referenced by interpolation (set by Load)
+	profile   string   // Profile overlay merged over the base file (set by LoadProfile)
 }
 
 // Agent represents a single agent configuration
@@ -33,6 +71,8 @@ type Agent struct {
 	Strategy        string           `yaml:"strategy,omitempty"`
 	BiddingStrategy string           `yaml:"bidding_strategy"` // Required: review, claim, exclusive, or ignore
 	Environment     []string         `yaml:"environment,omitempty"`
+	EnvFile         []string         `yaml:"env_file,omitempty"` // Files of KEY=VALUE lines, relative to holt.yml (environment entries take precedence)
+	Secrets         []Secret         `yaml:"secrets,omitempty"`  // Local files mounted read-only at /run/secrets/<name>
 	Resources       *ResourcesConfig `yaml:"resources,omitempty"`
 	Prompts         *PromptsConfig   `yaml:"prompts,omitempty"`
 
@@ -42,6 +82,28 @@ type Agent struct {
 
 	// M3.9: Configurable health checks
 	HealthCheck *HealthCheckConfig `yaml:"health_check,omitempty"` // Optional: custom health check
+
+	// Concurrent claim execution (traditional agents only)
+	Concurrency int `yaml:"concurrency,omitempty"` // Max parallel executions per pup (0 or omitted: 1)
+
+	// Declarative type matching: bid bidding_strategy on matching artefact types, ignore otherwise
+	Consumes []string `yaml:"consumes,omitempty"` // Artefact type globs (e.g. "GoalDefined", "*Spec")
+	Produces []string `yaml:"produces,omitempty"` // Artefact types this agent emits (pipeline validation only)
+	Terminal []string `yaml:"terminal,omitempty"` // Produced types that end the workflow (exempt from dead-end warnings)
+
+	// Content-based routing: only bid when this expression (CEL subset, see internal/expr) is true
+	// for the target artefact, e.g. `payload.risk > 7`. Variables: artefact, payload, context.
+	When string `yaml:"when,omitempty"`
+
+	// Pup commits workspace changes after the tool exits and uses the commit hash as payload (requires rw workspace)
+	AutoCommit bool `yaml:"auto_commit,omitempty"`
+
+	// Context assembly settings for the tool's context_chain (default: depth 10, Standard/Answer/Review, unlimited size)
+	Context *ContextConfig `yaml:"context,omitempty"`
+
+	// Tool protocol: "subprocess" (default, command run per claim) or "http" (command started once, claims POSTed)
+	Protocol string              `yaml:"protocol,omitempty"`
+	HTTP     *HTTPProtocolConfig `yaml:"http,omitempty"` // Settings for protocol: http
 }
 
 // BuildConfig specifies how to build an agent's container image
@@ -51,7 +113,132 @@ type BuildConfig struct {
 
 // WorkspaceConfig specifies workspace mount configuration
 type WorkspaceConfig struct {
-	Mode string `yaml:"mode"` // "ro" or "rw"
+	Mode      string `yaml:"mode"`                // "ro" or "rw"
+	Isolation string `yaml:"isolation,omitempty"` // "worktree" runs each claim in its own git worktree (requires rw)
+}
+
+// validate checks mode and isolation values for a workspace mount
+func (w *WorkspaceConfig) validate() error {
+	if w.Mode != "" && w.Mode != "ro" && w.Mode != "rw" {
+		return fmt.Errorf("invalid workspace mode: %s (must be 'ro' or 'rw')", w.Mode)
+	}
+	if w.Isolation != "" && w.Isolation != "worktree" {
+		return fmt.Errorf("invalid workspace isolation: %s (must be 'worktree' or omitted)", w.Isolation)
+	}
+	if w.Isolation == "worktree" && w.Mode != "rw" {
+		return fmt.Errorf("workspace isolation 'worktree' requires mode 'rw'")
+	}
+	return nil
+}
+
+// ContextConfig controls how the pup assembles an agent's context_chain.
+// JSON tags are used to pass the settings to the pup via HOLT_AGENT_CONTEXT.
+type ContextConfig struct {
+	MaxDepth        int      `yaml:"max_depth,omitempty" json:"max_depth,omitempty"`               // Ancestor levels to traverse (default: 10)
+	Types           []string `yaml:"types,omitempty" json:"types,omitempty"`                       // Artefact type globs to include (default: all)
+	StructuralTypes []string `yaml:"structural_types,omitempty" json:"structural_types,omitempty"` // Default: Standard, Answer, Review
+	MaxBytes        int      `yaml:"max_bytes,omitempty" json:"max_bytes,omitempty"`               // Total payload budget (0 = unlimited)
+	Overflow        string   `yaml:"overflow,omitempty" json:"overflow,omitempty"`                 // "drop_oldest" (default) or "summarise"
+	IncludeSiblings bool     `yaml:"include_siblings,omitempty" json:"include_siblings,omitempty"` // Also include other branches derived from ancestors
+}
+
+// Context overflow strategies applied when max_bytes is exceeded
+const (
+	ContextOverflowDropOldest = "drop_oldest"
+	ContextOverflowSummarise  = "summarise"
+)
+
+// Validate checks context assembly settings.
+// Exported so the pup can re-check settings received via HOLT_AGENT_CONTEXT.
+func (c *ContextConfig) Validate() error {
+	if c.MaxDepth < 0 {
+		return fmt.Errorf("context.max_depth must not be negative, got %d", c.MaxDepth)
+	}
+	if c.MaxBytes < 0 {
+		return fmt.Errorf("context.max_bytes must not be negative, got %d", c.MaxBytes)
+	}
+	if c.Overflow != "" && c.Overflow != ContextOverflowDropOldest && c.Overflow != ContextOverflowSummarise {
+		return fmt.Errorf("invalid context.overflow: %s (must be '%s' or '%s')", c.Overflow, ContextOverflowDropOldest, ContextOverflowSummarise)
+	}
+	for _, pattern := range c.Types {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return fmt.Errorf("invalid context.types pattern %q", pattern)
+		}
+	}
+	for _, st := range c.StructuralTypes {
+		if err := blackboard.StructuralType(st).Validate(); err != nil {
+			return fmt.Errorf("invalid context.structural_types entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// Agent tool protocols
+const (
+	ProtocolSubprocess = "subprocess"
+	ProtocolHTTP       = "http"
+)
+
+// HTTPProtocolConfig configures a long-running agent reached over HTTP.
+// JSON tags are used to pass the settings to the pup via HOLT_AGENT_HTTP.
+type HTTPProtocolConfig struct {
+	Port           int    `yaml:"port,omitempty" json:"port,omitempty"`                       // Port the agent listens on inside the container (default: 9000)
+	Path           string `yaml:"path,omitempty" json:"path,omitempty"`                       // Endpoint receiving ToolInput (default: /execute)
+	HealthPath     string `yaml:"health_path,omitempty" json:"health_path,omitempty"`         // Readiness endpoint (default: /healthz)
+	StartupTimeout string `yaml:"startup_timeout,omitempty" json:"startup_timeout,omitempty"` // How long to wait for readiness (default: 60s)
+}
+
+// Default values for HTTPProtocolConfig
+const (
+	DefaultHTTPAgentPort           = 9000
+	DefaultHTTPAgentPath           = "/execute"
+	DefaultHTTPAgentHealthPath     = "/healthz"
+	DefaultHTTPAgentStartupTimeout = 60 * time.Second
+)
+
+// Validate checks HTTP protocol settings.
+// Exported so the pup can re-check settings received via HOLT_AGENT_HTTP.
+func (h *HTTPProtocolConfig) Validate() error {
+	if h.Port < 0 || h.Port > 65535 {
+		return fmt.Errorf("http.port must be between 1 and 65535, got %d", h.Port)
+	}
+	if h.Port == 8080 {
+		return fmt.Errorf("http.port 8080 is reserved for the pup health server")
+	}
+	if h.Path != "" && !strings.HasPrefix(h.Path, "/") {
+		return fmt.Errorf("http.path must start with '/', got %q", h.Path)
+	}
+	if h.HealthPath != "" && !strings.HasPrefix(h.HealthPath, "/") {
+		return fmt.Errorf("http.health_path must start with '/', got %q", h.HealthPath)
+	}
+	if h.StartupTimeout != "" {
+		d, err := time.ParseDuration(h.StartupTimeout)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid http.startup_timeout %q (must be a positive duration like '30s')", h.StartupTimeout)
+		}
+	}
+	return nil
+}
+
+// WithDefaults returns a copy with unset fields filled in
+func (h *HTTPProtocolConfig) WithDefaults() HTTPProtocolConfig {
+	result := HTTPProtocolConfig{}
+	if h != nil {
+		result = *h
+	}
+	if result.Port == 0 {
+		result.Port = DefaultHTTPAgentPort
+	}
+	if result.Path == "" {
+		result.Path = DefaultHTTPAgentPath
+	}
+	if result.HealthPath == "" {
+		result.HealthPath = DefaultHTTPAgentHealthPath
+	}
+	if result.StartupTimeout == "" {
+		result.StartupTimeout = DefaultHTTPAgentStartupTimeout.String()
+	}
+	return result
 }
 
 // WorkerConfig specifies worker configuration for controller-worker pattern (M3.4)
@@ -87,6 +274,30 @@ type HealthCheckConfig struct {
 	Timeout  string   `yaml:"timeout,omitempty"`  // Command timeout (default: 5s)
 }
 
+// Defaults applied by the pup when interval or timeout is omitted
+const (
+	defaultHealthCheckInterval = 30 * time.Second
+	defaultHealthCheckTimeout  = 5 * time.Second
+)
+
+// Validate checks the health check has a command and positive durations
+func (h *HealthCheckConfig) Validate() error {
+	if len(h.Command) == 0 {
+		return fmt.Errorf("health_check.command is required")
+	}
+	if h.Interval != "" {
+		if d, err := time.ParseDuration(h.Interval); err != nil || d <= 0 {
+			return fmt.Errorf("invalid health_check.interval %q (must be a positive duration like '30s')", h.Interval)
+		}
+	}
+	if h.Timeout != "" {
+		if d, err := time.ParseDuration(h.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("invalid health_check.timeout %q (must be a positive duration like '5s')", h.Timeout)
+		}
+	}
+	return nil
+}
+
 // ServicesConfig specifies service-level overrides
 type ServicesConfig struct {
 	Orchestrator *ServiceOverride `yaml:"orchestrator,omitempty"`
@@ -139,6 +350,22 @@ func (c *HoltConfig) Validate() error {
 	if *c.Orchestrator.MaxReviewIterations < 0 {
 		return fmt.Errorf("orchestrator.max_review_iterations must be >= 0 (0 = unlimited), got %d", *c.Orchestrator.MaxReviewIterations)
 	}
+	if c.Orchestrator.Replicas < 0 {
+		return fmt.Errorf("orchestrator.replicas must be >= 1, got %d", c.Orchestrator.Replicas)
+	}
+	if c.Orchestrator.LeaseTTL != "" {
+		d, err := time.ParseDuration(c.Orchestrator.LeaseTTL)
+		if err != nil || d < time.Second {
+			return fmt.Errorf("invalid orchestrator.lease_ttl %q (must be a duration of at least 1s, like '10s')", c.Orchestrator.LeaseTTL)
+		}
+	}
+
+	for _, name := range sortedWorkflowNames(c.Workflows) {
+		workflow := c.Workflows[name]
+		if err := workflow.validate(name, c.Agents); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -198,6 +425,31 @@ func (a *Agent) Validate(name string) error {
 		}
 	}
 
+	// Validate declarative type matching
+	for _, pattern := range a.Consumes {
+		if pattern == "" {
+			return fmt.Errorf("agent '%s': consumes entries cannot be empty", name)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("agent '%s': invalid consumes pattern '%s': %w", name, pattern, err)
+		}
+	}
+	if a.When != "" {
+		if _, err := expr.Compile(a.When, WhenVariables...); err != nil {
+			return fmt.Errorf("agent '%s': invalid when expression: %w", name, err)
+		}
+	}
+	for _, artefactType := range a.Produces {
+		if artefactType == "" {
+			return fmt.Errorf("agent '%s': produces entries cannot be empty", name)
+		}
+	}
+	for _, artefactType := range a.Terminal {
+		if !containsString(a.Produces, artefactType) {
+			return fmt.Errorf("agent '%s': terminal type '%s' must also be listed in produces", name, artefactType)
+		}
+	}
+
 	// If build.context specified AND no pre-built image is listed, verify path exists
 	// If a.Image is set, the build context is optional (pre-built image is used)
 	if a.Image == "" && a.Build != nil && a.Build.Context != "" {
@@ -206,10 +458,10 @@ func (a *Agent) Validate(name string) error {
 		}
 	}
 
-	// Validate workspace mode if specified
+	// Validate workspace mode and isolation if specified
 	if a.Workspace != nil {
-		if a.Workspace.Mode != "" && a.Workspace.Mode != "ro" && a.Workspace.Mode != "rw" {
-			return fmt.Errorf("agent '%s': invalid workspace mode: %s (must be 'ro' or 'rw')", name, a.Workspace.Mode)
+		if err := a.Workspace.validate(); err != nil {
+			return fmt.Errorf("agent '%s': %w", name, err)
 		}
 	}
 
@@ -218,6 +470,75 @@ func (a *Agent) Validate(name string) error {
 		return fmt.Errorf("agent '%s': invalid strategy: %s (must be 'reuse' or 'fresh_per_call')", name, a.Strategy)
 	}
 
+	// Validate concurrency if specified (0 is the same as omitting it)
+	if a.Concurrency < 0 {
+		return fmt.Errorf("agent '%s': concurrency must be >= 0 (0 = default of 1), got %d", name, a.Concurrency)
+	}
+	if a.Concurrency > 1 && a.Mode == "controller" {
+		return fmt.Errorf("agent '%s': concurrency is not supported for controllers (use worker.max_concurrent)", name)
+	}
+
+	// Validate secrets have a file and unique mount names
+	secretNames := make(map[string]bool, len(a.Secrets))
+	for _, secret := range a.Secrets {
+		if err := secret.validate(); err != nil {
+			return fmt.Errorf("agent '%s': %w", name, err)
+		}
+		if secretNames[secret.MountName()] {
+			return fmt.Errorf("agent '%s': duplicate secret name '%s'", name, secret.MountName())
+		}
+		secretNames[secret.MountName()] = true
+	}
+	for _, file := range a.EnvFile {
+		if file == "" {
+			return fmt.Errorf("agent '%s': env_file entries cannot be empty", name)
+		}
+	}
+
+	// Validate custom health check
+	if a.HealthCheck != nil {
+		if err := a.HealthCheck.Validate(); err != nil {
+			return fmt.Errorf("agent '%s': %w", name, err)
+		}
+	}
+
+	// Validate context assembly settings
+	if a.Context != nil {
+		if err := a.Context.Validate(); err != nil {
+			return fmt.Errorf("agent '%s': %w", name, err)
+		}
+	}
+
+	// Validate tool protocol
+	switch a.Protocol {
+	case "", ProtocolSubprocess:
+		if a.HTTP != nil {
+			return fmt.Errorf("agent '%s': http settings require protocol: http", name)
+		}
+	case ProtocolHTTP:
+		if a.Mode == "controller" {
+			return fmt.Errorf("agent '%s': protocol 'http' is not supported for controllers (workers run one claim per container)", name)
+		}
+		if a.HTTP != nil {
+			if err := a.HTTP.Validate(); err != nil {
+				return fmt.Errorf("agent '%s': %w", name, err)
+			}
+		}
+	default:
+		return fmt.Errorf("agent '%s': invalid protocol: %s (must be '%s' or '%s')", name, a.Protocol, ProtocolSubprocess, ProtocolHTTP)
+	}
+
+	// Validate auto_commit has a writable workspace to commit in
+	if a.AutoCommit {
+		workspace := a.Workspace
+		if a.Mode == "controller" && a.Worker != nil {
+			workspace = a.Worker.Workspace
+		}
+		if workspace == nil || workspace.Mode != "rw" {
+			return fmt.Errorf("agent '%s': auto_commit requires workspace mode 'rw'", name)
+		}
+	}
+
 	// M3.4: Validate controller-worker configuration
 	if a.Mode == "controller" {
 		// Validate worker config exists
@@ -245,10 +566,10 @@ func (a *Agent) Validate(name string) error {
 			return fmt.Errorf("agent '%s' worker.max_concurrent must be >= 1", name)
 		}
 
-		// Validate worker workspace mode if specified
-		if a.Worker.Workspace != nil && a.Worker.Workspace.Mode != "" {
-			if a.Worker.Workspace.Mode != "ro" && a.Worker.Workspace.Mode != "rw" {
-				return fmt.Errorf("agent '%s' worker: invalid workspace mode: %s (must be 'ro' or 'rw')", name, a.Worker.Workspace.Mode)
+		// Validate worker workspace mode and isolation if specified
+		if a.Worker.Workspace != nil {
+			if err := a.Worker.Workspace.validate(); err != nil {
+				return fmt.Errorf("agent '%s' worker: %w", name, err)
 			}
 		}
 	} else if a.Mode != "" {
@@ -259,18 +580,150 @@ func (a *Agent) Validate(name string) error {
 	return nil
 }
 
-// Load reads and validates holt.yml from the specified path
+// MatchArtefactType reports whether an artefact type matches any of the given
+// consumes patterns. Patterns use path.Match glob syntax ("*Spec", "Code?ommit").
+func MatchArtefactType(patterns []string, artefactType string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, artefactType); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// PipelineWarnings checks declared produces/consumes types for dead ends.
+// A produced type is a dead end when no agent can consume it and the producing
+// agent has not declared it terminal. Agents without consumes that use a bid_script
+// or a non-ignore bidding_strategy may consume anything, so they count as consumers
+// of every type. Returns human-readable warnings sorted for stable output.
+func (c *HoltConfig) PipelineWarnings() []string {
+	var warnings []string
+
+	for producerRole, producer := range c.Agents {
+		for _, artefactType := range producer.Produces {
+			if containsString(producer.Terminal, artefactType) {
+				continue
+			}
+			if !c.hasConsumer(artefactType) {
+				warnings = append(warnings, fmt.Sprintf(
+					"agent '%s' produces '%s' but no agent consumes it (dead-end pipeline; list it under terminal if this is intended)",
+					producerRole, artefactType))
+			}
+		}
+	}
+
+	sort.Strings(warnings)
+	return warnings
+}
+
+// hasConsumer reports whether any agent could bid on the given artefact type.
+func (c *HoltConfig) hasConsumer(artefactType string) bool {
+	for _, agent := range c.Agents {
+		if len(agent.Consumes) > 0 {
+			if MatchArtefactType(agent.Consumes, artefactType) {
+				return true
+			}
+			continue
+		}
+		// No declared consumes: bid scripts and non-ignore strategies may bid on anything
+		if len(agent.BidScript) > 0 || (agent.BiddingStrategy != "" && agent.BiddingStrategy != "ignore") {
+			return true
+		}
+	}
+	return false
+}
+
+// WhenVariables are the variables available to an agent's when expression:
+// artefact (the target artefact's fields, e.g. artefact.type), payload (the target's
+// payload decoded as JSON, or the raw string if it is not JSON) and context (the target's
+// context chain, oldest first, as a list of artefacts).
+var WhenVariables = []string{"artefact", "payload", "context"}
+
+// containsString reports whether s is present in values.
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Marshal encodes the configuration as YAML that Decode reads back
+func (c *HoltConfig) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c); err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode parses and validates an effective configuration, as printed by holt
+// config show and stored for a running instance. Values are taken literally:
+// there is no ${VAR} interpolation or profile overlay.
+func Decode(data []byte) (*HoltConfig, error) {
+	var config HoltConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return &config, nil
+}
+
+// Load reads and validates holt.yml from the specified path, expanding
+// ${VAR}, ${VAR:-default} and ${VAR:?message} references in values from the
+// host environment
 func Load(path string) (*HoltConfig, error) {
+	return LoadProfile(path, "")
+}
+
+// LoadProfile is Load with the holt.<profile>.yml overlay next to path merged
+// over the base file before interpolation. Mappings (including the agents map)
+// merge key by key, agent environment lists merge by variable name, and any
+// other value in the overlay replaces the base value. An overlay value tagged
+// !reset deletes the key; !override replaces a mapping instead of merging it.
+// An empty profile loads the base file alone.
+func LoadProfile(path, profile string) (*HoltConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
-	var config HoltConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML: %w", err)
 	}
 
+	if profile != "" {
+		if err := loadProfileOverlay(&doc, path, profile); err != nil {
+			return nil, err
+		}
+	}
+
+	// Expand ${VAR} references from the host environment before decoding
+	variables, err := interpolateDocument(&doc, os.LookupEnv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to interpolate config: %w", err)
+	}
+
+	var config HoltConfig
+	if len(doc.Content) > 0 {
+		if err := doc.Decode(&config); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+	}
+	config.variables = variables
+	config.profile = profile
+
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
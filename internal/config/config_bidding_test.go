@@ -123,3 +123,165 @@ func TestAgent_Validate_BiddingStrategy(t *testing.T) {
 		})
 	}
 }
+
+// TestAgent_Validate_ConsumesProduces tests declarative type matching validation
+func TestAgent_Validate_ConsumesProduces(t *testing.T) {
+	tests := []struct {
+		name          string
+		agent         Agent
+		expectError   bool
+		errorContains string
+	}{
+		{
+			name: "valid consumes globs",
+			agent: Agent{
+				Image:           "test:latest",
+				Command:         []string{"/app/run.sh"},
+				BiddingStrategy: "exclusive",
+				Consumes:        []string{"GoalDefined", "*Spec"},
+				Produces:        []string{"CodeCommit"},
+			},
+			expectError: false,
+		},
+		{
+			name: "malformed consumes glob",
+			agent: Agent{
+				Image:           "test:latest",
+				Command:         []string{"/app/run.sh"},
+				BiddingStrategy: "exclusive",
+				Consumes:        []string{"Design["},
+			},
+			expectError:   true,
+			errorContains: "invalid consumes pattern",
+		},
+		{
+			name: "empty consumes entry",
+			agent: Agent{
+				Image:           "test:latest",
+				Command:         []string{"/app/run.sh"},
+				BiddingStrategy: "exclusive",
+				Consumes:        []string{""},
+			},
+			expectError:   true,
+			errorContains: "consumes entries cannot be empty",
+		},
+		{
+			name: "valid when expression",
+			agent: Agent{
+				Image:           "test:latest",
+				Command:         []string{"/app/run.sh"},
+				BiddingStrategy: "review",
+				When:            `has(payload.risk) && payload.risk > 7`,
+			},
+			expectError: false,
+		},
+		{
+			name: "malformed when expression",
+			agent: Agent{
+				Image:           "test:latest",
+				Command:         []string{"/app/run.sh"},
+				BiddingStrategy: "review",
+				When:            "payload.risk >",
+			},
+			expectError:   true,
+			errorContains: "invalid when expression",
+		},
+		{
+			name: "when expression with unknown variable",
+			agent: Agent{
+				Image:           "test:latest",
+				Command:         []string{"/app/run.sh"},
+				BiddingStrategy: "review",
+				When:            "paylod.risk > 7",
+			},
+			expectError:   true,
+			errorContains: "undeclared reference to 'paylod'",
+		},
+		{
+			name: "terminal type not in produces",
+			agent: Agent{
+				Image:           "test:latest",
+				Command:         []string{"/app/run.sh"},
+				BiddingStrategy: "exclusive",
+				Produces:        []string{"CodeCommit"},
+				Terminal:        []string{"PackagedModule"},
+			},
+			expectError:   true,
+			errorContains: "must also be listed in produces",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.agent.Validate("test-agent")
+
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+
+			if tt.expectError && err != nil && tt.errorContains != "" {
+				if !strings.Contains(err.Error(), tt.errorContains) {
+					t.Errorf("Expected error containing '%s', got: %v", tt.errorContains, err)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchArtefactType(t *testing.T) {
+	patterns := []string{"GoalDefined", "*Spec"}
+
+	if !MatchArtefactType(patterns, "GoalDefined") {
+		t.Errorf("Expected exact match for GoalDefined")
+	}
+	if !MatchArtefactType(patterns, "DesignSpec") {
+		t.Errorf("Expected glob match for DesignSpec")
+	}
+	if MatchArtefactType(patterns, "CodeCommit") {
+		t.Errorf("Expected no match for CodeCommit")
+	}
+	if MatchArtefactType(nil, "GoalDefined") {
+		t.Errorf("Expected no match for empty pattern list")
+	}
+}
+
+func TestHoltConfig_PipelineWarnings(t *testing.T) {
+	cfg := &HoltConfig{
+		Agents: map[string]Agent{
+			"Designer": {
+				BiddingStrategy: "exclusive",
+				Consumes:        []string{"GoalDefined"},
+				Produces:        []string{"DesignSpec"},
+			},
+			"Coder": {
+				BiddingStrategy: "exclusive",
+				Consumes:        []string{"*Spec"},
+				Produces:        []string{"CodeCommit", "Notes"},
+			},
+			"Packager": {
+				BiddingStrategy: "exclusive",
+				Consumes:        []string{"CodeCommit"},
+				Produces:        []string{"PackagedModule"},
+				Terminal:        []string{"PackagedModule"},
+			},
+		},
+	}
+
+	warnings := cfg.PipelineWarnings()
+	if len(warnings) != 1 {
+		t.Fatalf("Expected 1 warning, got %d: %v", len(warnings), warnings)
+	}
+	if !strings.Contains(warnings[0], "'Coder' produces 'Notes'") {
+		t.Errorf("Expected dead-end warning for Notes, got: %s", warnings[0])
+	}
+
+	// An agent with a bid script and no consumes may consume anything
+	cfg.Agents["Scripted"] = Agent{BidScript: []string{"/app/bid.sh"}}
+	if warnings := cfg.PipelineWarnings(); len(warnings) != 0 {
+		t.Errorf("Expected no warnings with a catch-all bid script agent, got: %v", warnings)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -212,6 +213,182 @@ func TestAgentValidate_ValidWorkspaceModes(t *testing.T) {
 	}
 }
 
+func TestAgentValidate_WorkspaceIsolation(t *testing.T) {
+	tests := []struct {
+		name        string
+		workspace   *WorkspaceConfig
+		expectError string
+	}{
+		{"worktree with rw", &WorkspaceConfig{Mode: "rw", Isolation: "worktree"}, ""},
+		{"worktree with ro", &WorkspaceConfig{Mode: "ro", Isolation: "worktree"}, "requires mode 'rw'"},
+		{"worktree without mode", &WorkspaceConfig{Isolation: "worktree"}, "requires mode 'rw'"},
+		{"unknown isolation", &WorkspaceConfig{Mode: "rw", Isolation: "container"}, "invalid workspace isolation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := Agent{
+				Image:           "test-agent:latest",
+				Command:         []string{"./run.sh"},
+				BiddingStrategy: "exclusive",
+				Workspace:       tt.workspace,
+			}
+
+			err := agent.Validate("test-agent")
+			if tt.expectError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+			}
+		})
+	}
+}
+
+func TestAgentValidate_Protocol(t *testing.T) {
+	tests := []struct {
+		name        string
+		agent       Agent
+		expectError string
+	}{
+		{name: "default subprocess", agent: Agent{}},
+		{name: "explicit subprocess", agent: Agent{Protocol: "subprocess"}},
+		{name: "http with defaults", agent: Agent{Protocol: "http"}},
+		{
+			name:  "http with settings",
+			agent: Agent{Protocol: "http", HTTP: &HTTPProtocolConfig{Port: 9100, Path: "/run", HealthPath: "/ready", StartupTimeout: "2m"}},
+		},
+		{name: "unknown protocol", agent: Agent{Protocol: "grpc"}, expectError: "invalid protocol"},
+		{name: "http settings without http protocol", agent: Agent{HTTP: &HTTPProtocolConfig{Port: 9100}}, expectError: "require protocol: http"},
+		{name: "reserved port", agent: Agent{Protocol: "http", HTTP: &HTTPProtocolConfig{Port: 8080}}, expectError: "reserved"},
+		{name: "port out of range", agent: Agent{Protocol: "http", HTTP: &HTTPProtocolConfig{Port: 70000}}, expectError: "http.port"},
+		{name: "relative path", agent: Agent{Protocol: "http", HTTP: &HTTPProtocolConfig{Path: "execute"}}, expectError: "http.path"},
+		{name: "relative health path", agent: Agent{Protocol: "http", HTTP: &HTTPProtocolConfig{HealthPath: "healthz"}}, expectError: "http.health_path"},
+		{name: "bad startup timeout", agent: Agent{Protocol: "http", HTTP: &HTTPProtocolConfig{StartupTimeout: "soon"}}, expectError: "startup_timeout"},
+		{
+			name: "http controller",
+			agent: Agent{
+				Protocol: "http",
+				Mode:     "controller",
+				Worker:   &WorkerConfig{Image: "worker:latest", Command: []string{"./worker.sh"}},
+			},
+			expectError: "not supported for controllers",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := tt.agent
+			agent.Image = "coder:latest"
+			agent.Command = []string{"./run.sh"}
+			agent.BiddingStrategy = "exclusive"
+
+			err := agent.Validate("coder")
+			if tt.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHTTPProtocolConfig_WithDefaults(t *testing.T) {
+	var unset *HTTPProtocolConfig
+	assert.Equal(t, HTTPProtocolConfig{Port: 9000, Path: "/execute", HealthPath: "/healthz", StartupTimeout: "1m0s"}, unset.WithDefaults())
+
+	custom := &HTTPProtocolConfig{Port: 9100, Path: "/run"}
+	assert.Equal(t, HTTPProtocolConfig{Port: 9100, Path: "/run", HealthPath: "/healthz", StartupTimeout: "1m0s"}, custom.WithDefaults())
+}
+
+func TestAgentValidate_AutoCommit(t *testing.T) {
+	tests := []struct {
+		name        string
+		agent       Agent
+		expectError bool
+	}{
+		{
+			name:  "rw workspace",
+			agent: Agent{Workspace: &WorkspaceConfig{Mode: "rw"}},
+		},
+		{
+			name:        "ro workspace",
+			agent:       Agent{Workspace: &WorkspaceConfig{Mode: "ro"}},
+			expectError: true,
+		},
+		{
+			name:        "no workspace",
+			agent:       Agent{},
+			expectError: true,
+		},
+		{
+			name: "controller with rw worker workspace",
+			agent: Agent{
+				Mode: "controller",
+				Worker: &WorkerConfig{
+					Image:     "worker:latest",
+					Command:   []string{"./worker.sh"},
+					Workspace: &WorkspaceConfig{Mode: "rw"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := tt.agent
+			agent.Image = "coder:latest"
+			agent.Command = []string{"./run.sh"}
+			agent.BiddingStrategy = "exclusive"
+			agent.AutoCommit = true
+
+			err := agent.Validate("coder")
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "auto_commit requires workspace mode 'rw'")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAgentValidate_Context(t *testing.T) {
+	tests := []struct {
+		name        string
+		context     *ContextConfig
+		expectError string
+	}{
+		{"full settings", &ContextConfig{MaxDepth: 3, Types: []string{"*Spec"}, StructuralTypes: []string{"Standard"}, MaxBytes: 4096, Overflow: "summarise", IncludeSiblings: true}, ""},
+		{"empty settings", &ContextConfig{}, ""},
+		{"negative depth", &ContextConfig{MaxDepth: -1}, "context.max_depth must not be negative"},
+		{"negative bytes", &ContextConfig{MaxBytes: -1}, "context.max_bytes must not be negative"},
+		{"unknown overflow", &ContextConfig{Overflow: "truncate"}, "invalid context.overflow"},
+		{"malformed type glob", &ContextConfig{Types: []string{"[Spec"}}, "invalid context.types pattern"},
+		{"unknown structural type", &ContextConfig{StructuralTypes: []string{"Bogus"}}, "invalid context.structural_types entry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := Agent{
+				Image:           "test-agent:latest",
+				Command:         []string{"./run.sh"},
+				BiddingStrategy: "exclusive",
+				Context:         tt.context,
+			}
+
+			err := agent.Validate("test-agent")
+			if tt.expectError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+			}
+		})
+	}
+}
+
 func TestAgentValidate_InvalidStrategy(t *testing.T) {
 	agent := Agent{
 		Image:           "test-agent:latest",
@@ -528,6 +705,55 @@ func TestValidate_OrchestratorConfig_NegativeValue(t *testing.T) {
 	assert.Contains(t, err.Error(), "-1")
 }
 
+func TestValidate_OrchestratorConfig_HighAvailability(t *testing.T) {
+	tests := []struct {
+		name     string
+		replicas int
+		leaseTTL string
+		wantErr  string
+	}{
+		{"defaults", 0, "", ""},
+		{"three replicas", 3, "5s", ""},
+		{"negative replicas", -1, "", "orchestrator.replicas must be >= 1"},
+		{"unparseable lease", 2, "soon", "invalid orchestrator.lease_ttl"},
+		{"lease too short", 2, "500ms", "invalid orchestrator.lease_ttl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &HoltConfig{
+				Version:      "1.0",
+				Orchestrator: &OrchestratorConfig{Replicas: tt.replicas, LeaseTTL: tt.leaseTTL},
+				Agents: map[string]Agent{
+					"test": {
+						Image:           "test:latest",
+						Command:         []string{"test"},
+						BiddingStrategy: "exclusive",
+					},
+				},
+			}
+
+			err := config.Validate()
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestOrchestratorConfig_Defaults(t *testing.T) {
+	var missing *OrchestratorConfig
+	assert.Equal(t, 1, missing.ReplicaCount())
+	assert.Equal(t, DefaultOrchestratorLeaseTTL, missing.LeaseDuration())
+
+	configured := &OrchestratorConfig{Replicas: 3, LeaseTTL: "4s"}
+	assert.Equal(t, 3, configured.ReplicaCount())
+	assert.Equal(t, 4*time.Second, configured.LeaseDuration())
+}
+
 func TestLoad_WithOrchestratorConfig(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "holt.yml")
@@ -857,3 +1083,37 @@ agents:
 	assert.Equal(t, "", reviewer.Mode)
 	assert.Nil(t, reviewer.Worker)
 }
+
+func TestAgentValidate_Concurrency(t *testing.T) {
+	agent := Agent{
+		Image:           "coder:latest",
+		Command:         []string{"./run.sh"},
+		BiddingStrategy: "exclusive",
+		Concurrency:     4,
+	}
+	assert.NoError(t, agent.Validate("coder"))
+
+	// 0 is the documented default, the same as omitting the field
+	agent.Concurrency = 0
+	assert.NoError(t, agent.Validate("coder"))
+
+	agent.Concurrency = -1
+	err := agent.Validate("coder")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "concurrency must be >= 0 (0 = default of 1), got -1")
+
+	controller := Agent{
+		Image:           "coder:latest",
+		Command:         []string{"./run.sh"},
+		BiddingStrategy: "exclusive",
+		Mode:            "controller",
+		Concurrency:     2,
+		Worker: &WorkerConfig{
+			Image:   "coder-worker:latest",
+			Command: []string{"./run.sh"},
+		},
+	}
+	err = controller.Validate("coder")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not supported for controllers")
+}
@@ -0,0 +1,286 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SecretsDir is where secrets are mounted inside agent and worker containers
+const SecretsDir = "/run/secrets"
+
+// Secret is a local file mounted read-only into an agent's containers at
+// /run/secrets/<name>. Secrets are never passed as environment variables, and
+// the pup redacts their values from its logs and Failure payloads.
+type Secret struct {
+	Name string `yaml:"name,omitempty" json:"name,omitempty"` // File name under /run/secrets (default: base name of file)
+	File string `yaml:"file" json:"file"`                     // Host path, relative to holt.yml
+}
+
+// MountName returns the secret's file name under SecretsDir
+func (s Secret) MountName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return filepath.Base(s.File)
+}
+
+// MountPath returns where the secret is mounted inside containers
+func (s Secret) MountPath() string {
+	return path.Join(SecretsDir, s.MountName())
+}
+
+// HostPath resolves the secret file against baseDir, the directory holding holt.yml
+func (s Secret) HostPath(baseDir string) string {
+	return resolvePath(baseDir, s.File)
+}
+
+func (s Secret) validate() error {
+	if s.File == "" {
+		return fmt.Errorf("secrets: file is required")
+	}
+	name := s.MountName()
+	if name == "." || name == ".." || name == "/" || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("secrets: invalid name '%s' (must be a plain file name)", name)
+	}
+	return nil
+}
+
+// ResolveEnvironment returns the agent's container environment: the entries
+// of each env_file in order, then the environment list, so explicit entries
+// take precedence. Relative env_file paths are resolved against baseDir, the
+// directory holding holt.yml.
+func (a *Agent) ResolveEnvironment(baseDir string) ([]string, error) {
+	var env []string
+	for _, file := range a.EnvFile {
+		entries, err := ReadEnvFile(resolvePath(baseDir, file))
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, entries...)
+	}
+	return append(env, a.Environment...), nil
+}
+
+// ReadEnvFile parses a file of KEY=VALUE lines into environment entries.
+// Blank lines, # comments and a leading "export " are ignored; values may be
+// single- or double-quoted. Values are taken literally, without interpolation.
+func ReadEnvFile(filename string) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env_file: %w", err)
+	}
+	defer f.Close()
+
+	var env []string
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !isVariableName(key) {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", filename, lineNo)
+		}
+		env = append(env, key+"="+envFileValue(strings.TrimSpace(value)))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read env_file %s: %w", filename, err)
+	}
+	return env, nil
+}
+
+// envFileValue strips matching quotes, or a trailing " # comment" from an unquoted value
+func envFileValue(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		return strings.TrimSpace(value[:i])
+	}
+	return value
+}
+
+// Variables returns the host environment variables referenced by ${...}
+// interpolation when the config was loaded, sorted by name
+func (c *HoltConfig) Variables() []string {
+	return c.variables
+}
+
+// interpolation is the result of expanding one string
+type interpolation struct {
+	value      string
+	referenced []string // Every variable name referenced
+	unset      []string // Variables referenced without a default that are not set
+}
+
+// interpolate expands ${VAR}, ${VAR:-default} (used when VAR is unset or
+// empty) and ${VAR:?message} (an error when VAR is unset or empty) in s.
+// $${ escapes a literal ${; other $ characters are left alone so shell
+// commands keep working.
+func interpolate(s string, lookup func(string) (string, bool)) (interpolation, error) {
+	var result interpolation
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			break
+		}
+		if i > 0 && s[i-1] == '$' {
+			b.WriteString(s[:i-1] + "${")
+			s = s[i+2:]
+			continue
+		}
+		b.WriteString(s[:i])
+
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return result, fmt.Errorf("unterminated variable reference in %q", s[i:])
+		}
+		expr := s[i+2 : i+end]
+		s = s[i+end+1:]
+
+		name, rest := expr, ""
+		if j := strings.IndexByte(expr, ':'); j >= 0 {
+			name, rest = expr[:j], expr[j:]
+		}
+		if !isVariableName(name) {
+			return result, fmt.Errorf("invalid variable reference ${%s}", expr)
+		}
+		result.referenced = append(result.referenced, name)
+
+		value, set := lookup(name)
+		switch {
+		case rest == "":
+			if !set {
+				result.unset = append(result.unset, name)
+			}
+		case strings.HasPrefix(rest, ":-"):
+			if value == "" {
+				value = rest[2:]
+			}
+		case strings.HasPrefix(rest, ":?"):
+			if value == "" {
+				message := rest[2:]
+				if message == "" {
+					message = "is required"
+				}
+				return result, fmt.Errorf("variable %s %s", name, message)
+			}
+		default:
+			return result, fmt.Errorf("invalid variable reference ${%s} (use ${%s}, ${%s:-default} or ${%s:?message})", expr, name, name, name)
+		}
+		b.WriteString(value)
+	}
+	result.value = b.String()
+	return result, nil
+}
+
+// interpolationIssue is a problem expanding one scalar: an error, or a
+// variable referenced without a default that is not set
+type interpolationIssue struct {
+	node  *yaml.Node
+	err   error
+	unset string
+}
+
+// interpolateNode expands variable references in every scalar value under
+// node in place, returning the names referenced and any problems. Mapping keys
+// are never expanded.
+func interpolateNode(node *yaml.Node, lookup func(string) (string, bool)) ([]string, []interpolationIssue) {
+	var referenced []string
+	var issues []interpolationIssue
+
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		switch n.Kind {
+		case yaml.DocumentNode, yaml.SequenceNode:
+			for _, child := range n.Content {
+				walk(child)
+			}
+		case yaml.MappingNode:
+			for i := 1; i < len(n.Content); i += 2 {
+				walk(n.Content[i])
+			}
+		case yaml.ScalarNode:
+			if !strings.Contains(n.Value, "${") {
+				return
+			}
+			result, err := interpolate(n.Value, lookup)
+			if err != nil {
+				issues = append(issues, interpolationIssue{node: n, err: err})
+				return
+			}
+			referenced = append(referenced, result.referenced...)
+			for _, name := range result.unset {
+				issues = append(issues, interpolationIssue{node: n, unset: name})
+			}
+			n.Value = result.value
+			if n.Style == 0 {
+				// Re-resolve plain scalars so "${PORT:-9000}" decodes as an int
+				n.Tag = ""
+			}
+		}
+	}
+	walk(node)
+
+	return uniqueSorted(referenced), issues
+}
+
+// interpolateDocument expands variable references for Load, logging a
+// warning for each unset variable (which expands to an empty value)
+func interpolateDocument(doc *yaml.Node, lookup func(string) (string, bool)) ([]string, error) {
+	referenced, issues := interpolateNode(doc, lookup)
+	for _, issue := range issues {
+		if issue.err != nil {
+			return nil, fmt.Errorf("line %d: %w", issue.node.Line, issue.err)
+		}
+		log.Printf("[Config] Warning: line %d: variable %s is not set, using an empty value", issue.node.Line, issue.unset)
+	}
+	return referenced, nil
+}
+
+func isVariableName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, ch := range name {
+		if !(ch == '_' || (ch >= 'A' && ch <= 'Z') || (ch >= 'a' && ch <= 'z') || (i > 0 && ch >= '0' && ch <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
+func resolvePath(baseDir, p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(baseDir, p)
+}
+
+func uniqueSorted(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	sort.Strings(values)
+	unique := values[:1]
+	for _, v := range values[1:] {
+		if v != unique[len(unique)-1] {
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lookupFrom(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	lookup := lookupFrom(map[string]string{"REGISTRY": "ghcr.io/acme", "EMPTY": ""})
+
+	tests := []struct {
+		name       string
+		input      string
+		want       string
+		unset      []string
+		referenced []string
+	}{
+		{name: "no references", input: "plain value", want: "plain value"},
+		{name: "braced variable", input: "${REGISTRY}/coder:latest", want: "ghcr.io/acme/coder:latest", referenced: []string{"REGISTRY"}},
+		{name: "default when unset", input: "${MODEL:-small}", want: "small", referenced: []string{"MODEL"}},
+		{name: "default when empty", input: "${EMPTY:-fallback}", want: "fallback", referenced: []string{"EMPTY"}},
+		{name: "default ignored when set", input: "${REGISTRY:-docker.io}", want: "ghcr.io/acme", referenced: []string{"REGISTRY"}},
+		{name: "unset without default", input: "key=${MISSING}", want: "key=", unset: []string{"MISSING"}, referenced: []string{"MISSING"}},
+		{name: "escaped reference", input: "echo $${HOME}", want: "echo ${HOME}"},
+		{name: "bare dollar untouched", input: "echo $HOME $1", want: "echo $HOME $1"},
+		{name: "several references", input: "${REGISTRY}/${NAME:-coder}", want: "ghcr.io/acme/coder", referenced: []string{"REGISTRY", "NAME"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := interpolate(tt.input, lookup)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.value)
+			assert.Equal(t, tt.unset, result.unset)
+			assert.Equal(t, tt.referenced, result.referenced)
+		})
+	}
+}
+
+func TestInterpolate_Errors(t *testing.T) {
+	lookup := lookupFrom(map[string]string{"EMPTY": ""})
+
+	tests := []struct {
+		input         string
+		errorContains string
+	}{
+		{input: "${API_KEY:?must be set for CI}", errorContains: "variable API_KEY must be set for CI"},
+		{input: "${EMPTY:?}", errorContains: "variable EMPTY is required"},
+		{input: "${UNCLOSED", errorContains: "unterminated variable reference"},
+		{input: "${1BAD}", errorContains: "invalid variable reference ${1BAD}"},
+		{input: "${VAR-default}", errorContains: "invalid variable reference ${VAR-default}"},
+		{input: "${VAR:+alt}", errorContains: "use ${VAR}, ${VAR:-default} or ${VAR:?message}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := interpolate(tt.input, lookup)
+			assert.ErrorContains(t, err, tt.errorContains)
+		})
+	}
+}
+
+func TestLoad_Interpolation(t *testing.T) {
+	t.Setenv("HOLT_TEST_REGISTRY", "ghcr.io/acme")
+	t.Setenv("HOLT_TEST_KEY", "sk-123")
+
+	path := filepath.Join(t.TempDir(), "holt.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`version: "1.0"
+agents:
+  Coder:
+    image: ${HOLT_TEST_REGISTRY}/coder:latest
+    command: [sh, -c, "echo $HOME"]
+    bidding_strategy: ${HOLT_TEST_STRATEGY:-exclusive}
+    concurrency: ${HOLT_TEST_CONCURRENCY:-2}
+    environment:
+      - "API_KEY=${HOLT_TEST_KEY}"
+`), 0644))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	coder := cfg.Agents["Coder"]
+	assert.Equal(t, "ghcr.io/acme/coder:latest", coder.Image)
+	assert.Equal(t, []string{"sh", "-c", "echo $HOME"}, coder.Command)
+	assert.Equal(t, "exclusive", coder.BiddingStrategy)
+	assert.Equal(t, 2, coder.Concurrency, "plain scalars are re-resolved after interpolation")
+	assert.Equal(t, []string{"API_KEY=sk-123"}, coder.Environment)
+	assert.Equal(t, []string{"HOLT_TEST_CONCURRENCY", "HOLT_TEST_KEY", "HOLT_TEST_REGISTRY", "HOLT_TEST_STRATEGY"}, cfg.Variables())
+}
+
+func TestLoad_InterpolationRequiredVariable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holt.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`version: "1.0"
+agents:
+  Coder:
+    image: coder:latest
+    command: [/app/run.sh]
+    bidding_strategy: exclusive
+    environment:
+      - "API_KEY=${HOLT_TEST_UNSET_KEY:?is required}"
+`), 0644))
+
+	_, err := Load(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 8: variable HOLT_TEST_UNSET_KEY is required")
+}
+
+func TestReadEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte(`# Model settings
+MODEL=gpt-small
+export REGION=eu-west-1
+
+QUOTED="hello # not a comment"
+SINGLE='a=b'
+INLINE=value # trailing comment
+EMPTY=
+`), 0600))
+
+	env, err := ReadEnvFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"MODEL=gpt-small",
+		"REGION=eu-west-1",
+		"QUOTED=hello # not a comment",
+		"SINGLE=a=b",
+		"INLINE=value",
+		"EMPTY=",
+	}, env)
+}
+
+func TestReadEnvFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := ReadEnvFile(filepath.Join(dir, "missing.env"))
+	assert.ErrorContains(t, err, "failed to read env_file")
+
+	path := filepath.Join(dir, "bad.env")
+	require.NoError(t, os.WriteFile(path, []byte("GOOD=1\nnot a pair\n"), 0600))
+	_, err = ReadEnvFile(path)
+	assert.ErrorContains(t, err, "bad.env:2: expected KEY=VALUE")
+}
+
+func TestAgent_ResolveEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "base.env"), []byte("MODEL=small\nREGION=eu\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "local.env"), []byte("MODEL=medium\n"), 0600))
+
+	agent := Agent{
+		EnvFile:     []string{"base.env", filepath.Join(dir, "local.env")},
+		Environment: []string{"MODEL=large"},
+	}
+
+	env, err := agent.ResolveEnvironment(dir)
+	require.NoError(t, err)
+	// Later entries win in Docker, so environment overrides env_file
+	assert.Equal(t, []string{"MODEL=small", "REGION=eu", "MODEL=medium", "MODEL=large"}, env)
+
+	agent.EnvFile = []string{"missing.env"}
+	_, err = agent.ResolveEnvironment(dir)
+	assert.Error(t, err)
+}
+
+func TestSecret(t *testing.T) {
+	secret := Secret{File: "secrets/openai.key"}
+	assert.Equal(t, "openai.key", secret.MountName())
+	assert.Equal(t, "/run/secrets/openai.key", secret.MountPath())
+	assert.Equal(t, "/repo/secrets/openai.key", secret.HostPath("/repo"))
+
+	named := Secret{Name: "api_key", File: "/home/me/.keys/openai"}
+	assert.Equal(t, "/run/secrets/api_key", named.MountPath())
+	assert.Equal(t, "/home/me/.keys/openai", named.HostPath("/repo"))
+}
+
+func TestAgentValidate_Secrets(t *testing.T) {
+	base := Agent{Image: "coder:latest", Command: []string{"/app/run.sh"}, BiddingStrategy: "exclusive"}
+
+	tests := []struct {
+		name          string
+		secrets       []Secret
+		errorContains string
+	}{
+		{name: "valid", secrets: []Secret{{File: "a.key"}, {Name: "b", File: "a.key"}}},
+		{name: "missing file", secrets: []Secret{{Name: "key"}}, errorContains: "secrets: file is required"},
+		{name: "name with slash", secrets: []Secret{{Name: "../etc", File: "a.key"}}, errorContains: "invalid name '../etc'"},
+		{name: "duplicate name", secrets: []Secret{{File: "keys/a.key"}, {File: "other/a.key"}}, errorContains: "duplicate secret name 'a.key'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := base
+			agent.Secrets = tt.secrets
+			err := agent.Validate("Coder")
+			if tt.errorContains == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.errorContains)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// schemaRequired lists the required keys of each config struct
+var schemaRequired = map[string][]string{
+	"HoltConfig":        {"version", "agents"},
+	"Agent":             {"image", "command"},
+	"BuildConfig":       {"context"},
+	"WorkspaceConfig":   {"mode"},
+	"WorkerConfig":      {"image", "command"},
+	"HealthCheckConfig": {"command"},
+	"Secret":            {"file"},
+	"Workflow":          {"stages"},
+	"WorkflowStage":     {"name", "role", "consumes"},
+}
+
+// schemaEnums lists the accepted values of enumerated fields, keyed by
+// "Struct.field"
+var schemaEnums = map[string][]string{
+	"Agent.bidding_strategy":    {"review", "claim", "exclusive", "ignore"},
+	"Agent.strategy":            {"reuse", "fresh_per_call"},
+	"Agent.mode":                {"controller"},
+	"Agent.protocol":            {ProtocolSubprocess, ProtocolHTTP},
+	"WorkspaceConfig.mode":      {"ro", "rw"},
+	"WorkspaceConfig.isolation": {"worktree"},
+	"ContextConfig.overflow":    {ContextOverflowDropOldest, ContextOverflowSummarise},
+	"WorkflowStage.phase":       {PhaseReview, PhaseParallel, PhaseExclusive},
+}
+
+// schemaDescriptions documents fields for editor hover text, keyed by
+// "Struct.field"
+var schemaDescriptions = map[string]string{
+	"HoltConfig.version":                       "Config format version",
+	"HoltConfig.agents":                        "Agents keyed by role (alphanumeric with hyphens, PascalCase recommended)",
+	"HoltConfig.workflows":                     "Declarative stage DAGs enforced on top of bidding",
+	"OrchestratorConfig.max_review_iterations": "How many times an artefact can be rejected and reworked (0 = unlimited, default 3)",
+	"OrchestratorConfig.replicas":              "Orchestrator containers to run (default 1)",
+	"OrchestratorConfig.lease_ttl":             "Leader lease TTL, bounding failover time (default 10s)",
+	"Agent.image":                              "Docker image for this agent",
+	"Agent.command":                            "Tool command the pup runs for each claim",
+	"Agent.bid_script":                         "Command whose output is the bid (alternative to bidding_strategy)",
+	"Agent.bidding_strategy":                   "Static bid for every artefact",
+	"Agent.replicas":                           "Ignored: each agent runs as a single container",
+	"Agent.strategy":                           "Ignored: container strategies are not implemented",
+	"Agent.prompts":                            "Ignored: holt does not read prompts",
+	"Agent.resources":                          "Ignored: resource limits are not applied",
+	"Agent.mode":                               "controller runs a bidding-only controller that launches a worker per claim",
+	"Agent.concurrency":                        "Max parallel executions per pup (0 or omitted: 1)",
+	"Agent.consumes":                           "Artefact type globs this agent bids on",
+	"Agent.produces":                           "Artefact types this agent emits (pipeline validation only)",
+	"Agent.terminal":                           "Produced types that end the workflow",
+	"Agent.when":                               "Expression over the artefact that must hold for the agent to bid",
+	"Agent.auto_commit":                        "Commit workspace changes as a CodeCommit after each run (requires workspace mode rw)",
+	"Agent.protocol":                           "How the pup talks to the tool (default subprocess)",
+	"Agent.environment":                        "KEY=VALUE entries; ${VAR} and ${VAR:-default} are expanded from the host environment",
+	"Agent.env_file":                           "Files of KEY=VALUE lines, relative to holt.yml (environment entries take precedence)",
+	"Agent.secrets":                            "Local files mounted read-only at /run/secrets/<name> and redacted from logs",
+	"BuildConfig.context":                      "Directory containing the agent's Dockerfile",
+	"HealthCheckConfig.interval":               "Check interval (default 30s)",
+	"HealthCheckConfig.timeout":                "Command timeout (default 5s)",
+	"ServicesConfig.orchestrator":              "Ignored: the orchestrator always runs the holt image",
+	"ServiceOverride.resources":                "Ignored: resource limits are not applied",
+	"WorkflowStage.role":                       "Agent role (key in agents)",
+	"WorkflowStage.after":                      "Only act on artefacts produced by these stages",
+}
+
+// JSONSchema returns a JSON Schema (draft-07) for holt.yml, generated from the
+// config types, so editors can validate and autocomplete the file
+func JSONSchema() ([]byte, error) {
+	schema := schemaFor(reflect.TypeOf(HoltConfig{}))
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["title"] = "holt.yml"
+
+	properties := schema["properties"].(map[string]interface{})
+	// YAML reads an unquoted 1.0 as a number, which holt also accepts
+	properties["version"] = map[string]interface{}{
+		"description": schemaDescriptions["HoltConfig.version"],
+		"enum":        []interface{}{"1.0", 1},
+	}
+	agents := properties["agents"].(map[string]interface{})
+	agents["propertyNames"] = map[string]interface{}{"pattern": "^[A-Za-z0-9-]{1,64}$"}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func schemaFor(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := yamlName(field)
+			if name == "" {
+				continue
+			}
+			property := schemaFor(field.Type)
+			key := t.Name() + "." + name
+			if values, ok := schemaEnums[key]; ok {
+				property["enum"] = values
+			}
+			if description, ok := schemaDescriptions[key]; ok {
+				property["description"] = description
+			}
+			properties[name] = property
+		}
+		schema := map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+		if required, ok := schemaRequired[t.Name()]; ok {
+			schema["required"] = required
+		}
+		return schema
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": schemaFor(t.Elem()),
+		}
+	case reflect.Slice:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": schemaFor(t.Elem()),
+		}
+	case reflect.Int:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
@@ -0,0 +1,540 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity classifies a lint Issue
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a single Lint finding, positioned at the YAML node it concerns.
+// Line and Column are 1-based and zero when the finding has no position.
+type Issue struct {
+	Line     int
+	Column   int
+	Path     string // Dotted field path, e.g. agents.Coder.health_check.interval
+	Severity Severity
+	Message  string
+}
+
+// String renders the issue as "line:col: path: message", omitting unknown parts
+func (i Issue) String() string {
+	var b strings.Builder
+	if i.Line > 0 {
+		fmt.Fprintf(&b, "%d:", i.Line)
+		if i.Column > 0 {
+			fmt.Fprintf(&b, "%d:", i.Column)
+		}
+		b.WriteString(" ")
+	}
+	if i.Path != "" {
+		b.WriteString(i.Path + ": ")
+	}
+	b.WriteString(i.Message)
+	return b.String()
+}
+
+// HasErrors reports whether any issue has error severity
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoredAgentFields are accepted in agent definitions for compatibility but
+// have no effect, mapped to the explanation shown by Lint.
+var ignoredAgentFields = map[string]string{
+	"replicas":  "is ignored: each agent runs as a single container (use concurrency or mode: controller to scale)",
+	"strategy":  "is ignored: container strategies are not implemented",
+	"prompts":   "is ignored: holt does not read prompts (pass them to your tool via command or environment)",
+	"resources": "is ignored: resource limits are not applied to agent containers",
+}
+
+// retiredFields are keys from earlier config formats, keyed by "Struct.field"
+// and mapped to what replaced them
+var retiredFields = map[string]string{
+	"Agent.role": "the agent's key under agents is its role",
+}
+
+var yamlLinePattern = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// Lint checks holt.yml content without Docker, reporting every problem it can
+// find with its position: YAML syntax errors, unknown fields and wrongly typed
+// values, the semantic rules enforced by Validate, fields holt ignores, missing
+// build contexts, env files and secrets, unset ${VAR} references and unusable
+// health-check durations. baseDir is the directory relative paths are
+// resolved against. Issues are sorted by position.
+func Lint(data []byte, baseDir string) []Issue {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []Issue{syntaxIssue(err)}
+	}
+	if len(doc.Content) == 0 {
+		return []Issue{{Severity: SeverityError, Message: "file is empty"}}
+	}
+
+	l := &linter{baseDir: baseDir}
+	_, interpolationIssues := interpolateNode(&doc, os.LookupEnv)
+	for _, issue := range interpolationIssues {
+		if issue.err != nil {
+			l.add(issue.node, "", SeverityError, "%v", issue.err)
+			continue
+		}
+		l.add(issue.node, "", SeverityWarning, "variable %s is not set, using an empty value (use ${%s:-default} to make this explicit)", issue.unset, issue.unset)
+	}
+
+	root := resolveAlias(doc.Content[0])
+	l.checkShape(root, reflect.TypeOf(HoltConfig{}), "")
+	l.checkAgents(root)
+	l.checkServices(root)
+	if !HasErrors(l.issues) {
+		l.checkSemantics(root)
+	}
+
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		a, b := l.issues[i], l.issues[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return l.issues
+}
+
+// LintFile reads and lints a holt.yml, resolving build contexts relative to
+// the file's directory
+func LintFile(path string) ([]Issue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	return Lint(data, filepath.Dir(path)), nil
+}
+
+// syntaxIssue converts a yaml.v3 parse error into a positioned issue
+func syntaxIssue(err error) Issue {
+	issue := Issue{Severity: SeverityError, Message: err.Error()}
+	if m := yamlLinePattern.FindStringSubmatch(err.Error()); m != nil {
+		issue.Line, _ = strconv.Atoi(m[1])
+		issue.Message = m[2]
+	}
+	return issue
+}
+
+type linter struct {
+	baseDir string
+	issues  []Issue
+}
+
+func (l *linter) add(node *yaml.Node, path string, severity Severity, format string, args ...interface{}) {
+	issue := Issue{Path: path, Severity: severity, Message: fmt.Sprintf(format, args...)}
+	if node != nil {
+		issue.Line, issue.Column = node.Line, node.Column
+	}
+	l.issues = append(l.issues, issue)
+}
+
+// checkShape walks node against the Go type it decodes into, reporting
+// unknown fields and values of the wrong kind
+func (l *linter) checkShape(node *yaml.Node, t reflect.Type, path string) {
+	node = resolveAlias(node)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if !l.expectKind(node, yaml.MappingNode, path) {
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "<<" {
+				continue
+			}
+			field, ok := fields[key.Value]
+			if replacement, retired := retiredFields[t.Name()+"."+key.Value]; retired && !ok {
+				l.add(key, joinPath(path, key.Value), SeverityError, "field is no longer supported: %s", replacement)
+				continue
+			}
+			if !ok {
+				l.add(key, joinPath(path, key.Value), SeverityError, "unknown field%s", suggestField(key.Value, fields))
+				continue
+			}
+			l.checkShape(value, field.Type, joinPath(path, key.Value))
+		}
+	case reflect.Map:
+		if !l.expectKind(node, yaml.MappingNode, path) {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			l.checkShape(node.Content[i+1], t.Elem(), joinPath(path, node.Content[i].Value))
+		}
+	case reflect.Slice:
+		if !l.expectKind(node, yaml.SequenceNode, path) {
+			return
+		}
+		for i, item := range node.Content {
+			l.checkShape(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.Int:
+		if l.expectKind(node, yaml.ScalarNode, path) {
+			var v int
+			if node.Decode(&v) != nil {
+				l.add(node, path, SeverityError, "expected an integer, got %q", node.Value)
+			}
+		}
+	case reflect.Bool:
+		if l.expectKind(node, yaml.ScalarNode, path) {
+			var v bool
+			if node.Decode(&v) != nil {
+				l.add(node, path, SeverityError, "expected true or false, got %q", node.Value)
+			}
+		}
+	default:
+		l.expectKind(node, yaml.ScalarNode, path)
+	}
+}
+
+func (l *linter) expectKind(node *yaml.Node, kind yaml.Kind, path string) bool {
+	if node.Kind == kind {
+		return true
+	}
+	l.add(node, path, SeverityError, "expected %s, got %s", kindName(kind), kindName(node.Kind))
+	return false
+}
+
+// checkAgents reports ignored agent fields, build contexts that cannot be
+// found and health checks that cannot run as configured
+func (l *linter) checkAgents(root *yaml.Node) {
+	_, agents := mappingEntry(root, "agents")
+	if agents == nil || agents.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(agents.Content); i += 2 {
+		name, agent := agents.Content[i].Value, resolveAlias(agents.Content[i+1])
+		if agent.Kind != yaml.MappingNode {
+			continue
+		}
+		path := "agents." + name
+		if name != "" && (name[0] < 'A' || name[0] > 'Z') {
+			l.add(agents.Content[i], path, SeverityWarning, "role should start with an uppercase letter (PascalCase convention)")
+		}
+
+		for j := 0; j+1 < len(agent.Content); j += 2 {
+			key := agent.Content[j]
+			if reason, ok := ignoredAgentFields[key.Value]; ok {
+				l.add(key, joinPath(path, key.Value), SeverityWarning, "%s", reason)
+			}
+		}
+
+		if _, build := mappingEntry(agent, "build"); build != nil {
+			if contextKey, contextNode := mappingEntry(build, "context"); contextNode != nil {
+				l.checkBuildContext(contextKey, contextNode.Value, path+".build.context")
+			}
+		}
+
+		if _, envFiles := mappingEntry(agent, "env_file"); envFiles != nil && envFiles.Kind == yaml.SequenceNode {
+			for k, file := range envFiles.Content {
+				if file.Value == "" {
+					continue
+				}
+				if _, err := ReadEnvFile(resolvePath(l.baseDir, file.Value)); err != nil {
+					l.add(file, fmt.Sprintf("%s.env_file[%d]", path, k), SeverityError, "%v", err)
+				}
+			}
+		}
+
+		if _, secrets := mappingEntry(agent, "secrets"); secrets != nil && secrets.Kind == yaml.SequenceNode {
+			for k, secret := range secrets.Content {
+				key, file := mappingEntry(secret, "file")
+				if file == nil || file.Value == "" {
+					continue
+				}
+				info, err := os.Stat(resolvePath(l.baseDir, file.Value))
+				secretPath := fmt.Sprintf("%s.secrets[%d].file", path, k)
+				switch {
+				case err != nil:
+					l.add(key, secretPath, SeverityError, "secret file %s does not exist", file.Value)
+				case info.IsDir():
+					l.add(key, secretPath, SeverityError, "secret file %s is a directory", file.Value)
+				}
+			}
+		}
+
+		if _, health := mappingEntry(agent, "health_check"); health != nil {
+			l.checkHealthCheck(health, path+".health_check")
+		}
+	}
+}
+
+func (l *linter) checkBuildContext(node *yaml.Node, dir, path string) {
+	if dir == "" {
+		return
+	}
+	resolved := dir
+	if !filepath.IsAbs(resolved) {
+		resolved = filepath.Join(l.baseDir, resolved)
+	}
+
+	info, err := os.Stat(resolved)
+	switch {
+	case err != nil:
+		l.add(node, path, SeverityWarning, "build context %s does not exist", dir)
+	case !info.IsDir():
+		l.add(node, path, SeverityWarning, "build context %s is not a directory", dir)
+	default:
+		if _, err := os.Stat(filepath.Join(resolved, "Dockerfile")); err != nil {
+			l.add(node, path, SeverityWarning, "build context %s has no Dockerfile", dir)
+		}
+	}
+}
+
+func (l *linter) checkHealthCheck(health *yaml.Node, path string) {
+	durations := map[string]time.Duration{}
+	for _, field := range []string{"interval", "timeout"} {
+		_, node := mappingEntry(health, field)
+		if node == nil || node.Value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(node.Value)
+		if err != nil || d <= 0 {
+			l.add(node, joinPath(path, field), SeverityError, "invalid duration %q (must be positive, like '30s')", node.Value)
+			continue
+		}
+		durations[field] = d
+	}
+
+	interval, ok := durations["interval"]
+	if !ok {
+		interval = defaultHealthCheckInterval
+	}
+	timeout, ok := durations["timeout"]
+	if !ok {
+		timeout = defaultHealthCheckTimeout
+	}
+	if timeout >= interval {
+		key, _ := mappingEntry(health, "timeout")
+		if key == nil {
+			key, _ = mappingEntry(health, "interval")
+		}
+		l.add(key, path, SeverityWarning, "timeout %s is not shorter than interval %s, so checks will overlap and be skipped", timeout, interval)
+	}
+}
+
+// checkServices reports service overrides holt ignores
+func (l *linter) checkServices(root *yaml.Node) {
+	_, services := mappingEntry(root, "services")
+	if services == nil {
+		return
+	}
+	if key, _ := mappingEntry(services, "orchestrator"); key != nil {
+		l.add(key, "services.orchestrator", SeverityWarning, "is ignored: the orchestrator always runs the holt image")
+	}
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		if key, _ := mappingEntry(services.Content[i+1], "resources"); key != nil {
+			l.add(key, "services."+services.Content[i].Value+".resources", SeverityWarning, "is ignored: resource limits are not applied to service containers")
+		}
+	}
+}
+
+// checkSemantics applies Validate's rules, positioning each failure at the
+// agent, workflow or field it concerns. Agents with errors from earlier
+// checks are skipped so a problem is only reported once.
+func (l *linter) checkSemantics(root *yaml.Node) {
+	var cfg HoltConfig
+	if err := root.Decode(&cfg); err != nil {
+		l.add(root, "", SeverityError, "%v", err)
+		return
+	}
+
+	agentFailed := false
+	keys, agents := mappingEntry(root, "agents")
+	if agents != nil {
+		for i := 0; i+1 < len(agents.Content); i += 2 {
+			key := agents.Content[i]
+			path := "agents." + key.Value
+			if l.hasErrorsUnder(path) {
+				agentFailed = true
+				continue
+			}
+			if err := validateRoleName(key.Value); err != nil {
+				l.add(key, path, SeverityError, "invalid agent role: %v", err)
+				agentFailed = true
+				continue
+			}
+			agent := cfg.Agents[key.Value]
+			if err := agent.Validate(key.Value); err != nil {
+				l.add(key, path, SeverityError, "%s", strings.TrimPrefix(err.Error(), fmt.Sprintf("agent '%s': ", key.Value)))
+				agentFailed = true
+			}
+		}
+	}
+	if agentFailed || HasErrors(l.issues) {
+		return
+	}
+
+	err := cfg.Validate()
+	if err == nil {
+		return
+	}
+	message := err.Error()
+	switch {
+	case strings.HasPrefix(message, "unsupported version"):
+		key, _ := mappingEntry(root, "version")
+		l.add(key, "version", SeverityError, "%s", message)
+	case strings.HasPrefix(message, "no agents defined"):
+		l.add(keys, "agents", SeverityError, "%s", message)
+	case strings.HasPrefix(message, "orchestrator."):
+		key, _ := mappingEntry(root, "orchestrator")
+		l.add(key, "orchestrator", SeverityError, "%s", strings.TrimPrefix(message, "orchestrator."))
+	case strings.HasPrefix(message, "workflow '"):
+		name := strings.SplitN(strings.TrimPrefix(message, "workflow '"), "'", 2)[0]
+		_, workflows := mappingEntry(root, "workflows")
+		key, _ := mappingEntry(workflows, name)
+		l.add(key, "workflows."+name, SeverityError, "%s", message)
+	default:
+		l.add(nil, "", SeverityError, "%s", message)
+	}
+}
+
+func (l *linter) hasErrorsUnder(path string) bool {
+	for _, issue := range l.issues {
+		if issue.Severity == SeverityError && (issue.Path == path || strings.HasPrefix(issue.Path, path+".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// mappingEntry returns the key and value nodes for key in a mapping node,
+// or nils when node is not a mapping or has no such key
+func mappingEntry(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node == nil {
+		return nil, nil
+	}
+	node = resolveAlias(node)
+	if node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], resolveAlias(node.Content[i+1])
+		}
+	}
+	return nil, nil
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// yamlFields maps a struct's yaml keys to its fields
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if name := yamlName(field); name != "" {
+			fields[name] = field
+		}
+	}
+	return fields
+}
+
+// yamlName returns the key a field is decoded from, or "" if it is skipped
+func yamlName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+	return name
+}
+
+// suggestField returns a " (did you mean ...?)" hint for a misspelt key
+func suggestField(key string, fields map[string]reflect.StructField) string {
+	best, bestDistance := "", 3
+	for name := range fields {
+		if d := editDistance(key, name); d < bestDistance || (d == bestDistance && best != "" && name < best) {
+			best, bestDistance = name, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %q?)", best)
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(b)]
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func kindName(kind yaml.Kind) string {
+	switch kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	case yaml.ScalarNode:
+		return "a single value"
+	case yaml.AliasNode:
+		return "an alias"
+	default:
+		return "a document"
+	}
+}
@@ -0,0 +1,325 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lintValidConfig = `version: "1.0"
+agents:
+  Coder:
+    image: coder:latest
+    command: [/app/run.sh]
+    bidding_strategy: exclusive
+`
+
+// findIssue returns the first issue at path, failing the test if none exists
+func findIssue(t *testing.T, issues []Issue, path string) Issue {
+	t.Helper()
+	for _, issue := range issues {
+		if issue.Path == path {
+			return issue
+		}
+	}
+	t.Fatalf("no issue at %s in %v", path, issues)
+	return Issue{}
+}
+
+func TestLint_ValidConfig(t *testing.T) {
+	assert.Empty(t, Lint([]byte(lintValidConfig), t.TempDir()))
+}
+
+func TestLint_SyntaxError(t *testing.T) {
+	issues := Lint([]byte("version: \"1.0\"\nagents:\n  Coder: [\n"), t.TempDir())
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, SeverityError, issues[0].Severity)
+	assert.Equal(t, 3, issues[0].Line)
+	assert.NotContains(t, issues[0].Message, "yaml:")
+}
+
+func TestLint_UnknownFieldWithSuggestion(t *testing.T) {
+	content := lintValidConfig + "    bidding_stratgy: review\n"
+
+	issue := findIssue(t, Lint([]byte(content), t.TempDir()), "agents.Coder.bidding_stratgy")
+	assert.Equal(t, SeverityError, issue.Severity)
+	assert.Equal(t, 7, issue.Line)
+	assert.Equal(t, 5, issue.Column)
+	assert.Contains(t, issue.Message, `did you mean "bidding_strategy"?`)
+}
+
+func TestLint_RetiredRoleField(t *testing.T) {
+	content := lintValidConfig + "    role: Coder\n"
+
+	issue := findIssue(t, Lint([]byte(content), t.TempDir()), "agents.Coder.role")
+	assert.Contains(t, issue.Message, "no longer supported")
+}
+
+func TestLint_WrongKinds(t *testing.T) {
+	content := `version: "1.0"
+agents:
+  Coder:
+    image: coder:latest
+    command: /app/run.sh
+    bidding_strategy: exclusive
+    concurrency: lots
+    auto_commit: maybe
+`
+	issues := Lint([]byte(content), t.TempDir())
+
+	assert.Equal(t, "expected a list, got a single value", findIssue(t, issues, "agents.Coder.command").Message)
+	assert.Equal(t, `expected an integer, got "lots"`, findIssue(t, issues, "agents.Coder.concurrency").Message)
+	assert.Equal(t, `expected true or false, got "maybe"`, findIssue(t, issues, "agents.Coder.auto_commit").Message)
+	assert.Equal(t, 7, findIssue(t, issues, "agents.Coder.concurrency").Line)
+}
+
+func TestLint_SemanticErrorPositionedAtAgent(t *testing.T) {
+	content := `version: "1.0"
+agents:
+  Coder:
+    image: coder:latest
+    command: [/app/run.sh]
+    bidding_strategy: sometimes
+`
+	issue := findIssue(t, Lint([]byte(content), t.TempDir()), "agents.Coder")
+	assert.Equal(t, SeverityError, issue.Severity)
+	assert.Equal(t, 3, issue.Line)
+	assert.Contains(t, issue.Message, "invalid bidding_strategy: sometimes")
+	assert.NotContains(t, issue.Message, "agent 'Coder'")
+}
+
+func TestLint_SemanticErrorPositionedAtWorkflow(t *testing.T) {
+	content := lintValidConfig + `workflows:
+  feature:
+    stages:
+      - name: ship
+        role: Shipper
+        consumes: [GoalDefined]
+`
+	issue := findIssue(t, Lint([]byte(content), t.TempDir()), "workflows.feature")
+	assert.Equal(t, 8, issue.Line)
+	assert.Contains(t, issue.Message, "role 'Shipper' is not defined")
+}
+
+func TestLint_VersionError(t *testing.T) {
+	content := `version: "2.0"
+agents:
+  Coder:
+    image: coder:latest
+    command: [/app/run.sh]
+    bidding_strategy: exclusive
+`
+	issue := findIssue(t, Lint([]byte(content), t.TempDir()), "version")
+	assert.Equal(t, 1, issue.Line)
+	assert.Contains(t, issue.Message, "unsupported version")
+}
+
+func TestLint_IgnoredFieldsAreWarnings(t *testing.T) {
+	content := lintValidConfig + `    replicas: 3
+    strategy: reuse
+    prompts:
+      claim: "Do the thing"
+    resources:
+      limits:
+        cpus: "1"
+services:
+  orchestrator:
+    image: custom
+  redis:
+    image: redis:7
+    resources:
+      limits:
+        memory: 1g
+`
+	issues := Lint([]byte(content), t.TempDir())
+
+	assert.False(t, HasErrors(issues))
+	for _, path := range []string{
+		"agents.Coder.replicas", "agents.Coder.strategy", "agents.Coder.prompts", "agents.Coder.resources",
+		"services.orchestrator", "services.redis.resources",
+	} {
+		issue := findIssue(t, issues, path)
+		assert.Equal(t, SeverityWarning, issue.Severity, path)
+		assert.Contains(t, issue.Message, "ignored", path)
+	}
+}
+
+func TestLint_RoleNamingWarning(t *testing.T) {
+	content := `version: "1.0"
+agents:
+  coder:
+    image: coder:latest
+    command: [/app/run.sh]
+    bidding_strategy: exclusive
+`
+	issues := Lint([]byte(content), t.TempDir())
+
+	assert.False(t, HasErrors(issues))
+	assert.Contains(t, findIssue(t, issues, "agents.coder").Message, "PascalCase")
+}
+
+func TestLint_BuildContext(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "agents", "with-dockerfile"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "agents", "with-dockerfile", "Dockerfile"), []byte("FROM scratch\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "agents", "empty"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "agents", "file"), nil, 0644))
+
+	tests := []struct {
+		context string
+		message string
+	}{
+		{context: "agents/with-dockerfile"},
+		{context: "agents/missing", message: "does not exist"},
+		{context: "agents/empty", message: "has no Dockerfile"},
+		{context: "agents/file", message: "is not a directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.context, func(t *testing.T) {
+			content := lintValidConfig + "    build:\n      context: " + tt.context + "\n"
+			issues := Lint([]byte(content), dir)
+
+			if tt.message == "" {
+				assert.Empty(t, issues)
+				return
+			}
+			issue := findIssue(t, issues, "agents.Coder.build.context")
+			assert.Equal(t, SeverityWarning, issue.Severity)
+			assert.Contains(t, issue.Message, tt.message)
+		})
+	}
+}
+
+func TestLint_HealthCheckDurations(t *testing.T) {
+	healthCheck := func(interval, timeout string) string {
+		return lintValidConfig + "    health_check:\n      command: [/bin/true]\n      interval: " + interval + "\n      timeout: " + timeout + "\n"
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		assert.Empty(t, Lint([]byte(healthCheck("30s", "5s")), t.TempDir()))
+	})
+
+	t.Run("unparseable and non-positive durations", func(t *testing.T) {
+		issues := Lint([]byte(healthCheck("soon", "0s")), t.TempDir())
+
+		interval := findIssue(t, issues, "agents.Coder.health_check.interval")
+		assert.Equal(t, SeverityError, interval.Severity)
+		assert.Equal(t, 9, interval.Line)
+		assert.Contains(t, interval.Message, `invalid duration "soon"`)
+		assert.Equal(t, SeverityError, findIssue(t, issues, "agents.Coder.health_check.timeout").Severity)
+		// Reported once, not again by the semantic checks
+		assert.Len(t, issues, 2)
+	})
+
+	t.Run("timeout not shorter than interval", func(t *testing.T) {
+		issues := Lint([]byte(healthCheck("5s", "10s")), t.TempDir())
+
+		issue := findIssue(t, issues, "agents.Coder.health_check")
+		assert.Equal(t, SeverityWarning, issue.Severity)
+		assert.Contains(t, issue.Message, "not shorter than interval")
+	})
+}
+
+func TestLint_SortedByPosition(t *testing.T) {
+	content := `version: "1.0"
+agents:
+  Coder:
+    image: coder:latest
+    command: [/app/run.sh]
+    bidding_strategy: exclusive
+    replicas: 2
+    concurrency: many
+`
+	issues := Lint([]byte(content), t.TempDir())
+
+	require.Len(t, issues, 2)
+	assert.Equal(t, 7, issues[0].Line)
+	assert.Equal(t, 8, issues[1].Line)
+}
+
+func TestIssue_String(t *testing.T) {
+	assert.Equal(t, "3:5: agents.Coder: bad", Issue{Line: 3, Column: 5, Path: "agents.Coder", Message: "bad"}.String())
+	assert.Equal(t, "3: bad", Issue{Line: 3, Message: "bad"}.String())
+	assert.Equal(t, "bad", Issue{Message: "bad"}.String())
+}
+
+func TestHealthCheckConfig_Validate(t *testing.T) {
+	assert.NoError(t, (&HealthCheckConfig{Command: []string{"/bin/true"}}).Validate())
+	assert.ErrorContains(t, (&HealthCheckConfig{}).Validate(), "health_check.command is required")
+	assert.ErrorContains(t, (&HealthCheckConfig{Command: []string{"x"}, Interval: "-1s"}).Validate(), "invalid health_check.interval")
+	assert.ErrorContains(t, (&HealthCheckConfig{Command: []string{"x"}, Timeout: "fast"}).Validate(), "invalid health_check.timeout")
+}
+
+func TestJSONSchema(t *testing.T) {
+	data, err := JSONSchema()
+	require.NoError(t, err)
+
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &schema))
+	assert.Equal(t, "http://json-schema.org/draft-07/schema#", schema["$schema"])
+	assert.Equal(t, false, schema["additionalProperties"])
+	assert.ElementsMatch(t, []interface{}{"version", "agents"}, schema["required"])
+
+	properties := schema["properties"].(map[string]interface{})
+	agents := properties["agents"].(map[string]interface{})
+	agent := agents["additionalProperties"].(map[string]interface{})
+	agentProperties := agent["properties"].(map[string]interface{})
+
+	assert.Equal(t, false, agent["additionalProperties"])
+	assert.ElementsMatch(t, []interface{}{"image", "command"}, agent["required"])
+	assert.Equal(t, "array", agentProperties["command"].(map[string]interface{})["type"])
+	assert.Equal(t, "integer", agentProperties["concurrency"].(map[string]interface{})["type"])
+	assert.ElementsMatch(t, []interface{}{"review", "claim", "exclusive", "ignore"},
+		agentProperties["bidding_strategy"].(map[string]interface{})["enum"])
+
+	stage := properties["workflows"].(map[string]interface{})["additionalProperties"].(map[string]interface{})["properties"].(map[string]interface{})["stages"].(map[string]interface{})["items"].(map[string]interface{})
+	assert.Contains(t, stage["properties"], "after")
+}
+
+func TestLint_Interpolation(t *testing.T) {
+	t.Setenv("HOLT_LINT_CONCURRENCY", "3")
+	content := lintValidConfig + `    concurrency: ${HOLT_LINT_CONCURRENCY}
+    environment:
+      - "KEY=${HOLT_LINT_UNSET}"
+      - "BAD=${HOLT_LINT_REQUIRED:?must be set}"
+`
+	issues := Lint([]byte(content), t.TempDir())
+
+	require.Len(t, issues, 2, "the interpolated integer is accepted")
+	assert.Equal(t, SeverityWarning, issues[0].Severity)
+	assert.Equal(t, 9, issues[0].Line)
+	assert.Contains(t, issues[0].Message, "variable HOLT_LINT_UNSET is not set")
+	assert.Equal(t, SeverityError, issues[1].Severity)
+	assert.Equal(t, 10, issues[1].Line)
+	assert.Contains(t, issues[1].Message, "HOLT_LINT_REQUIRED must be set")
+}
+
+func TestLint_EnvFilesAndSecrets(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("MODEL=small\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "openai.key"), []byte("sk-test\n"), 0600))
+
+	valid := lintValidConfig + `    env_file: [.env]
+    secrets:
+      - file: openai.key
+`
+	assert.Empty(t, Lint([]byte(valid), dir))
+
+	missing := lintValidConfig + `    env_file: [missing.env]
+    secrets:
+      - file: missing.key
+      - name: dir
+        file: .
+`
+	issues := Lint([]byte(missing), dir)
+
+	assert.Contains(t, findIssue(t, issues, "agents.Coder.env_file[0]").Message, "failed to read env_file")
+	assert.Contains(t, findIssue(t, issues, "agents.Coder.secrets[0].file").Message, "secret file missing.key does not exist")
+	assert.Contains(t, findIssue(t, issues, "agents.Coder.secrets[1].file").Message, "is a directory")
+}
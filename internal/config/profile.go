@@ -0,0 +1,196 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Overlay tags, following Docker Compose: !reset removes the key from the base
+// config and !override replaces the base value instead of merging into it
+const (
+	tagReset    = "!reset"
+	tagOverride = "!override"
+)
+
+// profileNamePattern restricts profile names to what is safe in a file name
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// ProfilePath returns the overlay file for a profile, next to the base config:
+// holt.yml with profile "ci" becomes holt.ci.yml
+func ProfilePath(basePath, profile string) string {
+	ext := filepath.Ext(basePath)
+	return strings.TrimSuffix(basePath, ext) + "." + profile + ext
+}
+
+// Profile returns the profile whose overlay was merged when the config was
+// loaded, or "" for the base config alone
+func (c *HoltConfig) Profile() string {
+	return c.profile
+}
+
+// validateProfileName checks a --profile value before it is used in a path
+func validateProfileName(profile string) error {
+	if !profileNamePattern.MatchString(profile) {
+		return fmt.Errorf("invalid profile name '%s': must contain only letters, digits, '-' and '_'", profile)
+	}
+	return nil
+}
+
+// loadProfileOverlay reads holt.<profile>.yml and merges it over the base document
+func loadProfileOverlay(doc *yaml.Node, basePath, profile string) error {
+	if err := validateProfileName(profile); err != nil {
+		return err
+	}
+
+	overlayPath := ProfilePath(basePath, profile)
+	data, err := os.ReadFile(overlayPath)
+	if err != nil {
+		return fmt.Errorf("failed to read profile '%s': %w", profile, err)
+	}
+
+	var overlay yaml.Node
+	if err := yaml.Unmarshal(data, &overlay); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filepath.Base(overlayPath), err)
+	}
+
+	if err := mergeDocuments(doc, &overlay); err != nil {
+		return fmt.Errorf("failed to apply %s: %w", filepath.Base(overlayPath), err)
+	}
+	return nil
+}
+
+// mergeDocuments merges the overlay document into base in place. An empty
+// overlay leaves base untouched; an empty base takes the overlay as is.
+func mergeDocuments(base, overlay *yaml.Node) error {
+	if len(overlay.Content) == 0 {
+		return nil
+	}
+	if len(base.Content) == 0 {
+		stripOverlayTags(overlay.Content[0])
+		*base = *overlay
+		return nil
+	}
+
+	baseRoot, overlayRoot := base.Content[0], overlay.Content[0]
+	if baseRoot.Kind != yaml.MappingNode || overlayRoot.Kind != yaml.MappingNode {
+		return fmt.Errorf("both files must contain a YAML mapping at the top level")
+	}
+	return mergeMapping(baseRoot, overlayRoot, "")
+}
+
+// mergeMapping merges overlay keys into the base mapping: nested mappings merge
+// recursively, !reset deletes the key, and any other value replaces the base value
+func mergeMapping(base, overlay *yaml.Node, path string) error {
+	for i := 0; i+1 < len(overlay.Content); i += 2 {
+		key, value := overlay.Content[i], resolveAlias(overlay.Content[i+1])
+		keyPath := joinPath(path, key.Value)
+
+		index := -1
+		for j := 0; j+1 < len(base.Content); j += 2 {
+			if base.Content[j].Value == key.Value {
+				index = j
+				break
+			}
+		}
+
+		if value.Tag == tagReset {
+			if index >= 0 {
+				base.Content = append(base.Content[:index], base.Content[index+2:]...)
+			}
+			continue
+		}
+
+		if index < 0 {
+			stripOverlayTags(value)
+			base.Content = append(base.Content, key, value)
+			continue
+		}
+
+		// Merge into a copy: the base value may be an anchor that other keys alias
+		merged, err := mergeValue(copyNode(resolveAlias(base.Content[index+1])), value, keyPath)
+		if err != nil {
+			return err
+		}
+		base.Content[index+1] = merged
+	}
+	return nil
+}
+
+// mergeValue returns the result of laying overlay over base at path
+func mergeValue(base, overlay *yaml.Node, path string) (*yaml.Node, error) {
+	if overlay.Tag == tagOverride {
+		stripOverlayTags(overlay)
+		return overlay, nil
+	}
+
+	switch {
+	case base.Kind == yaml.MappingNode && overlay.Kind == yaml.MappingNode:
+		if err := mergeMapping(base, overlay, path); err != nil {
+			return nil, err
+		}
+		return base, nil
+	case base.Kind == yaml.SequenceNode && overlay.Kind == yaml.SequenceNode && isEnvironmentPath(path):
+		return mergeEnvironment(base, overlay), nil
+	}
+
+	// Scalars, sequences and kind changes: the overlay wins outright
+	stripOverlayTags(overlay)
+	return overlay, nil
+}
+
+// isEnvironmentPath reports whether path is an agent's environment list, which
+// merges by variable name rather than being replaced
+func isEnvironmentPath(path string) bool {
+	return strings.HasPrefix(path, "agents.") && strings.HasSuffix(path, ".environment")
+}
+
+// mergeEnvironment merges KEY=VALUE entries: overlay entries replace base entries
+// with the same key and new keys are appended in overlay order
+func mergeEnvironment(base, overlay *yaml.Node) *yaml.Node {
+	envKey := func(node *yaml.Node) string {
+		key, _, _ := strings.Cut(node.Value, "=")
+		return key
+	}
+
+	for _, entry := range overlay.Content {
+		stripOverlayTags(entry)
+		replaced := false
+		for i, existing := range base.Content {
+			if existing.Kind == yaml.ScalarNode && envKey(existing) == envKey(entry) {
+				base.Content[i] = entry
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			base.Content = append(base.Content, entry)
+		}
+	}
+	return base
+}
+
+// copyNode returns a copy of node with its own Content slice, so merging into it
+// leaves the original, and every alias of it, untouched. Children are copied when
+// the merge descends into them.
+func copyNode(node *yaml.Node) *yaml.Node {
+	clone := *node
+	clone.Anchor = ""
+	clone.Content = append([]*yaml.Node(nil), node.Content...)
+	return &clone
+}
+
+// stripOverlayTags clears !reset and !override tags left in overlay content that
+// is copied into the base, so they never reach the decoder
+func stripOverlayTags(node *yaml.Node) {
+	if node.Tag == tagReset || node.Tag == tagOverride {
+		node.Tag = ""
+	}
+	for _, child := range node.Content {
+		stripOverlayTags(child)
+	}
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profileBaseConfig = `version: "1.0"
+orchestrator:
+  max_review_iterations: 5
+agents:
+  Coder:
+    image: coder:latest
+    command: [/app/run.sh]
+    bidding_strategy: exclusive
+    consumes: [GoalDefined]
+    environment:
+      - LOG_LEVEL=info
+      - MODEL=large
+  Reviewer:
+    image: reviewer:latest
+    command: [/app/review.sh]
+    bidding_strategy: review
+  Tester:
+    image: tester:latest
+    command: [/app/test.sh]
+    bidding_strategy: claim
+    health_check:
+      command: [/app/healthy.sh]
+      interval: 10s
+`
+
+// writeProfileConfig writes holt.yml and the overlays into a temp dir and returns the holt.yml path
+func writeProfileConfig(t *testing.T, base string, overlays map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "holt.yml")
+	require.NoError(t, os.WriteFile(path, []byte(base), 0644))
+	for profile, overlay := range overlays {
+		require.NoError(t, os.WriteFile(ProfilePath(path, profile), []byte(overlay), 0644))
+	}
+	return path
+}
+
+func TestProfilePath(t *testing.T) {
+	assert.Equal(t, "holt.ci.yml", ProfilePath("holt.yml", "ci"))
+	assert.Equal(t, "/repo/holt.local.yaml", ProfilePath("/repo/holt.yaml", "local"))
+}
+
+func TestLoadProfile_DeepMergesAgents(t *testing.T) {
+	path := writeProfileConfig(t, profileBaseConfig, map[string]string{"ci": `agents:
+  Coder:
+    image: registry.example.com/coder:ci
+    environment:
+      - MODEL=small
+      - CI=true
+  Reviewer: !reset
+  Linter:
+    image: linter:latest
+    command: [/app/lint.sh]
+    bidding_strategy: claim
+`})
+
+	cfg, err := LoadProfile(path, "ci")
+	require.NoError(t, err)
+	assert.Equal(t, "ci", cfg.Profile())
+
+	coder := cfg.Agents["Coder"]
+	assert.Equal(t, "registry.example.com/coder:ci", coder.Image)
+	assert.Equal(t, []string{"/app/run.sh"}, coder.Command, "unset overlay fields keep base values")
+	assert.Equal(t, []string{"GoalDefined"}, coder.Consumes)
+	assert.Equal(t, []string{"LOG_LEVEL=info", "MODEL=small", "CI=true"}, coder.Environment)
+
+	assert.NotContains(t, cfg.Agents, "Reviewer", "!reset deletes the agent")
+	assert.Contains(t, cfg.Agents, "Linter")
+	assert.Contains(t, cfg.Agents, "Tester")
+	require.NotNil(t, cfg.Orchestrator.MaxReviewIterations)
+	assert.Equal(t, 5, *cfg.Orchestrator.MaxReviewIterations)
+}
+
+func TestLoadProfile_Override(t *testing.T) {
+	path := writeProfileConfig(t, profileBaseConfig, map[string]string{"fast": `agents:
+  Tester:
+    health_check: !override
+      command: [/app/ping.sh]
+  Coder:
+    consumes: [SpecApproved]
+`})
+
+	cfg, err := LoadProfile(path, "fast")
+	require.NoError(t, err)
+
+	healthCheck := cfg.Agents["Tester"].HealthCheck
+	require.NotNil(t, healthCheck)
+	assert.Equal(t, []string{"/app/ping.sh"}, healthCheck.Command)
+	assert.Empty(t, healthCheck.Interval, "!override replaces the mapping instead of merging it")
+	assert.Equal(t, []string{"SpecApproved"}, cfg.Agents["Coder"].Consumes, "sequences are replaced")
+}
+
+func TestLoadProfile_AnchorsAreNotMutated(t *testing.T) {
+	base := `version: "1.0"
+agents:
+  Coder: &common
+    image: base:1
+    command: [/app/run.sh]
+    bidding_strategy: exclusive
+    environment: &env
+      - LOG_LEVEL=info
+  Reviewer: *common
+  Tester:
+    image: tester:latest
+    command: [/app/test.sh]
+    bidding_strategy: claim
+    environment: *env
+`
+	path := writeProfileConfig(t, base, map[string]string{"ci": `agents:
+  Coder:
+    image: ci:2
+    environment:
+      - LOG_LEVEL=debug
+`})
+
+	cfg, err := LoadProfile(path, "ci")
+	require.NoError(t, err)
+
+	assert.Equal(t, "ci:2", cfg.Agents["Coder"].Image)
+	assert.Equal(t, []string{"LOG_LEVEL=debug"}, cfg.Agents["Coder"].Environment)
+	assert.Equal(t, "base:1", cfg.Agents["Reviewer"].Image, "aliases of a merged anchor keep the base value")
+	assert.Equal(t, []string{"LOG_LEVEL=info"}, cfg.Agents["Reviewer"].Environment)
+	assert.Equal(t, []string{"LOG_LEVEL=info"}, cfg.Agents["Tester"].Environment)
+}
+
+func TestLoadProfile_InterpolatesAfterMerge(t *testing.T) {
+	t.Setenv("HOLT_TEST_CI_TAG", "build-42")
+
+	path := writeProfileConfig(t, profileBaseConfig, map[string]string{"ci": `agents:
+  Coder:
+    image: coder:${HOLT_TEST_CI_TAG}
+`})
+
+	cfg, err := LoadProfile(path, "ci")
+	require.NoError(t, err)
+	assert.Equal(t, "coder:build-42", cfg.Agents["Coder"].Image)
+	assert.Equal(t, []string{"HOLT_TEST_CI_TAG"}, cfg.Variables())
+}
+
+func TestLoadProfile_EmptyProfileLoadsBase(t *testing.T) {
+	path := writeProfileConfig(t, profileBaseConfig, nil)
+
+	cfg, err := LoadProfile(path, "")
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Profile())
+	assert.Len(t, cfg.Agents, 3)
+}
+
+func TestLoadProfile_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		profile       string
+		overlay       string
+		errorContains string
+	}{
+		{name: "missing overlay", profile: "staging", errorContains: "failed to read profile 'staging'"},
+		{name: "invalid profile name", profile: "../ci", errorContains: "invalid profile name"},
+		{name: "overlay not a mapping", profile: "ci", overlay: "- one\n- two\n", errorContains: "holt.ci.yml"},
+		{name: "merged result invalid", profile: "ci", overlay: "agents:\n  Coder:\n    bidding_strategy: sometimes\n", errorContains: "invalid configuration"},
+		{name: "all agents removed", profile: "ci", overlay: "agents: !reset\n", errorContains: "invalid configuration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overlays := map[string]string{}
+			if tt.overlay != "" {
+				overlays[tt.profile] = tt.overlay
+			}
+			path := writeProfileConfig(t, profileBaseConfig, overlays)
+
+			_, err := LoadProfile(path, tt.profile)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+}
+
+func TestDecode_RoundTripsEffectiveConfig(t *testing.T) {
+	t.Setenv("HOLT_TEST_CI_TAG", "build-42")
+	path := writeProfileConfig(t, profileBaseConfig+`  Shell:
+    image: shell:${HOLT_TEST_CI_TAG}
+    command: [sh, -c, "echo $${HOME}"]
+    bidding_strategy: claim
+`, nil)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	data, err := cfg.Marshal()
+	require.NoError(t, err)
+
+	decoded, err := Decode(data)
+	require.NoError(t, err)
+	assert.Equal(t, cfg.Agents, decoded.Agents)
+	assert.Equal(t, []string{"sh", "-c", "echo ${HOME}"}, decoded.Agents["Shell"].Command, "decoded values are not interpolated again")
+	assert.Equal(t, "shell:build-42", decoded.Agents["Shell"].Image)
+}
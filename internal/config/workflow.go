@@ -0,0 +1,437 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Workflow stage phases. Each maps to the bid type an agent must submit to act in the stage.
+const (
+	PhaseReview    = "review"
+	PhaseParallel  = "parallel"
+	PhaseExclusive = "exclusive"
+)
+
+// DefaultWorkflowTrigger is the artefact type that starts a workflow when trigger is omitted
+const DefaultWorkflowTrigger = "GoalDefined"
+
+// Workflow declares the shape of a pipeline: which roles act on which artefact types, in
+// which phase. A workflow applies to artefacts that are, or descend from, an artefact of a
+// trigger type. Within that lineage the orchestrator enforces the stages on top of bidding:
+// bids from roles that appear in the workflow are only granted when a stage allows them
+// (see Workflows.AllowsBid).
+//
+// Example:
+//
+//	workflows:
+//	  feature:
+//	    trigger: [GoalDefined]
+//	    stages:
+//	      - name: design
+//	        role: Designer
+//	        consumes: [GoalDefined]
+//	        produces: [DesignSpec]
+//	      - name: review-design
+//	        role: Reviewer
+//	        phase: review
+//	        consumes: [DesignSpec]
+//	      - name: build
+//	        role: Coder
+//	        consumes: [DesignSpec]
+//	        after: [design]
+//	        produces: [CodeCommit]
+type Workflow struct {
+	Description string          `yaml:"description,omitempty"`
+	Trigger     []string        `yaml:"trigger,omitempty"` // Artefact type globs that start the workflow (default: GoalDefined)
+	Stages      []WorkflowStage `yaml:"stages"`
+}
+
+// WorkflowStage is one step of a workflow.
+type WorkflowStage struct {
+	Name     string   `yaml:"name"`               // Unique within the workflow
+	Role     string   `yaml:"role"`               // Agent role (key in agents)
+	Consumes []string `yaml:"consumes"`           // Artefact type globs the stage acts on
+	Phase    string   `yaml:"phase,omitempty"`    // review, parallel or exclusive (default: exclusive)
+	Produces []string `yaml:"produces,omitempty"` // Artefact types the stage emits (drives the DAG)
+	After    []string `yaml:"after,omitempty"`    // Condition: only act on artefacts produced by these stages
+}
+
+// Workflows maps workflow names to their definitions.
+type Workflows map[string]Workflow
+
+// BidType returns the bid an agent submits to act in this stage's phase
+func (s *WorkflowStage) BidType() string {
+	switch s.phase() {
+	case PhaseReview:
+		return "review"
+	case PhaseParallel:
+		return "claim"
+	default:
+		return "exclusive"
+	}
+}
+
+// phase returns the stage phase with the default applied
+func (s *WorkflowStage) phase() string {
+	if s.Phase == "" {
+		return PhaseExclusive
+	}
+	return s.Phase
+}
+
+// triggers returns the workflow's entry types with the default applied
+func (w *Workflow) triggers() []string {
+	if len(w.Trigger) == 0 {
+		return []string{DefaultWorkflowTrigger}
+	}
+	return w.Trigger
+}
+
+// TriggeredBy reports whether the workflow applies to an artefact whose lineage (the
+// artefact and its ancestors) contains these types
+func (w *Workflow) TriggeredBy(lineageTypes []string) bool {
+	for _, artefactType := range lineageTypes {
+		if MatchArtefactType(w.triggers(), artefactType) {
+			return true
+		}
+	}
+	return false
+}
+
+// stage returns the named stage, or nil
+func (w *Workflow) stage(name string) *WorkflowStage {
+	for i := range w.Stages {
+		if w.Stages[i].Name == name {
+			return &w.Stages[i]
+		}
+	}
+	return nil
+}
+
+// validate checks a workflow's structure against the configured agents
+func (w *Workflow) validate(name string, agents map[string]Agent) error {
+	if len(w.Stages) == 0 {
+		return fmt.Errorf("workflow '%s' has no stages", name)
+	}
+
+	seen := make(map[string]bool)
+	for i, stage := range w.Stages {
+		if stage.Name == "" {
+			return fmt.Errorf("workflow '%s' stage %d: name is required", name, i+1)
+		}
+		if seen[stage.Name] {
+			return fmt.Errorf("workflow '%s': duplicate stage name '%s'", name, stage.Name)
+		}
+		seen[stage.Name] = true
+	}
+
+	for _, stage := range w.Stages {
+		prefix := fmt.Sprintf("workflow '%s' stage '%s'", name, stage.Name)
+		if stage.Role == "" {
+			return fmt.Errorf("%s: role is required", prefix)
+		}
+		if _, ok := agents[stage.Role]; !ok {
+			return fmt.Errorf("%s: role '%s' is not defined in agents", prefix, stage.Role)
+		}
+		if len(stage.Consumes) == 0 {
+			return fmt.Errorf("%s: consumes is required", prefix)
+		}
+		switch stage.Phase {
+		case "", PhaseReview, PhaseParallel, PhaseExclusive:
+		default:
+			return fmt.Errorf("%s: invalid phase '%s' (must be 'review', 'parallel' or 'exclusive')", prefix, stage.Phase)
+		}
+		for _, after := range stage.After {
+			if after == stage.Name {
+				return fmt.Errorf("%s: after cannot reference the stage itself", prefix)
+			}
+			if w.stage(after) == nil {
+				return fmt.Errorf("%s: after references unknown stage '%s'", prefix, after)
+			}
+		}
+	}
+
+	return nil
+}
+
+// acceptsFrom reports whether the stage's after condition admits an artefact of artefactType
+// produced by producerRole. Stages without after accept artefacts from any producer.
+func (w *Workflow) acceptsFrom(stage *WorkflowStage, producerRole, artefactType string) bool {
+	if len(stage.After) == 0 {
+		return true
+	}
+	for _, name := range stage.After {
+		upstream := w.stage(name)
+		if upstream == nil || upstream.Role != producerRole {
+			continue
+		}
+		if len(upstream.Produces) == 0 || MatchArtefactType(upstream.Produces, artefactType) {
+			return true
+		}
+	}
+	return false
+}
+
+// GovernsRole reports whether role appears in a stage of any workflow triggered for the
+// lineage. Bids from governed roles are only granted when a stage allows them; other roles
+// bid freely.
+func (ws Workflows) GovernsRole(role string, lineageTypes []string) bool {
+	for _, workflow := range ws {
+		if !workflow.TriggeredBy(lineageTypes) {
+			continue
+		}
+		for _, stage := range workflow.Stages {
+			if stage.Role == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AllowsBid reports whether a stage of any workflow triggered for the lineage lets role
+// submit bidType on an artefact of artefactType produced by producerRole. Returns the
+// allowing workflow and stage names.
+func (ws Workflows) AllowsBid(role, bidType, artefactType, producerRole string, lineageTypes []string) (workflow, stage string, ok bool) {
+	for _, name := range sortedWorkflowNames(ws) {
+		w := ws[name]
+		if !w.TriggeredBy(lineageTypes) {
+			continue
+		}
+		for i := range w.Stages {
+			s := &w.Stages[i]
+			if s.Role != role || s.BidType() != bidType {
+				continue
+			}
+			if !MatchArtefactType(s.Consumes, artefactType) {
+				continue
+			}
+			if !w.acceptsFrom(s, producerRole, artefactType) {
+				continue
+			}
+			return name, s.Name, true
+		}
+	}
+	return "", "", false
+}
+
+// edges returns the stage graph: stage name -> downstream stage names. A stage feeds another
+// when the downstream stage lists it in after, or (with no after) consumes a type it produces.
+// A stage that consumes a type it produces feeds itself, so self-loops show up as cycles.
+func (w *Workflow) edges() map[string][]string {
+	edges := make(map[string][]string)
+	for i := range w.Stages {
+		downstream := &w.Stages[i]
+		for j := range w.Stages {
+			upstream := &w.Stages[j]
+			if len(downstream.After) > 0 {
+				if containsString(downstream.After, upstream.Name) {
+					edges[upstream.Name] = append(edges[upstream.Name], downstream.Name)
+				}
+				continue
+			}
+			for _, produced := range upstream.Produces {
+				if MatchArtefactType(downstream.Consumes, produced) {
+					edges[upstream.Name] = append(edges[upstream.Name], downstream.Name)
+					break
+				}
+			}
+		}
+	}
+	return edges
+}
+
+// entryStages returns stages that act directly on the workflow's trigger types
+func (w *Workflow) entryStages() []string {
+	var entries []string
+	for _, stage := range w.Stages {
+		if len(stage.After) > 0 {
+			continue
+		}
+		for _, trigger := range w.triggers() {
+			if MatchArtefactType(stage.Consumes, trigger) {
+				entries = append(entries, stage.Name)
+				break
+			}
+		}
+	}
+	return entries
+}
+
+// Issues analyses the workflow graph and returns problems: stages no path from the trigger
+// reaches, cycles between stages, and stages whose agent's consumes exclude the stage's types
+// (so the agent never bids). Returns human-readable issues sorted for stable output.
+func (w *Workflow) Issues(name string, agents map[string]Agent) []string {
+	var issues []string
+	edges := w.edges()
+
+	// Reachability from the trigger
+	reached := make(map[string]bool)
+	queue := w.entryStages()
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if reached[current] {
+			continue
+		}
+		reached[current] = true
+		queue = append(queue, edges[current]...)
+	}
+	for _, stage := range w.Stages {
+		if !reached[stage.Name] {
+			issues = append(issues, fmt.Sprintf(
+				"workflow '%s': stage '%s' is unreachable (nothing from trigger %v leads to %v)",
+				name, stage.Name, w.triggers(), stage.Consumes))
+		}
+	}
+
+	// Cycles (reported once per cycle, starting from its alphabetically-first stage)
+	for _, cycle := range w.cycles(edges) {
+		issues = append(issues, fmt.Sprintf("workflow '%s': cycle between stages %s",
+			name, strings.Join(cycle, " -> ")))
+	}
+
+	// Stages the agent's own consumes filter would never bid on
+	for _, stage := range w.Stages {
+		agent, ok := agents[stage.Role]
+		if !ok || len(agent.Consumes) == 0 {
+			continue
+		}
+		for _, consumed := range stage.Consumes {
+			if !MatchArtefactType(agent.Consumes, consumed) {
+				issues = append(issues, fmt.Sprintf(
+					"workflow '%s': stage '%s' consumes '%s' but agent '%s' does not (consumes: %v)",
+					name, stage.Name, consumed, stage.Role, agent.Consumes))
+			}
+		}
+	}
+
+	sort.Strings(issues)
+	return issues
+}
+
+// cycles finds the distinct cycles in the stage graph
+func (w *Workflow) cycles(edges map[string][]string) [][]string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	var stack []string
+	var cycles [][]string
+	seen := make(map[string]bool)
+
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		stack = append(stack, name)
+
+		for _, next := range edges[name] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case visiting:
+				// Back edge: the cycle is the stack from next onwards
+				start := 0
+				for i, s := range stack {
+					if s == next {
+						start = i
+						break
+					}
+				}
+				cycle := rotateToMin(append([]string{}, stack[start:]...))
+				key := strings.Join(cycle, ",")
+				if !seen[key] {
+					seen[key] = true
+					cycles = append(cycles, append(cycle, cycle[0]))
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[name] = done
+	}
+
+	names := make([]string, 0, len(w.Stages))
+	for _, stage := range w.Stages {
+		names = append(names, stage.Name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+	return cycles
+}
+
+// rotateToMin rotates a cycle so it starts at its alphabetically-first stage
+func rotateToMin(cycle []string) []string {
+	min := 0
+	for i := range cycle {
+		if cycle[i] < cycle[min] {
+			min = i
+		}
+	}
+	return append(cycle[min:], cycle[:min]...)
+}
+
+// Order returns the workflow's stages in dependency order (entry stages first). Stages in
+// cycles or unreachable from the trigger are appended in declaration order.
+func (w *Workflow) Order() []WorkflowStage {
+	edges := w.edges()
+	indegree := make(map[string]int)
+	for _, targets := range edges {
+		for _, target := range targets {
+			indegree[target]++
+		}
+	}
+
+	var ordered []WorkflowStage
+	placed := make(map[string]bool)
+	for {
+		progressed := false
+		for _, stage := range w.Stages {
+			if placed[stage.Name] || indegree[stage.Name] > 0 {
+				continue
+			}
+			ordered = append(ordered, stage)
+			placed[stage.Name] = true
+			progressed = true
+			for _, target := range edges[stage.Name] {
+				indegree[target]--
+			}
+		}
+		if !progressed {
+			break
+		}
+	}
+	for _, stage := range w.Stages {
+		if !placed[stage.Name] {
+			ordered = append(ordered, stage)
+		}
+	}
+	return ordered
+}
+
+// WorkflowIssues returns the graph issues of every workflow, sorted
+func (c *HoltConfig) WorkflowIssues() []string {
+	var issues []string
+	for _, name := range sortedWorkflowNames(c.Workflows) {
+		w := c.Workflows[name]
+		issues = append(issues, w.Issues(name, c.Agents)...)
+	}
+	return issues
+}
+
+// sortedWorkflowNames returns workflow names in name order
+func sortedWorkflowNames(ws Workflows) []string {
+	names := make([]string, 0, len(ws))
+	for name := range ws {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func workflowTestConfig() *HoltConfig {
+	agent := Agent{Image: "test:latest", Command: []string{"/app/run.sh"}, BiddingStrategy: "exclusive"}
+	return &HoltConfig{
+		Version: "1.0",
+		Agents: map[string]Agent{
+			"Designer": agent,
+			"Reviewer": agent,
+			"Coder":    agent,
+		},
+		Workflows: Workflows{
+			"feature": {
+				Stages: []WorkflowStage{
+					{Name: "design", Role: "Designer", Consumes: []string{"GoalDefined"}, Produces: []string{"DesignSpec"}},
+					{Name: "review-design", Role: "Reviewer", Phase: PhaseReview, Consumes: []string{"DesignSpec"}},
+					{Name: "build", Role: "Coder", Consumes: []string{"DesignSpec"}, After: []string{"design"}, Produces: []string{"CodeCommit"}},
+				},
+			},
+		},
+	}
+}
+
+func TestWorkflow_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		mutate        func(w *Workflow)
+		errorContains string
+	}{
+		{
+			name:          "no stages",
+			mutate:        func(w *Workflow) { w.Stages = nil },
+			errorContains: "workflow 'feature' has no stages",
+		},
+		{
+			name:          "missing name",
+			mutate:        func(w *Workflow) { w.Stages[1].Name = "" },
+			errorContains: "stage 2: name is required",
+		},
+		{
+			name:          "duplicate name",
+			mutate:        func(w *Workflow) { w.Stages[2].Name = "design" },
+			errorContains: "duplicate stage name 'design'",
+		},
+		{
+			name:          "unknown role",
+			mutate:        func(w *Workflow) { w.Stages[0].Role = "Tester" },
+			errorContains: "role 'Tester' is not defined in agents",
+		},
+		{
+			name:          "missing consumes",
+			mutate:        func(w *Workflow) { w.Stages[0].Consumes = nil },
+			errorContains: "stage 'design': consumes is required",
+		},
+		{
+			name:          "invalid phase",
+			mutate:        func(w *Workflow) { w.Stages[0].Phase = "sometimes" },
+			errorContains: "invalid phase 'sometimes'",
+		},
+		{
+			name:          "after unknown stage",
+			mutate:        func(w *Workflow) { w.Stages[2].After = []string{"plan"} },
+			errorContains: "after references unknown stage 'plan'",
+		},
+		{
+			name:          "after itself",
+			mutate:        func(w *Workflow) { w.Stages[2].After = []string{"build"} },
+			errorContains: "after cannot reference the stage itself",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := workflowTestConfig()
+			workflow := cfg.Workflows["feature"]
+			tt.mutate(&workflow)
+			cfg.Workflows["feature"] = workflow
+
+			err := cfg.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, workflowTestConfig().Validate())
+	})
+}
+
+func TestWorkflows_AllowsBid(t *testing.T) {
+	workflows := workflowTestConfig().Workflows
+
+	tests := []struct {
+		name         string
+		role         string
+		bidType      string
+		artefactType string
+		producer     string
+		allowed      bool
+		stage        string
+	}{
+		{"entry stage", "Designer", "exclusive", "GoalDefined", "user", true, "design"},
+		{"wrong phase", "Designer", "review", "GoalDefined", "user", false, ""},
+		{"wrong type", "Designer", "exclusive", "DesignSpec", "Designer", false, ""},
+		{"review stage", "Reviewer", "review", "DesignSpec", "Designer", true, "review-design"},
+		{"after satisfied", "Coder", "exclusive", "DesignSpec", "Designer", true, "build"},
+		{"after not satisfied", "Coder", "exclusive", "DesignSpec", "Reviewer", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workflow, stage, ok := workflows.AllowsBid(tt.role, tt.bidType, tt.artefactType, tt.producer, []string{"GoalDefined", tt.artefactType})
+			assert.Equal(t, tt.allowed, ok)
+			assert.Equal(t, tt.stage, stage)
+			if ok {
+				assert.Equal(t, "feature", workflow)
+			}
+		})
+	}
+
+	assert.True(t, workflows.GovernsRole("Coder", []string{"GoalDefined"}))
+	assert.False(t, workflows.GovernsRole("Packager", []string{"GoalDefined"}))
+
+	// Outside a triggered lineage the workflow does not apply
+	assert.False(t, workflows.GovernsRole("Coder", []string{"BugReport"}))
+	_, _, ok := workflows.AllowsBid("Designer", "exclusive", "GoalDefined", "user", []string{"BugReport"})
+	assert.False(t, ok)
+}
+
+func TestWorkflow_TriggeredBy(t *testing.T) {
+	workflow := Workflow{Trigger: []string{"Bug*"}}
+	assert.True(t, workflow.TriggeredBy([]string{"BugReport", "CodeCommit"}))
+	assert.False(t, workflow.TriggeredBy([]string{"GoalDefined"}))
+
+	// Trigger defaults to GoalDefined
+	workflow.Trigger = nil
+	assert.True(t, workflow.TriggeredBy([]string{"GoalDefined"}))
+	assert.False(t, workflow.TriggeredBy(nil))
+}
+
+func TestWorkflow_Issues(t *testing.T) {
+	t.Run("clean workflow", func(t *testing.T) {
+		cfg := workflowTestConfig()
+		assert.Empty(t, cfg.WorkflowIssues())
+	})
+
+	t.Run("unreachable stage", func(t *testing.T) {
+		cfg := workflowTestConfig()
+		workflow := cfg.Workflows["feature"]
+		workflow.Stages = append(workflow.Stages, WorkflowStage{
+			Name: "package", Role: "Coder", Consumes: []string{"TestReport"},
+		})
+		cfg.Workflows["feature"] = workflow
+
+		issues := cfg.WorkflowIssues()
+		require.Len(t, issues, 1)
+		assert.Contains(t, issues[0], "stage 'package' is unreachable")
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		cfg := workflowTestConfig()
+		workflow := cfg.Workflows["feature"]
+		workflow.Stages[1].Produces = []string{"ReworkRequest"}
+		workflow.Stages[0].Consumes = []string{"GoalDefined", "ReworkRequest"}
+		cfg.Workflows["feature"] = workflow
+
+		issues := cfg.WorkflowIssues()
+		require.Len(t, issues, 1)
+		assert.Contains(t, issues[0], "cycle between stages design -> review-design -> design")
+	})
+
+	t.Run("self-loop", func(t *testing.T) {
+		cfg := workflowTestConfig()
+		workflow := cfg.Workflows["feature"]
+		workflow.Stages = append(workflow.Stages, WorkflowStage{
+			Name: "refactor", Role: "Coder", Consumes: []string{"CodeCommit"}, Produces: []string{"CodeCommit"},
+		})
+		cfg.Workflows["feature"] = workflow
+
+		issues := cfg.WorkflowIssues()
+		require.Len(t, issues, 1)
+		assert.Contains(t, issues[0], "cycle between stages refactor -> refactor")
+	})
+
+	t.Run("agent consumes excludes stage", func(t *testing.T) {
+		cfg := workflowTestConfig()
+		coder := cfg.Agents["Coder"]
+		coder.Consumes = []string{"TestReport"}
+		cfg.Agents["Coder"] = coder
+
+		issues := cfg.WorkflowIssues()
+		require.Len(t, issues, 1)
+		assert.Contains(t, issues[0], "stage 'build' consumes 'DesignSpec' but agent 'Coder' does not")
+	})
+}
+
+func TestWorkflow_Order(t *testing.T) {
+	workflow := workflowTestConfig().Workflows["feature"]
+	workflow.Stages = []WorkflowStage{workflow.Stages[2], workflow.Stages[1], workflow.Stages[0]}
+
+	var names []string
+	for _, stage := range workflow.Order() {
+		names = append(names, stage.Name)
+	}
+	assert.Equal(t, []string{"design", "build", "review-design"}, names)
+}
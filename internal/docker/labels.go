@@ -14,8 +14,11 @@ const (
 	LabelWorkspacePath = "holt.workspace.path"
 	LabelComponent     = "holt.component"
 	LabelRedisPort     = "holt.redis.port"
-	LabelAgentName     = "holt.agent.name" // M2.2: Agent name label
-	LabelAgentRole     = "holt.agent.role" // M3.6: Agent role label
+	LabelAgentName     = "holt.agent.name"        // M2.2: Agent name label
+	LabelAgentRole     = "holt.agent.role"        // M3.6: Agent role label
+	LabelClaimID       = "holt.claim.id"          // Claim executed by a worker container
+	LabelProfile       = "holt.profile"           // Config profile the instance was started with
+	LabelConfigHash    = "holt.agent.config_hash" // Hash of the agent's config and resolved environment
 )
 
 // BuildLabels creates the standard label set for all Holt resources.
@@ -58,6 +61,15 @@ func OrchestratorContainerName(instanceName string) string {
 	return fmt.Sprintf("holt-orchestrator-%s", instanceName)
 }
 
+// OrchestratorReplicaContainerName returns the container name for an orchestrator replica
+// (numbered from 1). The first replica keeps the plain orchestrator name.
+func OrchestratorReplicaContainerName(instanceName string, replica int) string {
+	if replica <= 1 {
+		return OrchestratorContainerName(instanceName)
+	}
+	return fmt.Sprintf("holt-orchestrator-%s-%d", instanceName, replica)
+}
+
 // AgentContainerName returns the agent container name for an instance and agent
 // M3.7: Now uses role-based naming (agentRole = agent key from holt.yml)
 func AgentContainerName(instanceName, agentRole string) string {
@@ -99,3 +99,19 @@ func TestOrchestratorContainerName(t *testing.T) {
 		assert.Equal(t, tc.expected, result)
 	}
 }
+
+func TestOrchestratorReplicaContainerName(t *testing.T) {
+	testCases := []struct {
+		replica  int
+		expected string
+	}{
+		{1, "holt-orchestrator-prod"},
+		{2, "holt-orchestrator-prod-2"},
+		{3, "holt-orchestrator-prod-3"},
+	}
+
+	for _, tc := range testCases {
+		result := OrchestratorReplicaContainerName("prod", tc.replica)
+		assert.Equal(t, tc.expected, result)
+	}
+}
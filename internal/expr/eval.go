@@ -0,0 +1,461 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// node is a syntax tree node
+type node interface {
+	eval(vars map[string]interface{}) (interface{}, error)
+}
+
+type literalNode struct{ value interface{} }
+
+type identNode struct{ name string }
+
+type selectNode struct {
+	operand node
+	field   string
+}
+
+type indexNode struct {
+	operand node
+	index   node
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+type logicalNode struct {
+	op          string
+	left, right node
+}
+
+type ternaryNode struct{ cond, then, otherwise node }
+
+type listNode struct{ items []node }
+
+type hasNode struct{ target node }
+
+// callNode is a function or method call; target is the receiver (or size()'s argument)
+type callNode struct {
+	name   string
+	target node
+	args   []node
+}
+
+func (n *literalNode) eval(map[string]interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+func (n *identNode) eval(vars map[string]interface{}) (interface{}, error) {
+	v, ok := vars[n.name]
+	if !ok {
+		return nil, fmt.Errorf("undeclared reference to '%s'", n.name)
+	}
+	return normalize(v), nil
+}
+
+func (n *selectNode) eval(vars map[string]interface{}) (interface{}, error) {
+	operand, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := operand.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cannot select field '%s' from %s", n.field, typeName(operand))
+	}
+	v, ok := m[n.field]
+	if !ok {
+		return nil, fmt.Errorf("no such key: %s", n.field)
+	}
+	return normalize(v), nil
+}
+
+func (n *indexNode) eval(vars map[string]interface{}) (interface{}, error) {
+	operand, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	index, err := n.index.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	v, found, err := lookup(operand, index)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("no such key: %v", index)
+	}
+	return normalize(v), nil
+}
+
+func (n *hasNode) eval(vars map[string]interface{}) (interface{}, error) {
+	switch target := n.target.(type) {
+	case *selectNode:
+		operand, err := target.operand.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		m, ok := operand.(map[string]interface{})
+		if !ok {
+			return false, nil
+		}
+		_, found := m[target.field]
+		return found, nil
+	case *indexNode:
+		operand, err := target.operand.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		index, err := target.index.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		_, found, err := lookup(operand, index)
+		if err != nil {
+			return false, nil
+		}
+		return found, nil
+	}
+	return nil, fmt.Errorf("invalid has() argument")
+}
+
+// lookup indexes a map by string key or a list by integral position
+func lookup(operand, index interface{}) (interface{}, bool, error) {
+	switch container := operand.(type) {
+	case map[string]interface{}:
+		key, ok := index.(string)
+		if !ok {
+			return nil, false, fmt.Errorf("map index must be a string, got %s", typeName(index))
+		}
+		v, found := container[key]
+		return v, found, nil
+	case []interface{}:
+		f, ok := index.(float64)
+		if !ok || f != math.Trunc(f) {
+			return nil, false, fmt.Errorf("list index must be an integer, got %v", index)
+		}
+		i := int(f)
+		if i < 0 || i >= len(container) {
+			return nil, false, nil
+		}
+		return container[i], true, nil
+	}
+	return nil, false, fmt.Errorf("cannot index %s", typeName(operand))
+}
+
+func (n *unaryNode) eval(vars map[string]interface{}) (interface{}, error) {
+	operand, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "!":
+		b, ok := operand.(bool)
+		if !ok {
+			return nil, fmt.Errorf("operator ! requires bool, got %s", typeName(operand))
+		}
+		return !b, nil
+	default:
+		f, ok := operand.(float64)
+		if !ok {
+			return nil, fmt.Errorf("operator - requires number, got %s", typeName(operand))
+		}
+		return -f, nil
+	}
+}
+
+func (n *logicalNode) eval(vars map[string]interface{}) (interface{}, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	l, ok := left.(bool)
+	if !ok {
+		return nil, fmt.Errorf("operator %s requires bool operands, got %s", n.op, typeName(left))
+	}
+
+	// Short-circuit so guards like has(payload.x) && payload.x > 1 work
+	if (n.op == "&&" && !l) || (n.op == "||" && l) {
+		return l, nil
+	}
+
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := right.(bool)
+	if !ok {
+		return nil, fmt.Errorf("operator %s requires bool operands, got %s", n.op, typeName(right))
+	}
+	return r, nil
+}
+
+func (n *ternaryNode) eval(vars map[string]interface{}) (interface{}, error) {
+	cond, err := n.cond.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := cond.(bool)
+	if !ok {
+		return nil, fmt.Errorf("condition of ?: must be bool, got %s", typeName(cond))
+	}
+	if b {
+		return n.then.eval(vars)
+	}
+	return n.otherwise.eval(vars)
+}
+
+func (n *listNode) eval(vars map[string]interface{}) (interface{}, error) {
+	items := make([]interface{}, 0, len(n.items))
+	for _, item := range n.items {
+		v, err := item.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+	}
+	return items, nil
+}
+
+func (n *binaryNode) eval(vars map[string]interface{}) (interface{}, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	case "in":
+		switch container := right.(type) {
+		case []interface{}:
+			for _, item := range container {
+				if equal(left, item) {
+					return true, nil
+				}
+			}
+			return false, nil
+		case map[string]interface{}:
+			key, ok := left.(string)
+			if !ok {
+				return nil, fmt.Errorf("map key must be a string, got %s", typeName(left))
+			}
+			_, found := container[key]
+			return found, nil
+		}
+		return nil, fmt.Errorf("operator in requires list or map, got %s", typeName(right))
+	case "<", "<=", ">", ">=":
+		return compare(n.op, left, right)
+	}
+
+	// Arithmetic; + also concatenates strings and lists
+	if n.op == "+" {
+		switch l := left.(type) {
+		case string:
+			if r, ok := right.(string); ok {
+				return l + r, nil
+			}
+		case []interface{}:
+			if r, ok := right.([]interface{}); ok {
+				return append(append([]interface{}{}, l...), r...), nil
+			}
+		}
+	}
+	l, lok := left.(float64)
+	r, rok := right.(float64)
+	if !lok || !rok {
+		return nil, fmt.Errorf("operator %s not supported for %s and %s", n.op, typeName(left), typeName(right))
+	}
+	switch n.op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return l / r, nil
+	default:
+		if r == 0 {
+			return nil, fmt.Errorf("modulus by zero")
+		}
+		return math.Mod(l, r), nil
+	}
+}
+
+// compare orders two numbers or two strings
+func compare(op string, left, right interface{}) (bool, error) {
+	var c int
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return false, fmt.Errorf("cannot compare number with %s", typeName(right))
+		}
+		switch {
+		case l < r:
+			c = -1
+		case l > r:
+			c = 1
+		}
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return false, fmt.Errorf("cannot compare string with %s", typeName(right))
+		}
+		c = strings.Compare(l, r)
+	default:
+		return false, fmt.Errorf("operator %s not supported for %s", op, typeName(left))
+	}
+
+	switch op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	default:
+		return c >= 0, nil
+	}
+}
+
+func (n *callNode) eval(vars map[string]interface{}) (interface{}, error) {
+	target, err := n.target.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.name == "size" {
+		if len(n.args) != 0 {
+			return nil, fmt.Errorf("size() takes no arguments")
+		}
+		switch v := target.(type) {
+		case string:
+			return float64(len([]rune(v))), nil
+		case []interface{}:
+			return float64(len(v)), nil
+		case map[string]interface{}:
+			return float64(len(v)), nil
+		}
+		return nil, fmt.Errorf("size() not supported for %s", typeName(target))
+	}
+
+	s, ok := target.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s() requires a string receiver, got %s", n.name, typeName(target))
+	}
+	if len(n.args) != 1 {
+		return nil, fmt.Errorf("%s() takes exactly one argument", n.name)
+	}
+	argValue, err := n.args[0].eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	arg, ok := argValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s() argument must be a string, got %s", n.name, typeName(argValue))
+	}
+
+	switch n.name {
+	case "contains":
+		return strings.Contains(s, arg), nil
+	case "startsWith":
+		return strings.HasPrefix(s, arg), nil
+	case "endsWith":
+		return strings.HasSuffix(s, arg), nil
+	case "matches":
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", arg, err)
+		}
+		return re.MatchString(s), nil
+	}
+	return nil, fmt.Errorf("unknown method '%s'", n.name)
+}
+
+// equal compares JSON-shaped values structurally
+func equal(a, b interface{}) bool {
+	switch av := a.(type) {
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equal(normalize(av[i]), normalize(bv[i])) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			other, found := bv[k]
+			if !found || !equal(normalize(v), normalize(other)) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+// normalize converts Go numeric types to float64 so comparisons match JSON numbers
+func normalize(v interface{}) interface{} {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float32:
+		return float64(n)
+	}
+	return v
+}
+
+// typeName names a value's type for error messages
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "map"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,445 @@
+// Package expr implements a small, sandboxed expression language for routing decisions.
+//
+// The syntax is a subset of CEL (Common Expression Language):
+//
+//	payload.risk > 7 && artefact.produced_by_role == "Designer"
+//	has(payload.tags) && "security" in payload.tags
+//	artefact.type.startsWith("Design") || size(context) > 3
+//	payload.service.matches("^auth-") ? true : payload.severity >= 5
+//
+// Supported: number, string, bool, null and list literals; field selection (a.b),
+// indexing (a["b"], a[0]); ! - * / % + - < <= > >= == != in && || and ?:; the functions
+// size(x) and has(a.b); and the string methods contains, startsWith, endsWith and matches
+// (RE2 syntax). Numbers are float64, matching JSON.
+//
+// Expressions cannot loop, call out, or mutate anything, and their size is bounded, so
+// evaluation always terminates quickly. Type mismatches and missing fields are evaluation
+// errors rather than silently false; guard optional fields with has().
+package expr
+
+import (
+	"fmt"
+)
+
+const (
+	// MaxSourceLength bounds the length of an expression
+	MaxSourceLength = 4096
+
+	// MaxNodes bounds the number of syntax nodes in an expression
+	MaxNodes = 512
+)
+
+// Program is a compiled expression, safe for concurrent evaluation.
+type Program struct {
+	source string
+	root   node
+	idents map[string]bool
+}
+
+// Compile parses src. If variables are given, references to any other top-level name are
+// rejected so that typos fail at configuration time rather than on every evaluation.
+func Compile(src string, variables ...string) (*Program, error) {
+	if len(src) > MaxSourceLength {
+		return nil, fmt.Errorf("expression too long (%d bytes, max %d)", len(src), MaxSourceLength)
+	}
+
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, idents: make(map[string]bool)}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at offset %d", tok.text, tok.pos)
+	}
+
+	if len(variables) > 0 {
+		declared := make(map[string]bool, len(variables))
+		for _, v := range variables {
+			declared[v] = true
+		}
+		for ident := range p.idents {
+			if !declared[ident] {
+				return nil, fmt.Errorf("undeclared reference to '%s' (available: %v)", ident, variables)
+			}
+		}
+	}
+
+	return &Program{source: src, root: root, idents: p.idents}, nil
+}
+
+// String returns the expression source
+func (p *Program) String() string {
+	return p.source
+}
+
+// References reports whether the expression refers to the top-level variable name.
+// Callers use it to skip building expensive variables the expression never reads.
+func (p *Program) References(name string) bool {
+	return p.idents[name]
+}
+
+// Eval evaluates the expression against vars. Values should be JSON-shaped:
+// nil, bool, float64 (other numeric types are converted), string,
+// []interface{} and map[string]interface{}.
+func (p *Program) Eval(vars map[string]interface{}) (interface{}, error) {
+	return p.root.eval(vars)
+}
+
+// EvalBool evaluates the expression and requires a bool result.
+func (p *Program) EvalBool(vars map[string]interface{}) (bool, error) {
+	v, err := p.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression must evaluate to bool, got %s", typeName(v))
+	}
+	return b, nil
+}
+
+// parser is a recursive-descent parser over the token stream.
+// Precedence, lowest first: ?: then || then && then comparisons (including in),
+// then + -, then * / %, then unary ! -, then selection, indexing and calls.
+type parser struct {
+	tokens []token
+	pos    int
+	nodes  int
+	idents map[string]bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is the operator op
+func (p *parser) accept(op string) bool {
+	if tok := p.peek(); tok.kind == tokOp && tok.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.accept(op) {
+		tok := p.peek()
+		if tok.kind == tokEOF {
+			return fmt.Errorf("expected %q at end of expression", op)
+		}
+		return fmt.Errorf("expected %q at offset %d, got %q", op, tok.pos, tok.text)
+	}
+	return nil
+}
+
+// count enforces MaxNodes
+func (p *parser) count(n node) (node, error) {
+	p.nodes++
+	if p.nodes > MaxNodes {
+		return nil, fmt.Errorf("expression too complex (more than %d nodes)", MaxNodes)
+	}
+	return n, nil
+}
+
+func (p *parser) parseExpr() (node, error) {
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept("?") {
+		return cond, nil
+	}
+	then, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return p.count(&ternaryNode{cond: cond, then: then, otherwise: otherwise})
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.count(&logicalNode{op: "||", left: left, right: right}); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseRelation()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseRelation()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.count(&logicalNode{op: "&&", left: left, right: right}); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseRelation() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	var op string
+	switch {
+	case tok.kind == tokOp && (tok.text == "==" || tok.text == "!=" || tok.text == "<" ||
+		tok.text == "<=" || tok.text == ">" || tok.text == ">="):
+		op = tok.text
+	case tok.kind == tokIdent && tok.text == "in":
+		op = "in"
+	default:
+		return left, nil
+	}
+	p.next()
+
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return p.count(&binaryNode{op: op, left: left, right: right})
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokOp || (tok.text != "+" && tok.text != "-") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.count(&binaryNode{op: tok.text, left: left, right: right}); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokOp || (tok.text != "*" && tok.text != "/" && tok.text != "%") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.count(&binaryNode{op: tok.text, left: left, right: right}); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	tok := p.peek()
+	if tok.kind == tokOp && (tok.text == "!" || tok.text == "-") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return p.count(&unaryNode{op: tok.text, operand: operand})
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("."):
+			tok := p.next()
+			if tok.kind != tokIdent {
+				return nil, fmt.Errorf("expected field name after '.' at offset %d", tok.pos)
+			}
+			if p.accept("(") {
+				args, err := p.parseArgs()
+				if err != nil {
+					return nil, err
+				}
+				n, err = p.count(&callNode{name: tok.text, target: n, args: args})
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if n, err = p.count(&selectNode{operand: n, field: tok.text}); err != nil {
+				return nil, err
+			}
+
+		case p.accept("["):
+			index, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			if n, err = p.count(&indexNode{operand: n, index: index}); err != nil {
+				return nil, err
+			}
+
+		default:
+			return n, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		return p.count(&literalNode{value: tok.num})
+
+	case tokString:
+		return p.count(&literalNode{value: tok.text})
+
+	case tokIdent:
+		switch tok.text {
+		case "true":
+			return p.count(&literalNode{value: true})
+		case "false":
+			return p.count(&literalNode{value: false})
+		case "null":
+			return p.count(&literalNode{value: nil})
+		}
+		if p.accept("(") {
+			args, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			return p.parseGlobalCall(tok, args)
+		}
+		p.idents[tok.text] = true
+		return p.count(&identNode{name: tok.text})
+
+	case tokOp:
+		switch tok.text {
+		case "(":
+			inner, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+		case "[":
+			var items []node
+			if !p.accept("]") {
+				for {
+					item, err := p.parseExpr()
+					if err != nil {
+						return nil, err
+					}
+					items = append(items, item)
+					if p.accept("]") {
+						break
+					}
+					if err := p.expect(","); err != nil {
+						return nil, err
+					}
+				}
+			}
+			return p.count(&listNode{items: items})
+		}
+		return nil, fmt.Errorf("unexpected %q at offset %d", tok.text, tok.pos)
+
+	default:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+}
+
+// parseArgs parses call arguments after the opening parenthesis
+func (p *parser) parseArgs() ([]node, error) {
+	var args []node
+	if p.accept(")") {
+		return args, nil
+	}
+	for {
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if p.accept(")") {
+			return args, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseGlobalCall builds a call to size() or the has() macro
+func (p *parser) parseGlobalCall(tok token, args []node) (node, error) {
+	switch tok.text {
+	case "has":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("has() takes exactly one argument")
+		}
+		switch args[0].(type) {
+		case *selectNode, *indexNode:
+		default:
+			return nil, fmt.Errorf("has() argument must be a field selection like has(payload.risk)")
+		}
+		return p.count(&hasNode{target: args[0]})
+	case "size":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("size() takes exactly one argument")
+		}
+		return p.count(&callNode{name: "size", target: args[0]})
+	}
+	return nil, fmt.Errorf("unknown function '%s' at offset %d", tok.text, tok.pos)
+}
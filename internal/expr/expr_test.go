@@ -0,0 +1,152 @@
+package expr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testVars() map[string]interface{} {
+	return map[string]interface{}{
+		"artefact": map[string]interface{}{
+			"type":             "DesignSpec",
+			"produced_by_role": "Designer",
+			"version":          2,
+		},
+		"payload": map[string]interface{}{
+			"risk":    float64(8),
+			"service": "auth-gateway",
+			"tags":    []interface{}{"security", "api"},
+			"owner":   nil,
+		},
+		"context": []interface{}{
+			map[string]interface{}{"type": "GoalDefined"},
+		},
+	}
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected interface{}
+	}{
+		{`payload.risk > 7`, true},
+		{`payload.risk > 7 && artefact.produced_by_role == "Designer"`, true},
+		{`payload.risk >= 9 || artefact.type == 'DesignSpec'`, true},
+		{`!(payload.risk < 5)`, true},
+		{`"security" in payload.tags`, true},
+		{`"risk" in payload`, true},
+		{`"ops" in payload.tags`, false},
+		{`has(payload.risk)`, true},
+		{`has(payload.missing)`, false},
+		{`has(payload.missing) && payload.missing > 1`, false},
+		{`payload.tags[0] == "security"`, true},
+		{`payload["service"].startsWith("auth-")`, true},
+		{`payload.service.matches("^auth-(gateway|api)$")`, true},
+		{`artefact.type.endsWith("Spec") && artefact.type.contains("sign")`, true},
+		{`size(payload.tags) == 2 && payload.tags.size() == 2`, true},
+		{`size(context) > 0 && context[0].type == "GoalDefined"`, true},
+		{`artefact.version == 2`, true},
+		{`payload.owner == null`, true},
+		{`payload.risk * 2 - 1`, float64(15)},
+		{`payload.risk % 3`, float64(2)},
+		{`-payload.risk`, float64(-8)},
+		{`"a" + "b"`, "ab"},
+		{`[1, 2] + [3] == [1, 2, 3]`, true},
+		{`payload.risk > 5 ? "high" : "low"`, "high"},
+		{`payload.tags == ["security", "api"]`, true},
+		{`1 == "1"`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			program, err := Compile(tt.expr)
+			require.NoError(t, err)
+			result, err := program.Eval(testVars())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestEval_Errors(t *testing.T) {
+	tests := []struct {
+		expr          string
+		errorContains string
+	}{
+		{`payload.missing > 1`, "no such key: missing"},
+		{`payload.service > 1`, "cannot compare string with number"},
+		{`payload.risk && true`, "operator && requires bool operands, got number"},
+		{`payload.risk.value`, "cannot select field 'value' from number"},
+		{`payload.tags[5]`, "no such key: 5"},
+		{`payload.risk / 0`, "division by zero"},
+		{`payload.service.matches("(")`, "invalid regular expression"},
+		{`payload.risk.startsWith("x")`, "startsWith() requires a string receiver"},
+		{`1 in "abc"`, "operator in requires list or map"},
+		{`secrets.token`, "undeclared reference to 'secrets'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			program, err := Compile(tt.expr)
+			require.NoError(t, err)
+			_, err = program.Eval(testVars())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+}
+
+func TestEvalBool(t *testing.T) {
+	program, err := Compile(`payload.risk`)
+	require.NoError(t, err)
+	_, err = program.EvalBool(testVars())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must evaluate to bool, got number")
+
+	program, err = Compile(`payload.risk > 7`)
+	require.NoError(t, err)
+	ok, err := program.EvalBool(testVars())
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		expr          string
+		errorContains string
+	}{
+		{`payload.risk >`, "unexpected end of expression"},
+		{`(payload.risk > 7`, `expected ")" at end of expression`},
+		{`payload.risk > 7 7`, `unexpected "7" at offset 17`},
+		{`"unterminated`, "unterminated string"},
+		{`payload.risk # 1`, "unexpected character '#'"},
+		{`exec("rm -rf /")`, "unknown function 'exec'"},
+		{`has(payload)`, "has() argument must be a field selection"},
+		{`size(1, 2)`, "size() takes exactly one argument"},
+		{strings.Repeat("a", MaxSourceLength+1), "expression too long"},
+		{strings.Repeat("-", MaxNodes) + "1", "expression too complex"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr[:min(len(tt.expr), 40)], func(t *testing.T) {
+			_, err := Compile(tt.expr)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+}
+
+func TestCompile_DeclaredVariables(t *testing.T) {
+	program, err := Compile(`payload.risk > 7 && size(context) > 0`, "artefact", "payload", "context")
+	require.NoError(t, err)
+	assert.True(t, program.References("payload"))
+	assert.True(t, program.References("context"))
+	assert.False(t, program.References("artefact"))
+
+	_, err = Compile(`paylod.risk > 7`, "artefact", "payload", "context")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "undeclared reference to 'paylod'")
+}
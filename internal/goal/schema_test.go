@@ -0,0 +1,130 @@
+package goal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const moduleSchema = `
+type: object
+required: [module, owner]
+additionalProperties: false
+properties:
+  module:
+    type: string
+    pattern: "^[a-z][a-z0-9-]*$"
+    maxLength: 20
+  owner:
+    enum: [platform, payments]
+  replicas:
+    type: integer
+    minimum: 1
+    maximum: 5
+  tags:
+    type: array
+    minItems: 1
+    items: {type: string}
+`
+
+func loadTestSchema(t *testing.T, src string) map[string]interface{} {
+	t.Helper()
+	var schema map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(src), &schema))
+	return schema
+}
+
+func TestValidatePayload(t *testing.T) {
+	schema := loadTestSchema(t, moduleSchema)
+
+	tests := []struct {
+		name    string
+		payload string
+		want    []string
+	}{
+		{
+			name:    "valid",
+			payload: `{"module": "billing", "owner": "payments", "replicas": 3, "tags": ["go"]}`,
+		},
+		{
+			name:    "missing required",
+			payload: `{"module": "billing"}`,
+			want:    []string{`$: missing required property "owner"`},
+		},
+		{
+			name:    "wrong root type",
+			payload: `["billing"]`,
+			want:    []string{"$: expected object, got array"},
+		},
+		{
+			name:    "enum and pattern",
+			payload: `{"module": "Billing", "owner": "ops"}`,
+			want: []string{
+				`$.module: must match pattern "^[a-z][a-z0-9-]*$"`,
+				`$.owner: must be one of "platform", "payments"`,
+			},
+		},
+		{
+			name:    "integer bounds",
+			payload: `{"module": "billing", "owner": "platform", "replicas": 9}`,
+			want:    []string{"$.replicas: must be <= 5"},
+		},
+		{
+			name:    "integer type",
+			payload: `{"module": "billing", "owner": "platform", "replicas": 1.5}`,
+			want:    []string{"$.replicas: expected integer, got number"},
+		},
+		{
+			name:    "array items",
+			payload: `{"module": "billing", "owner": "platform", "tags": ["go", 3]}`,
+			want:    []string{"$.tags[1]: expected string, got integer"},
+		},
+		{
+			name:    "min items",
+			payload: `{"module": "billing", "owner": "platform", "tags": []}`,
+			want:    []string{"$.tags: must have at least 1 items"},
+		},
+		{
+			name:    "additional property",
+			payload: `{"module": "billing", "owner": "platform", "colour": "red"}`,
+			want:    []string{"$.colour: property is not allowed"},
+		},
+		{
+			name:    "max length",
+			payload: `{"module": "a-very-long-module-name-indeed", "owner": "platform"}`,
+			want:    []string{"$.module: must be at most 20 characters"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := ValidatePayload(tt.payload, schema)
+			require.NoError(t, err)
+			var got []string
+			for _, v := range violations {
+				got = append(got, v.String())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidatePayload_NotJSON(t *testing.T) {
+	_, err := ValidatePayload("Build a REST API", map[string]interface{}{"type": "object"})
+	assert.ErrorContains(t, err, "payload is not valid JSON")
+}
+
+func TestValidatePayload_TypeList(t *testing.T) {
+	schema := map[string]interface{}{"type": []interface{}{"string", "null"}}
+
+	violations, err := ValidatePayload(`null`, schema)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+
+	violations, err = ValidatePayload(`true`, schema)
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "$: expected string or null, got boolean", violations[0].String())
+}
@@ -0,0 +1,260 @@
+// Package goal builds the root artefact submitted by holt forage: goal templates
+// stored under the project's .holt directory and optional JSON Schema checks on
+// the resulting payload.
+package goal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultType is the root artefact type used when neither --type nor a template sets one.
+const DefaultType = "GoalDefined"
+
+// Dir is the project directory holding goal templates and schemas, relative to the Git root.
+const Dir = ".holt"
+
+var templateNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// ErrTemplateNotFound is returned by LoadTemplate when no file exists for the name.
+var ErrTemplateNotFound = errors.New("goal template not found")
+
+// Template is a reusable goal stored at .holt/templates/<name>.yml.
+//
+//	description: Scaffold a new Go module
+//	type: NewModuleRequest
+//	params:
+//	  name: {required: true, description: Module name}
+//	  owner: {default: platform}
+//	payload: |
+//	  {"module": {{json .name}}, "owner": {{json .owner}}}
+//	schema: new-module.schema.json
+//
+// Payload is a text/template rendered with the parameter values; the json function
+// quotes a value as a JSON string. Schema is either a path relative to the template
+// file or an inline JSON Schema.
+type Template struct {
+	Name        string           `yaml:"-"`
+	Path        string           `yaml:"-"`
+	Description string           `yaml:"description"`
+	Type        string           `yaml:"type"`
+	Params      map[string]Param `yaml:"params"`
+	Payload     string           `yaml:"payload"`
+	Schema      interface{}      `yaml:"schema"`
+}
+
+// Param declares a template parameter set with --set key=value.
+type Param struct {
+	Description string  `yaml:"description"`
+	Required    bool    `yaml:"required"`
+	Default     *string `yaml:"default"`
+}
+
+// TemplatesDir returns the goal template directory for a project root.
+func TemplatesDir(projectRoot string) string {
+	return filepath.Join(projectRoot, Dir, "templates")
+}
+
+// SchemasDir returns the directory holding per-type payload schemas for a project root.
+func SchemasDir(projectRoot string) string {
+	return filepath.Join(projectRoot, Dir, "schemas")
+}
+
+// LoadTemplate reads .holt/templates/<name>.yml (or .yaml) under projectRoot.
+func LoadTemplate(projectRoot, name string) (*Template, error) {
+	if !templateNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid template name %q: use letters, digits, '-' and '_'", name)
+	}
+
+	dir := TemplatesDir(projectRoot)
+	for _, ext := range []string{".yml", ".yaml"} {
+		path := filepath.Join(dir, name+ext)
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+
+		var tmpl Template
+		if err := yaml.Unmarshal(data, &tmpl); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		tmpl.Name = name
+		tmpl.Path = path
+		if strings.TrimSpace(tmpl.Payload) == "" {
+			return nil, fmt.Errorf("template %s has no payload", path)
+		}
+		return &tmpl, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, filepath.Join(dir, name+".yml"))
+}
+
+// ListTemplates returns the names of the templates under projectRoot, sorted.
+// A missing templates directory yields an empty list.
+func ListTemplates(projectRoot string) ([]string, error) {
+	entries, err := os.ReadDir(TemplatesDir(projectRoot))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(entry.Name())
+		if ext != ".yml" && ext != ".yaml" {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), ext))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ParseSet parses repeated --set key=value flags. A later value for the same key wins.
+func ParseSet(pairs []string) (map[string]string, error) {
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --set %q: expected key=value", pair)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// Render fills the template's parameters and returns the payload. Unknown keys and
+// missing required parameters are errors, so a typo never silently produces a goal.
+func (t *Template) Render(values map[string]string) (string, error) {
+	var unknown []string
+	for key := range values {
+		if _, ok := t.Params[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return "", fmt.Errorf("unknown parameter(s) %s for template %q (declared: %s)",
+			strings.Join(unknown, ", "), t.Name, strings.Join(t.paramNames(), ", "))
+	}
+
+	data := make(map[string]string, len(t.Params))
+	var missing []string
+	for name, param := range t.Params {
+		if value, ok := values[name]; ok {
+			data[name] = value
+			continue
+		}
+		if param.Default != nil {
+			data[name] = *param.Default
+			continue
+		}
+		if param.Required {
+			missing = append(missing, name)
+			continue
+		}
+		data[name] = ""
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", fmt.Errorf("missing required parameter(s) %s for template %q", strings.Join(missing, ", "), t.Name)
+	}
+
+	parsed, err := template.New(t.Name).
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"json": jsonString}).
+		Parse(t.Payload)
+	if err != nil {
+		return "", fmt.Errorf("invalid payload in template %q: %w", t.Name, err)
+	}
+
+	var out bytes.Buffer
+	if err := parsed.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to render template %q: %w", t.Name, err)
+	}
+	return out.String(), nil
+}
+
+// LoadSchema returns the template's schema, reading it from disk when Schema is a
+// path. Returns nil when the template declares no schema.
+func (t *Template) LoadSchema() (map[string]interface{}, error) {
+	switch schema := t.Schema.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		return schema, nil
+	case string:
+		path := schema
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(t.Path), path)
+		}
+		return ReadSchema(path)
+	default:
+		return nil, fmt.Errorf("template %q: schema must be a file path or a mapping", t.Name)
+	}
+}
+
+// TypeSchema returns the schema at .holt/schemas/<artefactType>.json, or nil when
+// the project has none for that type.
+func TypeSchema(projectRoot, artefactType string) (map[string]interface{}, error) {
+	if !templateNamePattern.MatchString(artefactType) {
+		return nil, nil
+	}
+	path := filepath.Join(SchemasDir(projectRoot), artefactType+".json")
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return ReadSchema(path)
+}
+
+// ReadSchema reads a JSON Schema file. YAML is accepted too, as a superset of JSON.
+func ReadSchema(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	var schema map[string]interface{}
+	if err := yaml.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse schema %s: %w", path, err)
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("schema %s is empty", path)
+	}
+	return schema, nil
+}
+
+func (t *Template) paramNames() []string {
+	names := make([]string, 0, len(t.Params))
+	for name := range t.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return []string{"none"}
+	}
+	return names
+}
+
+func jsonString(value string) (string, error) {
+	quoted, err := json.Marshal(value)
+	return string(quoted), err
+}
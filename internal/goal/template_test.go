@@ -0,0 +1,147 @@
+package goal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTemplate(t *testing.T, root, name, content string) {
+	t.Helper()
+	dir := TemplatesDir(root)
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+}
+
+const newModuleTemplate = `description: Scaffold a new Go module
+type: NewModuleRequest
+params:
+  name:
+    description: Module name
+    required: true
+  owner:
+    default: platform
+  notes: {}
+payload: |
+  {"module": {{json .name}}, "owner": {{json .owner}}, "notes": {{json .notes}}}
+schema:
+  type: object
+  required: [module, owner]
+`
+
+func TestLoadTemplate(t *testing.T) {
+	root := t.TempDir()
+	writeTemplate(t, root, "new-module.yml", newModuleTemplate)
+
+	tmpl, err := LoadTemplate(root, "new-module")
+	require.NoError(t, err)
+	assert.Equal(t, "new-module", tmpl.Name)
+	assert.Equal(t, "NewModuleRequest", tmpl.Type)
+	assert.Len(t, tmpl.Params, 3)
+	assert.True(t, tmpl.Params["name"].Required)
+
+	_, err = LoadTemplate(root, "missing")
+	assert.ErrorIs(t, err, ErrTemplateNotFound)
+
+	_, err = LoadTemplate(root, "../escape")
+	assert.ErrorContains(t, err, "invalid template name")
+
+	writeTemplate(t, root, "empty.yaml", "description: nothing\n")
+	_, err = LoadTemplate(root, "empty")
+	assert.ErrorContains(t, err, "has no payload")
+}
+
+func TestListTemplates(t *testing.T) {
+	root := t.TempDir()
+	names, err := ListTemplates(root)
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	writeTemplate(t, root, "b.yml", "payload: b\n")
+	writeTemplate(t, root, "a.yaml", "payload: a\n")
+	writeTemplate(t, root, "a.schema.json", "{}")
+
+	names, err = ListTemplates(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, names)
+}
+
+func TestParseSet(t *testing.T) {
+	values, err := ParseSet([]string{"name=billing", "owner=team=payments", "name=ledger", "empty="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"name": "ledger", "owner": "team=payments", "empty": ""}, values)
+
+	_, err = ParseSet([]string{"novalue"})
+	assert.EqualError(t, err, `invalid --set "novalue": expected key=value`)
+
+	_, err = ParseSet([]string{"=value"})
+	assert.Error(t, err)
+}
+
+func TestTemplateRender(t *testing.T) {
+	root := t.TempDir()
+	writeTemplate(t, root, "new-module.yml", newModuleTemplate)
+	tmpl, err := LoadTemplate(root, "new-module")
+	require.NoError(t, err)
+
+	payload, err := tmpl.Render(map[string]string{"name": `say "hi"`})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"module": "say \"hi\"", "owner": "platform", "notes": ""}`, payload)
+
+	payload, err = tmpl.Render(map[string]string{"name": "billing", "owner": "payments"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"module": "billing", "owner": "payments", "notes": ""}`, payload)
+
+	_, err = tmpl.Render(map[string]string{})
+	assert.EqualError(t, err, `missing required parameter(s) name for template "new-module"`)
+
+	_, err = tmpl.Render(map[string]string{"name": "x", "nmae": "y"})
+	assert.EqualError(t, err, `unknown parameter(s) nmae for template "new-module" (declared: name, notes, owner)`)
+}
+
+func TestTemplateRender_UndeclaredReference(t *testing.T) {
+	tmpl := &Template{Name: "bad", Payload: "Build {{.thing}}"}
+	_, err := tmpl.Render(nil)
+	assert.ErrorContains(t, err, "failed to render template")
+}
+
+func TestTemplateLoadSchema(t *testing.T) {
+	root := t.TempDir()
+	writeTemplate(t, root, "new-module.yml", newModuleTemplate)
+	tmpl, err := LoadTemplate(root, "new-module")
+	require.NoError(t, err)
+
+	schema, err := tmpl.LoadSchema()
+	require.NoError(t, err)
+	assert.Equal(t, "object", schema["type"])
+
+	writeTemplate(t, root, "release.yml", "payload: '{}'\nschema: release.schema.json\n")
+	writeTemplate(t, root, "release.schema.json", `{"type": "object", "required": ["version"]}`)
+	tmpl, err = LoadTemplate(root, "release")
+	require.NoError(t, err)
+	schema, err = tmpl.LoadSchema()
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"version"}, schema["required"])
+
+	tmpl = &Template{Name: "plain", Payload: "text"}
+	schema, err = tmpl.LoadSchema()
+	require.NoError(t, err)
+	assert.Nil(t, schema)
+}
+
+func TestTypeSchema(t *testing.T) {
+	root := t.TempDir()
+	schema, err := TypeSchema(root, "NewModuleRequest")
+	require.NoError(t, err)
+	assert.Nil(t, schema)
+
+	require.NoError(t, os.MkdirAll(SchemasDir(root), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(SchemasDir(root), "NewModuleRequest.json"), []byte(`{"type": "object"}`), 0o644))
+
+	schema, err = TypeSchema(root, "NewModuleRequest")
+	require.NoError(t, err)
+	assert.Equal(t, "object", schema["type"])
+}
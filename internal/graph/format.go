@@ -0,0 +1,170 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is a graph output format.
+type Format string
+
+const (
+	FormatDOT     Format = "dot"
+	FormatMermaid Format = "mermaid"
+	FormatJSON    Format = "json"
+)
+
+// outcomeStyle is the fill and border colour for each highlighted outcome
+var outcomeStyle = map[string]struct{ fill, stroke string }{
+	OutcomeFailure:        {"#f8d7da", "#c0392b"},
+	OutcomeReviewRejected: {"#fde2c4", "#d35400"},
+	OutcomeReviewApproved: {"#d4edda", "#27ae60"},
+	OutcomeTerminal:       {"#d6eaf8", "#2471a3"},
+	OutcomeQuestion:       {"#fcf3cf", "#b7950b"},
+	OutcomeAnswer:         {"#fcf3cf", "#b7950b"},
+}
+
+// Write renders the graph in the given format.
+func Write(w io.Writer, g *Graph, format Format) error {
+	switch format {
+	case FormatDOT:
+		return WriteDOT(w, g)
+	case FormatMermaid:
+		return WriteMermaid(w, g)
+	case FormatJSON:
+		return WriteJSON(w, g)
+	default:
+		return fmt.Errorf("unknown graph format: %s", format)
+	}
+}
+
+// WriteDOT renders the graph as a Graphviz digraph.
+func WriteDOT(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	b.WriteString("digraph holt {\n")
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=\"#ffffff\", fontname=\"Helvetica\"];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=10];\n")
+
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "  %q [label=%q", n.ID, strings.Join(nodeLabel(n), "\n"))
+		if style, ok := outcomeStyle[n.Outcome]; ok {
+			fmt.Fprintf(&b, ", fillcolor=%q, color=%q", style.fill, style.stroke)
+		}
+		if n.Outcome == OutcomeTerminal {
+			b.WriteString(", peripheries=2")
+		}
+		b.WriteString("];\n")
+	}
+
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %q -> %q", e.From, e.To)
+		switch e.Kind {
+		case EdgeVersion:
+			b.WriteString(" [style=dashed, label=\"next version\"]")
+		case EdgeChildWorkflow:
+			b.WriteString(" [style=bold, label=\"child workflow\"]")
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid renders the graph as a Mermaid flowchart.
+func WriteMermaid(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", mermaidID(n.ID), mermaidEscape(strings.Join(nodeLabel(n), "<br/>")))
+	}
+
+	for _, e := range g.Edges {
+		arrow := "-->"
+		switch e.Kind {
+		case EdgeVersion:
+			arrow = "-.->|next version|"
+		case EdgeChildWorkflow:
+			arrow = "==>|child workflow|"
+		}
+		fmt.Fprintf(&b, "  %s %s %s\n", mermaidID(e.From), arrow, mermaidID(e.To))
+	}
+
+	// Class definitions only for outcomes present, so pasted diagrams stay short
+	used := make(map[string][]string)
+	for _, n := range g.Nodes {
+		if _, ok := outcomeStyle[n.Outcome]; ok {
+			used[n.Outcome] = append(used[n.Outcome], mermaidID(n.ID))
+		}
+	}
+	for _, outcome := range []string{OutcomeFailure, OutcomeReviewRejected, OutcomeReviewApproved, OutcomeTerminal, OutcomeQuestion, OutcomeAnswer} {
+		ids := used[outcome]
+		if len(ids) == 0 {
+			continue
+		}
+		style := outcomeStyle[outcome]
+		fmt.Fprintf(&b, "  classDef %s fill:%s,stroke:%s\n", outcome, style.fill, style.stroke)
+		fmt.Fprintf(&b, "  class %s %s\n", strings.Join(ids, ","), outcome)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON renders the graph as pretty-printed JSON.
+func WriteJSON(w io.Writer, g *Graph) error {
+	out := *g
+	if out.Nodes == nil {
+		out.Nodes = []Node{}
+	}
+	if out.Edges == nil {
+		out.Edges = []Edge{}
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal graph: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// nodeLabel returns the lines of a node's label: type and version, role and
+// structural type (plus review verdict), and short ID
+func nodeLabel(n Node) []string {
+	kind := n.StructuralType
+	switch n.Outcome {
+	case OutcomeReviewRejected:
+		kind += " (rejected)"
+	case OutcomeReviewApproved:
+		kind += " (approved)"
+	}
+	return []string{
+		fmt.Sprintf("%s v%d", n.Type, n.Version),
+		fmt.Sprintf("%s · %s", n.ProducedByRole, kind),
+		shortID(n.ID),
+	}
+}
+
+// mermaidID returns a Mermaid-safe node identifier for an artefact ID
+func mermaidID(id string) string {
+	return "a" + strings.ReplaceAll(id, "-", "")
+}
+
+// mermaidEscape escapes characters that would end a quoted Mermaid label
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
+
+// shortID returns the first 8 characters of an ID, as hoard displays them
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	goalID    = "11111111-0000-0000-0000-000000000000"
+	designID  = "22222222-0000-0000-0000-000000000000"
+	reviewID  = "33333333-0000-0000-0000-000000000000"
+	design2ID = "44444444-0000-0000-0000-000000000000"
+)
+
+func testGraph() *Graph {
+	return &Graph{
+		Nodes: []Node{
+			{ID: goalID, Version: 1, Type: "GoalDefined", StructuralType: "Standard", ProducedByRole: "user"},
+			{ID: designID, Version: 1, Type: "DesignSpec", StructuralType: "Standard", ProducedByRole: "designer"},
+			{ID: reviewID, Version: 1, Type: `Review "strict"`, StructuralType: "Review", ProducedByRole: "reviewer", Outcome: OutcomeReviewRejected},
+			{ID: design2ID, Version: 2, Type: "DesignSpec", StructuralType: "Standard", ProducedByRole: "designer"},
+		},
+		Edges: []Edge{
+			{From: goalID, To: designID, Kind: EdgeSource},
+			{From: designID, To: reviewID, Kind: EdgeSource},
+			{From: designID, To: design2ID, Kind: EdgeVersion},
+		},
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, testGraph(), FormatDOT))
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "digraph holt {\n"))
+	assert.Contains(t, out, `"`+goalID+`" [label="GoalDefined v1\nuser · Standard\n11111111"];`)
+	assert.Contains(t, out, `"`+reviewID+`" [label="Review \"strict\" v1\nreviewer · Review (rejected)\n33333333", fillcolor="#fde2c4", color="#d35400"];`)
+	assert.Contains(t, out, `"`+goalID+`" -> "`+designID+`";`)
+	assert.Contains(t, out, `"`+designID+`" -> "`+design2ID+`" [style=dashed, label="next version"];`)
+	assert.True(t, strings.HasSuffix(out, "}\n"))
+}
+
+func TestWriteMermaid(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, testGraph(), FormatMermaid))
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "flowchart TD\n"))
+	assert.Contains(t, out, `a11111111000000000000000000000000["GoalDefined v1<br/>user · Standard<br/>11111111"]`)
+	assert.Contains(t, out, `Review #quot;strict#quot; v1`)
+	assert.Contains(t, out, "a11111111000000000000000000000000 --> a22222222000000000000000000000000")
+	assert.Contains(t, out, "a22222222000000000000000000000000 -.->|next version| a44444444000000000000000000000000")
+	assert.Contains(t, out, "classDef review_rejected fill:#fde2c4,stroke:#d35400")
+	assert.Contains(t, out, "class a33333333000000000000000000000000 review_rejected")
+	assert.NotContains(t, out, "classDef failure", "unused outcome classes are omitted")
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, testGraph(), FormatJSON))
+
+	var decoded Graph
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, testGraph(), &decoded)
+
+	buf.Reset()
+	require.NoError(t, WriteJSON(&buf, &Graph{}))
+	assert.JSONEq(t, `{"nodes": [], "edges": []}`, buf.String())
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	assert.Error(t, Write(&bytes.Buffer{}, testGraph(), Format("png")))
+}
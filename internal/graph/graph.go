@@ -0,0 +1,242 @@
+// Package graph builds artefact lineage graphs from the blackboard - derivation
+// via SourceArtefacts, versions via LogicalID threads and sub-workflows via
+// ParentClaimID - and renders them as DOT, Mermaid or JSON.
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+// Edge kinds
+const (
+	// EdgeSource links a source artefact to an artefact derived from it
+	EdgeSource = "source"
+	// EdgeVersion links a version to the next version of the same logical artefact
+	EdgeVersion = "version"
+	// EdgeChildWorkflow links a parked claim's artefact to the child workflow's goal
+	EdgeChildWorkflow = "child_workflow"
+)
+
+// Node outcomes, used to colour nodes
+const (
+	OutcomeFailure        = "failure"
+	OutcomeReviewRejected = "review_rejected"
+	OutcomeReviewApproved = "review_approved"
+	OutcomeTerminal       = "terminal"
+	OutcomeQuestion       = "question"
+	OutcomeAnswer         = "answer"
+)
+
+// Node is an artefact in the graph.
+type Node struct {
+	ID             string `json:"id"`
+	LogicalID      string `json:"logical_id"`
+	Version        int    `json:"version"`
+	Type           string `json:"type"`
+	StructuralType string `json:"structural_type"`
+	ProducedByRole string `json:"produced_by_role"`
+	CreatedAtMs    int64  `json:"created_at_ms"`
+	Outcome        string `json:"outcome,omitempty"` // Highlight for failures, review verdicts, terminals and questions
+}
+
+// Edge is a directed link between two artefacts, pointing from earlier to later work.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// Graph is a set of artefacts and the links between them.
+// Nodes are ordered by creation time and edges by endpoint, so output is stable.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// LoadArtefacts reads every artefact on the blackboard, along with the parent artefact
+// of each child workflow goal (keyed by goal ID). Malformed artefacts are skipped.
+func LoadArtefacts(ctx context.Context, bbClient *blackboard.Client) ([]*blackboard.Artefact, map[string]string, error) {
+	ids, err := bbClient.ScanArtefacts(ctx, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan artefacts: %w", err)
+	}
+
+	artefacts := make([]*blackboard.Artefact, 0, len(ids))
+	childParents := make(map[string]string)
+	for _, id := range ids {
+		artefact, err := bbClient.GetArtefact(ctx, id)
+		if err != nil {
+			continue
+		}
+		artefacts = append(artefacts, artefact)
+
+		if artefact.ParentClaimID != "" {
+			claim, err := bbClient.GetClaim(ctx, artefact.ParentClaimID)
+			if err == nil {
+				childParents[artefact.ID] = claim.ArtefactID
+			}
+		}
+	}
+	return artefacts, childParents, nil
+}
+
+// Build creates the graph of all given artefacts. childParents maps child workflow goal IDs
+// to the artefact whose claim requested them (see LoadArtefacts); it may be nil.
+// Links to artefacts not in the set are dropped.
+func Build(artefacts []*blackboard.Artefact, childParents map[string]string) *Graph {
+	g := &Graph{}
+	present := make(map[string]bool, len(artefacts))
+	for _, artefact := range artefacts {
+		present[artefact.ID] = true
+	}
+
+	threads := make(map[string][]*blackboard.Artefact)
+	for _, artefact := range artefacts {
+		g.Nodes = append(g.Nodes, Node{
+			ID:             artefact.ID,
+			LogicalID:      artefact.LogicalID,
+			Version:        artefact.Version,
+			Type:           artefact.Type,
+			StructuralType: string(artefact.StructuralType),
+			ProducedByRole: artefact.ProducedByRole,
+			CreatedAtMs:    artefact.CreatedAtMs,
+			Outcome:        outcome(artefact),
+		})
+
+		for _, sourceID := range artefact.SourceArtefacts {
+			if present[sourceID] {
+				g.Edges = append(g.Edges, Edge{From: sourceID, To: artefact.ID, Kind: EdgeSource})
+			}
+		}
+		if parentID := childParents[artefact.ID]; parentID != "" && present[parentID] {
+			g.Edges = append(g.Edges, Edge{From: parentID, To: artefact.ID, Kind: EdgeChildWorkflow})
+		}
+		threads[artefact.LogicalID] = append(threads[artefact.LogicalID], artefact)
+	}
+
+	for _, versions := range threads {
+		sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+		for i := 1; i < len(versions); i++ {
+			g.Edges = append(g.Edges, Edge{From: versions[i-1].ID, To: versions[i].ID, Kind: EdgeVersion})
+		}
+	}
+
+	g.sort()
+	return g
+}
+
+// Lineage returns the subgraph of an artefact's ancestors and descendants: everything it
+// was derived from (including earlier versions) and everything derived from it.
+func (g *Graph) Lineage(id string) *Graph {
+	forward := make(map[string][]string)
+	backward := make(map[string][]string)
+	for _, e := range g.Edges {
+		forward[e.From] = append(forward[e.From], e.To)
+		backward[e.To] = append(backward[e.To], e.From)
+	}
+
+	keep := reachable(id, backward)
+	for node := range reachable(id, forward) {
+		keep[node] = true
+	}
+	return g.subgraph(keep)
+}
+
+// Workflow returns the connected subgraph containing an artefact - the whole workflow
+// it belongs to, including sibling branches that do not share its lineage.
+func (g *Graph) Workflow(id string) *Graph {
+	neighbours := make(map[string][]string)
+	for _, e := range g.Edges {
+		neighbours[e.From] = append(neighbours[e.From], e.To)
+		neighbours[e.To] = append(neighbours[e.To], e.From)
+	}
+	return g.subgraph(reachable(id, neighbours))
+}
+
+// Node returns the node with the given ID, or nil.
+func (g *Graph) Node(id string) *Node {
+	for i := range g.Nodes {
+		if g.Nodes[i].ID == id {
+			return &g.Nodes[i]
+		}
+	}
+	return nil
+}
+
+// subgraph returns the nodes in keep and the edges between them
+func (g *Graph) subgraph(keep map[string]bool) *Graph {
+	sub := &Graph{}
+	for _, n := range g.Nodes {
+		if keep[n.ID] {
+			sub.Nodes = append(sub.Nodes, n)
+		}
+	}
+	for _, e := range g.Edges {
+		if keep[e.From] && keep[e.To] {
+			sub.Edges = append(sub.Edges, e)
+		}
+	}
+	return sub
+}
+
+// sort orders nodes by creation time and edges by endpoints
+func (g *Graph) sort() {
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		if g.Nodes[i].CreatedAtMs != g.Nodes[j].CreatedAtMs {
+			return g.Nodes[i].CreatedAtMs < g.Nodes[j].CreatedAtMs
+		}
+		return g.Nodes[i].ID < g.Nodes[j].ID
+	})
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Kind < b.Kind
+	})
+}
+
+// reachable returns the set of nodes reachable from start (including start)
+func reachable(start string, adjacency map[string][]string) map[string]bool {
+	seen := map[string]bool{start: true}
+	queue := []string{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range adjacency[current] {
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return seen
+}
+
+// outcome classifies an artefact for highlighting
+func outcome(artefact *blackboard.Artefact) string {
+	switch artefact.StructuralType {
+	case blackboard.StructuralTypeFailure:
+		return OutcomeFailure
+	case blackboard.StructuralTypeReview:
+		if blackboard.IsReviewApproval(artefact.Payload) {
+			return OutcomeReviewApproved
+		}
+		return OutcomeReviewRejected
+	case blackboard.StructuralTypeTerminal:
+		return OutcomeTerminal
+	case blackboard.StructuralTypeQuestion:
+		return OutcomeQuestion
+	case blackboard.StructuralTypeAnswer:
+		return OutcomeAnswer
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,146 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixture is a small workflow:
+//
+//	goal ─→ design v1 ─→ review (rejected)
+//	          └┄┄→ design v2 ─→ code ─→ failure
+//	goal ─→ docs (sibling branch)
+type fixture struct {
+	goal, design1, review, design2, code, failure, docs, unrelated *blackboard.Artefact
+}
+
+func artefact(typ string, st blackboard.StructuralType, createdAt int64, sources ...*blackboard.Artefact) *blackboard.Artefact {
+	a := &blackboard.Artefact{
+		ID:              uuid.New().String(),
+		LogicalID:       uuid.New().String(),
+		Version:         1,
+		StructuralType:  st,
+		Type:            typ,
+		SourceArtefacts: []string{},
+		ProducedByRole:  "agent",
+		CreatedAtMs:     createdAt,
+	}
+	for _, s := range sources {
+		a.SourceArtefacts = append(a.SourceArtefacts, s.ID)
+	}
+	return a
+}
+
+func newFixture() *fixture {
+	f := &fixture{}
+	f.goal = artefact("GoalDefined", blackboard.StructuralTypeStandard, 1)
+	f.design1 = artefact("DesignSpec", blackboard.StructuralTypeStandard, 2, f.goal)
+	f.review = artefact("ReviewFeedback", blackboard.StructuralTypeReview, 3, f.design1)
+	f.review.Payload = `{"issue": "missing error handling"}`
+	f.design2 = artefact("DesignSpec", blackboard.StructuralTypeStandard, 4, f.goal)
+	f.design2.LogicalID = f.design1.LogicalID
+	f.design2.Version = 2
+	f.code = artefact("CodeCommit", blackboard.StructuralTypeStandard, 5, f.design2)
+	f.failure = artefact("ToolFailure", blackboard.StructuralTypeFailure, 6, f.code)
+	f.docs = artefact("Docs", blackboard.StructuralTypeStandard, 7, f.goal)
+	f.unrelated = artefact("GoalDefined", blackboard.StructuralTypeStandard, 8)
+	return f
+}
+
+func (f *fixture) all() []*blackboard.Artefact {
+	return []*blackboard.Artefact{f.failure, f.goal, f.design1, f.review, f.design2, f.code, f.docs, f.unrelated}
+}
+
+func nodeIDs(g *Graph) []string {
+	var ids []string
+	for _, n := range g.Nodes {
+		ids = append(ids, n.ID)
+	}
+	return ids
+}
+
+func TestBuild(t *testing.T) {
+	f := newFixture()
+	g := Build(f.all(), nil)
+
+	assert.Equal(t, []string{f.goal.ID, f.design1.ID, f.review.ID, f.design2.ID, f.code.ID, f.failure.ID, f.docs.ID, f.unrelated.ID},
+		nodeIDs(g), "nodes are ordered by creation time")
+
+	assert.Contains(t, g.Edges, Edge{From: f.goal.ID, To: f.design1.ID, Kind: EdgeSource})
+	assert.Contains(t, g.Edges, Edge{From: f.design1.ID, To: f.design2.ID, Kind: EdgeVersion})
+	assert.Contains(t, g.Edges, Edge{From: f.code.ID, To: f.failure.ID, Kind: EdgeSource})
+	assert.Len(t, g.Edges, 7)
+
+	assert.Equal(t, OutcomeReviewRejected, g.Node(f.review.ID).Outcome)
+	assert.Equal(t, OutcomeFailure, g.Node(f.failure.ID).Outcome)
+	assert.Empty(t, g.Node(f.code.ID).Outcome)
+}
+
+func TestBuild_ReviewApprovalAndMissingSources(t *testing.T) {
+	source := artefact("DesignSpec", blackboard.StructuralTypeStandard, 1)
+	review := artefact("Review", blackboard.StructuralTypeReview, 2, source)
+	review.Payload = "{}"
+
+	// The source artefact is not in the set, so its edge is dropped
+	g := Build([]*blackboard.Artefact{review}, nil)
+	assert.Equal(t, OutcomeReviewApproved, g.Node(review.ID).Outcome)
+	assert.Empty(t, g.Edges)
+}
+
+func TestGraph_Lineage(t *testing.T) {
+	f := newFixture()
+	g := Build(f.all(), nil)
+
+	// design v2's lineage: its ancestors (v1 and goal) and descendants, but not
+	// the sibling docs branch or the review of v1
+	lineage := g.Lineage(f.design2.ID)
+	assert.ElementsMatch(t, []string{f.goal.ID, f.design1.ID, f.design2.ID, f.code.ID, f.failure.ID}, nodeIDs(lineage))
+	for _, e := range lineage.Edges {
+		assert.NotEqual(t, f.docs.ID, e.To)
+	}
+
+	// Later versions are descendants
+	assert.Contains(t, nodeIDs(g.Lineage(f.design1.ID)), f.failure.ID)
+}
+
+func TestGraph_Workflow(t *testing.T) {
+	f := newFixture()
+	g := Build(f.all(), nil)
+
+	workflow := g.Workflow(f.failure.ID)
+	assert.Len(t, workflow.Nodes, 7, "every artefact connected to the goal")
+	assert.NotContains(t, nodeIDs(workflow), f.unrelated.ID)
+	assert.Len(t, workflow.Edges, 7)
+}
+
+func TestLoadArtefacts_ChildWorkflows(t *testing.T) {
+	mr := miniredis.RunT(t)
+	bbClient, err := blackboard.NewClient(&redis.Options{Addr: mr.Addr()}, "test")
+	require.NoError(t, err)
+	defer bbClient.Close()
+	ctx := context.Background()
+
+	parent := artefact("DesignSpec", blackboard.StructuralTypeStandard, 1)
+	require.NoError(t, bbClient.CreateArtefact(ctx, parent))
+	claim := &blackboard.Claim{ID: uuid.New().String(), ArtefactID: parent.ID, Status: blackboard.ClaimStatusAwaitingChild}
+	require.NoError(t, bbClient.CreateClaim(ctx, claim))
+
+	childGoal := artefact("GoalDefined", blackboard.StructuralTypeStandard, 2)
+	childGoal.ParentClaimID = claim.ID
+	require.NoError(t, bbClient.CreateArtefact(ctx, childGoal))
+
+	artefacts, childParents, err := LoadArtefacts(ctx, bbClient)
+	require.NoError(t, err)
+	assert.Len(t, artefacts, 2)
+	assert.Equal(t, map[string]string{childGoal.ID: parent.ID}, childParents)
+
+	g := Build(artefacts, childParents)
+	assert.Equal(t, []Edge{{From: parent.ID, To: childGoal.ID, Kind: EdgeChildWorkflow}}, g.Edges)
+}
@@ -127,29 +127,33 @@ func VerifyInstanceRunning(ctx context.Context, cli *client.Client, instanceName
 	}
 
 	// Check that essential containers (Redis, orchestrator) are running
-	// Agent containers may exit after completing work, so they are not checked
+	// Agent containers may exit after completing work, so they are not checked.
+	// HA: an orchestrator standby may be down as long as one replica is running.
 	essentialComponents := map[string]bool{
 		"redis":        false,
 		"orchestrator": false,
 	}
+	lastState := make(map[string]string)
 
 	for _, container := range containers {
 		component := container.Labels[dockerpkg.LabelComponent]
 
-		// If this is an essential component, mark it as found and check if running
-		if _, isEssential := essentialComponents[component]; isEssential {
-			essentialComponents[component] = true
-			if container.State != "running" {
-				return fmt.Errorf("instance '%s' is not running (component '%s' is %s)", instanceName, component, container.State)
-			}
+		// If this is an essential component, mark it as running if any container is
+		if running, isEssential := essentialComponents[component]; isEssential {
+			essentialComponents[component] = running || container.State == "running"
+			lastState[component] = container.State
 		}
 	}
 
-	// Verify that all essential components were found
-	for component, found := range essentialComponents {
+	// Verify that all essential components were found and are running
+	for component, running := range essentialComponents {
+		state, found := lastState[component]
 		if !found {
 			return fmt.Errorf("instance '%s' is missing essential component '%s'", instanceName, component)
 		}
+		if !running {
+			return fmt.Errorf("instance '%s' is not running (component '%s' is %s)", instanceName, component, state)
+		}
 	}
 
 	return nil
@@ -46,4 +46,5 @@ type InstanceInfo struct {
 	Status    Status `json:"status"`
 	Workspace string `json:"workspace"`
 	Uptime    string `json:"uptime"`
+	Leader    string `json:"leader,omitempty"` // Orchestrator replica holding the leader lease
 }
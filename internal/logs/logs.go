@@ -0,0 +1,175 @@
+// Package logs aggregates the container output of a Holt instance - orchestrator,
+// agents and ephemeral workers - into a single, timestamp-ordered stream with
+// colour-coded per-container prefixes.
+package logs
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Line is a single log line from one container.
+type Line struct {
+	Time   time.Time // Docker-recorded timestamp (zero if the line carried none)
+	Source string    // Display name of the container that produced the line
+	Text   string    // Line content without timestamp or trailing newline
+}
+
+// Filter selects which lines are shown.
+type Filter struct {
+	Role    string    // Only show containers for this agent role (empty = all)
+	ClaimID string    // Only show this claim's worker, plus other lines that mention the claim ID
+	Since   time.Time // Only show lines at or after this time (zero = all)
+}
+
+// Matches reports whether a line from a container with the given role and claim passes the filter.
+// Lines from a claim's own worker always match the claim filter; other lines must mention the claim ID.
+func (f Filter) Matches(line Line, role, claimID string) bool {
+	if f.Role != "" && role != f.Role {
+		return false
+	}
+	if f.ClaimID != "" && claimID != f.ClaimID && !strings.Contains(line.Text, f.ClaimID) {
+		return false
+	}
+	if !f.Since.IsZero() && !line.Time.IsZero() && line.Time.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+// ParseLine splits a Docker log line captured with timestamps enabled into its
+// timestamp and text. Lines without a parseable timestamp keep a zero Time.
+func ParseLine(source, raw string) Line {
+	raw = strings.TrimRight(raw, "\r\n")
+	if i := strings.IndexByte(raw, ' '); i > 0 {
+		if t, err := time.Parse(time.RFC3339Nano, raw[:i]); err == nil {
+			return Line{Time: t, Source: source, Text: raw[i+1:]}
+		}
+	}
+	return Line{Source: source, Text: raw}
+}
+
+// ParseOutput splits timestamped multi-line output into Lines. Lines without a
+// timestamp inherit the previous line's so they stay in place when interleaved.
+func ParseOutput(source, output string) []Line {
+	var lines []Line
+	var last time.Time
+	for _, raw := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		if raw == "" {
+			continue
+		}
+		line := ParseLine(source, raw)
+		if line.Time.IsZero() {
+			line.Time = last
+		} else {
+			last = line.Time
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// SortLines orders lines by timestamp, keeping each container's lines in their original order on ties.
+func SortLines(lines []Line) {
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Time.Before(lines[j].Time)
+	})
+}
+
+// Interleave reads lines until in is closed, emitting them in timestamp order.
+// Lines are buffered for window before being flushed so output arriving from
+// several containers at once is merged; a zero window buffers until in is closed.
+func Interleave(in <-chan Line, window time.Duration, emit func(Line)) {
+	var pending []Line
+	flush := func() {
+		SortLines(pending)
+		for _, line := range pending {
+			emit(line)
+		}
+		pending = pending[:0]
+	}
+
+	var tick <-chan time.Time
+	if window > 0 {
+		ticker := time.NewTicker(window)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case line, ok := <-in:
+			if !ok {
+				flush()
+				return
+			}
+			pending = append(pending, line)
+		case <-tick:
+			flush()
+		}
+	}
+}
+
+// prefixColors is the palette sources are assigned from; the assignment is
+// derived from the source name so a container keeps its colour across runs.
+var prefixColors = []color.Attribute{
+	color.FgCyan,
+	color.FgGreen,
+	color.FgYellow,
+	color.FgBlue,
+	color.FgMagenta,
+	color.FgHiCyan,
+	color.FgHiGreen,
+	color.FgHiYellow,
+	color.FgHiBlue,
+	color.FgHiMagenta,
+}
+
+// Formatter renders lines as "<source> | <text>" with aligned, colour-coded prefixes.
+type Formatter struct {
+	noColor bool
+	width   int
+	colors  map[string]*color.Color
+}
+
+// NewFormatter creates a Formatter. Prefixes are left uncoloured when noColor is set.
+func NewFormatter(noColor bool) *Formatter {
+	return &Formatter{noColor: noColor, colors: make(map[string]*color.Color)}
+}
+
+// Align widens the prefix column to fit the given source names.
+func (f *Formatter) Align(sources ...string) {
+	for _, source := range sources {
+		if len(source) > f.width {
+			f.width = len(source)
+		}
+	}
+}
+
+// Format renders a single line.
+func (f *Formatter) Format(line Line) string {
+	f.Align(line.Source)
+	prefix := fmt.Sprintf("%-*s |", f.width, line.Source)
+	if !f.noColor {
+		prefix = f.colorFor(line.Source).Sprint(prefix)
+	}
+	return prefix + " " + line.Text
+}
+
+// colorFor returns the prefix colour for a source
+func (f *Formatter) colorFor(source string) *color.Color {
+	if c, ok := f.colors[source]; ok {
+		return c
+	}
+	h := fnv.New32a()
+	h.Write([]byte(source))
+	c := color.New(prefixColors[h.Sum32()%uint32(len(prefixColors))])
+	c.EnableColor()
+	f.colors[source] = c
+	return c
+}
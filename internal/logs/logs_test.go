@@ -0,0 +1,258 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
+	dockerpkg "github.com/dyluth/holt/internal/docker"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDocker serves canned containers and multiplexed log output
+type fakeDocker struct {
+	mu         sync.Mutex
+	containers []types.Container
+	output     map[string]string // container ID → timestamped output
+	removed    map[string]bool   // container IDs still listed but gone when logs are read
+}
+
+func (f *fakeDocker) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var matched []types.Container
+	for _, c := range f.containers {
+		ok := true
+		for _, label := range options.Filters.Get("label") {
+			key, value, _ := strings.Cut(label, "=")
+			if c.Labels[key] != value {
+				ok = false
+			}
+		}
+		if ok {
+			matched = append(matched, c)
+		}
+	}
+	return matched, nil
+}
+
+func (f *fakeDocker) ContainerLogs(ctx context.Context, containerID string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+	f.mu.Lock()
+	output := f.output[containerID]
+	removed := f.removed[containerID]
+	f.mu.Unlock()
+
+	if removed {
+		return nil, errdefs.NotFound(errors.New("No such container: " + containerID))
+	}
+
+	var buf bytes.Buffer
+	stdcopy.NewStdWriter(&buf, stdcopy.Stdout).Write([]byte(output))
+	return io.NopCloser(&buf), nil
+}
+
+func (f *fakeDocker) add(id, name, component, role, claimID, output string) {
+	labels := map[string]string{
+		dockerpkg.LabelInstanceName: "test",
+		dockerpkg.LabelComponent:    component,
+	}
+	if role != "" {
+		labels[dockerpkg.LabelAgentRole] = role
+	}
+	if claimID != "" {
+		labels[dockerpkg.LabelClaimID] = claimID
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.output == nil {
+		f.output = make(map[string]string)
+	}
+	f.containers = append(f.containers, types.Container{ID: id, Names: []string{"/" + name}, Labels: labels})
+	f.output[id] = output
+}
+
+type fakeStore struct {
+	logs []*blackboard.WorkerLog
+}
+
+func (s *fakeStore) GetWorkerLogs(ctx context.Context) ([]*blackboard.WorkerLog, error) {
+	return s.logs, nil
+}
+
+func TestParseLine(t *testing.T) {
+	line := ParseLine("coder", "2025-01-02T03:04:05.123456789Z [Pup] bidding on claim\n")
+	assert.Equal(t, "coder", line.Source)
+	assert.Equal(t, "[Pup] bidding on claim", line.Text)
+	assert.Equal(t, time.Date(2025, 1, 2, 3, 4, 5, 123456789, time.UTC), line.Time)
+
+	plain := ParseLine("coder", "no timestamp here")
+	assert.True(t, plain.Time.IsZero())
+	assert.Equal(t, "no timestamp here", plain.Text)
+}
+
+func TestParseOutput_InheritsTimestamp(t *testing.T) {
+	lines := ParseOutput("w", "2025-01-01T00:00:01Z first\ncontinuation\n\n2025-01-01T00:00:02Z second\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, lines[0].Time, lines[1].Time)
+	assert.Equal(t, "continuation", lines[1].Text)
+	assert.Equal(t, "second", lines[2].Text)
+}
+
+func TestFilter_Matches(t *testing.T) {
+	at := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	line := Line{Time: at, Text: "granted claim abc-123"}
+
+	assert.True(t, Filter{}.Matches(line, "", ""))
+	assert.True(t, Filter{Role: "coder"}.Matches(line, "coder", ""))
+	assert.False(t, Filter{Role: "coder"}.Matches(line, "tester", ""))
+
+	assert.True(t, Filter{ClaimID: "abc-123"}.Matches(line, "", ""), "lines mentioning the claim match")
+	assert.True(t, Filter{ClaimID: "xyz"}.Matches(line, "coder", "xyz"), "the claim's worker always matches")
+	assert.False(t, Filter{ClaimID: "xyz"}.Matches(line, "coder", ""))
+
+	assert.True(t, Filter{Since: at}.Matches(line, "", ""))
+	assert.False(t, Filter{Since: at.Add(time.Second)}.Matches(line, "", ""))
+}
+
+func TestInterleave_OrdersByTimestamp(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	in := make(chan Line, 4)
+	in <- Line{Time: base.Add(3 * time.Second), Source: "a", Text: "third"}
+	in <- Line{Time: base.Add(1 * time.Second), Source: "b", Text: "first"}
+	in <- Line{Time: base.Add(2 * time.Second), Source: "a", Text: "second"}
+	close(in)
+
+	var got []string
+	Interleave(in, 0, func(line Line) { got = append(got, line.Text) })
+	assert.Equal(t, []string{"first", "second", "third"}, got)
+}
+
+func TestFormatter(t *testing.T) {
+	f := NewFormatter(true)
+	f.Align("orchestrator")
+	assert.Equal(t, "coder        | hello", f.Format(Line{Source: "coder", Text: "hello"}))
+
+	colored := NewFormatter(false)
+	out := colored.Format(Line{Source: "coder", Text: "hello"})
+	assert.Contains(t, out, "\x1b[", "prefix should be colour-coded")
+	assert.True(t, strings.HasSuffix(out, " hello"), "text itself is not coloured")
+	assert.Equal(t, out, colored.Format(Line{Source: "coder", Text: "hello"}), "a source keeps its colour")
+}
+
+func TestDiscoverSources(t *testing.T) {
+	docker := &fakeDocker{}
+	docker.add("o1", "holt-orchestrator-test", "orchestrator", "", "", "")
+	docker.add("o2", "holt-orchestrator-test-2", "orchestrator", "", "", "")
+	docker.add("a1", "holt-test-coder", "agent", "coder", "", "")
+	docker.add("w1", "holt-test-coder-worker-12345678", "worker", "coder", "12345678-aaaa-bbbb-cccc-000000000000", "")
+	docker.add("r1", "holt-redis-test", "redis", "", "", "")
+
+	sources, err := DiscoverSources(context.Background(), docker, "test", Filter{})
+	require.NoError(t, err)
+
+	var names []string
+	for _, s := range sources {
+		names = append(names, s.Name)
+	}
+	assert.Equal(t, []string{"orchestrator", "orchestrator-2", "coder", "coder/worker-12345678"}, names)
+
+	sources, err = DiscoverSources(context.Background(), docker, "test", Filter{Role: "coder"})
+	require.NoError(t, err)
+	assert.Len(t, sources, 2, "role filter selects the agent and its workers")
+}
+
+func TestRun_InterleavesContainersAndPersistedWorkers(t *testing.T) {
+	claimID := "deadbeef-0000-0000-0000-000000000000"
+	docker := &fakeDocker{}
+	docker.add("o1", "holt-orchestrator-test", "orchestrator", "", "",
+		"2025-01-01T00:00:01Z granted claim "+claimID+"\n2025-01-01T00:00:04Z idle\n")
+	docker.add("a1", "holt-test-coder", "agent", "coder", "",
+		"2025-01-01T00:00:02Z controller bidding\n")
+	store := &fakeStore{logs: []*blackboard.WorkerLog{{
+		ClaimID:       claimID,
+		Role:          "coder",
+		ContainerName: "holt-test-coder-worker-deadbeef",
+		Output:        "2025-01-01T00:00:03Z worker running tool\n",
+	}}}
+
+	var out bytes.Buffer
+	err := Run(context.Background(), docker, store, Options{InstanceName: "test", NoColor: true}, &out)
+	require.NoError(t, err)
+
+	assert.Equal(t, strings.Join([]string{
+		"orchestrator          | granted claim " + claimID,
+		"coder                 | controller bidding",
+		"coder/worker-deadbeef | worker running tool",
+		"orchestrator          | idle",
+	}, "\n")+"\n", out.String())
+
+	t.Run("claim filter", func(t *testing.T) {
+		out.Reset()
+		err := Run(context.Background(), docker, store, Options{InstanceName: "test", NoColor: true, Filter: Filter{ClaimID: claimID}}, &out)
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "granted claim")
+		assert.Contains(t, out.String(), "worker running tool")
+		assert.NotContains(t, out.String(), "controller bidding")
+		assert.NotContains(t, out.String(), "idle")
+	})
+
+	t.Run("persisted log skipped while container still exists", func(t *testing.T) {
+		docker.add("w1", "holt-test-coder-worker-deadbeef", "worker", "coder", claimID,
+			"2025-01-01T00:00:03Z worker running tool\n")
+		out.Reset()
+		err := Run(context.Background(), docker, store, Options{InstanceName: "test", NoColor: true}, &out)
+		require.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(out.String(), "worker running tool"))
+	})
+}
+
+func TestRun_SkipsContainersRemovedAfterDiscovery(t *testing.T) {
+	claimID := "deadbeef-0000-0000-0000-000000000000"
+	docker := &fakeDocker{removed: map[string]bool{"w1": true}}
+	docker.add("a1", "holt-test-coder", "agent", "coder", "", "2025-01-01T00:00:01Z controller bidding\n")
+	docker.add("w1", "holt-test-coder-worker-deadbeef", "worker", "coder", claimID, "")
+	store := &fakeStore{logs: []*blackboard.WorkerLog{{
+		ClaimID:       claimID,
+		Role:          "coder",
+		ContainerName: "holt-test-coder-worker-deadbeef",
+		Output:        "2025-01-01T00:00:02Z worker running tool\n",
+	}}}
+
+	var out bytes.Buffer
+	err := Run(context.Background(), docker, store, Options{InstanceName: "test", NoColor: true}, &out)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "controller bidding")
+	assert.Contains(t, out.String(), "worker running tool", "the removed worker's persisted log is shown")
+}
+
+func TestRun_FollowPicksUpNewContainers(t *testing.T) {
+	docker := &fakeDocker{}
+	docker.add("a1", "holt-test-coder", "agent", "coder", "", "2025-01-01T00:00:01Z ready\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var out bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		done <- Run(ctx, docker, nil, Options{InstanceName: "test", NoColor: true, Follow: true, PollInterval: 20 * time.Millisecond}, &out)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	docker.add("w1", "holt-test-coder-worker-abcdef12", "worker", "coder", "abcdef12-0000", "2025-01-01T00:00:05Z worker done\n")
+	time.Sleep(400 * time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	assert.Contains(t, out.String(), "coder/worker-abcdef12 | worker done")
+}
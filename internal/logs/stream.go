@@ -0,0 +1,324 @@
+package logs
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
+	dockerpkg "github.com/dyluth/holt/internal/docker"
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+// DefaultPollInterval is how often --follow looks for newly started containers (e.g. workers).
+const DefaultPollInterval = 2 * time.Second
+
+// followWindow is how long live lines are buffered so concurrent output is merged in order
+const followWindow = 250 * time.Millisecond
+
+// DockerAPI is the subset of the Docker client used to discover and read containers.
+type DockerAPI interface {
+	ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error)
+	ContainerLogs(ctx context.Context, container string, options types.ContainerLogsOptions) (io.ReadCloser, error)
+}
+
+// WorkerLogStore provides worker output persisted after the worker container was removed.
+type WorkerLogStore interface {
+	GetWorkerLogs(ctx context.Context) ([]*blackboard.WorkerLog, error)
+}
+
+// Options configures Run.
+type Options struct {
+	InstanceName string
+	Filter       Filter
+	Follow       bool          // Keep streaming, picking up containers started later
+	NoColor      bool          // Disable colour-coded prefixes
+	PollInterval time.Duration // Container discovery interval when following (default DefaultPollInterval)
+}
+
+// Source is a container whose output is shown.
+type Source struct {
+	ContainerID   string
+	ContainerName string
+	Name          string // Display name used as the line prefix
+	Component     string // orchestrator, agent or worker
+	Role          string // Agent role (agents and workers)
+	ClaimID       string // Claim being executed (workers only)
+}
+
+// DiscoverSources lists the instance's orchestrator, agent and worker containers
+// (running or stopped) that pass the filter's role and claim selection.
+func DiscoverSources(ctx context.Context, docker DockerAPI, instanceName string, filter Filter) ([]Source, error) {
+	args := filters.NewArgs()
+	args.Add("label", fmt.Sprintf("%s=%s", dockerpkg.LabelInstanceName, instanceName))
+	if filter.Role != "" {
+		args.Add("label", fmt.Sprintf("%s=%s", dockerpkg.LabelAgentRole, filter.Role))
+	}
+
+	containers, err := docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: args})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers for instance '%s': %w", instanceName, err)
+	}
+
+	var sources []Source
+	for _, c := range containers {
+		source := Source{
+			ContainerID: c.ID,
+			Component:   c.Labels[dockerpkg.LabelComponent],
+			Role:        c.Labels[dockerpkg.LabelAgentRole],
+			ClaimID:     c.Labels[dockerpkg.LabelClaimID],
+		}
+		if len(c.Names) > 0 {
+			source.ContainerName = strings.TrimPrefix(c.Names[0], "/")
+		}
+
+		switch source.Component {
+		case "orchestrator":
+			source.Name = "orchestrator" + strings.TrimPrefix(source.ContainerName, dockerpkg.OrchestratorContainerName(instanceName))
+		case "agent":
+			source.Name = source.Role
+		case "worker":
+			source.Name = workerSourceName(source.Role, source.ClaimID)
+		default:
+			// Redis and other infrastructure containers are not part of the workflow's logs
+			continue
+		}
+		if source.Name == "" {
+			source.Name = source.ContainerName
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// workerSourceName returns the display name for a worker, e.g. "coder/worker-1a2b3c4d"
+func workerSourceName(role, claimID string) string {
+	if len(claimID) > 8 {
+		claimID = claimID[:8]
+	}
+	return fmt.Sprintf("%s/worker-%s", role, claimID)
+}
+
+// PersistedLines returns the lines of persisted worker logs that pass the filter,
+// skipping workers whose container still exists (their output is read from Docker).
+func PersistedLines(workerLogs []*blackboard.WorkerLog, existing map[string]bool, filter Filter) []Line {
+	var lines []Line
+	for _, workerLog := range workerLogs {
+		if existing[workerLog.ContainerName] {
+			continue
+		}
+		source := workerSourceName(workerLog.Role, workerLog.ClaimID)
+		for _, line := range ParseOutput(source, workerLog.Output) {
+			if filter.Matches(line, workerLog.Role, workerLog.ClaimID) {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines
+}
+
+// Run prints the instance's logs to w: first the existing output of all matching
+// containers and persisted workers, interleaved by timestamp, then - when following -
+// live output from those containers and any started later, until ctx is cancelled.
+func Run(ctx context.Context, docker DockerAPI, store WorkerLogStore, opts Options, w io.Writer) error {
+	formatter := NewFormatter(opts.NoColor)
+	emit := func(line Line) {
+		fmt.Fprintln(w, formatter.Format(line))
+	}
+
+	sources, err := DiscoverSources(ctx, docker, opts.InstanceName, opts.Filter)
+	if err != nil {
+		return err
+	}
+
+	// History ends at cutoff; following resumes from it so nothing is shown twice
+	cutoff := time.Now()
+
+	var history []Line
+	var available []Source
+	existing := make(map[string]bool, len(sources))
+	for _, source := range sources {
+		lines, err := readSource(ctx, docker, source, opts.Filter, cutoff)
+		if errdefs.IsNotFound(err) {
+			// Workers are removed as soon as they exit, so one can vanish after discovery.
+			// Its output comes from the persisted worker logs instead.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		existing[source.ContainerName] = true
+		available = append(available, source)
+		history = append(history, lines...)
+	}
+
+	if store != nil {
+		workerLogs, err := store.GetWorkerLogs(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read persisted worker logs: %w", err)
+		}
+		history = append(history, PersistedLines(workerLogs, existing, opts.Filter)...)
+		for _, workerLog := range workerLogs {
+			existing[workerLog.ContainerName] = true
+		}
+	}
+
+	for _, line := range history {
+		formatter.Align(line.Source)
+	}
+	SortLines(history)
+	for _, line := range history {
+		emit(line)
+	}
+
+	if !opts.Follow {
+		return nil
+	}
+	return follow(ctx, docker, store, available, existing, opts, cutoff, emit)
+}
+
+// readSource reads a container's existing output up to cutoff
+func readSource(ctx context.Context, docker DockerAPI, source Source, filter Filter, cutoff time.Time) ([]Line, error) {
+	options := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: true,
+		Until:      dockerTimestamp(cutoff),
+	}
+	if !filter.Since.IsZero() {
+		options.Since = dockerTimestamp(filter.Since)
+	}
+
+	var lines []Line
+	err := streamContainer(ctx, docker, source, options, func(line Line) {
+		if filter.Matches(line, source.Role, source.ClaimID) {
+			lines = append(lines, line)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logs for %s: %w", source.ContainerName, err)
+	}
+	return lines, nil
+}
+
+// follow streams live output from the known sources and from any matching containers
+// that appear later, until ctx is cancelled. shown holds the container names whose
+// output has already been printed; workers that start and are removed between polls
+// are picked up from the persisted worker logs instead.
+func follow(ctx context.Context, docker DockerAPI, store WorkerLogStore, sources []Source, shown map[string]bool, opts Options, since time.Time, emit func(Line)) error {
+	pollInterval := opts.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = DefaultPollInterval
+	}
+
+	lines := make(chan Line, 256)
+	var wg sync.WaitGroup
+	streaming := make(map[string]bool)
+
+	start := func(source Source, from time.Time) {
+		streaming[source.ContainerID] = true
+		shown[source.ContainerName] = true
+		options := types.ContainerLogsOptions{
+			ShowStdout: true,
+			ShowStderr: true,
+			Timestamps: true,
+			Follow:     true,
+		}
+		if !from.IsZero() {
+			options.Since = dockerTimestamp(from)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Stream errors (e.g. a worker removed mid-read) just end that container's stream
+			_ = streamContainer(ctx, docker, source, options, func(line Line) {
+				if opts.Filter.Matches(line, source.Role, source.ClaimID) {
+					select {
+					case lines <- line:
+					case <-ctx.Done():
+					}
+				}
+			})
+		}()
+	}
+
+	for _, source := range sources {
+		start(source, since)
+	}
+
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				wg.Wait()
+				close(lines)
+				return
+			case <-ticker.C:
+				discovered, err := DiscoverSources(ctx, docker, opts.InstanceName, opts.Filter)
+				if err != nil {
+					continue
+				}
+				for _, source := range discovered {
+					if !streaming[source.ContainerID] {
+						// Containers started after the history cutoff are streamed from their beginning
+						start(source, opts.Filter.Since)
+					}
+				}
+				if store != nil {
+					workerLogs, err := store.GetWorkerLogs(ctx)
+					if err != nil {
+						continue
+					}
+					for _, line := range PersistedLines(workerLogs, shown, opts.Filter) {
+						lines <- line
+					}
+					for _, workerLog := range workerLogs {
+						shown[workerLog.ContainerName] = true
+					}
+				}
+			}
+		}
+	}()
+
+	Interleave(lines, followWindow, emit)
+	return nil
+}
+
+// streamContainer reads a container's log stream, calling handle for each line
+func streamContainer(ctx context.Context, docker DockerAPI, source Source, options types.ContainerLogsOptions, handle func(Line)) error {
+	reader, err := docker.ContainerLogs(ctx, source.ContainerID, options)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	// Holt containers run without a TTY, so stdout and stderr arrive multiplexed
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(pw, pw, reader)
+		pw.CloseWithError(err)
+	}()
+	defer pr.Close()
+
+	scanner := bufio.NewScanner(pr)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		handle(ParseLine(source.Name, scanner.Text()))
+	}
+	return scanner.Err()
+}
+
+// dockerTimestamp formats t for the Docker logs API's since/until options
+func dockerTimestamp(t time.Time) string {
+	return fmt.Sprintf("%d.%09d", t.Unix(), t.Nanosecond())
+}
@@ -48,7 +48,9 @@ func (e *Engine) WaitForConsensus(ctx context.Context, claimID string) (map[stri
 				}
 			}
 
-			// Check if consensus achieved
+			// Check if consensus achieved. Only registered agents count: an agent
+			// removed by holt reload may have bid before it was stopped.
+			bids = e.registeredBids(bids)
 			receivedBidCount := len(bids)
 			if receivedBidCount == expectedBidCount {
 				consensusDuration := time.Since(consensusStart)
@@ -76,6 +78,17 @@ func (e *Engine) WaitForConsensus(ctx context.Context, claimID string) (map[stri
 	}
 }
 
+// registeredBids drops bids from agents that are not in the registry
+func (e *Engine) registeredBids(bids map[string]blackboard.BidType) map[string]blackboard.BidType {
+	registered := make(map[string]blackboard.BidType, len(bids))
+	for agentName, bidType := range bids {
+		if _, exists := e.agentRegistry[agentName]; exists {
+			registered[agentName] = bidType
+		}
+	}
+	return registered
+}
+
 // logBidArrival logs a single bid arrival event.
 func (e *Engine) logBidArrival(claimID, agentName string, bidType blackboard.BidType) {
 	log.Printf("[Orchestrator] Received %s bid from %s for claim %s", bidType, agentName, claimID)
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/dyluth/holt/internal/config"
@@ -23,6 +24,13 @@ type Engine struct {
 	phaseStates             map[string]*PhaseState // claimID -> PhaseState (M3.2: in-memory tracking)
 	pendingAssignmentClaims map[string]string      // claimID -> targetArtefactID (M3.3: feedback claim tracking)
 	workerManager           *WorkerManager         // M3.4: Worker lifecycle management
+	childWorkflows          map[string]string      // childGoalID -> parked parent claimID (sub-workflows)
+	lineages                map[string]*lineage    // artefactID -> memoised ancestry (see lineage.go)
+	configRevision          int                    // Stored configuration revision in use (0: holt.yml as loaded)
+
+	// configLock guards config and agentRegistry. holt reload swaps them on the event
+	// loop; code running off the loop (worker monitors) must read through agentConfig.
+	configLock sync.RWMutex
 }
 
 // NewEngine creates a new orchestrator engine.
@@ -47,6 +55,8 @@ func NewEngine(client *blackboard.Client, instanceName string, cfg *config.HoltC
 		phaseStates:             make(map[string]*PhaseState), // M3.2: Initialize phase state tracking
 		pendingAssignmentClaims: make(map[string]string),      // M3.3: Initialize feedback claim tracking
 		workerManager:           workerManager,                // M3.4: Worker lifecycle management
+		childWorkflows:          make(map[string]string),
+		lineages:                make(map[string]*lineage),
 	}
 
 	// M3.5: Set worker slot available callback for grant queue resumption
@@ -57,17 +67,30 @@ func NewEngine(client *blackboard.Client, instanceName string, cfg *config.HoltC
 	return engine
 }
 
+// DisableHealthServer stops Run from serving /healthz on :8080.
+// Used when the engine runs in-process (e.g. `holt test`) rather than in its container.
+func (e *Engine) DisableHealthServer() {
+	e.healthServer = nil
+}
+
 // Run starts the orchestrator engine and blocks until context is cancelled.
 // Returns error if subscription or processing fails.
 func (e *Engine) Run(ctx context.Context) error {
 	// Start health check server
-	if err := e.healthServer.Start(); err != nil {
-		return fmt.Errorf("failed to start health server: %w", err)
+	if e.healthServer != nil {
+		if err := e.healthServer.Start(); err != nil {
+			return fmt.Errorf("failed to start health server: %w", err)
+		}
+		defer e.healthServer.Shutdown(context.Background())
 	}
-	defer e.healthServer.Shutdown(context.Background())
 
 	log.Printf("[Orchestrator] Starting for instance '%s'", e.instanceName)
 
+	// Adopt the configuration last applied by holt reload, before recovery uses it
+	if err := e.adoptStoredConfig(ctx); err != nil {
+		return fmt.Errorf("failed to load stored configuration: %w", err)
+	}
+
 	// M3.5: Recover state from Redis before starting event loop
 	if err := e.RecoverState(ctx); err != nil {
 		return fmt.Errorf("failed to recover state: %w", err)
@@ -80,7 +103,28 @@ func (e *Engine) Run(ctx context.Context) error {
 	}
 	defer subscription.Close()
 
-	log.Printf("[Orchestrator] Subscribed to artefact_events")
+	// Subscribe to configuration revisions from holt reload
+	configSubscription, err := e.client.SubscribeRawChannel(ctx, blackboard.ConfigEventsChannel(e.instanceName))
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to config events: %w", err)
+	}
+	defer configSubscription.Close()
+
+	// Subscribe to pause state changes from holt pause, resume and drain
+	pauseSubscription, err := e.client.SubscribeRawChannel(ctx, blackboard.PauseEventsChannel(e.instanceName))
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to pause events: %w", err)
+	}
+	defer pauseSubscription.Close()
+
+	// Subscribe to manual interventions from holt retry and holt skip
+	requestSubscription, err := e.client.SubscribeRawChannel(ctx, blackboard.ClaimRequestsChannel(e.instanceName))
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to claim requests: %w", err)
+	}
+	defer requestSubscription.Close()
+
+	log.Printf("[Orchestrator] Subscribed to artefact_events, config_events, pause_events and claim_requests")
 
 	// Process events until context is cancelled
 	for {
@@ -101,13 +145,33 @@ func (e *Engine) Run(ctx context.Context) error {
 				"structural_type": artefact.StructuralType,
 			})
 
-			if err := e.processArtefact(ctx, artefact); err != nil {
-				log.Printf("[Orchestrator] Error processing artefact %s: %v", artefact.ID, err)
-				// Continue processing - don't crash on single artefact failure
+			// holt pause: hold the artefact until its workflow is resumed
+			if e.deferIfPaused(ctx, artefact) {
+				continue
+			}
+
+			e.handleArtefact(ctx, artefact)
+
+		case _, ok := <-pauseSubscription.Messages():
+			if !ok {
+				log.Printf("[Orchestrator] Pause subscription closed")
+				return nil
+			}
+			e.handlePauseChange(ctx)
+
+		case request, ok := <-requestSubscription.Messages():
+			if !ok {
+				log.Printf("[Orchestrator] Claim request subscription closed")
+				return nil
 			}
+			e.handleClaimRequest(ctx, request)
 
-			// M3.2: Also process artefact for phase completion tracking
-			e.processArtefactForPhases(ctx, artefact)
+		case revision, ok := <-configSubscription.Messages():
+			if !ok {
+				log.Printf("[Orchestrator] Config subscription closed")
+				return nil
+			}
+			e.handleConfigRevision(ctx, revision)
 
 		case err, ok := <-subscription.Errors():
 			if !ok {
@@ -120,6 +184,21 @@ func (e *Engine) Run(ctx context.Context) error {
 	}
 }
 
+// handleArtefact runs an artefact event through claim creation, phase tracking and
+// child workflow completion.
+func (e *Engine) handleArtefact(ctx context.Context, artefact *blackboard.Artefact) {
+	if err := e.processArtefact(ctx, artefact); err != nil {
+		log.Printf("[Orchestrator] Error processing artefact %s: %v", artefact.ID, err)
+		// Continue processing - don't crash on single artefact failure
+	}
+
+	// M3.2: Also process artefact for phase completion tracking
+	e.processArtefactForPhases(ctx, artefact)
+
+	// Sub-workflows: a Terminal or Failure may end a child workflow
+	e.checkChildWorkflowCompletion(ctx, artefact)
+}
+
 // processArtefact handles a single artefact event.
 // Creates a claim if appropriate, or skips if Terminal, Failure, or Review type.
 func (e *Engine) processArtefact(ctx context.Context, artefact *blackboard.Artefact) error {
@@ -150,6 +229,19 @@ func (e *Engine) processArtefact(ctx context.Context, artefact *blackboard.Artef
 		return nil
 	}
 
+	// holt drain: existing claims finish, but new work waits
+	if e.holdClaimCreation(ctx, artefact) {
+		return nil
+	}
+
+	// Sub-workflows: park the requesting claim before the child goal is claimed
+	if artefact.ParentClaimID != "" {
+		if err := e.parkParentClaim(ctx, artefact); err != nil {
+			e.rejectChildWorkflow(ctx, artefact, err)
+			return fmt.Errorf("rejected child workflow goal: %w", err)
+		}
+	}
+
 	// Create new claim
 	startTime := time.Now()
 	claimID := uuid.New().String()
@@ -178,7 +270,7 @@ func (e *Engine) processArtefact(ctx context.Context, artefact *blackboard.Artef
 
 	// M3.1: Wait for consensus and grant claim
 	if len(e.agentRegistry) > 0 {
-		if err := e.waitForConsensusAndGrant(ctx, claim); err != nil {
+		if err := e.waitForConsensusAndGrant(ctx, claim, artefact); err != nil {
 			log.Printf("[Orchestrator] Error in consensus/granting for claim %s: %v", claimID, err)
 			// Don't return error - continue processing other artefacts
 		}
@@ -303,13 +395,19 @@ func (e *Engine) checkPhaseCompletion(ctx context.Context, claim *blackboard.Cla
 
 // waitForConsensusAndGrant orchestrates the full consensus and granting process.
 // Uses the new M3.1 consensus and granting logic with bid tracking and alphabetical tie-breaking.
-func (e *Engine) waitForConsensusAndGrant(ctx context.Context, claim *blackboard.Claim) error {
+func (e *Engine) waitForConsensusAndGrant(ctx context.Context, claim *blackboard.Claim, artefact *blackboard.Artefact) error {
 	// Wait for full consensus (all agents bid)
 	bids, err := e.WaitForConsensus(ctx, claim.ID)
 	if err != nil {
 		return fmt.Errorf("failed to achieve consensus: %w", err)
 	}
 
+	// Drop bids that the declared workflows do not allow
+	bids = e.enforceWorkflows(ctx, claim, artefact, bids)
+
+	// Restrict child workflows to the agents they were requested with
+	bids = e.enforceChildAgents(ctx, claim, artefact, bids)
+
 	// Grant claim using deterministic selection
 	if err := e.GrantClaim(ctx, claim, bids); err != nil {
 		return fmt.Errorf("failed to grant claim: %w", err)
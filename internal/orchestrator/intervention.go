@@ -0,0 +1,238 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+)
+
+// Workflow events published after handling a claim request from holt retry or holt skip
+const (
+	EventClaimRetried       = "claim_retried"
+	EventClaimSkipped       = "claim_skipped"
+	EventClaimRequestFailed = "claim_request_failed"
+)
+
+// handleClaimRequest carries out a holt retry or holt skip request and reports the
+// outcome as a workflow event carrying the request ID. A retry is acknowledged as soon
+// as its claim exists; consensus and granting then run as for any new claim.
+func (e *Engine) handleClaimRequest(ctx context.Context, message string) {
+	var request blackboard.ClaimRequest
+	if err := json.Unmarshal([]byte(message), &request); err != nil {
+		log.Printf("[Orchestrator] Ignoring invalid claim request: %v", err)
+		return
+	}
+
+	switch request.Action {
+	case blackboard.ClaimActionRetry:
+		claim, artefact, err := e.retryClaim(ctx, &request)
+		if err != nil {
+			e.failClaimRequest(ctx, &request, err)
+			return
+		}
+		e.publishInterventionEvent(ctx, EventClaimRetried, map[string]interface{}{
+			"request_id":  request.ID,
+			"claim_id":    claim.ID,
+			"retry_of":    request.ClaimID,
+			"agent_role":  request.AgentRole,
+			"context_ids": claim.AdditionalContextIDs,
+		})
+		e.grantRetriedClaim(ctx, claim, artefact, request.AgentRole)
+
+	case blackboard.ClaimActionSkip:
+		artefact, err := e.skipClaim(ctx, &request)
+		if err != nil {
+			e.failClaimRequest(ctx, &request, err)
+			return
+		}
+		e.publishInterventionEvent(ctx, EventClaimSkipped, map[string]interface{}{
+			"request_id":  request.ID,
+			"claim_id":    request.ClaimID,
+			"artefact_id": artefact.ID,
+		})
+
+	default:
+		e.failClaimRequest(ctx, &request, fmt.Errorf("unknown claim request action %q", request.Action))
+	}
+}
+
+// retryClaim creates a fresh claim on a terminated claim's artefact. Unless the request
+// says otherwise, the Failure artefacts recorded against that artefact are passed to
+// the agent as additional context.
+func (e *Engine) retryClaim(ctx context.Context, request *blackboard.ClaimRequest) (*blackboard.Claim, *blackboard.Artefact, error) {
+	previous, err := e.interventionTarget(ctx, request.ClaimID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if request.AgentRole != "" {
+		if _, exists := e.agentRegistry[request.AgentRole]; !exists {
+			return nil, nil, fmt.Errorf("no agent with role '%s' in the running configuration", request.AgentRole)
+		}
+	}
+
+	artefact, err := e.client.GetArtefact(ctx, previous.ArtefactID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch artefact %s: %w", previous.ArtefactID, err)
+	}
+
+	contextIDs := []string{}
+	if !request.NoContext {
+		if contextIDs, err = e.failuresFor(ctx, artefact.ID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	claim := &blackboard.Claim{
+		ID:                    uuid.New().String(),
+		ArtefactID:            artefact.ID,
+		Status:                blackboard.ClaimStatusPendingReview, // Always start in review phase
+		GrantedReviewAgents:   []string{},
+		GrantedParallelAgents: []string{},
+		GrantedExclusiveAgent: "",
+		AdditionalContextIDs:  contextIDs,
+		RetryOf:               previous.ID,
+	}
+	if err := e.client.CreateClaim(ctx, claim); err != nil {
+		return nil, nil, fmt.Errorf("failed to create claim: %w", err)
+	}
+
+	e.logEvent("claim_retried", map[string]interface{}{
+		"claim_id":    claim.ID,
+		"retry_of":    previous.ID,
+		"artefact_id": artefact.ID,
+		"agent_role":  request.AgentRole,
+		"context_ids": contextIDs,
+	})
+	log.Printf("[Orchestrator] Claim %s retries terminated claim %s", claim.ID, previous.ID)
+
+	return claim, artefact, nil
+}
+
+// grantRetriedClaim grants a retry claim: straight to the requested agent as an
+// exclusive grant, or after consensus like any new claim.
+func (e *Engine) grantRetriedClaim(ctx context.Context, claim *blackboard.Claim, artefact *blackboard.Artefact, agentRole string) {
+	var err error
+	if agentRole != "" {
+		err = e.GrantClaim(ctx, claim, map[string]blackboard.BidType{agentRole: blackboard.BidTypeExclusive})
+	} else if len(e.agentRegistry) > 0 {
+		err = e.waitForConsensusAndGrant(ctx, claim, artefact)
+	}
+	if err != nil {
+		log.Printf("[Orchestrator] Error granting retry claim %s: %v", claim.ID, err)
+	}
+}
+
+// skipClaim resolves a terminated claim with a human-supplied artefact derived from its
+// target, as if the granted agent had produced it. The artefact is then claimed like any
+// other, so the workflow continues from it.
+func (e *Engine) skipClaim(ctx context.Context, request *blackboard.ClaimRequest) (*blackboard.Artefact, error) {
+	claim, err := e.interventionTarget(ctx, request.ClaimID)
+	if err != nil {
+		return nil, err
+	}
+	if request.ArtefactType == "" {
+		return nil, fmt.Errorf("skip needs an artefact type")
+	}
+
+	structuralType := blackboard.StructuralTypeStandard
+	if request.Terminal {
+		structuralType = blackboard.StructuralTypeTerminal
+	}
+
+	artefactID := uuid.New().String()
+	artefact := &blackboard.Artefact{
+		ID:              artefactID,
+		LogicalID:       artefactID,
+		Version:         1,
+		StructuralType:  structuralType,
+		Type:            request.ArtefactType,
+		Payload:         request.Payload,
+		SourceArtefacts: []string{claim.ArtefactID},
+		ProducedByRole:  "user",
+		CreatedAtMs:     time.Now().UnixMilli(),
+	}
+	if err := e.client.CreateArtefact(ctx, artefact); err != nil {
+		return nil, fmt.Errorf("failed to create artefact: %w", err)
+	}
+	if err := e.client.AddVersionToThread(ctx, artefact.LogicalID, artefact.ID, 1); err != nil {
+		log.Printf("[Orchestrator] Warning: Failed to add skip artefact %s to thread: %v", artefact.ID, err)
+	}
+
+	claim.Status = blackboard.ClaimStatusComplete
+	claim.SkippedWith = artefact.ID
+	if err := e.client.UpdateClaim(ctx, claim); err != nil {
+		return nil, fmt.Errorf("failed to complete claim: %w", err)
+	}
+	delete(e.phaseStates, claim.ID)
+
+	e.logEvent("claim_skipped", map[string]interface{}{
+		"claim_id":    claim.ID,
+		"artefact_id": artefact.ID,
+		"type":        artefact.Type,
+	})
+	log.Printf("[Orchestrator] Claim %s resolved manually with artefact %s", claim.ID, artefact.ID)
+
+	return artefact, nil
+}
+
+// interventionTarget returns the claim a request names, checking that it is terminated,
+// is still the latest claim on its artefact (feedback claims and earlier retries
+// supersede it) and is not in a paused workflow.
+func (e *Engine) interventionTarget(ctx context.Context, claimID string) (*blackboard.Claim, error) {
+	claim, err := e.client.GetClaim(ctx, claimID)
+	if err != nil {
+		if blackboard.IsNotFound(err) {
+			return nil, fmt.Errorf("claim %s not found", claimID)
+		}
+		return nil, fmt.Errorf("failed to fetch claim %s: %w", claimID, err)
+	}
+	if claim.Status != blackboard.ClaimStatusTerminated {
+		return nil, fmt.Errorf("claim %s is %s, only terminated claims can be retried or skipped", claim.ID, claim.Status)
+	}
+
+	latest, err := e.client.GetClaimByArtefactID(ctx, claim.ArtefactID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest claim on artefact %s: %w", claim.ArtefactID, err)
+	}
+	if latest.ID != claim.ID {
+		return nil, fmt.Errorf("claim %s has been superseded by claim %s (%s)", claim.ID, latest.ID, latest.Status)
+	}
+
+	state, err := e.client.GetPauseState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if state.DrainSinceMs != 0 || state.HoldsWorkflow(e.workflowRoot(ctx, claim.ArtefactID)) {
+		return nil, fmt.Errorf("the instance or this workflow is paused or draining; resume it first")
+	}
+
+	return claim, nil
+}
+
+// failuresFor returns the Failure artefacts derived from an artefact, oldest first.
+func (e *Engine) failuresFor(ctx context.Context, artefactID string) ([]string, error) {
+	return e.client.GetFailuresBySource(ctx, artefactID)
+}
+
+func (e *Engine) failClaimRequest(ctx context.Context, request *blackboard.ClaimRequest, err error) {
+	log.Printf("[Orchestrator] Claim request %s (%s %s) failed: %v", request.ID, request.Action, request.ClaimID, err)
+	e.publishInterventionEvent(ctx, EventClaimRequestFailed, map[string]interface{}{
+		"request_id": request.ID,
+		"claim_id":   request.ClaimID,
+		"action":     request.Action,
+		"error":      err.Error(),
+	})
+}
+
+// publishInterventionEvent publishes a claim request outcome; failures are only logged
+func (e *Engine) publishInterventionEvent(ctx context.Context, eventType string, data map[string]interface{}) {
+	if err := e.client.PublishWorkflowEvent(ctx, eventType, data); err != nil {
+		log.Printf("[Orchestrator] Failed to publish %s event: %v", eventType, err)
+	}
+}
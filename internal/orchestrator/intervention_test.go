@@ -0,0 +1,167 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTerminatedClaim stores a goal, a claim on it terminated by a tool failure and
+// the Failure artefact the agent produced.
+func setupTerminatedClaim(t *testing.T, client *blackboard.Client) (*blackboard.Claim, *blackboard.Artefact) {
+	t.Helper()
+	ctx := context.Background()
+
+	goal := createPauseTestArtefact(t, client)
+	claim := &blackboard.Claim{
+		ID:                    uuid.New().String(),
+		ArtefactID:            goal.ID,
+		Status:                blackboard.ClaimStatusTerminated,
+		GrantedReviewAgents:   []string{},
+		GrantedParallelAgents: []string{},
+		GrantedExclusiveAgent: "Coder",
+		TerminationReason:     "tool exited with status 1",
+	}
+	require.NoError(t, client.CreateClaim(ctx, claim))
+
+	failure := &blackboard.Artefact{
+		ID:              uuid.New().String(),
+		LogicalID:       uuid.New().String(),
+		Version:         1,
+		StructuralType:  blackboard.StructuralTypeFailure,
+		Type:            "ToolExecutionFailure",
+		Payload:         "exit 1",
+		SourceArtefacts: []string{goal.ID},
+		ProducedByRole:  "Coder",
+	}
+	require.NoError(t, client.CreateArtefact(ctx, failure))
+	return claim, failure
+}
+
+func claimRequest(t *testing.T, request blackboard.ClaimRequest) string {
+	t.Helper()
+	data, err := json.Marshal(request)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func nextWorkflowEvent(t *testing.T, sub *blackboard.WorkflowSubscription, eventType string) *blackboard.WorkflowEvent {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case event := <-sub.Events():
+			if event.Event == eventType {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("timeout waiting for %s event", eventType)
+		}
+	}
+}
+
+func TestHandleClaimRequest_RetryToAgent(t *testing.T) {
+	ctx := context.Background()
+	engine, client := setupReloadEngine(t)
+	previous, failure := setupTerminatedClaim(t, client)
+
+	sub, err := client.SubscribeWorkflowEvents(ctx)
+	require.NoError(t, err)
+	defer sub.Close()
+	time.Sleep(10 * time.Millisecond)
+
+	engine.handleClaimRequest(ctx, claimRequest(t, blackboard.ClaimRequest{
+		ID:        "req-1",
+		Action:    blackboard.ClaimActionRetry,
+		ClaimID:   previous.ID,
+		AgentRole: "Coder",
+	}))
+
+	event := nextWorkflowEvent(t, sub, EventClaimRetried)
+	assert.Equal(t, "req-1", event.Data["request_id"])
+
+	retry, err := client.GetClaimByArtefactID(ctx, previous.ArtefactID)
+	require.NoError(t, err)
+	assert.NotEqual(t, previous.ID, retry.ID)
+	assert.Equal(t, previous.ID, retry.RetryOf)
+	assert.Equal(t, []string{failure.ID}, retry.AdditionalContextIDs)
+	assert.Equal(t, blackboard.ClaimStatusPendingExclusive, retry.Status)
+	assert.Equal(t, "Coder", retry.GrantedExclusiveAgent)
+
+	// The original is superseded, so it cannot be retried twice
+	engine.handleClaimRequest(ctx, claimRequest(t, blackboard.ClaimRequest{
+		ID:      "req-2",
+		Action:  blackboard.ClaimActionRetry,
+		ClaimID: previous.ID,
+	}))
+	event = nextWorkflowEvent(t, sub, EventClaimRequestFailed)
+	assert.Equal(t, "req-2", event.Data["request_id"])
+	assert.Contains(t, event.Data["error"], "superseded")
+}
+
+func TestHandleClaimRequest_RetryRejections(t *testing.T) {
+	ctx := context.Background()
+	engine, client := setupReloadEngine(t)
+	previous, _ := setupTerminatedClaim(t, client)
+
+	active := &blackboard.Claim{
+		ID:                    uuid.New().String(),
+		ArtefactID:            createPauseTestArtefact(t, client).ID,
+		Status:                blackboard.ClaimStatusPendingExclusive,
+		GrantedReviewAgents:   []string{},
+		GrantedParallelAgents: []string{},
+	}
+	require.NoError(t, client.CreateClaim(ctx, active))
+
+	_, _, err := engine.retryClaim(ctx, &blackboard.ClaimRequest{ClaimID: active.ID})
+	assert.ErrorContains(t, err, "only terminated claims")
+
+	_, _, err = engine.retryClaim(ctx, &blackboard.ClaimRequest{ClaimID: previous.ID, AgentRole: "Ghost"})
+	assert.ErrorContains(t, err, "no agent with role 'Ghost'")
+
+	require.NoError(t, client.PauseInstance(ctx))
+	_, _, err = engine.retryClaim(ctx, &blackboard.ClaimRequest{ClaimID: previous.ID, AgentRole: "Coder"})
+	assert.ErrorContains(t, err, "paused")
+}
+
+func TestHandleClaimRequest_Skip(t *testing.T) {
+	ctx := context.Background()
+	engine, client := setupReloadEngine(t)
+	previous, _ := setupTerminatedClaim(t, client)
+
+	sub, err := client.SubscribeWorkflowEvents(ctx)
+	require.NoError(t, err)
+	defer sub.Close()
+	time.Sleep(10 * time.Millisecond)
+
+	engine.handleClaimRequest(ctx, claimRequest(t, blackboard.ClaimRequest{
+		ID:           "req-3",
+		Action:       blackboard.ClaimActionSkip,
+		ClaimID:      previous.ID,
+		ArtefactType: "CodeCommit",
+		Payload:      "abc123",
+	}))
+
+	event := nextWorkflowEvent(t, sub, EventClaimSkipped)
+	artefactID, _ := event.Data["artefact_id"].(string)
+	require.NotEmpty(t, artefactID)
+
+	artefact, err := client.GetArtefact(ctx, artefactID)
+	require.NoError(t, err)
+	assert.Equal(t, "CodeCommit", artefact.Type)
+	assert.Equal(t, "abc123", artefact.Payload)
+	assert.Equal(t, blackboard.StructuralTypeStandard, artefact.StructuralType)
+	assert.Equal(t, []string{previous.ArtefactID}, artefact.SourceArtefacts)
+	assert.Equal(t, "user", artefact.ProducedByRole)
+
+	resolved, err := client.GetClaim(ctx, previous.ID)
+	require.NoError(t, err)
+	assert.Equal(t, blackboard.ClaimStatusComplete, resolved.Status)
+	assert.Equal(t, artefactID, resolved.SkippedWith)
+}
@@ -0,0 +1,130 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dyluth/holt/internal/config"
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+// DefaultLeaseTTL is how long a leader lease lasts without renewal. A standby takes over
+// at most this long after the leader dies without releasing its lease.
+const DefaultLeaseTTL = config.DefaultOrchestratorLeaseTTL
+
+// LeaderElection runs an orchestrator replica as leader or standby. Replicas compete for a
+// Redis lease; the holder renews it every TTL/3 and runs the engine, while standbys retry
+// until the lease is free. Each acquisition issues a new fencing token, which is stamped on
+// the leader's claim writes so that a deposed leader cannot overwrite its successor's work.
+type LeaderElection struct {
+	client        *blackboard.Client
+	holderID      string
+	ttl           time.Duration
+	renewInterval time.Duration
+	retryInterval time.Duration
+}
+
+// NewLeaderElection creates a leader election for the replica identified by holderID.
+// A ttl of zero uses DefaultLeaseTTL.
+func NewLeaderElection(client *blackboard.Client, holderID string, ttl time.Duration) *LeaderElection {
+	if ttl <= 0 {
+		ttl = DefaultLeaseTTL
+	}
+	return &LeaderElection{
+		client:        client,
+		holderID:      holderID,
+		ttl:           ttl,
+		renewInterval: ttl / 3,
+		retryInterval: ttl / 3,
+	}
+}
+
+// Run campaigns for leadership until ctx is cancelled. Each time the lease is acquired, lead
+// is called with a context that is cancelled if leadership is lost; lead must return promptly
+// once its context is done. After losing leadership the replica returns to standby.
+// Returns lead's error if it fails while still leader.
+func (l *LeaderElection) Run(ctx context.Context, lead func(ctx context.Context) error) error {
+	for {
+		lease, err := l.client.AcquireLeadership(ctx, l.holderID, l.ttl)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("[Orchestrator] Leader election error: %v", err)
+		}
+
+		if lease != nil {
+			if err := l.lead(ctx, lease, lead); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(l.retryInterval):
+		}
+	}
+}
+
+// lead runs one leadership term: lead runs until ctx is cancelled, it returns, or the lease is lost
+func (l *LeaderElection) lead(ctx context.Context, lease *blackboard.LeaderLease, lead func(ctx context.Context) error) error {
+	log.Printf("[Orchestrator] Acquired leadership as '%s' (fencing token %d)", l.holderID, lease.FencingToken)
+	l.client.SetFencingToken(lease.FencingToken)
+	defer l.client.SetFencingToken(0)
+
+	termCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- lead(termCtx)
+	}()
+
+	renew := time.NewTicker(l.renewInterval)
+	defer renew.Stop()
+	lastRenewed := time.Now()
+
+	for {
+		select {
+		case err := <-done:
+			// The engine stopped on its own (shutdown or failure): hand over to a standby
+			l.release(lease)
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("orchestrator engine failed while leader: %w", err)
+			}
+			return errors.New("orchestrator engine stopped while leader")
+
+		case <-renew.C:
+			renewed, err := l.client.RenewLeadership(ctx, lease, l.ttl)
+			if err == nil && renewed {
+				lastRenewed = time.Now()
+				continue
+			}
+			if err != nil && time.Since(lastRenewed) < l.ttl {
+				// Transient error: the lease is still ours until it expires
+				log.Printf("[Orchestrator] Failed to renew leadership (will retry): %v", err)
+				continue
+			}
+
+			log.Printf("[Orchestrator] Lost leadership as '%s' (fencing token %d), returning to standby", l.holderID, lease.FencingToken)
+			cancel()
+			<-done
+			return nil
+		}
+	}
+}
+
+// release gives up the lease so a standby can take over without waiting for expiry
+func (l *LeaderElection) release(lease *blackboard.LeaderLease) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := l.client.ReleaseLeadership(ctx, lease); err != nil {
+		log.Printf("[Orchestrator] Failed to release leadership: %v", err)
+		return
+	}
+	log.Printf("[Orchestrator] Released leadership as '%s'", l.holderID)
+}
@@ -0,0 +1,130 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLeaseTTL = 300 * time.Millisecond
+
+func newElectionClient(t *testing.T, mr *miniredis.Miniredis) *blackboard.Client {
+	t.Helper()
+	client, err := blackboard.NewClient(&redis.Options{Addr: mr.Addr()}, "test-instance")
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// leadUntilCancelled records leadership terms and blocks until the term ends
+func leadUntilCancelled(terms chan<- int64, client *blackboard.Client) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		terms <- client.FencingToken()
+		<-ctx.Done()
+		return nil
+	}
+}
+
+func TestLeaderElection_StandbyTakesOverOnShutdown(t *testing.T) {
+	mr := miniredis.RunT(t)
+	clientA := newElectionClient(t, mr)
+	clientB := newElectionClient(t, mr)
+
+	termsA := make(chan int64, 1)
+	termsB := make(chan int64, 1)
+
+	ctxA, cancelA := context.WithCancel(context.Background())
+	doneA := make(chan error, 1)
+	go func() {
+		doneA <- NewLeaderElection(clientA, "orch-a", testLeaseTTL).Run(ctxA, leadUntilCancelled(termsA, clientA))
+	}()
+
+	select {
+	case token := <-termsA:
+		assert.Equal(t, int64(1), token)
+	case <-time.After(2 * time.Second):
+		t.Fatal("first replica never became leader")
+	}
+
+	ctxB, cancelB := context.WithCancel(context.Background())
+	defer cancelB()
+	go NewLeaderElection(clientB, "orch-b", testLeaseTTL).Run(ctxB, leadUntilCancelled(termsB, clientB))
+
+	// B stays on standby while A leads
+	select {
+	case <-termsB:
+		t.Fatal("standby became leader while the lease was held")
+	case <-time.After(2 * testLeaseTTL):
+	}
+
+	// A shuts down and releases the lease; B takes over with a newer token
+	cancelA()
+	require.NoError(t, <-doneA)
+	assert.Equal(t, int64(0), clientA.FencingToken())
+
+	select {
+	case token := <-termsB:
+		assert.Equal(t, int64(2), token)
+	case <-time.After(2 * time.Second):
+		t.Fatal("standby did not take over")
+	}
+
+	leader, err := clientB.GetLeader(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "orch-b", leader.HolderID)
+}
+
+func TestLeaderElection_StepsDownWhenLeaseLost(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := newElectionClient(t, mr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	termEnded := make(chan struct{})
+	started := make(chan struct{})
+	go NewLeaderElection(client, "orch-a", testLeaseTTL).Run(ctx, func(termCtx context.Context) error {
+		close(started)
+		<-termCtx.Done()
+		close(termEnded)
+		// Block re-election so the test observes a single term
+		<-ctx.Done()
+		return nil
+	})
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("replica never became leader")
+	}
+
+	// Another replica holds the lease (e.g. after a network partition expired ours)
+	require.NoError(t, mr.Set(blackboard.OrchestratorLeaderKey("test-instance"), `{"holder_id":"orch-b"}`))
+
+	select {
+	case <-termEnded:
+	case <-time.After(2 * time.Second):
+		t.Fatal("leader did not step down after losing the lease")
+	}
+}
+
+func TestLeaderElection_EngineFailureReleasesLease(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := newElectionClient(t, mr)
+
+	engineErr := errors.New("recovery failed")
+	err := NewLeaderElection(client, "orch-a", testLeaseTTL).Run(context.Background(), func(ctx context.Context) error {
+		return engineErr
+	})
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, engineErr)
+	assert.False(t, mr.Exists(blackboard.OrchestratorLeaderKey("test-instance")), "lease should be released for a standby")
+}
@@ -0,0 +1,87 @@
+package orchestrator
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+const (
+	// maxLineageDepth bounds how far back an uncached ancestry is fetched
+	maxLineageDepth = 1000
+
+	// maxLineageEntries bounds the lineage memo. It is cleared when full; dropped
+	// entries are rebuilt from the blackboard on demand.
+	maxLineageEntries = 10000
+)
+
+// lineage is the memoised ancestry of an artefact, so enforcement on each claim does not
+// walk the source graph. Artefacts are immutable, so an entry never goes stale.
+type lineage struct {
+	types []string               // Types of the artefact and all its ancestors, sorted
+	goals []*blackboard.Artefact // Child workflow goals among the artefact and its ancestors, nearest first
+}
+
+// lineageOf returns the artefact's lineage. It is built from the lineages of its sources,
+// which are memoised as artefacts are seen, so the blackboard is only read for sources the
+// memo does not hold (e.g. after a restart).
+func (e *Engine) lineageOf(ctx context.Context, artefact *blackboard.Artefact) *lineage {
+	return e.lineageAt(ctx, artefact, 0)
+}
+
+// lineageAt builds the lineage of an artefact depth levels below the one first asked for
+func (e *Engine) lineageAt(ctx context.Context, artefact *blackboard.Artefact, depth int) *lineage {
+	if cached, ok := e.lineages[artefact.ID]; ok {
+		return cached
+	}
+
+	types := map[string]bool{artefact.Type: true}
+	var goals []*blackboard.Artefact
+	seenGoals := make(map[string]bool)
+	if artefact.ParentClaimID != "" {
+		goals = append(goals, artefact)
+		seenGoals[artefact.ID] = true
+	}
+
+	for _, sourceID := range artefact.SourceArtefacts {
+		if depth >= maxLineageDepth {
+			log.Printf("[Orchestrator] Lineage of artefact %s truncated at depth %d", artefact.ID, depth)
+			break
+		}
+
+		source, ok := e.lineages[sourceID]
+		if !ok {
+			sourceArtefact, err := e.client.GetArtefact(ctx, sourceID)
+			if err != nil {
+				log.Printf("[Orchestrator] Failed to fetch artefact %s while tracing lineage: %v", sourceID, err)
+				continue
+			}
+			source = e.lineageAt(ctx, sourceArtefact, depth+1)
+		}
+		for _, sourceType := range source.types {
+			types[sourceType] = true
+		}
+		for _, goal := range source.goals {
+			if !seenGoals[goal.ID] {
+				seenGoals[goal.ID] = true
+				goals = append(goals, goal)
+			}
+		}
+	}
+
+	result := &lineage{types: make([]string, 0, len(types)), goals: goals}
+	for artefactType := range types {
+		result.types = append(result.types, artefactType)
+	}
+	sort.Strings(result.types)
+
+	if artefact.ID != "" {
+		if len(e.lineages) >= maxLineageEntries {
+			e.lineages = make(map[string]*lineage)
+		}
+		e.lineages[artefact.ID] = result
+	}
+	return result
+}
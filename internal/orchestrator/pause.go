@@ -0,0 +1,214 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+// holt pause, holt resume and holt drain write the pause state to Redis and announce
+// it on pause_events. The orchestrator reads the state as each artefact arrives, so a
+// pause takes effect between claims and survives restarts:
+//   - Paused (instance or workflow): the artefact is deferred untouched - no claim is
+//     created, no phase advances and no queued grant is resumed. Agents finish the
+//     work they already hold; their output waits.
+//   - Draining: phases keep advancing so existing claims run to completion, but new
+//     artefacts do not get claims.
+//
+// Deferred artefacts are kept in order in Redis and replayed through the normal path
+// once nothing holds them, either on the next pause_events message or by RecoverState.
+
+// deferIfPaused defers the artefact if its workflow is paused.
+// Returns true if the artefact was deferred and must not be processed now.
+func (e *Engine) deferIfPaused(ctx context.Context, artefact *blackboard.Artefact) bool {
+	state, err := e.client.GetPauseState(ctx)
+	if err != nil {
+		log.Printf("[Orchestrator] Warning: Failed to read pause state, processing artefact %s: %v", artefact.ID, err)
+		return false
+	}
+	if state.InstanceSinceMs == 0 && len(state.Workflows) == 0 {
+		return false
+	}
+
+	root := e.workflowRoot(ctx, artefact.ID)
+	if !state.HoldsWorkflow(root) {
+		return false
+	}
+
+	e.deferArtefact(ctx, artefact, root, false)
+	return true
+}
+
+// holdClaimCreation defers claim creation for the artefact while the instance is
+// draining. Child workflow goals are not held: they continue the work of a claim that
+// already exists, which could otherwise never finish.
+func (e *Engine) holdClaimCreation(ctx context.Context, artefact *blackboard.Artefact) bool {
+	if artefact.ParentClaimID != "" {
+		return false
+	}
+
+	state, err := e.client.GetPauseState(ctx)
+	if err != nil {
+		log.Printf("[Orchestrator] Warning: Failed to read pause state, creating claim for artefact %s: %v", artefact.ID, err)
+		return false
+	}
+	if state.DrainSinceMs == 0 {
+		return false
+	}
+
+	e.deferArtefact(ctx, artefact, e.workflowRoot(ctx, artefact.ID), true)
+	return true
+}
+
+// deferArtefact adds the artefact to the deferred list. If it cannot be stored the
+// artefact is lost to the orchestrator, as it would be if the event were missed.
+func (e *Engine) deferArtefact(ctx context.Context, artefact *blackboard.Artefact, root string, claimOnly bool) {
+	deferred := blackboard.DeferredArtefact{
+		ArtefactID:   artefact.ID,
+		WorkflowID:   root,
+		ClaimOnly:    claimOnly,
+		DeferredAtMs: time.Now().UnixMilli(),
+	}
+	if err := e.client.AppendDeferredArtefact(ctx, deferred); err != nil {
+		log.Printf("[Orchestrator] Error deferring artefact %s: %v", artefact.ID, err)
+		return
+	}
+
+	e.logEvent("artefact_deferred", map[string]interface{}{
+		"artefact_id": artefact.ID,
+		"workflow_id": root,
+		"claim_only":  claimOnly,
+	})
+}
+
+// workflowRoot returns the root artefact of the artefact's workflow, or the artefact
+// itself if its lineage cannot be read.
+func (e *Engine) workflowRoot(ctx context.Context, artefactID string) string {
+	root, err := e.client.WorkflowRoot(ctx, artefactID)
+	if err != nil {
+		log.Printf("[Orchestrator] Warning: Failed to find workflow root of artefact %s: %v", artefactID, err)
+		return artefactID
+	}
+	return root
+}
+
+// handlePauseChange reacts to a pause_events message: it replays whatever is no longer
+// held and, unless the instance is still paused, resumes grant queues that filled up
+// while it was.
+func (e *Engine) handlePauseChange(ctx context.Context) {
+	state, err := e.client.GetPauseState(ctx)
+	if err != nil {
+		log.Printf("[Orchestrator] Error reading pause state: %v", err)
+		return
+	}
+
+	e.logEvent("pause_state_changed", map[string]interface{}{
+		"instance_paused":  state.InstanceSinceMs != 0,
+		"draining":         state.DrainSinceMs != 0,
+		"paused_workflows": state.PausedWorkflows(),
+	})
+
+	if err := e.replayDeferredArtefacts(ctx); err != nil {
+		log.Printf("[Orchestrator] Error replaying deferred artefacts: %v", err)
+	}
+
+	if state.InstanceSinceMs == 0 {
+		e.resumeGrantQueues(ctx)
+	}
+}
+
+// replayDeferredArtefacts processes, in their original order, the deferred artefacts
+// that the current pause state no longer holds. Each entry is removed once replayed;
+// an artefact held again by a drain is simply deferred anew.
+func (e *Engine) replayDeferredArtefacts(ctx context.Context) error {
+	deferred, err := e.client.GetDeferredArtefacts(ctx)
+	if err != nil {
+		return err
+	}
+	if len(deferred) == 0 {
+		return nil
+	}
+
+	state, err := e.client.GetPauseState(ctx)
+	if err != nil {
+		return err
+	}
+
+	replayed := 0
+	for _, d := range deferred {
+		if state.HoldsWorkflow(d.WorkflowID) || (d.ClaimOnly && state.DrainSinceMs != 0) {
+			continue
+		}
+
+		artefact, err := e.client.GetArtefact(ctx, d.ArtefactID)
+		if err != nil {
+			if !blackboard.IsNotFound(err) {
+				return fmt.Errorf("failed to fetch deferred artefact %s: %w", d.ArtefactID, err)
+			}
+			log.Printf("[Orchestrator] Warning: Deferred artefact %s no longer exists, dropping it", d.ArtefactID)
+		} else if d.ClaimOnly {
+			if err := e.processArtefact(ctx, artefact); err != nil {
+				log.Printf("[Orchestrator] Error processing deferred artefact %s: %v", artefact.ID, err)
+			}
+		} else {
+			e.handleArtefact(ctx, artefact)
+		}
+
+		if err := e.client.RemoveDeferredArtefact(ctx, d); err != nil {
+			return err
+		}
+		replayed++
+	}
+
+	if replayed > 0 {
+		e.logEvent("deferred_artefacts_replayed", map[string]interface{}{
+			"replayed":   replayed,
+			"still_held": len(deferred) - replayed,
+		})
+		log.Printf("[Orchestrator] Replayed %d deferred artefacts (%d still held)", replayed, len(deferred)-replayed)
+	}
+	return nil
+}
+
+// isInstancePaused reports whether holt pause (without --workflow) is in effect.
+// A pause state that cannot be read is treated as not paused.
+func (e *Engine) isInstancePaused(ctx context.Context) bool {
+	state, err := e.client.GetPauseState(ctx)
+	if err != nil {
+		log.Printf("[Orchestrator] Warning: Failed to read pause state: %v", err)
+		return false
+	}
+	return state.InstanceSinceMs != 0
+}
+
+// resumeGrantQueues grants queued claims to controllers with free worker slots, as
+// the worker exit callback would have done had the instance not been paused.
+func (e *Engine) resumeGrantQueues(ctx context.Context) {
+	if e.workerManager == nil {
+		return
+	}
+
+	for role, agent := range e.config.Agents {
+		if agent.Mode != "controller" || agent.Worker == nil {
+			continue
+		}
+
+		queueKey := fmt.Sprintf("holt:%s:grant_queue:%s", e.instanceName, role)
+		queued, err := e.client.ZRange(ctx, queueKey, 0, -1)
+		if err != nil {
+			log.Printf("[Orchestrator] Warning: Failed to read grant queue for role '%s': %v", role, err)
+			continue
+		}
+
+		// Each call takes one claim off the queue, so this is bounded by its length
+		for range queued {
+			if e.workerManager.IsAtWorkerLimit(role, agent.Worker.MaxConcurrent) {
+				break
+			}
+			e.handleWorkerSlotAvailable(ctx, role)
+		}
+	}
+}
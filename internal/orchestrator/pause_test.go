@@ -0,0 +1,137 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createPauseTestArtefact stores a standard artefact derived from the given sources.
+func createPauseTestArtefact(t *testing.T, client *blackboard.Client, sources ...string) *blackboard.Artefact {
+	t.Helper()
+	artefact := &blackboard.Artefact{
+		ID:              uuid.New().String(),
+		LogicalID:       uuid.New().String(),
+		Version:         1,
+		StructuralType:  blackboard.StructuralTypeStandard,
+		Type:            "Work",
+		Payload:         "work",
+		SourceArtefacts: append([]string{}, sources...),
+		ProducedByRole:  "user",
+	}
+	require.NoError(t, client.CreateArtefact(context.Background(), artefact))
+	return artefact
+}
+
+func hasClaim(t *testing.T, client *blackboard.Client, artefactID string) bool {
+	t.Helper()
+	_, err := client.GetClaimByArtefactID(context.Background(), artefactID)
+	if blackboard.IsNotFound(err) {
+		return false
+	}
+	require.NoError(t, err)
+	return true
+}
+
+func TestPause_InstanceDefersUntilResume(t *testing.T) {
+	ctx := context.Background()
+	engine, client, _ := setupTestEngine(t)
+
+	require.NoError(t, client.PauseInstance(ctx))
+
+	goal := createPauseTestArtefact(t, client)
+	assert.True(t, engine.deferIfPaused(ctx, goal))
+	assert.False(t, hasClaim(t, client, goal.ID))
+
+	deferred, err := client.GetDeferredArtefacts(ctx)
+	require.NoError(t, err)
+	require.Len(t, deferred, 1)
+	assert.Equal(t, goal.ID, deferred[0].ArtefactID)
+	assert.Equal(t, goal.ID, deferred[0].WorkflowID)
+	assert.False(t, deferred[0].ClaimOnly)
+
+	// A change that leaves the instance paused replays nothing
+	engine.handlePauseChange(ctx)
+	assert.False(t, hasClaim(t, client, goal.ID))
+
+	require.NoError(t, client.ResumeAll(ctx))
+	engine.handlePauseChange(ctx)
+
+	assert.True(t, hasClaim(t, client, goal.ID))
+	deferred, err = client.GetDeferredArtefacts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, deferred)
+}
+
+func TestPause_WorkflowHoldsOnlyThatWorkflow(t *testing.T) {
+	ctx := context.Background()
+	engine, client, _ := setupTestEngine(t)
+
+	pausedGoal := createPauseTestArtefact(t, client)
+	otherGoal := createPauseTestArtefact(t, client)
+	require.NoError(t, client.PauseWorkflow(ctx, pausedGoal.ID))
+
+	derived := createPauseTestArtefact(t, client, pausedGoal.ID)
+	assert.True(t, engine.deferIfPaused(ctx, derived))
+
+	other := createPauseTestArtefact(t, client, otherGoal.ID)
+	assert.False(t, engine.deferIfPaused(ctx, other))
+
+	require.NoError(t, client.ResumeWorkflow(ctx, pausedGoal.ID))
+	engine.handlePauseChange(ctx)
+	assert.True(t, hasClaim(t, client, derived.ID))
+}
+
+func TestPause_DrainHoldsOnlyClaimCreation(t *testing.T) {
+	ctx := context.Background()
+	engine, client, _ := setupTestEngine(t)
+
+	require.NoError(t, client.StartDrain(ctx))
+
+	goal := createPauseTestArtefact(t, client)
+	assert.False(t, engine.deferIfPaused(ctx, goal), "drain does not hold phase tracking")
+	require.NoError(t, engine.processArtefact(ctx, goal))
+	assert.False(t, hasClaim(t, client, goal.ID))
+
+	deferred, err := client.GetDeferredArtefacts(ctx)
+	require.NoError(t, err)
+	require.Len(t, deferred, 1)
+	assert.True(t, deferred[0].ClaimOnly)
+
+	require.NoError(t, client.ResumeAll(ctx))
+	engine.handlePauseChange(ctx)
+	assert.True(t, hasClaim(t, client, goal.ID))
+}
+
+func TestRecoverState_KeepsPauseAndReplaysResumed(t *testing.T) {
+	ctx := context.Background()
+	engine, client, _ := setupTestEngine(t)
+
+	held := createPauseTestArtefact(t, client)
+	resumed := createPauseTestArtefact(t, client)
+	require.NoError(t, client.PauseWorkflow(ctx, held.ID))
+	for _, a := range []*blackboard.Artefact{held, resumed} {
+		require.NoError(t, client.AppendDeferredArtefact(ctx, blackboard.DeferredArtefact{
+			ArtefactID: a.ID,
+			WorkflowID: a.ID,
+		}))
+	}
+
+	// A restarted orchestrator replays what is no longer paused and keeps the rest
+	require.NoError(t, engine.RecoverState(ctx))
+
+	assert.True(t, hasClaim(t, client, resumed.ID))
+	assert.False(t, hasClaim(t, client, held.ID))
+	deferred, err := client.GetDeferredArtefacts(ctx)
+	require.NoError(t, err)
+	require.Len(t, deferred, 1)
+	assert.Equal(t, held.ID, deferred[0].ArtefactID)
+
+	// ...and it still holds new work in the paused workflow
+	derived := createPauseTestArtefact(t, client, held.ID)
+	assert.True(t, engine.deferIfPaused(ctx, derived))
+}
@@ -198,6 +198,12 @@ func (e *Engine) resumeFromQueue(ctx context.Context, role string) (*blackboard.
 func (e *Engine) handleWorkerSlotAvailable(ctx context.Context, role string) {
 	log.Printf("[Orchestrator] Worker slot available for role '%s', checking grant queue", role)
 
+	// holt pause: queued claims stay queued until resume
+	if e.isInstancePaused(ctx) {
+		log.Printf("[Orchestrator] Instance paused, leaving grant queue for role '%s' untouched", role)
+		return
+	}
+
 	// Try to resume next claim from queue
 	claim, err := e.resumeFromQueue(ctx, role)
 	if err != nil {
@@ -212,7 +218,8 @@ func (e *Engine) handleWorkerSlotAvailable(ctx context.Context, role string) {
 
 	// Grant to agent stored in GrantQueue.AgentName (before we cleared it)
 	// M3.7: Agent key IS the role - direct lookup
-	agent, found := e.config.Agents[role]
+	// Runs on the worker monitor goroutine, concurrently with holt reload
+	agent, found := e.agentConfig(role)
 	if !found {
 		log.Printf("[Orchestrator] No agent found for role '%s', cannot resume claim %s", role, claim.ID)
 		return
@@ -15,7 +15,9 @@ import (
 // 2. Scan Redis for active claims
 // 3. Reconstruct in-memory phase state
 // 4. Re-trigger grants for claims missing artefacts
-// 5. Recover grant queues
+// 5. Resume claims whose child workflow finished during the outage
+// 6. Recover grant queues
+// 7. Replay artefacts deferred by holt pause or drain that are no longer held
 func (e *Engine) RecoverState(ctx context.Context) error {
 	log.Printf("[Orchestrator] Starting state recovery...")
 	startTime := time.Now()
@@ -34,6 +36,7 @@ func (e *Engine) RecoverState(ctx context.Context) error {
 		string(blackboard.ClaimStatusPendingParallel),
 		string(blackboard.ClaimStatusPendingExclusive),
 		string(blackboard.ClaimStatusPendingAssignment),
+		string(blackboard.ClaimStatusAwaitingChild),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to scan for active claims: %w", err)
@@ -56,6 +59,12 @@ func (e *Engine) RecoverState(ctx context.Context) error {
 		}
 	}
 
+	// Resume parents whose child workflow finished while we were down
+	if err := e.recoverChildWorkflows(ctx); err != nil {
+		log.Printf("[Orchestrator] Warning: Failed to recover child workflows: %v", err)
+		// Non-fatal - continue
+	}
+
 	// Step 4: Recover grant queues
 	if e.workerManager != nil {
 		if err := e.recoverGrantQueues(ctx); err != nil {
@@ -64,6 +73,19 @@ func (e *Engine) RecoverState(ctx context.Context) error {
 		}
 	}
 
+	// Step 5: Pause state lives in Redis, so a paused instance stays paused; anything
+	// resumed while we were down is replayed now
+	if state, err := e.client.GetPauseState(ctx); err != nil {
+		log.Printf("[Orchestrator] Warning: Failed to read pause state: %v", err)
+	} else if state.IsActive() {
+		log.Printf("[Orchestrator] Recovered pause state: instance_paused=%t draining=%t paused_workflows=%d",
+			state.InstanceSinceMs != 0, state.DrainSinceMs != 0, len(state.Workflows))
+	}
+	if err := e.replayDeferredArtefacts(ctx); err != nil {
+		log.Printf("[Orchestrator] Warning: Failed to replay deferred artefacts: %v", err)
+		// Non-fatal - they are retried on the next pause change
+	}
+
 	duration := time.Since(startTime)
 	e.logEvent("recovery_complete", map[string]interface{}{
 		"claims_recovered":  recoveredCount,
@@ -93,6 +115,15 @@ func (e *Engine) reconstructPhaseState(ctx context.Context, claim *blackboard.Cl
 		return nil
 	}
 
+	// Sub-workflows: parked claims keep their exclusive phase state (ArtefactExpected is
+	// false, so nothing is re-triggered) and resume when their child finishes
+	if claim.Status == blackboard.ClaimStatusAwaitingChild {
+		if claim.ChildGoalID == "" {
+			return fmt.Errorf("claim awaiting child workflow has no child goal")
+		}
+		e.childWorkflows[claim.ChildGoalID] = claim.ID
+	}
+
 	// For phased claims, we need persisted phase state
 	if claim.PhaseState == nil {
 		return fmt.Errorf("claim in phased status (%s) has no persisted phase state", claim.Status)
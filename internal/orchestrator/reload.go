@@ -0,0 +1,160 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+
+	"github.com/dyluth/holt/internal/config"
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+// Workflow events published after handling a configuration revision from holt reload
+const (
+	EventConfigReloaded     = "config_reloaded"
+	EventConfigReloadFailed = "config_reload_failed"
+)
+
+// adoptStoredConfig switches to the configuration holt up or holt reload stored
+// in Redis, if any, so a reload survives orchestrator restarts and failover.
+// Instances started before configurations were stored keep the holt.yml config.
+func (e *Engine) adoptStoredConfig(ctx context.Context) error {
+	stored, err := e.client.GetInstanceConfig(ctx)
+	if err != nil {
+		if blackboard.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	cfg, err := config.Decode([]byte(stored.Config))
+	if err != nil {
+		return fmt.Errorf("stored configuration revision %d: %w", stored.Revision, err)
+	}
+
+	e.ApplyConfig(cfg, stored.WorkerEnv)
+	e.configRevision = stored.Revision
+	log.Printf("[Orchestrator] Using stored configuration revision %d (%d agents)", stored.Revision, len(cfg.Agents))
+	return nil
+}
+
+// handleConfigRevision applies the revision announced on config_events and
+// reports the outcome as a workflow event for the waiting holt reload. It runs on
+// the event loop, so the new configuration takes effect between claims.
+func (e *Engine) handleConfigRevision(ctx context.Context, message string) {
+	revision, err := strconv.Atoi(message)
+	if err != nil {
+		log.Printf("[Orchestrator] Ignoring invalid config revision %q", message)
+		return
+	}
+
+	if err := e.reloadConfig(ctx, revision); err != nil {
+		log.Printf("[Orchestrator] Config reload to revision %d failed: %v", revision, err)
+		e.publishConfigEvent(ctx, EventConfigReloadFailed, map[string]interface{}{
+			"revision": revision,
+			"error":    err.Error(),
+		})
+		return
+	}
+
+	e.publishConfigEvent(ctx, EventConfigReloaded, map[string]interface{}{
+		"revision": e.configRevision,
+		"agents":   e.agentNames(),
+	})
+}
+
+// reloadConfig loads the stored configuration and applies it if it is at least
+// the announced revision. Older stored revisions mean the message was stale.
+func (e *Engine) reloadConfig(ctx context.Context, revision int) error {
+	stored, err := e.client.GetInstanceConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read stored configuration: %w", err)
+	}
+	if stored.Revision < revision {
+		return fmt.Errorf("stored configuration is revision %d, expected %d", stored.Revision, revision)
+	}
+
+	cfg, err := config.Decode([]byte(stored.Config))
+	if err != nil {
+		return err
+	}
+
+	previous := e.agentNames()
+	e.ApplyConfig(cfg, stored.WorkerEnv)
+	e.configRevision = stored.Revision
+
+	added, removed := diffNames(previous, e.agentNames())
+	e.logEvent("config_reloaded", map[string]interface{}{
+		"revision":       stored.Revision,
+		"agents_added":   added,
+		"agents_removed": removed,
+	})
+	return nil
+}
+
+// ApplyConfig replaces the engine's configuration and rebuilds the agent registry.
+// workerEnv is the resolved environment for each controller's workers.
+func (e *Engine) ApplyConfig(cfg *config.HoltConfig, workerEnv map[string][]string) {
+	// M3.7: Agent key IS the role - simplified identity mapping
+	agentRegistry := make(map[string]string, len(cfg.Agents))
+	for agentRole := range cfg.Agents {
+		agentRegistry[agentRole] = agentRole
+	}
+
+	e.configLock.Lock()
+	e.config = cfg
+	e.agentRegistry = agentRegistry
+	e.configLock.Unlock()
+	if e.workerManager != nil {
+		e.workerManager.SetWorkerEnvironment(workerEnv)
+	}
+}
+
+// agentConfig returns the configuration for a role. Safe to call from any goroutine.
+func (e *Engine) agentConfig(role string) (config.Agent, bool) {
+	e.configLock.RLock()
+	defer e.configLock.RUnlock()
+
+	agent, found := e.config.Agents[role]
+	return agent, found
+}
+
+// publishConfigEvent publishes a reload outcome; failures are only logged
+func (e *Engine) publishConfigEvent(ctx context.Context, eventType string, data map[string]interface{}) {
+	if err := e.client.PublishWorkflowEvent(ctx, eventType, data); err != nil {
+		log.Printf("[Orchestrator] Failed to publish %s event: %v", eventType, err)
+	}
+}
+
+// agentNames returns the registered agent names, sorted
+func (e *Engine) agentNames() []string {
+	names := make([]string, 0, len(e.agentRegistry))
+	for name := range e.agentRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// diffNames returns the names only in after (added) and only in before (removed)
+func diffNames(before, after []string) (added, removed []string) {
+	inBefore := make(map[string]bool, len(before))
+	for _, name := range before {
+		inBefore[name] = true
+	}
+	inAfter := make(map[string]bool, len(after))
+	for _, name := range after {
+		inAfter[name] = true
+		if !inBefore[name] {
+			added = append(added, name)
+		}
+	}
+	for _, name := range before {
+		if !inAfter[name] {
+			removed = append(removed, name)
+		}
+	}
+	return added, removed
+}
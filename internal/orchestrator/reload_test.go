@@ -0,0 +1,186 @@
+package orchestrator
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dyluth/holt/internal/config"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const reloadBaseConfig = `version: "1.0"
+agents:
+  Coder:
+    image: coder:latest
+    command: [/app/run.sh]
+    bidding_strategy: exclusive
+  Reviewer:
+    image: reviewer:latest
+    command: [/app/review.sh]
+    bidding_strategy: review
+`
+
+const reloadNewConfig = `version: "1.0"
+orchestrator:
+  max_review_iterations: 7
+agents:
+  Coder:
+    image: coder:v2
+    command: [/app/run.sh]
+    bidding_strategy: exclusive
+  Tester:
+    image: tester:latest
+    command: [/app/test.sh]
+    bidding_strategy: claim
+`
+
+func setupReloadEngine(t *testing.T) (*Engine, *blackboard.Client) {
+	t.Helper()
+	engine, client, _ := setupTestEngine(t)
+
+	cfg, err := config.Decode([]byte(reloadBaseConfig))
+	require.NoError(t, err)
+	engine.ApplyConfig(cfg, nil)
+	return engine, client
+}
+
+func TestApplyConfig_RebuildsRegistry(t *testing.T) {
+	engine, _ := setupReloadEngine(t)
+	assert.Equal(t, []string{"Coder", "Reviewer"}, engine.agentNames())
+
+	cfg, err := config.Decode([]byte(reloadNewConfig))
+	require.NoError(t, err)
+	engine.ApplyConfig(cfg, nil)
+
+	assert.Equal(t, []string{"Coder", "Tester"}, engine.agentNames())
+	assert.Equal(t, "coder:v2", engine.config.Agents["Coder"].Image)
+	assert.Equal(t, 7, *engine.config.Orchestrator.MaxReviewIterations)
+}
+
+// Worker monitors resume grant queues on their own goroutine; run with -race
+func TestApplyConfig_ConcurrentWithWorkerExit(t *testing.T) {
+	engine, client := setupReloadEngine(t)
+	ctx := context.Background()
+
+	const queued = 20
+	for i := 0; i < queued; i++ {
+		claim := &blackboard.Claim{
+			ID:                    uuid.New().String(),
+			ArtefactID:            createPauseTestArtefact(t, client).ID,
+			Status:                blackboard.ClaimStatusPendingExclusive,
+			GrantedReviewAgents:   []string{},
+			GrantedParallelAgents: []string{},
+		}
+		require.NoError(t, client.CreateClaim(ctx, claim))
+		require.NoError(t, engine.pauseGrantForQueue(ctx, claim, "Coder", "Coder"))
+	}
+
+	base, err := config.Decode([]byte(reloadBaseConfig))
+	require.NoError(t, err)
+	next, err := config.Decode([]byte(reloadNewConfig))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < queued; i++ {
+			engine.handleWorkerSlotAvailable(ctx, "Coder")
+		}
+	}()
+	for i := 0; i < queued; i++ {
+		if i%2 == 0 {
+			engine.ApplyConfig(next, nil)
+		} else {
+			engine.ApplyConfig(base, nil)
+		}
+	}
+	wg.Wait()
+
+	remaining, err := client.ZRange(ctx, "holt:test-instance:grant_queue:Coder", 0, -1)
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+}
+
+func TestHandleConfigRevision(t *testing.T) {
+	ctx := context.Background()
+	engine, client := setupReloadEngine(t)
+
+	sub, err := client.SubscribeWorkflowEvents(ctx)
+	require.NoError(t, err)
+	defer sub.Close()
+	time.Sleep(10 * time.Millisecond)
+
+	stored := &blackboard.InstanceConfig{Config: reloadNewConfig}
+	require.NoError(t, client.SaveInstanceConfig(ctx, stored))
+
+	engine.handleConfigRevision(ctx, "1")
+
+	assert.Equal(t, 1, engine.configRevision)
+	assert.Equal(t, []string{"Coder", "Tester"}, engine.agentNames())
+
+	select {
+	case event := <-sub.Events():
+		assert.Equal(t, EventConfigReloaded, event.Event)
+		assert.Equal(t, float64(1), event.Data["revision"])
+		assert.Equal(t, []interface{}{"Coder", "Tester"}, event.Data["agents"])
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for config_reloaded event")
+	}
+}
+
+func TestHandleConfigRevision_InvalidConfigKeepsCurrent(t *testing.T) {
+	ctx := context.Background()
+	engine, client := setupReloadEngine(t)
+
+	sub, err := client.SubscribeWorkflowEvents(ctx)
+	require.NoError(t, err)
+	defer sub.Close()
+	time.Sleep(10 * time.Millisecond)
+
+	require.NoError(t, client.SaveInstanceConfig(ctx, &blackboard.InstanceConfig{Config: "version: \"1.0\"\nagents: {}\n"}))
+
+	engine.handleConfigRevision(ctx, "1")
+
+	assert.Equal(t, 0, engine.configRevision)
+	assert.Equal(t, []string{"Coder", "Reviewer"}, engine.agentNames(), "a rejected revision leaves the registry untouched")
+
+	select {
+	case event := <-sub.Events():
+		assert.Equal(t, EventConfigReloadFailed, event.Event)
+		assert.Contains(t, event.Data["error"], "invalid configuration")
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for config_reload_failed event")
+	}
+}
+
+func TestAdoptStoredConfig(t *testing.T) {
+	ctx := context.Background()
+	engine, client := setupReloadEngine(t)
+
+	// Nothing stored: keep the holt.yml configuration
+	require.NoError(t, engine.adoptStoredConfig(ctx))
+	assert.Equal(t, []string{"Coder", "Reviewer"}, engine.agentNames())
+
+	require.NoError(t, client.SaveInstanceConfig(ctx, &blackboard.InstanceConfig{Config: reloadBaseConfig}))
+	require.NoError(t, client.SaveInstanceConfig(ctx, &blackboard.InstanceConfig{Config: reloadNewConfig}))
+
+	require.NoError(t, engine.adoptStoredConfig(ctx))
+	assert.Equal(t, 2, engine.configRevision)
+	assert.Equal(t, []string{"Coder", "Tester"}, engine.agentNames())
+}
+
+func TestRegisteredBids(t *testing.T) {
+	engine, _ := setupReloadEngine(t)
+
+	bids := engine.registeredBids(map[string]blackboard.BidType{
+		"Coder":   blackboard.BidTypeExclusive,
+		"Removed": blackboard.BidTypeExclusive,
+	})
+	assert.Equal(t, map[string]blackboard.BidType{"Coder": blackboard.BidTypeExclusive}, bids)
+}
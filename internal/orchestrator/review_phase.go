@@ -144,36 +144,9 @@ func (e *Engine) CheckReviewPhaseCompletion(ctx context.Context, claim *blackboa
 }
 
 // isApproval checks if a review payload represents approval.
-// Approval is indicated by an empty JSON object {} or empty JSON array [].
-// Any other content (including invalid JSON) is treated as feedback.
-//
-// Edge cases:
-//   - "{}" → approval (empty object)
-//   - "[]" → approval (empty array)
-//   - "{\"issue\": \"fix this\"}" → feedback (non-empty object)
-//   - "[\"problem\"]" → feedback (non-empty array)
-//   - "" → feedback (empty string, not JSON)
-//   - "true" → feedback (JSON boolean, not object/array)
-//   - "42" → feedback (JSON number)
-//   - Invalid JSON → feedback
+// See blackboard.IsReviewApproval for the rules.
 func isApproval(payload string) bool {
-	// Attempt to parse as JSON
-	var jsonData interface{}
-	err := json.Unmarshal([]byte(payload), &jsonData)
-	if err != nil {
-		// Not valid JSON → feedback
-		return false
-	}
-
-	// Check if empty object or empty array
-	switch v := jsonData.(type) {
-	case map[string]interface{}:
-		return len(v) == 0 // {} = approval
-	case []interface{}:
-		return len(v) == 0 // [] = approval
-	default:
-		return false // Any other JSON type = feedback
-	}
+	return blackboard.IsReviewApproval(payload)
 }
 
 // publishGrantNotificationWithType publishes a grant notification with claim_type field.
@@ -0,0 +1,277 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+)
+
+// Sub-workflows let an agent holding an exclusive claim ask for a child workflow instead
+// of producing an artefact. The pup creates a root GoalDefined artefact carrying the
+// parent claim ID; the orchestrator then:
+//  1. Parks the parent claim (status awaiting_child) and claims the child goal as usual
+//  2. Restricts bidding on the child's artefacts to the requested agent roles, if any
+//  3. On the first Terminal or Failure artefact descending from the child goal, grants
+//     the parent claim back to its agent with that artefact as child_result_id
+//
+// e.childWorkflows maps each running child goal to its parked parent claim.
+
+// parkParentClaim parks the claim that requested the child goal. Returns an error if the
+// goal does not come from the agent currently holding that claim exclusively, in which
+// case no claim is created for the goal.
+func (e *Engine) parkParentClaim(ctx context.Context, goal *blackboard.Artefact) error {
+	parent, err := e.client.GetClaim(ctx, goal.ParentClaimID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch parent claim %s: %w", goal.ParentClaimID, err)
+	}
+
+	if parent.Status != blackboard.ClaimStatusPendingExclusive {
+		return fmt.Errorf("parent claim %s is %s, not pending_exclusive", parent.ID, parent.Status)
+	}
+	if e.agentRegistry[parent.GrantedExclusiveAgent] != goal.ProducedByRole {
+		return fmt.Errorf("child goal produced by %s but parent claim %s is granted to %s",
+			goal.ProducedByRole, parent.ID, parent.GrantedExclusiveAgent)
+	}
+
+	parent.Status = blackboard.ClaimStatusAwaitingChild
+	parent.ChildGoalID = goal.ID
+	parent.ChildResultID = ""
+	parent.ArtefactExpected = false
+
+	if err := e.client.UpdateClaim(ctx, parent); err != nil {
+		return fmt.Errorf("failed to park parent claim: %w", err)
+	}
+
+	e.childWorkflows[goal.ID] = parent.ID
+
+	e.publishChildWorkflowEvent(ctx, "child_workflow_started", map[string]interface{}{
+		"claim_id":      parent.ID,
+		"child_goal_id": goal.ID,
+		"agent_name":    parent.GrantedExclusiveAgent,
+		"goal":          goal.Payload,
+		"child_agents":  goal.ChildAgents,
+	})
+
+	log.Printf("[Orchestrator] Claim %s parked awaiting child workflow %s", parent.ID, goal.ID)
+	return nil
+}
+
+// rejectChildWorkflow records a child goal that parkParentClaim refused. The requesting
+// pup has already returned from its claim, so a parent claim still held by the goal's
+// producer is terminated instead of waiting for output that will never come. A Failure
+// artefact explains the rejection: derived from the parent's target when the parent is
+// terminated (so retry and enclosing child workflows see it), otherwise from the goal.
+func (e *Engine) rejectChildWorkflow(ctx context.Context, goal *blackboard.Artefact, reason error) {
+	e.logEvent("child_workflow_rejected", map[string]interface{}{
+		"artefact_id":     goal.ID,
+		"parent_claim_id": goal.ParentClaimID,
+		"reason":          reason.Error(),
+	})
+
+	// Never terminate a claim the goal's producer does not hold
+	parent, err := e.client.GetClaim(ctx, goal.ParentClaimID)
+	if err != nil || parent.Status != blackboard.ClaimStatusPendingExclusive ||
+		parent.GrantedExclusiveAgent != goal.ProducedByRole {
+		parent = nil
+	}
+
+	source := goal.ID
+	if parent != nil {
+		source = parent.ArtefactID
+	}
+
+	failure := &blackboard.Artefact{
+		ID:              uuid.New().String(),
+		LogicalID:       uuid.New().String(),
+		Version:         1,
+		StructuralType:  blackboard.StructuralTypeFailure,
+		Type:            "ChildWorkflowRejected",
+		Payload:         fmt.Sprintf("Child workflow goal %s was rejected: %v", goal.ID, reason),
+		SourceArtefacts: []string{source},
+		ProducedByRole:  "orchestrator",
+		CreatedAtMs:     time.Now().UnixMilli(),
+	}
+	if err := e.client.CreateArtefact(ctx, failure); err != nil {
+		log.Printf("[Orchestrator] Failed to create Failure artefact for rejected child goal %s: %v", goal.ID, err)
+	}
+
+	if parent == nil {
+		return
+	}
+
+	parent.Status = blackboard.ClaimStatusTerminated
+	parent.TerminationReason = fmt.Sprintf("Child workflow request rejected: %v", reason)
+	if err := e.client.UpdateClaim(ctx, parent); err != nil {
+		log.Printf("[Orchestrator] Failed to terminate claim %s after rejected child goal: %v", parent.ID, err)
+		return
+	}
+	delete(e.phaseStates, parent.ID)
+
+	log.Printf("[Orchestrator] Claim %s terminated: child workflow goal %s rejected", parent.ID, goal.ID)
+}
+
+// checkChildWorkflowCompletion resumes the parked parent claim when a Terminal or Failure
+// artefact ends a running child workflow. Later results from the same child are ignored.
+func (e *Engine) checkChildWorkflowCompletion(ctx context.Context, artefact *blackboard.Artefact) {
+	if len(e.childWorkflows) == 0 {
+		return
+	}
+	if artefact.StructuralType != blackboard.StructuralTypeTerminal &&
+		artefact.StructuralType != blackboard.StructuralTypeFailure {
+		return
+	}
+
+	goal := e.childGoalFor(ctx, artefact)
+	if goal == nil {
+		return
+	}
+
+	if err := e.resumeParentClaim(ctx, goal, artefact); err != nil {
+		log.Printf("[Orchestrator] Failed to resume parent of child workflow %s: %v", goal.ID, err)
+	}
+}
+
+// resumeParentClaim grants the parked parent claim back to its agent, with the artefact
+// that ended the child workflow as the claim's child result.
+func (e *Engine) resumeParentClaim(ctx context.Context, goal *blackboard.Artefact, result *blackboard.Artefact) error {
+	parentClaimID := e.childWorkflows[goal.ID]
+	delete(e.childWorkflows, goal.ID)
+
+	parent, err := e.client.GetClaim(ctx, parentClaimID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch parent claim %s: %w", parentClaimID, err)
+	}
+
+	if parent.Status != blackboard.ClaimStatusAwaitingChild || parent.ChildGoalID != goal.ID {
+		return fmt.Errorf("parent claim %s is no longer awaiting child %s (status %s)", parent.ID, goal.ID, parent.Status)
+	}
+
+	parent.Status = blackboard.ClaimStatusPendingExclusive
+	parent.ChildResultID = result.ID
+	parent.ArtefactExpected = true
+	parent.LastGrantAgent = parent.GrantedExclusiveAgent
+	parent.LastGrantTime = time.Now().Unix()
+
+	if err := e.client.UpdateClaim(ctx, parent); err != nil {
+		return fmt.Errorf("failed to resume parent claim: %w", err)
+	}
+
+	e.publishChildWorkflowEvent(ctx, "child_workflow_completed", map[string]interface{}{
+		"claim_id":        parent.ID,
+		"child_goal_id":   goal.ID,
+		"agent_name":      parent.GrantedExclusiveAgent,
+		"result_id":       result.ID,
+		"result_type":     result.Type,
+		"structural_type": string(result.StructuralType),
+	})
+
+	log.Printf("[Orchestrator] Child workflow %s finished with %s %s, resuming claim %s",
+		goal.ID, result.StructuralType, result.ID, parent.ID)
+
+	// Same delivery path as a grant lost to a restart: notification or worker launch
+	return e.retriggerGrant(ctx, parent, []string{parent.GrantedExclusiveAgent})
+}
+
+// childGoalFor returns the running child goal the artefact descends from (including the
+// goal itself), or nil. Goals are checked nearest first, so a grandchild's results resume
+// the child claim rather than the outermost parent. The goals come from the memoised
+// lineage (see lineage.go), so this does not walk the ancestry on every claim.
+func (e *Engine) childGoalFor(ctx context.Context, artefact *blackboard.Artefact) *blackboard.Artefact {
+	for _, goal := range e.lineageOf(ctx, artefact).goals {
+		if _, running := e.childWorkflows[goal.ID]; running {
+			return goal
+		}
+	}
+	return nil
+}
+
+// enforceChildAgents downgrades bids on a child workflow's artefacts from roles outside
+// the agent subset the child was requested with.
+func (e *Engine) enforceChildAgents(ctx context.Context, claim *blackboard.Claim, artefact *blackboard.Artefact, bids map[string]blackboard.BidType) map[string]blackboard.BidType {
+	if len(e.childWorkflows) == 0 {
+		return bids
+	}
+
+	goal := e.childGoalFor(ctx, artefact)
+	if goal == nil || len(goal.ChildAgents) == 0 {
+		return bids
+	}
+
+	allowed := make(map[string]bool, len(goal.ChildAgents))
+	for _, role := range goal.ChildAgents {
+		allowed[role] = true
+	}
+
+	enforced := make(map[string]blackboard.BidType, len(bids))
+	for agentName, bid := range bids {
+		enforced[agentName] = bid
+		if bid == blackboard.BidTypeIgnore || allowed[e.agentRegistry[agentName]] {
+			continue
+		}
+
+		enforced[agentName] = blackboard.BidTypeIgnore
+		e.logEvent("child_workflow_bid_ignored", map[string]interface{}{
+			"claim_id":      claim.ID,
+			"agent_name":    agentName,
+			"bid_type":      string(bid),
+			"child_goal_id": goal.ID,
+		})
+	}
+
+	return enforced
+}
+
+// recoverChildWorkflows resumes parents whose child workflow finished while no
+// orchestrator was running. Only Terminal and Failure artefacts created since the oldest
+// running child goal can end one, so those are read from the results index, oldest first.
+func (e *Engine) recoverChildWorkflows(ctx context.Context) error {
+	if len(e.childWorkflows) == 0 {
+		return nil
+	}
+
+	var sinceMs int64 = -1
+	for goalID := range e.childWorkflows {
+		goal, err := e.client.GetArtefact(ctx, goalID)
+		if err != nil {
+			// Without the goal's creation time, every indexed result has to be checked
+			log.Printf("[Orchestrator] Failed to fetch child goal %s during recovery: %v", goalID, err)
+			sinceMs = 0
+			break
+		}
+		if sinceMs < 0 || goal.CreatedAtMs < sinceMs {
+			sinceMs = goal.CreatedAtMs
+		}
+	}
+
+	ids, err := e.client.GetResultsSince(ctx, sinceMs)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		result, err := e.client.GetArtefact(ctx, id)
+		if err != nil {
+			continue
+		}
+		e.checkChildWorkflowCompletion(ctx, result)
+	}
+
+	return nil
+}
+
+// publishChildWorkflowEvent logs and publishes a child workflow lifecycle event
+func (e *Engine) publishChildWorkflowEvent(ctx context.Context, eventType string, eventData map[string]interface{}) {
+	logData := make(map[string]interface{}, len(eventData))
+	for k, v := range eventData {
+		logData[k] = v
+	}
+	e.logEvent(eventType, logData)
+
+	if err := e.client.PublishWorkflowEvent(ctx, eventType, eventData); err != nil {
+		log.Printf("[Orchestrator] Failed to publish %s event: %v", eventType, err)
+	}
+}
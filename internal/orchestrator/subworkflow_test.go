@@ -0,0 +1,279 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestArtefact(t *testing.T, bbClient *blackboard.Client, structuralType blackboard.StructuralType, artefactType, producer string, sources ...string) *blackboard.Artefact {
+	t.Helper()
+	if sources == nil {
+		sources = []string{}
+	}
+	artefact := &blackboard.Artefact{
+		ID:              uuid.New().String(),
+		LogicalID:       uuid.New().String(),
+		Version:         1,
+		StructuralType:  structuralType,
+		Type:            artefactType,
+		Payload:         "payload",
+		SourceArtefacts: sources,
+		ProducedByRole:  producer,
+		CreatedAtMs:     time.Now().UnixMilli(),
+	}
+	require.NoError(t, bbClient.CreateArtefact(context.Background(), artefact))
+	return artefact
+}
+
+// newExclusiveClaim creates a claim granted exclusively to Coder, as GrantClaim leaves it
+func newExclusiveClaim(t *testing.T, engine *Engine, bbClient *blackboard.Client) *blackboard.Claim {
+	t.Helper()
+	target := newTestArtefact(t, bbClient, blackboard.StructuralTypeStandard, "GoalDefined", "user")
+	claim := &blackboard.Claim{
+		ID:                    uuid.New().String(),
+		ArtefactID:            target.ID,
+		Status:                blackboard.ClaimStatusPendingExclusive,
+		GrantedReviewAgents:   []string{},
+		GrantedParallelAgents: []string{},
+		GrantedExclusiveAgent: "Coder",
+		PhaseState: &blackboard.PhaseState{
+			Current:       "exclusive",
+			GrantedAgents: []string{"Coder"},
+			Received:      map[string]string{},
+			AllBids:       map[string]blackboard.BidType{"Coder": blackboard.BidTypeExclusive},
+		},
+	}
+	require.NoError(t, bbClient.CreateClaim(context.Background(), claim))
+	engine.phaseStates[claim.ID] = &PhaseState{
+		ClaimID:           claim.ID,
+		Phase:             "exclusive",
+		GrantedAgents:     []string{"Coder"},
+		ReceivedArtefacts: map[string]string{},
+	}
+	return claim
+}
+
+func newChildGoal(t *testing.T, bbClient *blackboard.Client, parentClaimID, producer string, agents ...string) *blackboard.Artefact {
+	t.Helper()
+	goal := &blackboard.Artefact{
+		ID:              uuid.New().String(),
+		LogicalID:       uuid.New().String(),
+		Version:         1,
+		StructuralType:  blackboard.StructuralTypeStandard,
+		Type:            "GoalDefined",
+		Payload:         "Research SSO providers",
+		SourceArtefacts: []string{},
+		ProducedByRole:  producer,
+		CreatedAtMs:     time.Now().UnixMilli(),
+		ParentClaimID:   parentClaimID,
+		ChildAgents:     agents,
+	}
+	require.NoError(t, bbClient.CreateArtefact(context.Background(), goal))
+	return goal
+}
+
+func TestSubWorkflow_ParkAndResume(t *testing.T) {
+	ctx := context.Background()
+	engine, bbClient := setupTestEngineWithMaxIterations(t, 3)
+
+	sub, err := bbClient.SubscribeWorkflowEvents(ctx)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	parent := newExclusiveClaim(t, engine, bbClient)
+	goal := newChildGoal(t, bbClient, parent.ID, "Coder")
+
+	require.NoError(t, engine.parkParentClaim(ctx, goal))
+
+	parked, err := bbClient.GetClaim(ctx, parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, blackboard.ClaimStatusAwaitingChild, parked.Status)
+	assert.Equal(t, goal.ID, parked.ChildGoalID)
+	assert.Equal(t, parent.ID, engine.childWorkflows[goal.ID])
+
+	// Child work does not complete the parent's exclusive phase
+	work := newTestArtefact(t, bbClient, blackboard.StructuralTypeStandard, "Research", "Coder", goal.ID)
+	engine.processArtefactForPhases(ctx, work)
+	engine.checkChildWorkflowCompletion(ctx, work)
+	parked, err = bbClient.GetClaim(ctx, parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, blackboard.ClaimStatusAwaitingChild, parked.Status)
+
+	// The child's Terminal resumes the parent with the result attached
+	result := newTestArtefact(t, bbClient, blackboard.StructuralTypeTerminal, "Report", "Coder", work.ID)
+	engine.checkChildWorkflowCompletion(ctx, result)
+
+	resumed, err := bbClient.GetClaim(ctx, parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, blackboard.ClaimStatusPendingExclusive, resumed.Status)
+	assert.Equal(t, result.ID, resumed.ChildResultID)
+	assert.True(t, resumed.ArtefactExpected)
+	assert.Empty(t, engine.childWorkflows)
+
+	// A second result from the same child is ignored
+	late := newTestArtefact(t, bbClient, blackboard.StructuralTypeFailure, "ToolExecutionFailure", "Coder", work.ID)
+	engine.checkChildWorkflowCompletion(ctx, late)
+	resumed, err = bbClient.GetClaim(ctx, parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, result.ID, resumed.ChildResultID)
+
+	var started, completed bool
+	deadline := time.After(time.Second)
+	for !(started && completed) {
+		select {
+		case event := <-sub.Events():
+			switch event.Event {
+			case "child_workflow_started":
+				started = true
+				assert.Equal(t, goal.ID, event.Data["child_goal_id"])
+			case "child_workflow_completed":
+				completed = true
+				assert.Equal(t, result.ID, event.Data["result_id"])
+				assert.Equal(t, "Terminal", event.Data["structural_type"])
+			}
+		case <-deadline:
+			t.Fatalf("timeout waiting for child workflow events (started=%v completed=%v)", started, completed)
+		}
+	}
+}
+
+func TestSubWorkflow_ParkRejectsUngrantedProducer(t *testing.T) {
+	ctx := context.Background()
+	engine, bbClient := setupTestEngineWithMaxIterations(t, 3)
+
+	parent := newExclusiveClaim(t, engine, bbClient)
+	goal := newChildGoal(t, bbClient, parent.ID, "Reviewer")
+
+	err := engine.parkParentClaim(ctx, goal)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "granted to Coder")
+
+	unchanged, err := bbClient.GetClaim(ctx, parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, blackboard.ClaimStatusPendingExclusive, unchanged.Status)
+	assert.Empty(t, engine.childWorkflows)
+
+	// processArtefact creates no claim for the rejected goal
+	require.Error(t, engine.processArtefact(ctx, goal))
+	_, err = bbClient.GetClaimByArtefactID(ctx, goal.ID)
+	assert.True(t, blackboard.IsNotFound(err))
+
+	// ...and leaves the claim Coder holds alone, recording the rejection against the goal
+	unchanged, err = bbClient.GetClaim(ctx, parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, blackboard.ClaimStatusPendingExclusive, unchanged.Status)
+	failures, err := engine.failuresFor(ctx, goal.ID)
+	require.NoError(t, err)
+	assert.Len(t, failures, 1)
+}
+
+func TestSubWorkflow_RejectionTerminatesParent(t *testing.T) {
+	ctx := context.Background()
+	engine, bbClient := setupTestEngineWithMaxIterations(t, 3)
+
+	parent := newExclusiveClaim(t, engine, bbClient)
+	goal := newChildGoal(t, bbClient, parent.ID, "Coder")
+
+	// The parent's agent was removed by holt reload while its pup was working
+	delete(engine.agentRegistry, "Coder")
+
+	require.Error(t, engine.processArtefact(ctx, goal))
+	_, err := bbClient.GetClaimByArtefactID(ctx, goal.ID)
+	assert.True(t, blackboard.IsNotFound(err), "no claim is created for the rejected goal")
+
+	terminated, err := bbClient.GetClaim(ctx, parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, blackboard.ClaimStatusTerminated, terminated.Status)
+	assert.Contains(t, terminated.TerminationReason, "Child workflow request rejected")
+	assert.NotContains(t, engine.phaseStates, parent.ID)
+
+	failures, err := engine.failuresFor(ctx, parent.ArtefactID)
+	require.NoError(t, err)
+	require.Len(t, failures, 1)
+	failure, err := bbClient.GetArtefact(ctx, failures[0])
+	require.NoError(t, err)
+	assert.Equal(t, "ChildWorkflowRejected", failure.Type)
+	assert.Contains(t, failure.Payload, goal.ID)
+}
+
+func TestSubWorkflow_EnforceChildAgents(t *testing.T) {
+	ctx := context.Background()
+	engine, bbClient := setupTestEngineWithMaxIterations(t, 3)
+
+	parent := newExclusiveClaim(t, engine, bbClient)
+	goal := newChildGoal(t, bbClient, parent.ID, "Coder", "Coder")
+	require.NoError(t, engine.parkParentClaim(ctx, goal))
+
+	bids := map[string]blackboard.BidType{
+		"Coder":    blackboard.BidTypeExclusive,
+		"Reviewer": blackboard.BidTypeReview,
+	}
+
+	// On the goal itself and on its descendants, only the requested roles may bid
+	for _, artefact := range []*blackboard.Artefact{
+		goal,
+		newTestArtefact(t, bbClient, blackboard.StructuralTypeStandard, "Research", "Coder", goal.ID),
+	} {
+		enforced := engine.enforceChildAgents(ctx, &blackboard.Claim{ID: "c"}, artefact, bids)
+		assert.Equal(t, blackboard.BidTypeExclusive, enforced["Coder"])
+		assert.Equal(t, blackboard.BidTypeIgnore, enforced["Reviewer"])
+	}
+
+	// Descendants resolve through the memoised lineage of their sources, without
+	// re-reading the ancestry (neither artefact below is on the blackboard)
+	draft := &blackboard.Artefact{ID: uuid.New().String(), Type: "Draft", SourceArtefacts: []string{goal.ID}, ProducedByRole: "Coder"}
+	engine.enforceChildAgents(ctx, &blackboard.Claim{ID: "c"}, draft, bids)
+	edit := &blackboard.Artefact{ID: uuid.New().String(), Type: "Edit", SourceArtefacts: []string{draft.ID}, ProducedByRole: "Coder"}
+	enforced := engine.enforceChildAgents(ctx, &blackboard.Claim{ID: "c"}, edit, bids)
+	assert.Equal(t, blackboard.BidTypeIgnore, enforced["Reviewer"])
+
+	// Artefacts outside the child workflow are unaffected
+	outside := newTestArtefact(t, bbClient, blackboard.StructuralTypeStandard, "Design", "user")
+	enforced = engine.enforceChildAgents(ctx, &blackboard.Claim{ID: "c"}, outside, bids)
+	assert.Equal(t, bids, enforced)
+}
+
+func TestSubWorkflow_RecoveryResumesFinishedChild(t *testing.T) {
+	ctx := context.Background()
+	engine, bbClient := setupTestEngineWithMaxIterations(t, 3)
+
+	parent := newExclusiveClaim(t, engine, bbClient)
+	goal := newChildGoal(t, bbClient, parent.ID, "Coder")
+	require.NoError(t, engine.parkParentClaim(ctx, goal))
+
+	// The child finished while no orchestrator was running
+	result := newTestArtefact(t, bbClient, blackboard.StructuralTypeTerminal, "Report", "Coder", goal.ID)
+
+	restarted := NewEngine(bbClient, engine.instanceName, engine.config, nil)
+	require.NoError(t, restarted.RecoverState(ctx))
+
+	resumed, err := bbClient.GetClaim(ctx, parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, blackboard.ClaimStatusPendingExclusive, resumed.Status)
+	assert.Equal(t, result.ID, resumed.ChildResultID)
+	assert.Empty(t, restarted.childWorkflows)
+	assert.Contains(t, restarted.phaseStates, parent.ID)
+}
+
+func TestSubWorkflow_RecoveryKeepsRunningChildParked(t *testing.T) {
+	ctx := context.Background()
+	engine, bbClient := setupTestEngineWithMaxIterations(t, 3)
+
+	parent := newExclusiveClaim(t, engine, bbClient)
+	goal := newChildGoal(t, bbClient, parent.ID, "Coder")
+	require.NoError(t, engine.parkParentClaim(ctx, goal))
+
+	restarted := NewEngine(bbClient, engine.instanceName, engine.config, nil)
+	require.NoError(t, restarted.RecoverState(ctx))
+
+	parked, err := bbClient.GetClaim(ctx, parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, blackboard.ClaimStatusAwaitingChild, parked.Status)
+	assert.Equal(t, parent.ID, restarted.childWorkflows[goal.ID])
+}
@@ -1,6 +1,7 @@
 package orchestrator
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -14,6 +15,7 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/dyluth/holt/internal/config"
 	dockerpkg "github.com/dyluth/holt/internal/docker"
 	"github.com/dyluth/holt/pkg/blackboard"
@@ -49,6 +51,9 @@ type WorkerManager struct {
 
 	// M3.5: Callback invoked when worker slot opens (for grant queue resumption)
 	onWorkerSlotAvailable func(ctx context.Context, role string)
+
+	// Resolved env_file and environment entries by role, from holt up (HOLT_WORKER_ENV)
+	workerEnv map[string][]string
 }
 
 // NewWorkerManager creates a new worker manager
@@ -70,6 +75,15 @@ func (wm *WorkerManager) SetWorkerSlotAvailableCallback(callback func(ctx contex
 	wm.onWorkerSlotAvailable = callback
 }
 
+// SetWorkerEnvironment sets the custom environment passed to each role's workers.
+// holt up resolves it on the host, where env files can be read, and holt reload
+// replaces it.
+func (wm *WorkerManager) SetWorkerEnvironment(env map[string][]string) {
+	wm.workerLock.Lock()
+	wm.workerEnv = env
+	wm.workerLock.Unlock()
+}
+
 // CleanupOrphanedWorkers removes worker containers from previous orchestrator runs (M3.5).
 // Identifies orphans by checking for containers with holt labels but not in activeWorkers map.
 func (wm *WorkerManager) CleanupOrphanedWorkers(ctx context.Context) error {
@@ -171,6 +185,10 @@ func (wm *WorkerManager) LaunchWorker(ctx context.Context, claim *blackboard.Cla
 		},
 		Labels: dockerpkg.BuildLabels(wm.instanceName, uuid.New().String(), wm.workspacePath, "worker"),
 	}
+	// Role and claim labels let `holt logs --agent/--claim` find the worker
+	containerConfig.Labels[dockerpkg.LabelAgentName] = agentRole
+	containerConfig.Labels[dockerpkg.LabelAgentRole] = agentRole
+	containerConfig.Labels[dockerpkg.LabelClaimID] = claim.ID
 
 	// Add HOLT_AGENT_COMMAND environment variable if configured
 	if len(agent.Worker.Command) > 0 {
@@ -190,6 +208,31 @@ func (wm *WorkerManager) LaunchWorker(ctx context.Context, claim *blackboard.Cla
 		containerConfig.Env = append(containerConfig.Env, fmt.Sprintf("HOLT_AGENT_BID_SCRIPT=%s", bidScriptJSON))
 	}
 
+	// Pass workspace mode and isolation so the worker can run the claim in its own worktree
+	if agent.Worker.Workspace != nil && agent.Worker.Workspace.Mode != "" {
+		containerConfig.Env = append(containerConfig.Env, fmt.Sprintf("HOLT_WORKSPACE_MODE=%s", agent.Worker.Workspace.Mode))
+		if agent.Worker.Workspace.Isolation != "" {
+			containerConfig.Env = append(containerConfig.Env, fmt.Sprintf("HOLT_WORKSPACE_ISOLATION=%s", agent.Worker.Workspace.Isolation))
+		}
+	}
+	if agent.AutoCommit {
+		containerConfig.Env = append(containerConfig.Env, "HOLT_AUTO_COMMIT=true")
+	}
+
+	// Add HOLT_AGENT_CONTEXT so workers assemble context like the controller's agent would
+	if agent.Context != nil {
+		contextJSON, err := json.Marshal(agent.Context)
+		if err != nil {
+			return fmt.Errorf("failed to marshal agent context settings to JSON: %w", err)
+		}
+		containerConfig.Env = append(containerConfig.Env, fmt.Sprintf("HOLT_AGENT_CONTEXT=%s", contextJSON))
+	}
+
+	// Custom environment from env_file and environment (interpolated at load)
+	wm.workerLock.RLock()
+	containerConfig.Env = append(containerConfig.Env, wm.workerEnv[agentRole]...)
+	wm.workerLock.RUnlock()
+
 	// Build host config
 	hostConfig := &container.HostConfig{
 		NetworkMode: container.NetworkMode(wm.networkName),
@@ -207,6 +250,16 @@ func (wm *WorkerManager) LaunchWorker(ctx context.Context, claim *blackboard.Cla
 		hostConfig.Mounts = []mount.Mount{mountType}
 	}
 
+	// Secrets are mounted read-only from the host, never passed as env
+	for _, secret := range agent.Secrets {
+		hostConfig.Mounts = append(hostConfig.Mounts, mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   secret.HostPath(wm.workspacePath),
+			Target:   secret.MountPath(),
+			ReadOnly: true,
+		})
+	}
+
 	// Create container
 	resp, err := wm.dockerClient.ContainerCreate(ctx, containerConfig, hostConfig, nil, nil, containerName)
 	if err != nil {
@@ -264,16 +317,22 @@ func (wm *WorkerManager) monitorWorker(ctx context.Context, containerID string,
 	// Wait for container to exit
 	statusCh, errCh := wm.dockerClient.ContainerWait(ctx, containerID, container.WaitConditionNotRunning)
 
+	// Exit code stays -1 when the wait itself fails and the real code is unknown
+	exitCode := -1
 	select {
 	case err := <-errCh:
 		log.Printf("[Orchestrator] Error waiting for worker %s: %v", containerID, err)
 		wm.handleWorkerError(ctx, worker, err, bbClient)
 
 	case status := <-statusCh:
-		log.Printf("[Orchestrator] Worker %s exited with code %d", containerID, status.StatusCode)
-		wm.handleWorkerExit(ctx, worker, int(status.StatusCode), bbClient)
+		exitCode = int(status.StatusCode)
+		log.Printf("[Orchestrator] Worker %s exited with code %d", containerID, exitCode)
+		wm.handleWorkerExit(ctx, worker, exitCode, bbClient)
 	}
 
+	// Persist logs on both paths, before cleanup removes the container
+	wm.persistWorkerLogs(ctx, worker, exitCode, bbClient)
+
 	// M3.5: Cleanup and trigger queue resumption
 	role := wm.cleanupWorker(ctx, containerID)
 
@@ -420,6 +479,40 @@ func (wm *WorkerManager) getWorkerLogs(ctx context.Context, containerID string)
 	return string(logs)
 }
 
+// persistWorkerLogs saves a worker's full, timestamped output to the blackboard so it
+// remains available to `holt logs` after the container is removed
+func (wm *WorkerManager) persistWorkerLogs(ctx context.Context, worker *WorkerState, exitCode int, bbClient *blackboard.Client) {
+	reader, err := wm.dockerClient.ContainerLogs(ctx, worker.ContainerID, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: true,
+	})
+	if err != nil {
+		log.Printf("[Orchestrator] Warning: Failed to capture logs for worker %s: %v", worker.ContainerName, err)
+		return
+	}
+	defer reader.Close()
+
+	// Workers run without a TTY, so stdout and stderr arrive multiplexed
+	var output bytes.Buffer
+	if _, err := stdcopy.StdCopy(&output, &output, reader); err != nil {
+		log.Printf("[Orchestrator] Warning: Failed to read logs for worker %s: %v", worker.ContainerName, err)
+	}
+
+	workerLog := &blackboard.WorkerLog{
+		ClaimID:       worker.ClaimID,
+		Role:          worker.Role,
+		ContainerName: worker.ContainerName,
+		ExitCode:      exitCode,
+		StartedAtMs:   worker.LaunchedAt.UnixMilli(),
+		FinishedAtMs:  time.Now().UnixMilli(),
+		Output:        output.String(),
+	}
+	if err := bbClient.SaveWorkerLog(ctx, workerLog); err != nil {
+		log.Printf("[Orchestrator] Warning: Failed to persist logs for worker %s: %v", worker.ContainerName, err)
+	}
+}
+
 // IsAtWorkerLimit checks if role has reached max concurrent workers
 // M3.4: Used by grant decision logic to pause granting
 func (wm *WorkerManager) IsAtWorkerLimit(role string, maxConcurrent int) bool {
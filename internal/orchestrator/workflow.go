@@ -0,0 +1,74 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+// enforceWorkflows applies the workflows declared in holt.yml on top of bidding.
+// Only workflows triggered by the artefact's lineage apply. Bids from roles that appear in
+// one of their stages are only honoured when a stage allows that role to act on the
+// artefact's type, in the bid's phase, after its upstream stages. Disallowed bids are
+// downgraded to ignore and reported as workflow_bid_rejected events. Roles outside every
+// applicable workflow bid freely, so configs without workflows are unaffected.
+func (e *Engine) enforceWorkflows(ctx context.Context, claim *blackboard.Claim, artefact *blackboard.Artefact, bids map[string]blackboard.BidType) map[string]blackboard.BidType {
+	if e.config == nil || len(e.config.Workflows) == 0 {
+		return bids
+	}
+
+	lineageTypes := e.lineageOf(ctx, artefact).types
+
+	// Deterministic order for logs and events
+	agentNames := make([]string, 0, len(bids))
+	for agentName := range bids {
+		agentNames = append(agentNames, agentName)
+	}
+	sort.Strings(agentNames)
+
+	enforced := make(map[string]blackboard.BidType, len(bids))
+	for _, agentName := range agentNames {
+		bid := bids[agentName]
+		enforced[agentName] = bid
+
+		role := e.agentRegistry[agentName]
+		if bid == blackboard.BidTypeIgnore || !e.config.Workflows.GovernsRole(role, lineageTypes) {
+			continue
+		}
+
+		if _, _, ok := e.config.Workflows.AllowsBid(role, string(bid), artefact.Type, artefact.ProducedByRole, lineageTypes); ok {
+			continue
+		}
+
+		enforced[agentName] = blackboard.BidTypeIgnore
+		reason := fmt.Sprintf("no workflow stage lets %s bid '%s' on %s produced by %s",
+			role, bid, artefact.Type, artefact.ProducedByRole)
+		e.publishWorkflowBidRejected(ctx, claim, artefact, agentName, bid, reason)
+	}
+
+	return enforced
+}
+
+// publishWorkflowBidRejected logs and publishes a workflow_bid_rejected event
+func (e *Engine) publishWorkflowBidRejected(ctx context.Context, claim *blackboard.Claim, artefact *blackboard.Artefact, agentName string, bid blackboard.BidType, reason string) {
+	eventData := map[string]interface{}{
+		"claim_id":      claim.ID,
+		"agent_name":    agentName,
+		"bid_type":      string(bid),
+		"artefact_type": artefact.Type,
+		"reason":        reason,
+	}
+
+	logData := make(map[string]interface{}, len(eventData))
+	for k, v := range eventData {
+		logData[k] = v
+	}
+	e.logEvent("workflow_bid_rejected", logData)
+
+	if err := e.client.PublishWorkflowEvent(ctx, "workflow_bid_rejected", eventData); err != nil {
+		log.Printf("[Orchestrator] Failed to publish workflow_bid_rejected event: %v", err)
+	}
+}
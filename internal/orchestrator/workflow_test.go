@@ -0,0 +1,132 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dyluth/holt/internal/config"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnforceWorkflows(t *testing.T) {
+	engine, bbClient := setupTestEngineWithMaxIterations(t, 3)
+	engine.config.Agents["Designer"] = config.Agent{Image: "test:latest", Command: []string{"test"}, BiddingStrategy: "exclusive"}
+	engine.config.Agents["Linter"] = config.Agent{Image: "test:latest", Command: []string{"test"}, BiddingStrategy: "review"}
+	engine.agentRegistry["Designer"] = "Designer"
+	engine.agentRegistry["Linter"] = "Linter"
+	engine.config.Workflows = config.Workflows{
+		"feature": {
+			Stages: []config.WorkflowStage{
+				{Name: "design", Role: "Designer", Consumes: []string{"GoalDefined"}, Produces: []string{"DesignSpec"}},
+				{Name: "build", Role: "Coder", Consumes: []string{"DesignSpec"}, After: []string{"design"}},
+				{Name: "review", Role: "Reviewer", Phase: config.PhaseReview, Consumes: []string{"DesignSpec"}},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	sub, err := bbClient.SubscribeWorkflowEvents(ctx)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	claim := &blackboard.Claim{ID: uuid.New().String()}
+	goal := &blackboard.Artefact{ID: uuid.New().String(), Type: "GoalDefined", ProducedByRole: "user"}
+
+	// The Coder and Reviewer are only allowed on DesignSpec; the ungoverned Linter is untouched
+	bids := map[string]blackboard.BidType{
+		"Designer": blackboard.BidTypeExclusive,
+		"Coder":    blackboard.BidTypeExclusive,
+		"Reviewer": blackboard.BidTypeReview,
+		"Linter":   blackboard.BidTypeReview,
+	}
+	enforced := engine.enforceWorkflows(ctx, claim, goal, bids)
+	assert.Equal(t, map[string]blackboard.BidType{
+		"Designer": blackboard.BidTypeExclusive,
+		"Coder":    blackboard.BidTypeIgnore,
+		"Reviewer": blackboard.BidTypeIgnore,
+		"Linter":   blackboard.BidTypeReview,
+	}, enforced)
+
+	for _, expected := range []string{"Coder", "Reviewer"} {
+		select {
+		case event := <-sub.Events():
+			assert.Equal(t, "workflow_bid_rejected", event.Event)
+			assert.Equal(t, expected, event.Data["agent_name"])
+			assert.Equal(t, claim.ID, event.Data["claim_id"])
+			assert.Equal(t, "GoalDefined", event.Data["artefact_type"])
+		case <-time.After(time.Second):
+			t.Fatalf("timeout waiting for workflow_bid_rejected event for %s", expected)
+		}
+	}
+
+	// A DesignSpec from the Designer satisfies the build stage's after condition
+	spec := &blackboard.Artefact{Type: "DesignSpec", SourceArtefacts: []string{goal.ID}, ProducedByRole: "Designer"}
+	enforced = engine.enforceWorkflows(ctx, claim, spec, map[string]blackboard.BidType{
+		"Coder":    blackboard.BidTypeExclusive,
+		"Reviewer": blackboard.BidTypeReview,
+	})
+	assert.Equal(t, blackboard.BidTypeExclusive, enforced["Coder"])
+	assert.Equal(t, blackboard.BidTypeReview, enforced["Reviewer"])
+
+	// The same type from anyone else does not
+	rework := &blackboard.Artefact{Type: "DesignSpec", SourceArtefacts: []string{goal.ID}, ProducedByRole: "Reviewer"}
+	enforced = engine.enforceWorkflows(ctx, claim, rework, map[string]blackboard.BidType{
+		"Coder": blackboard.BidTypeExclusive,
+	})
+	assert.Equal(t, blackboard.BidTypeIgnore, enforced["Coder"])
+}
+
+func TestEnforceWorkflows_Trigger(t *testing.T) {
+	engine, bbClient := setupTestEngineWithMaxIterations(t, 3)
+	engine.config.Workflows = config.Workflows{
+		"hotfix": {
+			Trigger: []string{"BugReport"},
+			Stages: []config.WorkflowStage{
+				{Name: "fix", Role: "Coder", Consumes: []string{"BugReport"}, Produces: []string{"CodeCommit"}},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	claim := &blackboard.Claim{ID: uuid.New().String()}
+
+	// Outside the workflow's lineage the Coder bids freely
+	goal := &blackboard.Artefact{ID: uuid.New().String(), Type: "GoalDefined", ProducedByRole: "user"}
+	enforced := engine.enforceWorkflows(ctx, claim, goal, map[string]blackboard.BidType{
+		"Coder": blackboard.BidTypeExclusive,
+	})
+	assert.Equal(t, blackboard.BidTypeExclusive, enforced["Coder"])
+
+	// Descendants of the trigger are governed, with the ancestry read from the blackboard
+	bug := &blackboard.Artefact{
+		ID:              uuid.New().String(),
+		LogicalID:       uuid.New().String(),
+		Version:         1,
+		StructuralType:  blackboard.StructuralTypeStandard,
+		Type:            "BugReport",
+		Payload:         "login fails",
+		SourceArtefacts: []string{},
+		ProducedByRole:  "user",
+	}
+	require.NoError(t, bbClient.CreateArtefact(ctx, bug))
+
+	commit := &blackboard.Artefact{ID: uuid.New().String(), Type: "CodeCommit", SourceArtefacts: []string{bug.ID}, ProducedByRole: "Coder"}
+	enforced = engine.enforceWorkflows(ctx, claim, commit, map[string]blackboard.BidType{
+		"Coder": blackboard.BidTypeExclusive,
+	})
+	assert.Equal(t, blackboard.BidTypeIgnore, enforced["Coder"])
+	assert.Equal(t, []string{"BugReport", "CodeCommit"}, engine.lineages[commit.ID].types)
+}
+
+func TestEnforceWorkflows_NoWorkflows(t *testing.T) {
+	engine, _ := setupTestEngineWithMaxIterations(t, 3)
+
+	bids := map[string]blackboard.BidType{"Coder": blackboard.BidTypeExclusive}
+	enforced := engine.enforceWorkflows(context.Background(), &blackboard.Claim{ID: "c1"},
+		&blackboard.Artefact{Type: "Anything"}, bids)
+	assert.Equal(t, bids, enforced)
+}
@@ -0,0 +1,116 @@
+package pup
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+const (
+	// claimRefPrefix namespaces refs that keep auto-committed worktree commits reachable
+	// after their detached worktree is removed
+	claimRefPrefix = "refs/holt/claims/"
+)
+
+// autoCommitOutput captures the tool's workspace changes as a git commit and rewrites
+// the output payload to the resulting hash. Only Standard and Terminal outputs are
+// committed - Reviews, Questions and Failures describe work rather than produce it.
+// When the tool left the tree clean, the payload is kept unless empty, in which case
+// it is set to the current HEAD so the artefact still points at a commit.
+// For batch outputs only work entries with an empty payload receive the hash, since
+// other entries (e.g. task descriptions) carry their own content.
+func (e *Engine) autoCommitOutput(claim *blackboard.Claim, workDir string, output *ToolOutput) error {
+	if !producesWork(output) {
+		log.Printf("[DEBUG] Skipping auto-commit for %s output: claim_id=%s", output.GetStructuralType(), claim.ID)
+		return nil
+	}
+
+	message := buildCommitMessage(output.Summary, claim, e.config.AgentName)
+	hash, committed, err := commitWorkspaceChanges(workDir, message, e.config.AgentName)
+	if err != nil {
+		return err
+	}
+
+	if !committed {
+		log.Printf("[INFO] Auto-commit found no workspace changes: claim_id=%s head=%s", claim.ID, hash)
+		if output.IsBatch() {
+			fillBatchPayloads(output, hash)
+		} else if output.ArtefactPayload == "" {
+			output.ArtefactPayload = hash
+		}
+		return nil
+	}
+
+	// Commits made in a detached worktree are only reachable from the worktree's HEAD
+	if workDir != e.workspace() {
+		if out, err := runGit(workDir, "update-ref", claimRefPrefix+claim.ID, hash); err != nil {
+			return fmt.Errorf("git update-ref failed: %s", out)
+		}
+	}
+
+	log.Printf("[INFO] Auto-committed workspace changes: claim_id=%s commit=%s", claim.ID, hash)
+	if output.IsBatch() {
+		fillBatchPayloads(output, hash)
+	} else {
+		output.ArtefactPayload = hash
+	}
+	return nil
+}
+
+// producesWork reports whether the output (or any batch entry) is Standard or Terminal
+func producesWork(output *ToolOutput) bool {
+	if !output.IsBatch() {
+		return isWorkStructuralType(output.GetStructuralType())
+	}
+	for _, entry := range output.Artefacts {
+		if isWorkStructuralType(entry.GetStructuralType()) {
+			return true
+		}
+	}
+	return false
+}
+
+// fillBatchPayloads sets the commit hash on batch work entries that have no payload
+func fillBatchPayloads(output *ToolOutput, hash string) {
+	for i := range output.Artefacts {
+		entry := &output.Artefacts[i]
+		if entry.ArtefactPayload == "" && isWorkStructuralType(entry.GetStructuralType()) {
+			entry.ArtefactPayload = hash
+		}
+	}
+}
+
+// isWorkStructuralType reports whether artefacts of this structural type carry produced work
+func isWorkStructuralType(st blackboard.StructuralType) bool {
+	return st == blackboard.StructuralTypeStandard || st == blackboard.StructuralTypeTerminal
+}
+
+// buildCommitMessage derives a commit message from the tool summary.
+// The first summary line becomes the subject; claim and agent trailers are appended
+// so commits can be traced back to the blackboard. An empty summary gets a generated
+// subject, since git would otherwise promote the first trailer to the subject.
+func buildCommitMessage(summary string, claim *blackboard.Claim, agentName string) string {
+	subject, body, _ := strings.Cut(strings.TrimSpace(summary), "\n")
+	if subject = strings.TrimSpace(subject); subject == "" {
+		shortClaimID := claim.ID
+		if len(shortClaimID) > 8 {
+			shortClaimID = shortClaimID[:8]
+		}
+		subject = fmt.Sprintf("holt: %s output for claim %s", agentName, shortClaimID)
+	}
+
+	var b strings.Builder
+	b.WriteString(subject)
+	b.WriteString("\n\n")
+	if body = strings.TrimSpace(body); body != "" {
+		b.WriteString(body)
+		b.WriteString("\n\n")
+	}
+	fmt.Fprintf(&b, "Holt-Claim-Id: %s\n", claim.ID)
+	fmt.Fprintf(&b, "Holt-Artefact-Id: %s\n", claim.ArtefactID)
+	fmt.Fprintf(&b, "Holt-Agent: %s\n", agentName)
+
+	return b.String()
+}
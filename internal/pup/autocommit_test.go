@@ -0,0 +1,125 @@
+package pup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCommitMessage(t *testing.T) {
+	claim := &blackboard.Claim{ID: "claim-1", ArtefactID: "art-1"}
+
+	message := buildCommitMessage("Add login handler\n\nUses bcrypt for hashing.", claim, "coder")
+
+	assert.Equal(t, "Add login handler\n\nUses bcrypt for hashing.\n\n"+
+		"Holt-Claim-Id: claim-1\nHolt-Artefact-Id: art-1\nHolt-Agent: coder\n", message)
+}
+
+func TestBuildCommitMessage_EmptySummary(t *testing.T) {
+	claim := &blackboard.Claim{ID: "4f1c2d3e-aaaa-bbbb-cccc-000000000000", ArtefactID: "art-1"}
+
+	for _, summary := range []string{"", "  \n\t "} {
+		message := buildCommitMessage(summary, claim, "coder")
+		assert.Equal(t, "holt: coder output for claim 4f1c2d3e\n\n"+
+			"Holt-Claim-Id: 4f1c2d3e-aaaa-bbbb-cccc-000000000000\nHolt-Artefact-Id: art-1\nHolt-Agent: coder\n", message)
+	}
+
+	// git parses the claim ID as a trailer, not as the subject
+	repoDir := initTestRepo(t)
+	engine := New(&Config{AgentName: "coder", AutoCommit: true}, nil)
+	engine.workspaceRoot = repoDir
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "login.go"), []byte("package main\n"), 0644))
+	require.NoError(t, engine.autoCommitOutput(claim, repoDir, &ToolOutput{ArtefactType: "CodeCommit"}))
+
+	assert.Equal(t, "holt: coder output for claim 4f1c2d3e", gitOutput(t, repoDir, "log", "-1", "--format=%s"))
+	assert.Equal(t, claim.ID, gitOutput(t, repoDir, "log", "-1", "--format=%(trailers:key=Holt-Claim-Id,valueonly)"))
+}
+
+func TestAutoCommitOutput(t *testing.T) {
+	claim := &blackboard.Claim{ID: "claim-1", ArtefactID: "art-1"}
+
+	t.Run("commits changes and sets payload", func(t *testing.T) {
+		repoDir := initTestRepo(t)
+		engine := New(&Config{AgentName: "coder", AutoCommit: true}, nil)
+		engine.workspaceRoot = repoDir
+
+		require.NoError(t, os.WriteFile(filepath.Join(repoDir, "login.go"), []byte("package main\n"), 0644))
+		output := &ToolOutput{ArtefactType: "CodeCommit", Summary: "Add login handler"}
+
+		require.NoError(t, engine.autoCommitOutput(claim, repoDir, output))
+
+		assert.Equal(t, gitOutput(t, repoDir, "rev-parse", "HEAD"), output.ArtefactPayload)
+		message := gitOutput(t, repoDir, "log", "-1", "--format=%B")
+		assert.Contains(t, message, "Add login handler")
+		assert.Contains(t, message, "Holt-Claim-Id: claim-1")
+		assert.Contains(t, message, "Holt-Agent: coder")
+		assert.Empty(t, gitOutput(t, repoDir, "status", "--porcelain"))
+	})
+
+	t.Run("clean tree keeps HEAD", func(t *testing.T) {
+		repoDir := initTestRepo(t)
+		head := gitOutput(t, repoDir, "rev-parse", "HEAD")
+		engine := New(&Config{AgentName: "coder", AutoCommit: true}, nil)
+		engine.workspaceRoot = repoDir
+
+		output := &ToolOutput{ArtefactType: "CodeCommit", Summary: "Nothing to do"}
+		require.NoError(t, engine.autoCommitOutput(claim, repoDir, output))
+
+		assert.Equal(t, head, output.ArtefactPayload)
+		assert.Equal(t, head, gitOutput(t, repoDir, "rev-parse", "HEAD"))
+	})
+
+	t.Run("review output is not committed", func(t *testing.T) {
+		repoDir := initTestRepo(t)
+		engine := New(&Config{AgentName: "coder", AutoCommit: true}, nil)
+		engine.workspaceRoot = repoDir
+
+		require.NoError(t, os.WriteFile(filepath.Join(repoDir, "notes.txt"), []byte("notes\n"), 0644))
+		output := &ToolOutput{ArtefactType: "Review", ArtefactPayload: "{}", Summary: "Looks good"}
+
+		require.NoError(t, engine.autoCommitOutput(claim, repoDir, output))
+
+		assert.Equal(t, "{}", output.ArtefactPayload)
+		assert.NotEmpty(t, gitOutput(t, repoDir, "status", "--porcelain"))
+	})
+
+	t.Run("batch fills empty work payloads only", func(t *testing.T) {
+		repoDir := initTestRepo(t)
+		engine := New(&Config{AgentName: "coder", AutoCommit: true}, nil)
+		engine.workspaceRoot = repoDir
+
+		require.NoError(t, os.WriteFile(filepath.Join(repoDir, "login.go"), []byte("package main\n"), 0644))
+		output := &ToolOutput{Summary: "Add login", Artefacts: []OutputArtefact{
+			{ArtefactType: "CodeCommit"},
+			{ArtefactType: "Task", ArtefactPayload: "write tests"},
+			{ArtefactType: "Question", StructuralType: "Question"},
+		}}
+
+		require.NoError(t, engine.autoCommitOutput(claim, repoDir, output))
+
+		assert.Equal(t, gitOutput(t, repoDir, "rev-parse", "HEAD"), output.Artefacts[0].ArtefactPayload)
+		assert.Equal(t, "write tests", output.Artefacts[1].ArtefactPayload)
+		assert.Empty(t, output.Artefacts[2].ArtefactPayload)
+	})
+
+	t.Run("worktree commit stays reachable after removal", func(t *testing.T) {
+		repoDir := initTestRepo(t)
+		engine := New(&Config{AgentName: "coder", AutoCommit: true, WorkspaceMode: "rw", WorkspaceIsolation: "worktree"}, nil)
+		engine.workspaceRoot = repoDir
+
+		workDir, cleanup, err := engine.prepareClaimWorkspace(claim, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(filepath.Join(workDir, "login.go"), []byte("package main\n"), 0644))
+		output := &ToolOutput{ArtefactType: "CodeCommit", Summary: "Add login handler"}
+		require.NoError(t, engine.autoCommitOutput(claim, workDir, output))
+		cleanup()
+
+		assert.Equal(t, output.ArtefactPayload, gitOutput(t, repoDir, "rev-parse", claimRefPrefix+"claim-1"))
+		assert.NoError(t, commitExistsIn(repoDir, output.ArtefactPayload))
+	})
+}
@@ -179,4 +179,47 @@ echo "  claim  "
 		require.NoError(t, err)
 		require.Equal(t, blackboard.BidTypeParallel, bidType, "Should trim whitespace from script output")
 	})
+
+	t.Run("should ignore non-consumed types without running bid script", func(t *testing.T) {
+		// A bid script that would fail the test if executed
+		markerPath := filepath.Join(td, "consumes_marker")
+		markerScriptPath := filepath.Join(td, "marker_bid.sh")
+		markerScript := "#!/bin/sh\ntouch " + markerPath + "\necho exclusive\n"
+		err := os.WriteFile(markerScriptPath, []byte(markerScript), 0755)
+		require.NoError(t, err)
+
+		consumesEngine := &Engine{
+			config: &Config{
+				BidScript: []string{markerScriptPath},
+				Consumes:  []string{"*Spec"},
+			},
+		}
+
+		bidType, err := consumesEngine.determineBidType(ctx, &blackboard.Artefact{Type: "GoalDefined"})
+		require.NoError(t, err)
+		require.Equal(t, blackboard.BidTypeIgnore, bidType, "Should ignore types outside consumes")
+		_, statErr := os.Stat(markerPath)
+		require.True(t, os.IsNotExist(statErr), "Bid script must not run for non-consumed types")
+
+		bidType, err = consumesEngine.determineBidType(ctx, &blackboard.Artefact{Type: "DesignSpec"})
+		require.NoError(t, err)
+		require.Equal(t, blackboard.BidTypeExclusive, bidType, "Should run bid script for consumed types")
+	})
+
+	t.Run("should bid static strategy on consumed types", func(t *testing.T) {
+		consumesEngine := &Engine{
+			config: &Config{
+				BiddingStrategy: blackboard.BidTypeReview,
+				Consumes:        []string{"CodeCommit"},
+			},
+		}
+
+		bidType, err := consumesEngine.determineBidType(ctx, &blackboard.Artefact{Type: "CodeCommit"})
+		require.NoError(t, err)
+		require.Equal(t, blackboard.BidTypeReview, bidType)
+
+		bidType, err = consumesEngine.determineBidType(ctx, &blackboard.Artefact{Type: "GoalDefined"})
+		require.NoError(t, err)
+		require.Equal(t, blackboard.BidTypeIgnore, bidType)
+	})
 }
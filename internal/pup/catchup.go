@@ -0,0 +1,37 @@
+package pup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+// unbidClaims returns claims still waiting for consensus that agentName has not bid
+// on. A pup that starts while a claim is in consensus (e.g. recreated by holt
+// reload) missed its claim event, and the orchestrator waits for every registered
+// agent, so the pup bids on these before handling new events.
+func unbidClaims(ctx context.Context, bbClient *blackboard.Client, agentName string) ([]*blackboard.Claim, error) {
+	claims, err := bbClient.GetClaimsByStatus(ctx, []string{string(blackboard.ClaimStatusPendingReview)})
+	if err != nil {
+		return nil, err
+	}
+
+	var waiting []*blackboard.Claim
+	for _, claim := range claims {
+		// Granted claims are past consensus; late bids would be ignored
+		if len(claim.GrantedReviewAgents) > 0 || len(claim.GrantedParallelAgents) > 0 || claim.GrantedExclusiveAgent != "" {
+			continue
+		}
+
+		bids, err := bbClient.GetAllBids(ctx, claim.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bids for claim %s: %w", claim.ID, err)
+		}
+		if _, hasBid := bids[agentName]; hasBid {
+			continue
+		}
+		waiting = append(waiting, claim)
+	}
+	return waiting, nil
+}
@@ -0,0 +1,42 @@
+package pup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnbidClaims(t *testing.T) {
+	engine, bbClient := setupTestPup(t, "Coder", "Coder")
+	ctx := context.Background()
+
+	newClaim := func(status blackboard.ClaimStatus, exclusive string) *blackboard.Claim {
+		artefact := newWhenArtefact("{}")
+		require.NoError(t, bbClient.CreateArtefact(ctx, artefact))
+		claim := &blackboard.Claim{
+			ID:                    uuid.New().String(),
+			ArtefactID:            artefact.ID,
+			Status:                status,
+			GrantedReviewAgents:   []string{},
+			GrantedParallelAgents: []string{},
+			GrantedExclusiveAgent: exclusive,
+		}
+		require.NoError(t, bbClient.CreateClaim(ctx, claim))
+		return claim
+	}
+
+	waiting := newClaim(blackboard.ClaimStatusPendingReview, "")
+	alreadyBid := newClaim(blackboard.ClaimStatusPendingReview, "")
+	require.NoError(t, bbClient.SetBid(ctx, alreadyBid.ID, "Coder", blackboard.BidTypeExclusive))
+	newClaim(blackboard.ClaimStatusPendingExclusive, "Planner")
+	newClaim(blackboard.ClaimStatusComplete, "Planner")
+
+	claims, err := unbidClaims(ctx, bbClient, engine.config.AgentName)
+	require.NoError(t, err)
+	require.Len(t, claims, 1)
+	assert.Equal(t, waiting.ID, claims[0].ID)
+}
@@ -5,7 +5,12 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path"
+	"strconv"
 
+	"github.com/dyluth/holt/internal/config"
+	"github.com/dyluth/holt/internal/expr"
+	"github.com/dyluth/holt/internal/redact"
 	"github.com/dyluth/holt/pkg/blackboard"
 )
 
@@ -32,6 +37,46 @@ type Config struct {
 
 	// BidScript is the command array to execute for dynamic bidding (from HOLT_AGENT_BID_SCRIPT)
 	BidScript []string
+
+	// Consumes is the list of artefact type globs this agent bids on (from HOLT_AGENT_CONSUMES)
+	// Non-matching artefacts are ignored without running the bid script
+	Consumes []string
+
+	// When is an expression that must be true for the target artefact before bidding (from HOLT_AGENT_WHEN)
+	// Evaluated after consumes; evaluation errors are published as when_evaluation_failed events
+	When string
+
+	// Concurrency is the maximum number of claims executed in parallel (from HOLT_AGENT_CONCURRENCY)
+	// Defaults to 1 (sequential execution)
+	Concurrency int
+
+	// WorkspaceMode is the /workspace mount mode, "ro" or "rw" (from HOLT_WORKSPACE_MODE)
+	// Concurrent executions in an rw workspace are isolated in per-claim git worktrees
+	WorkspaceMode string
+
+	// WorkspaceIsolation selects per-claim isolation, "worktree" or empty (from HOLT_WORKSPACE_ISOLATION)
+	WorkspaceIsolation string
+
+	// AutoCommit commits workspace changes after the tool exits and uses the hash as payload (from HOLT_AUTO_COMMIT)
+	AutoCommit bool
+
+	// Context holds context assembly settings (from HOLT_AGENT_CONTEXT, JSON object)
+	// Nil means defaults: depth 10, Standard/Answer/Review artefacts, no size budget
+	Context *config.ContextConfig
+
+	// Protocol selects how the tool is invoked, "subprocess" (default) or "http" (from HOLT_AGENT_PROTOCOL)
+	Protocol string
+
+	// HTTP holds settings for the http protocol (from HOLT_AGENT_HTTP, JSON object)
+	HTTP *config.HTTPProtocolConfig
+
+	// Secrets redacts the values of secrets mounted at /run/secrets from logs and Failure payloads.
+	// Nil redacts nothing.
+	Secrets *redact.Redactor
+
+	// SkipCommitValidation accepts CodeCommit payloads without checking the git repository.
+	// Not loaded from the environment; set by `holt test` where tool output is scripted.
+	SkipCommitValidation bool
 }
 
 // LoadConfig reads and validates configuration from environment variables.
@@ -40,9 +85,34 @@ type Config struct {
 // at startup before any resources are allocated.
 func LoadConfig() (*Config, error) {
 	cfg := &Config{
-		InstanceName: os.Getenv("HOLT_INSTANCE_NAME"),
-		AgentName:    os.Getenv("HOLT_AGENT_NAME"), // M3.7: This IS the role
-		RedisURL:     os.Getenv("REDIS_URL"),
+		InstanceName:  os.Getenv("HOLT_INSTANCE_NAME"),
+		AgentName:     os.Getenv("HOLT_AGENT_NAME"), // M3.7: This IS the role
+		RedisURL:      os.Getenv("REDIS_URL"),
+		WorkspaceMode: os.Getenv("HOLT_WORKSPACE_MODE"),
+		Concurrency:   1,
+
+		WorkspaceIsolation: os.Getenv("HOLT_WORKSPACE_ISOLATION"),
+		Protocol:           os.Getenv("HOLT_AGENT_PROTOCOL"),
+		When:               os.Getenv("HOLT_AGENT_WHEN"),
+	}
+
+	// Parse auto-commit flag
+	if autoCommitStr := os.Getenv("HOLT_AUTO_COMMIT"); autoCommitStr != "" {
+		autoCommit, err := strconv.ParseBool(autoCommitStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse HOLT_AUTO_COMMIT as boolean: %w", err)
+		}
+		cfg.AutoCommit = autoCommit
+	}
+
+	// Parse execution concurrency
+	concurrencyStr := os.Getenv("HOLT_AGENT_CONCURRENCY")
+	if concurrencyStr != "" {
+		concurrency, err := strconv.Atoi(concurrencyStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse HOLT_AGENT_CONCURRENCY as integer: %w", err)
+		}
+		cfg.Concurrency = concurrency
 	}
 
 	// Parse command array from JSON
@@ -61,12 +131,45 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	// Parse consumes type globs from JSON
+	consumesJSON := os.Getenv("HOLT_AGENT_CONSUMES")
+	if consumesJSON != "" {
+		if err := json.Unmarshal([]byte(consumesJSON), &cfg.Consumes); err != nil {
+			return nil, fmt.Errorf("failed to parse HOLT_AGENT_CONSUMES as JSON array: %w", err)
+		}
+	}
+
+	// Parse context assembly settings from JSON
+	contextJSON := os.Getenv("HOLT_AGENT_CONTEXT")
+	if contextJSON != "" {
+		cfg.Context = &config.ContextConfig{}
+		if err := json.Unmarshal([]byte(contextJSON), cfg.Context); err != nil {
+			return nil, fmt.Errorf("failed to parse HOLT_AGENT_CONTEXT as JSON object: %w", err)
+		}
+	}
+
+	// Parse http protocol settings from JSON
+	httpJSON := os.Getenv("HOLT_AGENT_HTTP")
+	if httpJSON != "" {
+		cfg.HTTP = &config.HTTPProtocolConfig{}
+		if err := json.Unmarshal([]byte(httpJSON), cfg.HTTP); err != nil {
+			return nil, fmt.Errorf("failed to parse HOLT_AGENT_HTTP as JSON object: %w", err)
+		}
+	}
+
 	// Parse bidding strategy (M3.1)
 	biddingStrategyStr := os.Getenv("HOLT_BIDDING_STRATEGY")
 	if biddingStrategyStr != "" {
 		cfg.BiddingStrategy = blackboard.BidType(biddingStrategyStr)
 	}
 
+	// Secrets mounted by holt up and the orchestrator
+	secrets, err := redact.FromDir(config.SecretsDir)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Secrets = secrets
+
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
@@ -112,5 +215,42 @@ func (c *Config) Validate() error {
 		log.Printf("[WARN] No static bidding_strategy configured for agent %s, relying entirely on bid_script", c.AgentName)
 	}
 
+	if c.WorkspaceIsolation != "" && c.WorkspaceIsolation != "worktree" {
+		return fmt.Errorf("invalid HOLT_WORKSPACE_ISOLATION: %s (must be 'worktree' or empty)", c.WorkspaceIsolation)
+	}
+
+	if c.Concurrency < 0 {
+		return fmt.Errorf("HOLT_AGENT_CONCURRENCY must not be negative, got %d", c.Concurrency)
+	}
+
+	if c.Protocol != "" && c.Protocol != config.ProtocolSubprocess && c.Protocol != config.ProtocolHTTP {
+		return fmt.Errorf("invalid HOLT_AGENT_PROTOCOL: %s (must be '%s' or '%s')", c.Protocol, config.ProtocolSubprocess, config.ProtocolHTTP)
+	}
+
+	if c.HTTP != nil {
+		if err := c.HTTP.Validate(); err != nil {
+			return fmt.Errorf("invalid HOLT_AGENT_HTTP: %w", err)
+		}
+	}
+
+	if c.Context != nil {
+		if err := c.Context.Validate(); err != nil {
+			return fmt.Errorf("invalid HOLT_AGENT_CONTEXT: %w", err)
+		}
+	}
+
+	// Validate consumes patterns are well-formed globs
+	for _, pattern := range c.Consumes {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid HOLT_AGENT_CONSUMES pattern '%s': %w", pattern, err)
+		}
+	}
+
+	if c.When != "" {
+		if _, err := expr.Compile(c.When, config.WhenVariables...); err != nil {
+			return fmt.Errorf("invalid HOLT_AGENT_WHEN expression: %w", err)
+		}
+	}
+
 	return nil
 }
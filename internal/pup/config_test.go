@@ -238,3 +238,126 @@ func TestValidate_InvalidConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadConfig_Concurrency(t *testing.T) {
+	t.Setenv("HOLT_INSTANCE_NAME", "test-instance")
+	t.Setenv("HOLT_AGENT_NAME", "test-agent")
+	t.Setenv("REDIS_URL", "redis://localhost:6379")
+	t.Setenv("HOLT_AGENT_COMMAND", `["/app/run.sh"]`)
+	t.Setenv("HOLT_BIDDING_STRATEGY", "exclusive")
+	t.Setenv("HOLT_WORKSPACE_MODE", "rw")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Concurrency != 1 {
+		t.Errorf("Expected default Concurrency=1, got %d", cfg.Concurrency)
+	}
+	if cfg.WorkspaceMode != "rw" {
+		t.Errorf("Expected WorkspaceMode='rw', got '%s'", cfg.WorkspaceMode)
+	}
+
+	t.Setenv("HOLT_AGENT_CONCURRENCY", "4")
+	cfg, err = LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Concurrency != 4 {
+		t.Errorf("Expected Concurrency=4, got %d", cfg.Concurrency)
+	}
+
+	t.Setenv("HOLT_AGENT_CONCURRENCY", "many")
+	if _, err := LoadConfig(); err == nil {
+		t.Error("Expected error for non-integer HOLT_AGENT_CONCURRENCY")
+	}
+}
+
+func TestLoadConfig_AutoCommit(t *testing.T) {
+	t.Setenv("HOLT_INSTANCE_NAME", "test-instance")
+	t.Setenv("HOLT_AGENT_NAME", "test-agent")
+	t.Setenv("REDIS_URL", "redis://localhost:6379")
+	t.Setenv("HOLT_AGENT_COMMAND", `["/app/run.sh"]`)
+	t.Setenv("HOLT_BIDDING_STRATEGY", "exclusive")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.AutoCommit {
+		t.Error("Expected AutoCommit to default to false")
+	}
+
+	t.Setenv("HOLT_AUTO_COMMIT", "true")
+	cfg, err = LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !cfg.AutoCommit {
+		t.Error("Expected AutoCommit=true")
+	}
+
+	t.Setenv("HOLT_AUTO_COMMIT", "sometimes")
+	if _, err := LoadConfig(); err == nil {
+		t.Error("Expected error for non-boolean HOLT_AUTO_COMMIT")
+	}
+}
+
+func TestLoadConfig_Protocol(t *testing.T) {
+	t.Setenv("HOLT_INSTANCE_NAME", "test-instance")
+	t.Setenv("HOLT_AGENT_NAME", "test-agent")
+	t.Setenv("REDIS_URL", "redis://localhost:6379")
+	t.Setenv("HOLT_AGENT_COMMAND", `["/app/serve.sh"]`)
+	t.Setenv("HOLT_BIDDING_STRATEGY", "exclusive")
+	t.Setenv("HOLT_AGENT_PROTOCOL", "http")
+	t.Setenv("HOLT_AGENT_HTTP", `{"port":9100,"path":"/run"}`)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Protocol != "http" {
+		t.Errorf("Expected Protocol=http, got %q", cfg.Protocol)
+	}
+	if cfg.HTTP == nil || cfg.HTTP.Port != 9100 || cfg.HTTP.Path != "/run" {
+		t.Errorf("Expected HTTP settings to be parsed, got %+v", cfg.HTTP)
+	}
+
+	t.Setenv("HOLT_AGENT_HTTP", `{"port":8080}`)
+	if _, err := LoadConfig(); err == nil {
+		t.Error("Expected error for reserved HTTP agent port")
+	}
+
+	t.Setenv("HOLT_AGENT_HTTP", `not-json`)
+	if _, err := LoadConfig(); err == nil {
+		t.Error("Expected error for malformed HOLT_AGENT_HTTP")
+	}
+
+	t.Setenv("HOLT_AGENT_HTTP", "")
+	t.Setenv("HOLT_AGENT_PROTOCOL", "carrier-pigeon")
+	if _, err := LoadConfig(); err == nil {
+		t.Error("Expected error for unknown HOLT_AGENT_PROTOCOL")
+	}
+}
+
+func TestLoadConfig_Context(t *testing.T) {
+	t.Setenv("HOLT_INSTANCE_NAME", "test-instance")
+	t.Setenv("HOLT_AGENT_NAME", "test-agent")
+	t.Setenv("REDIS_URL", "redis://localhost:6379")
+	t.Setenv("HOLT_AGENT_COMMAND", `["/app/run.sh"]`)
+	t.Setenv("HOLT_BIDDING_STRATEGY", "exclusive")
+	t.Setenv("HOLT_AGENT_CONTEXT", `{"max_depth":3,"max_bytes":2048,"overflow":"summarise"}`)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Context == nil || cfg.Context.MaxDepth != 3 || cfg.Context.MaxBytes != 2048 || cfg.Context.Overflow != "summarise" {
+		t.Errorf("Expected context settings to be parsed, got %+v", cfg.Context)
+	}
+
+	t.Setenv("HOLT_AGENT_CONTEXT", `{"overflow":"truncate"}`)
+	if _, err := LoadConfig(); err == nil {
+		t.Error("Expected error for invalid HOLT_AGENT_CONTEXT overflow")
+	}
+}
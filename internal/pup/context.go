@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 
+	"github.com/dyluth/holt/internal/config"
 	"github.com/dyluth/holt/pkg/blackboard"
 )
 
@@ -19,21 +21,31 @@ const (
 //
 // Algorithm (from agent-pup.md):
 //  1. Start with target artefact's source_artefacts
-//  2. M3.3: Also add claim.AdditionalContextIDs for feedback claims
-//  3. For each level (max 10):
+//  2. M3.3: Also add claim.AdditionalContextIDs for feedback claims, and the child
+//     workflow result for claims resumed after a sub-workflow
+//  3. For each level (max_depth, default 10):
 //     - Fetch each artefact from blackboard
 //     - Use thread tracking to get latest version of that logical artefact
 //     - Store latest version in context map (de-duplicates by logical_id)
 //     - Add source_artefacts to next level queue
-//  4. Filter context to Standard and Answer artefacts only
-//  5. Sort chronologically (oldest → newest)
-//  6. Return filtered, sorted context chain
+//  4. Optionally add sibling branches (other artefacts derived from the ancestors)
+//  5. Filter context by structural type (default Standard, Answer, Review) and type globs
+//  6. Sort chronologically (oldest → newest)
+//  7. Apply the payload budget, dropping or summarising oldest artefacts first
 //
-// Returns empty array for root artefacts (no source_artefacts).
-func (e *Engine) assembleContext(ctx context.Context, targetArtefact *blackboard.Artefact, claim *blackboard.Claim) ([]*blackboard.Artefact, error) {
+// Returns empty array for root artefacts (no source_artefacts), plus a report of
+// anything the depth limit or payload budget left out.
+func (e *Engine) assembleContext(ctx context.Context, targetArtefact *blackboard.Artefact, claim *blackboard.Claim) ([]*blackboard.Artefact, *ContextTruncation, error) {
 	log.Printf("[INFO] Assembling context for artefact: artefact_id=%s type=%s",
 		targetArtefact.ID, targetArtefact.Type)
 
+	settings := e.contextSettings()
+	maxDepth := maxContextDepth
+	if settings.MaxDepth > 0 {
+		maxDepth = settings.MaxDepth
+	}
+	truncation := &ContextTruncation{MaxDepth: maxDepth, MaxPayloadBytes: settings.MaxBytes}
+
 	// Initialize BFS queue with target's source artefacts
 	queue := make([]string, len(targetArtefact.SourceArtefacts))
 	copy(queue, targetArtefact.SourceArtefacts)
@@ -45,6 +57,11 @@ func (e *Engine) assembleContext(ctx context.Context, targetArtefact *blackboard
 			len(claim.AdditionalContextIDs))
 	}
 
+	// Sub-workflows: a resumed claim also sees its child workflow's result and history
+	if claim.ChildResultID != "" {
+		queue = append(queue, claim.ChildResultID)
+	}
+
 	// Context map keyed by logical_id for de-duplication
 	// Also serves as cache for GetLatestVersion results
 	contextMap := make(map[string]*blackboard.Artefact)
@@ -55,8 +72,11 @@ func (e *Engine) assembleContext(ctx context.Context, targetArtefact *blackboard
 	// Track seen logical_ids to avoid duplicates
 	seenLogicalIDs := make(map[string]bool)
 
+	// BFS order of discovery, used to keep ordering deterministic
+	discovered := make([]*blackboard.Artefact, 0)
+
 	// BFS traversal
-	for len(queue) > 0 && depth < maxContextDepth {
+	for len(queue) > 0 && depth < maxDepth {
 		depth++
 		currentLevelSize := len(queue)
 
@@ -67,26 +87,11 @@ func (e *Engine) assembleContext(ctx context.Context, targetArtefact *blackboard
 			artefactID := queue[0]
 			queue = queue[1:] // Dequeue (pop from front)
 
-			// Fetch artefact from blackboard
-			artefact, err := e.bbClient.GetArtefact(ctx, artefactID)
-			if err != nil {
-				log.Printf("[WARN] Failed to fetch artefact %s: %v (skipping)", artefactID, err)
-				continue // Skip this artefact, continue traversal
-			}
-
-			if artefact == nil {
-				log.Printf("[WARN] Artefact %s not found (skipping)", artefactID)
+			latestArtefact := e.fetchContextArtefact(ctx, artefactID)
+			if latestArtefact == nil {
 				continue
 			}
 
-			// Get latest version of this logical artefact via thread tracking
-			latestArtefact, err := e.getLatestVersionForContext(ctx, artefact)
-			if err != nil {
-				log.Printf("[WARN] Failed to get latest version for logical_id=%s: %v (using discovered version)",
-					artefact.LogicalID, err)
-				latestArtefact = artefact // Fallback to discovered version
-			}
-
 			// De-duplicate by logical_id (keep first occurrence in BFS order)
 			if seenLogicalIDs[latestArtefact.LogicalID] {
 				log.Printf("[DEBUG] De-duplication: logical_id=%s already in context, skipping",
@@ -96,6 +101,7 @@ func (e *Engine) assembleContext(ctx context.Context, targetArtefact *blackboard
 
 			seenLogicalIDs[latestArtefact.LogicalID] = true
 			contextMap[latestArtefact.LogicalID] = latestArtefact
+			discovered = append(discovered, latestArtefact)
 			log.Printf("[DEBUG] Added to context: logical_id=%s version=%d type=%s",
 				latestArtefact.LogicalID, latestArtefact.Version, latestArtefact.Type)
 
@@ -106,21 +112,183 @@ func (e *Engine) assembleContext(ctx context.Context, targetArtefact *blackboard
 
 	if len(queue) > 0 {
 		log.Printf("[WARN] Depth limit reached: max_depth=%d artefacts_pending=%d",
-			maxContextDepth, len(queue))
+			maxDepth, len(queue))
+		truncation.DepthLimitReached = true
+	}
+
+	// Sibling branches: other work derived from the same ancestors
+	if settings.IncludeSiblings {
+		seenLogicalIDs[targetArtefact.LogicalID] = true // the target derives from its ancestors too
+		siblings, err := e.findSiblingArtefacts(ctx, discovered, seenLogicalIDs)
+		if err != nil {
+			log.Printf("[WARN] Failed to collect sibling branches: %v (continuing without)", err)
+		}
+		for _, sibling := range siblings {
+			contextMap[sibling.LogicalID] = sibling
+			discovered = append(discovered, sibling)
+		}
 	}
 
-	// Filter to Standard and Answer artefacts only
-	filtered := filterContextArtefacts(contextMap)
+	// Filter by structural type and artefact type, preserving discovery order
+	filtered := filterContextArtefactsBy(discovered, settings.StructuralTypes, settings.Types)
+	filtered = includeChildResult(filtered, discovered, claim.ChildResultID)
 	log.Printf("[DEBUG] Context filtering: total=%d filtered_to=%d",
 		len(contextMap), len(filtered))
 
 	// Sort chronologically (oldest → newest)
 	sortedContext := sortContextChronologically(filtered)
 
-	log.Printf("[DEBUG] Context assembly complete: total=%d depth=%d",
-		len(sortedContext), depth)
+	// Fit the payload budget
+	sortedContext = applyContextBudget(sortedContext, settings.MaxBytes, settings.Overflow, truncation)
 
-	return sortedContext, nil
+	log.Printf("[DEBUG] Context assembly complete: total=%d depth=%d payload_bytes=%d",
+		len(sortedContext), depth, truncation.PayloadBytes)
+
+	return sortedContext, truncation, nil
+}
+
+// includeChildResult keeps a child workflow's result in the filtered context even though
+// Terminal and Failure artefacts are normally filtered out: it is the answer the agent
+// asked for.
+func includeChildResult(filtered, discovered []*blackboard.Artefact, childResultID string) []*blackboard.Artefact {
+	if childResultID == "" {
+		return filtered
+	}
+	for _, artefact := range filtered {
+		if artefact.ID == childResultID {
+			return filtered
+		}
+	}
+	for _, artefact := range discovered {
+		if artefact.ID == childResultID {
+			return append(filtered, artefact)
+		}
+	}
+	return filtered
+}
+
+// contextSettings returns the agent's context settings, or defaults if none were configured.
+func (e *Engine) contextSettings() config.ContextConfig {
+	if e.config.Context == nil {
+		return config.ContextConfig{}
+	}
+	return *e.config.Context
+}
+
+// fetchContextArtefact fetches an artefact and resolves it to the latest version of its thread.
+// Returns nil if the artefact cannot be fetched (logged and skipped, never fatal).
+func (e *Engine) fetchContextArtefact(ctx context.Context, artefactID string) *blackboard.Artefact {
+	artefact, err := e.bbClient.GetArtefact(ctx, artefactID)
+	if err != nil {
+		log.Printf("[WARN] Failed to fetch artefact %s: %v (skipping)", artefactID, err)
+		return nil
+	}
+
+	if artefact == nil {
+		log.Printf("[WARN] Artefact %s not found (skipping)", artefactID)
+		return nil
+	}
+
+	// Get latest version of this logical artefact via thread tracking
+	latestArtefact, err := e.getLatestVersionForContext(ctx, artefact)
+	if err != nil {
+		log.Printf("[WARN] Failed to get latest version for logical_id=%s: %v (using discovered version)",
+			artefact.LogicalID, err)
+		return artefact // Fallback to discovered version
+	}
+
+	return latestArtefact
+}
+
+// findSiblingArtefacts returns artefacts derived directly from any of the ancestors
+// that are not themselves already in context (e.g. parallel branches of a workflow).
+// There is no reverse index of derivations, so this scans all artefacts; it is only
+// performed for agents that opt in via context.include_siblings.
+func (e *Engine) findSiblingArtefacts(ctx context.Context, ancestors []*blackboard.Artefact, seenLogicalIDs map[string]bool) ([]*blackboard.Artefact, error) {
+	ancestorIDs := make(map[string]bool, len(ancestors))
+	for _, ancestor := range ancestors {
+		ancestorIDs[ancestor.ID] = true
+	}
+	if len(ancestorIDs) == 0 {
+		return nil, nil
+	}
+
+	ids, err := e.bbClient.ScanArtefacts(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan artefacts: %w", err)
+	}
+
+	siblings := make([]*blackboard.Artefact, 0)
+	for _, id := range ids {
+		artefact, err := e.bbClient.GetArtefact(ctx, id)
+		if err != nil || artefact == nil || seenLogicalIDs[artefact.LogicalID] {
+			continue
+		}
+
+		for _, sourceID := range artefact.SourceArtefacts {
+			if !ancestorIDs[sourceID] {
+				continue
+			}
+			latest := e.fetchContextArtefact(ctx, artefact.ID)
+			if latest == nil || seenLogicalIDs[latest.LogicalID] {
+				break
+			}
+			seenLogicalIDs[latest.LogicalID] = true
+			siblings = append(siblings, latest)
+			log.Printf("[DEBUG] Added sibling to context: logical_id=%s type=%s", latest.LogicalID, latest.Type)
+			break
+		}
+	}
+
+	return siblings, nil
+}
+
+// applyContextBudget trims the chronologically sorted chain to maxBytes of total payload.
+// Oldest artefacts are condensed first: with the summarise strategy their payload is
+// replaced by their summary (when shorter), and any that still don't fit are dropped.
+// The truncation report is updated with what was changed and the final payload size.
+func applyContextBudget(chain []*blackboard.Artefact, maxBytes int, overflow string, truncation *ContextTruncation) []*blackboard.Artefact {
+	total := 0
+	for _, artefact := range chain {
+		total += len(artefact.Payload)
+	}
+
+	if maxBytes <= 0 || total <= maxBytes {
+		truncation.PayloadBytes = total
+		return chain
+	}
+
+	result := make([]*blackboard.Artefact, len(chain))
+	copy(result, chain)
+
+	if overflow == config.ContextOverflowSummarise {
+		for i := 0; i < len(result) && total > maxBytes; i++ {
+			artefact := result[i]
+			if artefact.Summary == "" || len(artefact.Summary) >= len(artefact.Payload) {
+				continue
+			}
+
+			// Copy so the caller's artefact (and thread cache) is not mutated
+			condensed := *artefact
+			condensed.Payload = artefact.Summary
+			total -= len(artefact.Payload) - len(condensed.Payload)
+			result[i] = &condensed
+			truncation.SummarisedArtefactIDs = append(truncation.SummarisedArtefactIDs, artefact.ID)
+		}
+	}
+
+	for len(result) > 0 && total > maxBytes {
+		oldest := result[0]
+		result = result[1:]
+		total -= len(oldest.Payload)
+		truncation.DroppedArtefactIDs = append(truncation.DroppedArtefactIDs, oldest.ID)
+	}
+
+	log.Printf("[INFO] Context exceeded max_bytes=%d: dropped=%d summarised=%d",
+		maxBytes, len(truncation.DroppedArtefactIDs), len(truncation.SummarisedArtefactIDs))
+
+	truncation.PayloadBytes = total
+	return result
 }
 
 // getLatestVersionForContext retrieves the latest version of a logical artefact
@@ -163,26 +331,51 @@ func (e *Engine) getLatestVersionForContext(ctx context.Context, discoveredArtef
 	return latestArtefact, nil
 }
 
-// filterContextArtefacts filters the context map to include only Standard, Answer, and Review artefacts.
+// defaultContextStructuralTypes are included in context when no structural_types are configured.
 // M3.3: Review artefacts are included for feedback claims to provide review feedback to agents.
 // This provides agents with a clean, actionable history without failures or terminal artefacts.
-func filterContextArtefacts(contextMap map[string]*blackboard.Artefact) []*blackboard.Artefact {
-	filtered := make([]*blackboard.Artefact, 0, len(contextMap))
-
-	for _, artefact := range contextMap {
-		if artefact.StructuralType == blackboard.StructuralTypeStandard ||
-			artefact.StructuralType == blackboard.StructuralTypeAnswer ||
-			artefact.StructuralType == blackboard.StructuralTypeReview {
-			filtered = append(filtered, artefact)
-		} else {
+var defaultContextStructuralTypes = []string{
+	string(blackboard.StructuralTypeStandard),
+	string(blackboard.StructuralTypeAnswer),
+	string(blackboard.StructuralTypeReview),
+}
+
+// filterContextArtefactsBy keeps artefacts whose structural type is in structuralTypes
+// (defaulting to Standard, Answer, Review) and whose type matches one of the types globs
+// (all types when empty). Input order is preserved.
+func filterContextArtefactsBy(artefacts []*blackboard.Artefact, structuralTypes, types []string) []*blackboard.Artefact {
+	if len(structuralTypes) == 0 {
+		structuralTypes = defaultContextStructuralTypes
+	}
+
+	filtered := make([]*blackboard.Artefact, 0, len(artefacts))
+	for _, artefact := range artefacts {
+		if !containsStructuralType(structuralTypes, artefact.StructuralType) {
 			log.Printf("[DEBUG] Filtered out artefact: logical_id=%s type=%s structural_type=%s",
 				artefact.LogicalID, artefact.Type, artefact.StructuralType)
+			continue
 		}
+		if len(types) > 0 && !config.MatchArtefactType(types, artefact.Type) {
+			log.Printf("[DEBUG] Filtered out artefact by type: logical_id=%s type=%s",
+				artefact.LogicalID, artefact.Type)
+			continue
+		}
+		filtered = append(filtered, artefact)
 	}
 
 	return filtered
 }
 
+// containsStructuralType reports whether st is listed in structuralTypes
+func containsStructuralType(structuralTypes []string, st blackboard.StructuralType) bool {
+	for _, candidate := range structuralTypes {
+		if blackboard.StructuralType(candidate) == st {
+			return true
+		}
+	}
+	return false
+}
+
 // sortContextChronologically sorts artefacts to provide chronological ordering.
 // Since Artefact structs don't have timestamps in Phase 2, we use BFS traversal order
 // as a proxy for chronological order. The graph structure (source_artefacts relationships)
@@ -201,5 +394,11 @@ func sortContextChronologically(artefacts []*blackboard.Artefact) []*blackboard.
 		sorted[i] = artefacts[len(artefacts)-1-i]
 	}
 
+	// Where creation timestamps are available they are authoritative (e.g. for sibling
+	// branches, which BFS order cannot place); the stable sort keeps BFS order for ties.
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAtMs < sorted[j].CreatedAtMs
+	})
+
 	return sorted
 }
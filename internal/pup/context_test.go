@@ -1,42 +1,48 @@
 package pup
 
 import (
+	"context"
+	"strings"
 	"testing"
 
+	"github.com/dyluth/holt/internal/config"
 	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestFilterContextArtefacts verifies filtering to Standard, Answer, and Review (M3.3)
 func TestFilterContextArtefacts(t *testing.T) {
-	contextMap := map[string]*blackboard.Artefact{
-		"log-1": {
+	artefacts := []*blackboard.Artefact{
+		{
 			LogicalID:      "log-1",
 			Type:           "GoalDefined",
 			StructuralType: blackboard.StructuralTypeStandard,
 		},
-		"log-2": {
+		{
 			LogicalID:      "log-2",
 			Type:           "DesignSpec",
 			StructuralType: blackboard.StructuralTypeStandard,
 		},
-		"log-3": {
+		{
 			LogicalID:      "log-3",
 			Type:           "ToolFailure",
 			StructuralType: blackboard.StructuralTypeFailure,
 		},
-		"log-4": {
+		{
 			LogicalID:      "log-4",
 			Type:           "UserAnswer",
 			StructuralType: blackboard.StructuralTypeAnswer,
 		},
-		"log-5": {
+		{
 			LogicalID:      "log-5",
 			Type:           "CodeReview",
 			StructuralType: blackboard.StructuralTypeReview,
 		},
 	}
 
-	filtered := filterContextArtefacts(contextMap)
+	filtered := filterContextArtefactsBy(artefacts, nil, nil)
 
 	// M3.3: Should include Standard, Answer, and Review (4 artefacts)
 	if len(filtered) != 4 {
@@ -60,10 +66,10 @@ func TestFilterContextArtefacts(t *testing.T) {
 	}
 }
 
-// TestFilterContextArtefacts_EmptyMap verifies empty map returns empty slice
+// TestFilterContextArtefacts_EmptyMap verifies empty input returns empty slice
 func TestFilterContextArtefacts_EmptyMap(t *testing.T) {
-	contextMap := make(map[string]*blackboard.Artefact)
-	filtered := filterContextArtefacts(contextMap)
+	artefacts := []*blackboard.Artefact{}
+	filtered := filterContextArtefactsBy(artefacts, nil, nil)
 
 	if len(filtered) != 0 {
 		t.Errorf("Expected empty filtered slice, got %d artefacts", len(filtered))
@@ -72,22 +78,22 @@ func TestFilterContextArtefacts_EmptyMap(t *testing.T) {
 
 // TestFilterContextArtefacts_AllFiltered verifies all artefacts can be filtered
 func TestFilterContextArtefacts_AllFiltered(t *testing.T) {
-	contextMap := map[string]*blackboard.Artefact{
-		"log-1": {
+	artefacts := []*blackboard.Artefact{
+		{
 			LogicalID:      "log-1",
 			StructuralType: blackboard.StructuralTypeFailure,
 		},
-		"log-2": {
+		{
 			LogicalID:      "log-2",
 			StructuralType: blackboard.StructuralTypeQuestion,
 		},
-		"log-3": {
+		{
 			LogicalID:      "log-3",
 			StructuralType: blackboard.StructuralTypeTerminal,
 		},
 	}
 
-	filtered := filterContextArtefacts(contextMap)
+	filtered := filterContextArtefactsBy(artefacts, nil, nil)
 
 	if len(filtered) != 0 {
 		t.Errorf("Expected all artefacts filtered out, got %d", len(filtered))
@@ -149,3 +155,130 @@ func TestSortContextChronologically_SingleArtefact(t *testing.T) {
 		t.Errorf("Expected 'only' artefact, got %s", sorted[0].LogicalID)
 	}
 }
+
+// TestFilterContextArtefactsBy verifies configured structural types and type globs
+func TestFilterContextArtefactsBy(t *testing.T) {
+	artefacts := []*blackboard.Artefact{
+		{LogicalID: "goal", Type: "GoalDefined", StructuralType: blackboard.StructuralTypeStandard},
+		{LogicalID: "design", Type: "DesignSpec", StructuralType: blackboard.StructuralTypeStandard},
+		{LogicalID: "failure", Type: "ToolExecutionFailure", StructuralType: blackboard.StructuralTypeFailure},
+	}
+
+	filtered := filterContextArtefactsBy(artefacts, nil, []string{"*Spec"})
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "design", filtered[0].LogicalID)
+
+	filtered = filterContextArtefactsBy(artefacts, []string{"Standard", "Failure"}, nil)
+	require.Len(t, filtered, 3)
+	assert.Equal(t, "goal", filtered[0].LogicalID, "input order should be preserved")
+}
+
+// TestApplyContextBudget verifies oldest-first dropping and summarising
+func TestApplyContextBudget(t *testing.T) {
+	chain := func() []*blackboard.Artefact {
+		return []*blackboard.Artefact{
+			{ID: "oldest", Payload: strings.Repeat("a", 100), Summary: "short"},
+			{ID: "middle", Payload: strings.Repeat("b", 100)},
+			{ID: "newest", Payload: strings.Repeat("c", 100)},
+		}
+	}
+
+	t.Run("under budget", func(t *testing.T) {
+		truncation := &ContextTruncation{}
+		result := applyContextBudget(chain(), 1000, "", truncation)
+		assert.Len(t, result, 3)
+		assert.Equal(t, 300, truncation.PayloadBytes)
+		assert.True(t, truncation.IsEmpty())
+	})
+
+	t.Run("drop oldest", func(t *testing.T) {
+		truncation := &ContextTruncation{}
+		result := applyContextBudget(chain(), 250, config.ContextOverflowDropOldest, truncation)
+		require.Len(t, result, 2)
+		assert.Equal(t, "middle", result[0].ID)
+		assert.Equal(t, []string{"oldest"}, truncation.DroppedArtefactIDs)
+		assert.Equal(t, 200, truncation.PayloadBytes)
+	})
+
+	t.Run("summarise", func(t *testing.T) {
+		original := chain()
+		truncation := &ContextTruncation{}
+		result := applyContextBudget(original, 250, config.ContextOverflowSummarise, truncation)
+		require.Len(t, result, 3)
+		assert.Equal(t, "short", result[0].Payload)
+		assert.Equal(t, []string{"oldest"}, truncation.SummarisedArtefactIDs)
+		assert.Empty(t, truncation.DroppedArtefactIDs)
+		assert.Equal(t, 205, truncation.PayloadBytes)
+		assert.Len(t, original[0].Payload, 100, "input artefacts must not be mutated")
+	})
+
+	t.Run("summarise then drop", func(t *testing.T) {
+		truncation := &ContextTruncation{}
+		result := applyContextBudget(chain(), 150, config.ContextOverflowSummarise, truncation)
+		require.Len(t, result, 1)
+		assert.Equal(t, "newest", result[0].ID)
+		assert.Equal(t, []string{"oldest", "middle"}, truncation.DroppedArtefactIDs)
+	})
+}
+
+// TestAssembleContext_Settings verifies max depth reporting and sibling branches
+func TestAssembleContext_Settings(t *testing.T) {
+	ctx := context.Background()
+	engine, bbClient := setupTestPup(t, "Coder", "Coder")
+
+	create := func(artefactType string, createdAt int64, sources ...string) *blackboard.Artefact {
+		id := uuid.New().String()
+		artefact := &blackboard.Artefact{
+			ID:              id,
+			LogicalID:       id,
+			Version:         1,
+			StructuralType:  blackboard.StructuralTypeStandard,
+			Type:            artefactType,
+			Payload:         artefactType,
+			SourceArtefacts: sources,
+			ProducedByRole:  "test",
+			CreatedAtMs:     createdAt,
+		}
+		require.NoError(t, bbClient.CreateArtefact(ctx, artefact))
+		return artefact
+	}
+
+	goal := create("GoalDefined", 1)
+	design := create("DesignSpec", 2, goal.ID)
+	create("TestPlan", 3, goal.ID) // sibling branch of design
+	target := create("CodeCommit", 4, design.ID)
+
+	types := func(chain []*blackboard.Artefact) []string {
+		result := make([]string, len(chain))
+		for i, artefact := range chain {
+			result[i] = artefact.Type
+		}
+		return result
+	}
+
+	claim := &blackboard.Claim{ID: "claim-1", ArtefactID: target.ID}
+
+	t.Run("default settings", func(t *testing.T) {
+		engine.config.Context = nil
+		chain, truncation, err := engine.assembleContext(ctx, target, claim)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"GoalDefined", "DesignSpec"}, types(chain))
+		assert.True(t, truncation.IsEmpty())
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		engine.config.Context = &config.ContextConfig{MaxDepth: 1}
+		chain, truncation, err := engine.assembleContext(ctx, target, claim)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"DesignSpec"}, types(chain))
+		assert.True(t, truncation.DepthLimitReached)
+		assert.Equal(t, 1, truncation.MaxDepth)
+	})
+
+	t.Run("include siblings", func(t *testing.T) {
+		engine.config.Context = &config.ContextConfig{IncludeSiblings: true}
+		chain, _, err := engine.assembleContext(ctx, target, claim)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"GoalDefined", "DesignSpec", "TestPlan"}, types(chain))
+	})
+}
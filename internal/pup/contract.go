@@ -4,120 +4,49 @@ import (
 	"encoding/json"
 	"fmt"
 
-	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/dyluth/holt/pkg/agentsdk"
 )
 
-// ToolInput represents the JSON structure passed to agent tools via stdin.
-// The agent tool reads this JSON from stdin to understand what work to perform.
+// Tool protocols share the ToolInput/ToolOutput contract below:
+//   - subprocess: ToolInput JSON on stdin, ToolOutput JSON on stdout, one process per claim
+//   - http: ToolInput JSON POSTed to a long-running agent, ToolOutput JSON in the response body
 //
-// Contract: The pup marshals this struct to JSON and writes it to the tool's stdin,
-// then immediately closes the stdin pipe.
-//
-// Example JSON:
-//
-//	{
-//	  "claim_type": "exclusive",
-//	  "target_artefact": {
-//	    "id": "abc-123",
-//	    "type": "GoalDefined",
-//	    "payload": "Implement user login",
-//	    ...
-//	  },
-//	  "context_chain": []
-//	}
-type ToolInput struct {
-	// ClaimType indicates the type of claim granted ("exclusive", "claim", "review")
-	// M2.3: Always "exclusive" (hardcoded)
-	// M3+: May be "claim" or "review" for parallel/review phases
-	ClaimType string `json:"claim_type"`
-
-	// TargetArtefact is the full artefact object that the claim is for.
-	// The tool operates on this artefact to produce new work.
-	TargetArtefact *blackboard.Artefact `json:"target_artefact"`
-
-	// ContextChain is the ordered list of ancestor artefacts providing context.
-	// M2.3: Always empty array []
-	// M2.4+: Populated by context assembly algorithm
-	ContextChain []interface{} `json:"context_chain"`
-}
+// HTTP requests also carry these headers so agents can locate per-claim state.
+const (
+	// HeaderClaimID carries the claim being executed
+	HeaderClaimID = agentsdk.HeaderClaimID
+
+	// HeaderWorkDir carries the directory the agent should work in
+	// (/workspace, or the claim's isolated worktree)
+	HeaderWorkDir = agentsdk.HeaderWorkDir
+)
 
-// ToolOutput represents the JSON structure that agent tools write to stdout.
-// The agent tool produces this JSON on stdout to describe the artefact it created.
-//
-// Contract: The tool must write exactly ONE valid JSON object to stdout and exit.
-// Multiple JSON objects or partial JSON will result in a Failure artefact.
+// The contract types are defined once in pkg/agentsdk, which agents build against; the
+// aliases below keep the pup's names for them.
 //
-// Example JSON:
-//
-//	{
-//	  "artefact_type": "CodeCommit",
-//	  "artefact_payload": "abc123def",
-//	  "summary": "Implemented user login feature",
-//	  "structural_type": "Standard"
-//	}
-type ToolOutput struct {
-	// ArtefactType is the user-defined domain type (e.g., "CodeCommit", "DesignSpec")
-	// This becomes the artefact's Type field.
-	// Required - must be non-empty string.
-	ArtefactType string `json:"artefact_type"`
-
-	// ArtefactPayload is the main content of the artefact (git hash, JSON, text)
-	// This becomes the artefact's Payload field.
-	// Required - may be empty string if semantically valid.
-	ArtefactPayload string `json:"artefact_payload"`
-
-	// Summary is a human-readable description of what the tool did.
-	// Required - must be non-empty string.
-	Summary string `json:"summary"`
-
-	// StructuralType optionally specifies the artefact's structural type.
-	// If omitted, defaults to "Standard".
-	// Valid values: "Standard", "Review", "Question", "Answer", "Failure", "Terminal"
-	StructuralType string `json:"structural_type,omitempty"`
-}
-
-// Validate checks that the ToolOutput has all required fields and valid values.
-// Returns an error if validation fails.
-func (o *ToolOutput) Validate() error {
-	if o.ArtefactType == "" {
-		return fmt.Errorf("artefact_type is required and cannot be empty")
-	}
-
-	if o.Summary == "" {
-		return fmt.Errorf("summary is required and cannot be empty")
-	}
-
-	// ArtefactPayload may be empty (e.g., some artefacts have no payload)
-	// StructuralType is optional - will default to "Standard" if empty
-
-	// If StructuralType is provided, validate it's a known type
-	if o.StructuralType != "" {
-		st := blackboard.StructuralType(o.StructuralType)
-		if err := st.Validate(); err != nil {
-			return fmt.Errorf("invalid structural_type: %w", err)
-		}
-	}
-
-	return nil
-}
-
-// GetStructuralType returns the structural type to use for the artefact.
-// It defaults to "Standard" but also automatically maps certain domain-specific
-// types (like "Review") to their correct structural type for system coordination.
-func (o *ToolOutput) GetStructuralType() blackboard.StructuralType {
-	// If agent explicitly sets it, respect their choice.
-	if o.StructuralType != "" {
-		return blackboard.StructuralType(o.StructuralType)
-	}
-
-	// Auto-map certain artefact types to their correct structural type.
-	if o.ArtefactType == "Review" {
-		return blackboard.StructuralTypeReview
-	}
-
-	// Default to Standard for all other types.
-	return blackboard.StructuralTypeStandard
-}
+// Contract: with protocol: subprocess the pup marshals the ToolInput to JSON, writes it to
+// the tool's stdin and closes the pipe; the tool must write exactly ONE valid JSON
+// ToolOutput to stdout and exit. Multiple JSON objects or partial JSON result in a Failure
+// artefact. With protocol: http the same JSON objects are the request and 2xx response
+// bodies. Every output is checked with ToolOutput.Validate before artefacts are created.
+type (
+	// ToolInput is the JSON passed to the agent tool for each claim
+	ToolInput = agentsdk.Input
+
+	// ContextTruncation describes how the context chain was cut down to fit the agent's
+	// context settings (max_depth and max_bytes)
+	ContextTruncation = agentsdk.ContextTruncation
+
+	// ToolOutput is the JSON the agent tool returns: a single artefact, a batch of
+	// artefacts, or a child workflow request
+	ToolOutput = agentsdk.Output
+
+	// ChildWorkflowRequest describes a child workflow requested by a ToolOutput
+	ChildWorkflowRequest = agentsdk.ChildWorkflow
+
+	// OutputArtefact is one entry in a batch ToolOutput
+	OutputArtefact = agentsdk.OutputArtefact
+)
 
 // FailureData represents the structured data stored in Failure artefact payloads.
 // This provides detailed diagnostic information about tool execution failures.
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/dyluth/holt/pkg/agentsdk"
 	"github.com/dyluth/holt/pkg/blackboard"
 )
 
@@ -21,7 +22,7 @@ func TestToolInput_JSONMarshaling(t *testing.T) {
 	input := &ToolInput{
 		ClaimType:      "exclusive",
 		TargetArtefact: artefact,
-		ContextChain:   []interface{}{},
+		ContextChain:   []*blackboard.Artefact{},
 	}
 
 	jsonBytes, err := json.Marshal(input)
@@ -311,3 +312,211 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+// TestToolOutput_Validate_Batch verifies artefacts array validation
+func TestToolOutput_Validate_Batch(t *testing.T) {
+	tests := []struct {
+		name        string
+		stdout      string
+		expectError string
+	}{
+		{
+			name:   "valid fan-out",
+			stdout: `{"summary":"split","artefacts":[{"ref":"plan","artefact_type":"Plan","artefact_payload":"p"},{"artefact_type":"Task","artefact_payload":"t1","source_refs":["plan"]}]}`,
+		},
+		{
+			name:        "mixed with single-artefact fields",
+			stdout:      `{"artefact_type":"Plan","summary":"split","artefacts":[{"artefact_type":"Task"}]}`,
+			expectError: "cannot be combined",
+		},
+		{
+			name:        "missing summary",
+			stdout:      `{"artefacts":[{"artefact_type":"Task"}]}`,
+			expectError: "summary is required",
+		},
+		{
+			name:        "entry missing type",
+			stdout:      `{"summary":"split","artefacts":[{"artefact_payload":"t1"}]}`,
+			expectError: "artefacts[0]: artefact_type is required",
+		},
+		{
+			name:        "forward reference",
+			stdout:      `{"summary":"split","artefacts":[{"artefact_type":"Task","source_refs":["plan"]},{"ref":"plan","artefact_type":"Plan"}]}`,
+			expectError: `source_ref "plan" does not name an earlier artefact`,
+		},
+		{
+			name:        "duplicate ref",
+			stdout:      `{"summary":"split","artefacts":[{"ref":"a","artefact_type":"Task"},{"ref":"a","artefact_type":"Task"}]}`,
+			expectError: `duplicate ref "a"`,
+		},
+		{
+			name:        "invalid structural type",
+			stdout:      `{"summary":"split","artefacts":[{"artefact_type":"Task","structural_type":"Bogus"}]}`,
+			expectError: "artefacts[0]: invalid structural_type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output ToolOutput
+			if err := json.Unmarshal([]byte(tt.stdout), &output); err != nil {
+				t.Fatalf("Failed to unmarshal: %v", err)
+			}
+
+			err := output.Validate()
+			if tt.expectError == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !contains(err.Error(), tt.expectError) {
+				t.Errorf("Expected error containing %q, got: %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+// TestToolOutput_Validate_ChildWorkflow verifies child workflow requests replace the artefact fields
+func TestToolOutput_Validate_ChildWorkflow(t *testing.T) {
+	tests := []struct {
+		name        string
+		stdout      string
+		expectError string
+	}{
+		{
+			name:   "valid request",
+			stdout: `{"summary":"need research","child_workflow":{"goal":"Compare libraries","agents":["Researcher"]}}`,
+		},
+		{
+			name:        "combined with artefact fields",
+			stdout:      `{"artefact_type":"Plan","summary":"need research","child_workflow":{"goal":"Compare libraries"}}`,
+			expectError: "child_workflow cannot be combined",
+		},
+		{
+			name:        "combined with batch",
+			stdout:      `{"summary":"need research","artefacts":[{"artefact_type":"Task"}],"child_workflow":{"goal":"Compare libraries"}}`,
+			expectError: "child_workflow cannot be combined",
+		},
+		{
+			name:        "missing goal",
+			stdout:      `{"summary":"need research","child_workflow":{}}`,
+			expectError: "child_workflow.goal is required",
+		},
+		{
+			name:        "missing summary",
+			stdout:      `{"child_workflow":{"goal":"Compare libraries"}}`,
+			expectError: "summary is required",
+		},
+		{
+			name:        "empty agent role",
+			stdout:      `{"summary":"need research","child_workflow":{"goal":"Compare libraries","agents":[""]}}`,
+			expectError: "child_workflow.agents[0]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output ToolOutput
+			if err := json.Unmarshal([]byte(tt.stdout), &output); err != nil {
+				t.Fatalf("Failed to unmarshal: %v", err)
+			}
+
+			err := output.Validate()
+			if tt.expectError == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !contains(err.Error(), tt.expectError) {
+				t.Errorf("Expected error containing %q, got: %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+// TestToolOutput_Entries verifies single outputs become one entry and batch summaries are inherited
+func TestToolOutput_Entries(t *testing.T) {
+	single := &ToolOutput{ArtefactType: "Review", ArtefactPayload: "{}", Summary: "ok"}
+	entries := single.Entries()
+	if len(entries) != 1 || entries[0].ArtefactType != "Review" || entries[0].GetStructuralType() != blackboard.StructuralTypeReview {
+		t.Errorf("Expected single Review entry, got %+v", entries)
+	}
+
+	batch := &ToolOutput{Summary: "split", Artefacts: []OutputArtefact{
+		{ArtefactType: "Task"},
+		{ArtefactType: "Task", Summary: "own summary"},
+	}}
+	entries = batch.Entries()
+	if entries[0].Summary != "split" || entries[1].Summary != "own summary" {
+		t.Errorf("Expected batch summary to be inherited only when unset, got %+v", entries)
+	}
+}
+
+// TestContract_AgentSDKCompatibility verifies ToolInput JSON round-trips through agentsdk.Input
+// and that outputs built with the agentsdk helpers pass the pup's parsing
+func TestContract_AgentSDKCompatibility(t *testing.T) {
+	goal := &blackboard.Artefact{ID: "goal-1", Type: "GoalDefined", Payload: "build it", StructuralType: blackboard.StructuralTypeStandard}
+	input := ToolInput{
+		ClaimType:           "exclusive",
+		TargetArtefact:      &blackboard.Artefact{ID: "spec-1", Type: "DesignSpec", SourceArtefacts: []string{"goal-1"}},
+		ContextChain:        []*blackboard.Artefact{goal},
+		ContextTruncation:   &ContextTruncation{DepthLimitReached: true, MaxDepth: 3, PayloadBytes: 8},
+		ChildWorkflowResult: &blackboard.Artefact{ID: "result-1", StructuralType: blackboard.StructuralTypeTerminal},
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		t.Fatalf("Failed to marshal ToolInput: %v", err)
+	}
+
+	var sdkInput agentsdk.Input
+	if err := json.Unmarshal(data, &sdkInput); err != nil {
+		t.Fatalf("agentsdk.Input could not parse ToolInput: %v", err)
+	}
+	if sdkInput.TargetArtefact.ID != "spec-1" || len(sdkInput.ContextChain) != 1 || sdkInput.ContextChain[0].Payload != "build it" {
+		t.Errorf("agentsdk.Input lost data: %+v", sdkInput)
+	}
+	if sdkInput.ChildWorkflowResult == nil || sdkInput.ChildWorkflowResult.ID != "result-1" {
+		t.Errorf("agentsdk.Input lost child_workflow_result: %+v", sdkInput.ChildWorkflowResult)
+	}
+	if sdkInput.ContextTruncation == nil || sdkInput.ContextTruncation.MaxDepth != 3 {
+		t.Errorf("agentsdk.Input lost context_truncation: %+v", sdkInput.ContextTruncation)
+	}
+
+	reject, err := agentsdk.Reject("add tests", "needs work")
+	if err != nil {
+		t.Fatalf("Failed to build rejection: %v", err)
+	}
+	outputs := []*agentsdk.Output{
+		agentsdk.NewOutput("CodeCommit", "abc123", "implemented"),
+		agentsdk.Approve("looks good"),
+		reject,
+		agentsdk.Question("which database?"),
+		agentsdk.Failure("no network"),
+		agentsdk.Batch("split", agentsdk.OutputArtefact{Ref: "plan", ArtefactType: "Plan"}, agentsdk.OutputArtefact{ArtefactType: "Task", SourceRefs: []string{"plan"}}),
+		agentsdk.RequestChildWorkflow("compare libraries", "need research", "Researcher"),
+	}
+
+	engine := &Engine{config: &Config{}}
+	for _, sdkOutput := range outputs {
+		data, err := json.Marshal(sdkOutput)
+		if err != nil {
+			t.Fatalf("Failed to marshal agentsdk.Output: %v", err)
+		}
+		output, err := engine.parseToolOutput(string(data))
+		if err != nil {
+			t.Errorf("pup rejected agentsdk output %s: %v", data, err)
+			continue
+		}
+		if output.Summary != sdkOutput.Summary || len(output.Artefacts) != len(sdkOutput.Artefacts) {
+			t.Errorf("pup parsed %s as %+v", data, output)
+		}
+		if (output.ChildWorkflow == nil) != (sdkOutput.ChildWorkflow == nil) {
+			t.Errorf("child_workflow mismatch for %s", data)
+		}
+		if output.StructuralType != sdkOutput.StructuralType {
+			t.Errorf("structural_type mismatch for %s: got %q", data, output.StructuralType)
+		}
+	}
+}
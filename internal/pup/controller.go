@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 
+	holtconfig "github.com/dyluth/holt/internal/config"
 	"github.com/dyluth/holt/pkg/blackboard"
 )
 
@@ -23,6 +24,18 @@ func RunControllerMode(ctx context.Context, config *Config, bbClient *blackboard
 
 	log.Printf("[Controller] Subscribed to claim events")
 
+	// The engine is used only for bid decisions (when expressions), never to execute work
+	engine := New(config, bbClient)
+
+	// Bid on claims created while this controller was not yet subscribed
+	missed, err := unbidClaims(ctx, bbClient, config.AgentName)
+	if err != nil {
+		log.Printf("[Controller] Failed to scan for claims awaiting bids: %v", err)
+	}
+	for _, claim := range missed {
+		submitControllerBid(ctx, engine, claim)
+	}
+
 	// Bidding loop (never executes work)
 	for {
 		select {
@@ -36,16 +49,7 @@ func RunControllerMode(ctx context.Context, config *Config, bbClient *blackboard
 				return nil
 			}
 
-			// Evaluate claim using bidding strategy from config
-			bid := config.BiddingStrategy
-
-			// Submit bid
-			if err := bbClient.SetBid(ctx, claim.ID, config.AgentName, bid); err != nil {
-				log.Printf("[Controller] Failed to submit bid for claim %s: %v", claim.ID, err)
-				continue
-			}
-
-			log.Printf("[Controller] Submitted bid: claim=%s type=%s status=%s", claim.ID, bid, claim.Status)
+			submitControllerBid(ctx, engine, claim)
 
 		case err, ok := <-subscription.Errors():
 			if !ok {
@@ -56,3 +60,35 @@ func RunControllerMode(ctx context.Context, config *Config, bbClient *blackboard
 		}
 	}
 }
+
+// submitControllerBid bids the controller's static strategy on a claim, ignoring
+// artefact types the role does not consume and artefacts its when expression rejects,
+// exactly as a regular agent would
+func submitControllerBid(ctx context.Context, engine *Engine, claim *blackboard.Claim) {
+	config, bbClient := engine.config, engine.bbClient
+
+	// Evaluate claim using bidding strategy from config
+	bid := config.BiddingStrategy
+
+	if len(config.Consumes) > 0 || config.When != "" {
+		target, err := bbClient.GetArtefact(ctx, claim.ArtefactID)
+		switch {
+		case err != nil || target == nil:
+			log.Printf("[Controller] Failed to fetch target artefact %s for bid decision: %v", claim.ArtefactID, err)
+			bid = blackboard.BidTypeIgnore
+		case len(config.Consumes) > 0 && !holtconfig.MatchArtefactType(config.Consumes, target.Type):
+			// Declarative type matching: ignore artefact types this role does not consume
+			bid = blackboard.BidTypeIgnore
+		case !engine.whenAllows(ctx, claim, target):
+			bid = blackboard.BidTypeIgnore
+		}
+	}
+
+	// Submit bid
+	if err := bbClient.SetBid(ctx, claim.ID, config.AgentName, bid); err != nil {
+		log.Printf("[Controller] Failed to submit bid for claim %s: %v", claim.ID, err)
+		return
+	}
+
+	log.Printf("[Controller] Submitted bid: claim=%s type=%s status=%s", claim.ID, bid, claim.Status)
+}
@@ -10,13 +10,15 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/dyluth/holt/internal/config"
+	"github.com/dyluth/holt/internal/expr"
 	"github.com/dyluth/holt/pkg/blackboard"
 )
 
 // Engine represents the core execution logic of the agent pup.
-// It manages two concurrent goroutines:
+// It manages concurrent goroutines:
 //   - Claim Watcher: Monitors for new claims and evaluates bidding opportunities (M2.2+)
-//   - Work Executor: Executes granted work and posts results (M2.3+)
+//   - Work Executors: Execute granted work and post results (M2.3+), one per concurrency slot
 //
 // The engine coordinates these goroutines via a work queue channel and
 // handles graceful shutdown through context cancellation.
@@ -24,6 +26,28 @@ type Engine struct {
 	config   *Config
 	bbClient *blackboard.Client
 	wg       sync.WaitGroup
+
+	// In-flight claim tracking (reported on /healthz)
+	inFlightMu sync.Mutex
+	inFlight   map[string]*InFlightClaim
+
+	// workspaceRoot overrides the /workspace mount location (see SetWorkspace)
+	workspaceRoot string
+
+	// excludeOnce adds the worktrees directory to git's exclude file once per engine;
+	// concurrent executors would otherwise race on the read-check-append
+	excludeOnce sync.Once
+
+	// httpAgent is the long-running agent process when protocol is http (nil for subprocess)
+	httpAgent *httpAgent
+
+	// toolRunner replaces tool execution entirely when set (see SetToolRunner)
+	toolRunner ToolRunner
+
+	// when is the compiled when expression (see whenAllows)
+	whenOnce sync.Once
+	when     *expr.Program
+	whenErr  error
 }
 
 // New creates a new agent pup engine with the provided configuration and blackboard client.
@@ -38,11 +62,13 @@ func New(config *Config, bbClient *blackboard.Client) *Engine {
 	return &Engine{
 		config:   config,
 		bbClient: bbClient,
+		inFlight: make(map[string]*InFlightClaim),
 	}
 }
 
 // Start launches the agent pup's concurrent goroutines and blocks until context cancellation.
-// Creates a work queue channel and starts both the Claim Watcher and Work Executor goroutines.
+// Creates a work queue channel and starts the Claim Watcher plus one Work Executor
+// goroutine per concurrency slot (config.Concurrency, default 1).
 //
 // The method blocks until:
 //   - The provided context is cancelled (normal shutdown)
@@ -54,25 +80,60 @@ func New(config *Config, bbClient *blackboard.Client) *Engine {
 //  3. Goroutines exit their loops and perform cleanup
 //  4. Start() returns once all goroutines complete
 //
+// With protocol http the agent process is started (and its health awaited) before any
+// claims are watched, and stopped after shutdown. If the agent exits unexpectedly the
+// engine shuts down and Start returns an error so the container restarts.
+//
 // Returns nil when shutdown completes successfully.
 func (e *Engine) Start(ctx context.Context) error {
 	log.Printf("[INFO] Agent pup starting for agent='%s' instance='%s'", e.config.AgentName, e.config.InstanceName)
 
-	// Create work queue with buffer size 1
-	// Buffer size 1 allows Claim Watcher to post one claim without blocking
-	workQueue := make(chan *blackboard.Claim, 1)
+	// Crash recovery: remove per-claim worktrees left behind by a previous run
+	e.cleanupStaleWorktrees()
+
+	var agentDone <-chan struct{}
+	if e.config.Protocol == config.ProtocolHTTP {
+		agent, err := startHTTPAgent(ctx, e.config.Command, e.workspace(), e.config.HTTP.WithDefaults())
+		if err != nil {
+			return fmt.Errorf("failed to start HTTP agent: %w", err)
+		}
+		e.httpAgent = agent
+		defer agent.stop()
+		agentDone = agent.done
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Create work queue with one buffer slot per executor
+	// Allows Claim Watcher to post claims without blocking while executors are busy
+	concurrency := e.Concurrency()
+	workQueue := make(chan *blackboard.Claim, concurrency)
 
 	// Launch Claim Watcher goroutine
 	e.wg.Add(1)
 	go e.claimWatcher(ctx, workQueue)
 
-	// Launch Work Executor goroutine
-	e.wg.Add(1)
-	go e.workExecutor(ctx, workQueue)
+	// Launch Work Executor goroutines
+	for i := 0; i < concurrency; i++ {
+		e.wg.Add(1)
+		go e.workExecutor(ctx, workQueue)
+	}
 
-	// Wait for context cancellation
-	<-ctx.Done()
-	log.Printf("[INFO] Shutdown signal received, initiating graceful shutdown")
+	if concurrency > 1 {
+		log.Printf("[INFO] Running %d concurrent work executors", concurrency)
+	}
+
+	// Wait for context cancellation (or the HTTP agent exiting)
+	var agentErr error
+	select {
+	case <-ctx.Done():
+		log.Printf("[INFO] Shutdown signal received, initiating graceful shutdown")
+	case <-agentDone:
+		agentErr = fmt.Errorf("HTTP agent process exited unexpectedly: %v", e.httpAgent.exitError())
+		log.Printf("[ERROR] %v, shutting down", agentErr)
+		cancel()
+	}
 
 	// Close work queue to signal Work Executor that no more work will arrive
 	close(workQueue)
@@ -81,7 +142,7 @@ func (e *Engine) Start(ctx context.Context) error {
 	e.wg.Wait()
 	log.Printf("[INFO] All goroutines exited, shutdown complete")
 
-	return nil
+	return agentErr
 }
 
 // claimWatcher monitors for new claims and grant notifications.
@@ -118,6 +179,16 @@ func (e *Engine) claimWatcher(ctx context.Context, workQueue chan *blackboard.Cl
 
 	log.Printf("[INFO] Claim Watcher subscribed to claim_events and %s", agentChannel)
 
+	// Bid on claims created while this pup was not yet subscribed
+	missed, err := unbidClaims(ctx, e.bbClient, e.config.AgentName)
+	if err != nil {
+		log.Printf("[WARN] Failed to scan for claims awaiting bids: %v", err)
+	}
+	for _, claim := range missed {
+		log.Printf("[INFO] Bidding on claim_id=%s created before startup", claim.ID)
+		e.handleClaimEvent(ctx, claim, workQueue)
+	}
+
 	// Dual-subscription select loop
 	for {
 		select {
@@ -198,6 +269,11 @@ func (e *Engine) handleClaimEvent(ctx context.Context, claim *blackboard.Claim,
 		bidType = blackboard.BidTypeIgnore
 	}
 
+	// Content-based routing: the when expression can veto a bid the agent would otherwise make
+	if bidType != blackboard.BidTypeIgnore && !e.whenAllows(ctx, claim, targetArtefact) {
+		bidType = blackboard.BidTypeIgnore
+	}
+
 	err = e.bbClient.SetBid(ctx, claim.ID, e.config.AgentName, bidType)
 	if err != nil {
 		log.Printf("[ERROR] Failed to submit bid for claim_id=%s: %v", claim.ID, err)
@@ -207,11 +283,19 @@ func (e *Engine) handleClaimEvent(ctx context.Context, claim *blackboard.Claim,
 	log.Printf("[INFO] Submitted %s bid for claim_id=%s", bidType, claim.ID)
 }
 
-// determineBidType determines the bid type for a claim. If the agent config includes a
-// `bid_script`, it executes the script with the target artefact as JSON on stdin.
+// determineBidType determines the bid type for a claim. If the agent config declares
+// `consumes` type globs, artefacts whose type matches none of them are ignored without
+// spawning any process. Otherwise, if the agent config includes a `bid_script`, it
+// executes the script with the target artefact as JSON on stdin.
 // The script's stdout is read as the bid type. If no script is provided, or if the
 // script fails, it falls back to the static `bidding_strategy` from the config.
 func (e *Engine) determineBidType(ctx context.Context, targetArtefact *blackboard.Artefact) (blackboard.BidType, error) {
+	// Declarative type matching: decide natively when the type is not consumed.
+	if len(e.config.Consumes) > 0 && !config.MatchArtefactType(e.config.Consumes, targetArtefact.Type) {
+		log.Printf("[DEBUG] Artefact type '%s' not in consumes %v, ignoring", targetArtefact.Type, e.config.Consumes)
+		return blackboard.BidTypeIgnore, nil
+	}
+
 	// Fallback to static bidding strategy if no bid script is defined.
 	if len(e.config.BidScript) == 0 {
 		return e.config.BiddingStrategy, nil
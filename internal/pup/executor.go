@@ -29,16 +29,21 @@ const (
 // Workflow:
 //  1. Fetch target artefact from blackboard
 //  2. Prepare tool input JSON (stdin)
-//  3. Execute tool subprocess with timeout
+//  3. Execute tool (subprocess, or POST to the HTTP agent) with timeout
 //  4. Parse tool output JSON (stdout)
-//  5. Create result artefact with derivative provenance
-//  6. Publish artefact to blackboard
+//  5. Commit workspace changes (auto_commit only)
+//  6. Create result artefact with derivative provenance
+//  7. Publish artefact to blackboard
 //
 // On any failure, creates a Failure artefact and continues (never crashes).
 func (e *Engine) executeWork(ctx context.Context, claim *blackboard.Claim) {
 	log.Printf("[INFO] Work Executor received claim from queue: claim_id=%s artefact_id=%s",
 		claim.ID, claim.ArtefactID)
 
+	// Track claim as in-flight for /healthz reporting
+	untrack := e.trackClaim(claim)
+	defer untrack()
+
 	// Fetch target artefact
 	targetArtefact, err := e.fetchTargetArtefact(ctx, claim)
 	if err != nil {
@@ -58,11 +63,30 @@ func (e *Engine) executeWork(ctx context.Context, claim *blackboard.Claim) {
 		return
 	}
 
-	// Execute tool subprocess
-	log.Printf("[INFO] Executing tool: command=%v claim_id=%s", e.config.Command, claim.ID)
+	// Prepare the directory the tool runs in (isolated worktree when enabled)
+	workDir, cleanupWorkspace, err := e.prepareClaimWorkspace(claim, targetArtefact)
+	if err != nil {
+		log.Printf("[ERROR] Failed to prepare claim workspace: %v", err)
+		e.createFailureArtefact(ctx, claim, -1, "", "", fmt.Sprintf("Failed to prepare claim workspace: %v", err))
+		return
+	}
+	defer cleanupWorkspace()
+	e.setClaimWorkDir(claim.ID, workDir)
+
+	// Execute tool (per-claim subprocess, or the long-running HTTP agent)
+	log.Printf("[INFO] Executing tool: command=%v claim_id=%s work_dir=%s", e.config.Command, claim.ID, workDir)
 	startTime := time.Now()
 
-	exitCode, stdout, stderr, err := e.executeToolSubprocess(ctx, inputJSON)
+	var exitCode int
+	var stdout, stderr string
+	switch {
+	case e.toolRunner != nil:
+		exitCode, stdout, stderr, err = e.toolRunner(ctx, inputJSON)
+	case e.httpAgent != nil:
+		exitCode, stdout, stderr, err = e.httpAgent.execute(ctx, inputJSON, workDir, claim.ID)
+	default:
+		exitCode, stdout, stderr, err = e.executeToolSubprocess(ctx, inputJSON, workDir, e.newProgressPublisher(ctx, claim))
+	}
 	duration := time.Since(startTime)
 
 	if err != nil {
@@ -85,8 +109,28 @@ func (e *Engine) executeWork(ctx context.Context, claim *blackboard.Claim) {
 		return
 	}
 
-	// Create result artefact
-	artefact, err := e.createResultArtefact(ctx, claim, output)
+	// Sub-workflows: the tool asked for a child workflow instead of producing artefacts
+	if output.ChildWorkflow != nil {
+		if _, err := e.requestChildWorkflow(ctx, claim, output); err != nil {
+			log.Printf("[ERROR] Failed to request child workflow: claim_id=%s error=%v", claim.ID, err)
+			e.createFailureArtefact(ctx, claim, exitCode, stdout, stderr,
+				fmt.Sprintf("Tool requested a child workflow but it could not be started: %v", err))
+		}
+		return
+	}
+
+	// Capture workspace changes as a commit before the worktree is cleaned up
+	if e.config.AutoCommit {
+		if err := e.autoCommitOutput(claim, workDir, output); err != nil {
+			log.Printf("[ERROR] Auto-commit failed: claim_id=%s error=%v", claim.ID, err)
+			e.createFailureArtefact(ctx, claim, exitCode, stdout, stderr,
+				fmt.Sprintf("Tool succeeded but auto-commit failed: %v", err))
+			return
+		}
+	}
+
+	// Create result artefact(s)
+	artefacts, err := e.createResultArtefacts(ctx, claim, output)
 	if err != nil {
 		log.Printf("[ERROR] Failed to create artefact: claim_id=%s error=%v", claim.ID, err)
 		// Try to create a Failure artefact describing the artefact creation failure
@@ -95,8 +139,10 @@ func (e *Engine) executeWork(ctx context.Context, claim *blackboard.Claim) {
 		return
 	}
 
-	log.Printf("[INFO] Created artefact: artefact_id=%s type=%s logical_id=%s version=%d",
-		artefact.ID, artefact.Type, artefact.LogicalID, artefact.Version)
+	for _, artefact := range artefacts {
+		log.Printf("[INFO] Created artefact: artefact_id=%s type=%s logical_id=%s version=%d",
+			artefact.ID, artefact.Type, artefact.LogicalID, artefact.Version)
+	}
 }
 
 // fetchTargetArtefact retrieves the artefact that the claim is for.
@@ -120,23 +166,34 @@ func (e *Engine) fetchTargetArtefact(ctx context.Context, claim *blackboard.Clai
 func (e *Engine) prepareToolInput(ctx context.Context, claim *blackboard.Claim, targetArtefact *blackboard.Artefact) (string, error) {
 	// Assemble context chain via BFS traversal with thread tracking
 	// M3.3: Pass claim for feedback claim context support
-	contextChain, err := e.assembleContext(ctx, targetArtefact, claim)
+	contextChain, truncation, err := e.assembleContext(ctx, targetArtefact, claim)
 	if err != nil {
 		return "", fmt.Errorf("failed to assemble context: %w", err)
 	}
 
 	log.Printf("[DEBUG] Prepared context chain: %d artefacts", len(contextChain))
 
-	// Convert to []interface{} for JSON marshaling
-	contextChainInterface := make([]interface{}, len(contextChain))
-	for i, art := range contextChain {
-		contextChainInterface[i] = art
+	// An empty chain is sent as [] rather than null
+	if contextChain == nil {
+		contextChain = []*blackboard.Artefact{}
 	}
 
 	input := &ToolInput{
 		ClaimType:      "exclusive", // M2.4: still hardcoded (Phase 3 will support review/parallel)
 		TargetArtefact: targetArtefact,
-		ContextChain:   contextChainInterface,
+		ContextChain:   contextChain,
+	}
+	if !truncation.IsEmpty() {
+		input.ContextTruncation = truncation
+	}
+
+	// Sub-workflows: a resumed claim carries the artefact that ended its child workflow
+	if claim.ChildResultID != "" {
+		childResult, err := e.bbClient.GetArtefact(ctx, claim.ChildResultID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get child workflow result %s: %w", claim.ChildResultID, err)
+		}
+		input.ChildWorkflowResult = childResult
 	}
 
 	jsonBytes, err := json.Marshal(input)
@@ -152,19 +209,20 @@ func (e *Engine) prepareToolInput(ctx context.Context, claim *blackboard.Claim,
 //
 // The subprocess is:
 //   - Given a 5-minute timeout via context
-//   - Run in /workspace directory
+//   - Run in workDir (/workspace, or the claim's isolated worktree)
 //   - Fed input JSON via stdin (pipe closed after write)
 //   - Output captured with 10MB limit on stdout and stderr
+//   - Given a progress side channel on fd 3 (HOLT_PROGRESS_FD) when progress is non-nil
 //
 // Returns (exitCode, stdout, stderr, error) where:
 //   - exitCode is the process exit code (0 = success, non-zero = failure, -1 = couldn't start)
 //   - stdout is the captured standard output (truncated at 10MB)
 //   - stderr is the captured standard error (truncated at 10MB)
 //   - error is non-nil if the process failed, timed out, or output exceeded limits
-func (e *Engine) executeToolSubprocess(ctx context.Context, inputJSON string) (int, string, string, error) {
-	// Validate /workspace directory exists (fail-fast check)
-	if _, err := os.Stat("/workspace"); os.IsNotExist(err) {
-		return -1, "", "", fmt.Errorf("/workspace directory does not exist - agent container must mount workspace")
+func (e *Engine) executeToolSubprocess(ctx context.Context, inputJSON, workDir string, progress *progressPublisher) (int, string, string, error) {
+	// Validate working directory exists (fail-fast check)
+	if _, err := os.Stat(workDir); os.IsNotExist(err) {
+		return -1, "", "", fmt.Errorf("%s directory does not exist - agent container must mount workspace", workDir)
 	}
 
 	// Create context with timeout
@@ -184,7 +242,7 @@ func (e *Engine) executeToolSubprocess(ctx context.Context, inputJSON string) (i
 	}
 
 	// Set working directory
-	cmd.Dir = "/workspace"
+	cmd.Dir = workDir
 
 	// Create stdin pipe
 	stdinPipe, err := cmd.StdinPipe()
@@ -198,11 +256,38 @@ func (e *Engine) executeToolSubprocess(ctx context.Context, inputJSON string) (i
 	cmd.Stdout = &limitedWriter{w: stdoutBuf, limit: maxOutputSize}
 	cmd.Stderr = &limitedWriter{w: stderrBuf, limit: maxOutputSize}
 
+	// Progress side channel: the tool inherits the write end as fd 3
+	var progressReader, progressWriter *os.File
+	if progress != nil {
+		progressReader, progressWriter, err = os.Pipe()
+		if err != nil {
+			return -1, "", "", fmt.Errorf("failed to create progress pipe: %w", err)
+		}
+		defer progressReader.Close()
+		cmd.ExtraFiles = []*os.File{progressWriter}
+		cmd.Env = append(os.Environ(), fmt.Sprintf("HOLT_PROGRESS_FD=%d", progressFD))
+	}
+
 	// Start process
 	if err := cmd.Start(); err != nil {
+		if progressWriter != nil {
+			progressWriter.Close()
+		}
 		return -1, "", "", fmt.Errorf("failed to start process: %w", err)
 	}
 
+	// Close our copy of the write end so the reader sees EOF when the tool exits
+	progressDone := make(chan struct{})
+	if progress != nil {
+		progressWriter.Close()
+		go func() {
+			defer close(progressDone)
+			progress.consume(progressReader)
+		}()
+	} else {
+		close(progressDone)
+	}
+
 	// Write input JSON to stdin and close pipe
 	go func() {
 		defer stdinPipe.Close()
@@ -214,6 +299,14 @@ func (e *Engine) executeToolSubprocess(ctx context.Context, inputJSON string) (i
 	// Wait for process to complete
 	err = cmd.Wait()
 
+	// Drain remaining progress; background children may hold fd 3 open, so don't wait forever
+	select {
+	case <-progressDone:
+	case <-time.After(time.Second):
+		progressReader.Close()
+		<-progressDone
+	}
+
 	stdout := stdoutBuf.String()
 	stderr := stderrBuf.String()
 
@@ -281,9 +374,9 @@ func (e *Engine) parseToolOutput(stdout string) (*ToolOutput, error) {
 // Agents remain completely unaware of this versioning logic.
 func (e *Engine) createResultArtefact(ctx context.Context, claim *blackboard.Claim, output *ToolOutput) (*blackboard.Artefact, error) {
 	// M2.4: Validate git commit for CodeCommit artefacts
-	if output.ArtefactType == "CodeCommit" {
+	if output.ArtefactType == "CodeCommit" && !e.config.SkipCommitValidation {
 		log.Printf("[INFO] Validating git commit: hash=%s", output.ArtefactPayload)
-		if err := validateCommitExists(output.ArtefactPayload); err != nil {
+		if err := e.validateCommitExists(output.ArtefactPayload); err != nil {
 			return nil, fmt.Errorf("git commit validation failed for hash %s: %w",
 				output.ArtefactPayload, err)
 		}
@@ -308,6 +401,7 @@ func (e *Engine) createResultArtefact(ctx context.Context, claim *blackboard.Cla
 		StructuralType:  output.GetStructuralType(),
 		Type:            output.ArtefactType,
 		Payload:         output.ArtefactPayload,
+		Summary:         output.Summary,
 		SourceArtefacts: []string{claim.ArtefactID}, // Derivative from target artefact
 		ProducedByRole:  e.config.AgentName,         // M3.7: AgentName IS the role
 		CreatedAtMs:     time.Now().UnixMilli(),     // M3.9: Millisecond precision timestamp
@@ -327,10 +421,85 @@ func (e *Engine) createResultArtefact(ctx context.Context, claim *blackboard.Cla
 	return artefact, nil
 }
 
+// createResultArtefacts creates every artefact described by the tool output.
+// Single-artefact outputs go through createResultArtefact unchanged. Batch outputs
+// are created atomically so the orchestrator sees the whole fan-out at once:
+//   - Each entry gets a new logical thread (version 1)
+//   - Entries without source_refs derive from the claim's target artefact
+//   - Entries with source_refs derive from the named earlier entries
+//
+// Feedback claims are rework of a single artefact, so they reject batches.
+func (e *Engine) createResultArtefacts(ctx context.Context, claim *blackboard.Claim, output *ToolOutput) ([]*blackboard.Artefact, error) {
+	if !output.IsBatch() {
+		artefact, err := e.createResultArtefact(ctx, claim, output)
+		if err != nil {
+			return nil, err
+		}
+		return []*blackboard.Artefact{artefact}, nil
+	}
+
+	if claim.Status == blackboard.ClaimStatusPendingAssignment {
+		return nil, fmt.Errorf("feedback claims must produce a single artefact, got a batch of %d", len(output.Artefacts))
+	}
+
+	refIDs := make(map[string]string)
+	createdAtMs := time.Now().UnixMilli()
+	artefacts := make([]*blackboard.Artefact, 0, len(output.Artefacts))
+
+	for i, entry := range output.Entries() {
+		// M2.4: Validate git commit for CodeCommit artefacts
+		if entry.ArtefactType == "CodeCommit" && !e.config.SkipCommitValidation {
+			if err := e.validateCommitExists(entry.ArtefactPayload); err != nil {
+				return nil, fmt.Errorf("artefacts[%d]: git commit validation failed for hash %s: %w",
+					i, entry.ArtefactPayload, err)
+			}
+		}
+
+		sourceArtefacts := []string{claim.ArtefactID}
+		if len(entry.SourceRefs) > 0 {
+			sourceArtefacts = make([]string, len(entry.SourceRefs))
+			for j, ref := range entry.SourceRefs {
+				sourceArtefacts[j] = refIDs[ref]
+			}
+		}
+
+		artefactID := uuid.New().String()
+		if entry.Ref != "" {
+			refIDs[entry.Ref] = artefactID
+		}
+
+		artefacts = append(artefacts, &blackboard.Artefact{
+			ID:              artefactID,
+			LogicalID:       artefactID, // Derivative: new logical thread
+			Version:         1,
+			StructuralType:  entry.GetStructuralType(),
+			Type:            entry.ArtefactType,
+			Payload:         entry.ArtefactPayload,
+			Summary:         entry.Summary,
+			SourceArtefacts: sourceArtefacts,
+			ProducedByRole:  e.config.AgentName,
+			CreatedAtMs:     createdAtMs,
+		})
+	}
+
+	// Create all artefacts and their threads in one transaction (also publishes events)
+	if err := e.bbClient.CreateArtefacts(ctx, artefacts); err != nil {
+		return nil, fmt.Errorf("failed to create artefact batch: %w", err)
+	}
+
+	log.Printf("[INFO] Created artefact batch: claim_id=%s count=%d", claim.ID, len(artefacts))
+
+	return artefacts, nil
+}
+
 // createFailureArtefact creates a Failure artefact describing a tool execution failure.
 // Uses the same derivative provenance model as success artefacts.
-// The failure payload contains diagnostic information (exit code, stdout, stderr, error message).
+// The failure payload contains diagnostic information (exit code, stdout, stderr, error message),
+// with secret values redacted.
 func (e *Engine) createFailureArtefact(ctx context.Context, claim *blackboard.Claim, exitCode int, stdout, stderr, reason string) {
+	// Tool output and errors can echo secret values; they must not reach the blackboard
+	stdout, stderr, reason = e.config.Secrets.String(stdout), e.config.Secrets.String(stderr), e.config.Secrets.String(reason)
+
 	log.Printf("[INFO] Creating Failure artefact: claim_id=%s reason=%s", claim.ID, reason)
 
 	// Prepare failure data
@@ -450,6 +619,7 @@ func (e *Engine) createReworkArtefact(ctx context.Context, claim *blackboard.Cla
 		StructuralType:  output.GetStructuralType(),
 		Type:            targetArtefact.Type, // Same type (rework)
 		Payload:         output.ArtefactPayload,
+		Summary:         output.Summary,
 		SourceArtefacts: sourceArtefacts,        // Target + Reviews
 		ProducedByRole:  e.config.AgentName,    // M3.7: AgentName IS the role
 		CreatedAtMs:     time.Now().UnixMilli(), // M3.9: Millisecond precision timestamp
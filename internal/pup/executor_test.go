@@ -3,8 +3,10 @@ package pup
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 
+	"github.com/dyluth/holt/internal/redact"
 	"github.com/dyluth/holt/pkg/blackboard"
 	"github.com/google/uuid"
 )
@@ -191,6 +193,33 @@ func TestCreateResultArtefact_Provenance(t *testing.T) {
 	_ = engine
 }
 
+// TestCreateFailureArtefact_RedactsSecrets verifies mounted secret values never reach
+// the blackboard through tool output or error messages
+func TestCreateFailureArtefact_RedactsSecrets(t *testing.T) {
+	ctx := context.Background()
+	engine, bbClient := setupTestPup(t, "Coder", "Coder")
+	engine.config.Secrets = redact.New("sk-live-abcdef\n")
+
+	claim := &blackboard.Claim{ID: uuid.New().String(), ArtefactID: uuid.New().String()}
+	engine.createFailureArtefact(ctx, claim, 1, "calling API with sk-live-abcdef", "401 for key sk-live-abcdef", "auth failed: sk-live-abcdef")
+
+	ids, err := bbClient.ScanArtefacts(ctx, "")
+	if err != nil || len(ids) != 1 {
+		t.Fatalf("expected one Failure artefact, got %v (err=%v)", ids, err)
+	}
+	artefact, err := bbClient.GetArtefact(ctx, ids[0])
+	if err != nil {
+		t.Fatalf("failed to read Failure artefact: %v", err)
+	}
+
+	if strings.Contains(artefact.Payload, "sk-live-abcdef") {
+		t.Errorf("payload leaks secret: %s", artefact.Payload)
+	}
+	if strings.Count(artefact.Payload, redact.Placeholder) < 3 {
+		t.Errorf("expected stdout, stderr and reason to be redacted: %s", artefact.Payload)
+	}
+}
+
 // TestTruncate verifies the truncate helper function
 func TestTruncate(t *testing.T) {
 	tests := []struct {
@@ -2,6 +2,7 @@ package pup
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 )
@@ -11,7 +12,7 @@ const (
 	workspaceDir = "/workspace"
 )
 
-// validateCommitExists verifies that a git commit hash exists in the repository.
+// validateCommitExists verifies that a git commit hash exists in the engine's workspace.
 // This is called after tool execution when the tool returns a CodeCommit artefact.
 //
 // Uses `git cat-file -e <hash>` which:
@@ -20,7 +21,12 @@ const (
 //   - Works with both full (40-char) and short commit hashes
 //
 // Returns nil if the commit exists, error otherwise.
-func validateCommitExists(commitHash string) error {
+func (e *Engine) validateCommitExists(commitHash string) error {
+	return commitExistsIn(e.workspace(), commitHash)
+}
+
+// commitExistsIn verifies that a git commit hash exists in the repository at repoDir.
+func commitExistsIn(repoDir, commitHash string) error {
 	if commitHash == "" {
 		return fmt.Errorf("commit hash is empty")
 	}
@@ -28,7 +34,7 @@ func validateCommitExists(commitHash string) error {
 	// Use git cat-file -e to check if commit exists
 	// -e flag: exit with zero status if object exists
 	cmd := exec.Command("git", "cat-file", "-e", commitHash)
-	cmd.Dir = workspaceDir
+	cmd.Dir = repoDir
 
 	// Run command and check exit code
 	output, err := cmd.CombinedOutput()
@@ -43,3 +49,83 @@ func validateCommitExists(commitHash string) error {
 
 	return nil
 }
+
+// createWorktree adds a detached git worktree at worktreePath checked out at ref.
+// The worktree shares the repository's object database, so commits made inside it
+// are immediately visible to validateCommitExists in the main workspace.
+func createWorktree(repoDir, worktreePath, ref string) error {
+	cmd := exec.Command("git", "worktree", "add", "--detach", worktreePath, ref)
+	cmd.Dir = repoDir
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git worktree add failed: %s", strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// removeWorktree force-removes a git worktree and prunes its administrative files.
+// Falls back to deleting the directory if git no longer knows about the worktree.
+func removeWorktree(repoDir, worktreePath string) error {
+	cmd := exec.Command("git", "worktree", "remove", "--force", worktreePath)
+	cmd.Dir = repoDir
+	output, err := cmd.CombinedOutput()
+
+	// Prune stale metadata regardless of the remove outcome
+	prune := exec.Command("git", "worktree", "prune")
+	prune.Dir = repoDir
+	_ = prune.Run()
+
+	if err != nil {
+		if _, statErr := os.Stat(worktreePath); os.IsNotExist(statErr) {
+			return nil
+		}
+		if rmErr := os.RemoveAll(worktreePath); rmErr != nil {
+			return fmt.Errorf("git worktree remove failed: %s", strings.TrimSpace(string(output)))
+		}
+	}
+
+	return nil
+}
+
+// commitWorkspaceChanges stages and commits all changes in workDir.
+// Returns the resulting HEAD hash and whether a new commit was made; when the
+// tree is clean no commit is created and the current HEAD is returned.
+// A committer identity is supplied only if the repository has none configured.
+func commitWorkspaceChanges(workDir, message, identity string) (string, bool, error) {
+	if output, err := runGit(workDir, "add", "-A"); err != nil {
+		return "", false, fmt.Errorf("git add failed: %s", output)
+	}
+
+	status, err := runGit(workDir, "status", "--porcelain")
+	if err != nil {
+		return "", false, fmt.Errorf("git status failed: %s", status)
+	}
+
+	committed := false
+	if status != "" {
+		args := []string{"commit", "-q", "-m", message}
+		if _, err := runGit(workDir, "config", "user.email"); err != nil {
+			args = append([]string{"-c", "user.name=" + identity, "-c", "user.email=" + identity + "@holt.local"}, args...)
+		}
+		if output, err := runGit(workDir, args...); err != nil {
+			return "", false, fmt.Errorf("git commit failed: %s", output)
+		}
+		committed = true
+	}
+
+	hash, err := runGit(workDir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", false, fmt.Errorf("git rev-parse failed: %s", hash)
+	}
+
+	return hash, committed, nil
+}
+
+// runGit runs a git command in dir and returns its trimmed combined output.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	return strings.TrimSpace(string(output)), err
+}
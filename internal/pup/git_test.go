@@ -1,12 +1,16 @@
 package pup
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
 // TestValidateCommitExists_EmptyHash verifies empty hash returns error
 func TestValidateCommitExists_EmptyHash(t *testing.T) {
-	err := validateCommitExists("")
+	err := New(&Config{}, nil).validateCommitExists("")
 	if err == nil {
 		t.Error("Expected error for empty commit hash, got nil")
 	}
@@ -18,7 +22,7 @@ func TestValidateCommitExists_EmptyHash(t *testing.T) {
 
 // TestValidateCommitExists_InvalidHash verifies invalid hash returns error
 func TestValidateCommitExists_InvalidHash(t *testing.T) {
-	err := validateCommitExists("invalid-hash-123")
+	err := New(&Config{}, nil).validateCommitExists("invalid-hash-123")
 	if err == nil {
 		t.Error("Expected error for invalid commit hash, got nil")
 	}
@@ -31,3 +35,87 @@ func TestValidateCommitExists_InvalidHash(t *testing.T) {
 
 // Note: Testing with valid commit hashes requires a real git repository
 // which is difficult in unit tests. Integration tests will cover this.
+
+// TestCreateAndRemoveWorktree verifies per-claim worktrees are created at a ref and cleaned up
+func TestCreateAndRemoveWorktree(t *testing.T) {
+	repoDir := initTestRepo(t)
+
+	worktreePath := claimWorktreePath(repoDir, "coder", "claim-1")
+	if err := os.MkdirAll(filepath.Dir(worktreePath), 0755); err != nil {
+		t.Fatalf("Failed to create worktrees dir: %v", err)
+	}
+
+	if err := createWorktree(repoDir, worktreePath, "HEAD"); err != nil {
+		t.Fatalf("Expected worktree creation to succeed, got: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, "README.md")); err != nil {
+		t.Errorf("Expected README.md checked out in worktree, got: %v", err)
+	}
+
+	if err := removeWorktree(repoDir, worktreePath); err != nil {
+		t.Fatalf("Expected worktree removal to succeed, got: %v", err)
+	}
+	if _, err := os.Stat(worktreePath); !os.IsNotExist(err) {
+		t.Errorf("Expected worktree directory to be removed")
+	}
+
+	// Removing an already-removed worktree is not an error
+	if err := removeWorktree(repoDir, worktreePath); err != nil {
+		t.Errorf("Expected idempotent removal, got: %v", err)
+	}
+}
+
+// TestExcludeWorktreesDir verifies the worktrees directory is added to git exclude exactly once
+func TestExcludeWorktreesDir(t *testing.T) {
+	repoDir := initTestRepo(t)
+
+	for i := 0; i < 2; i++ {
+		if err := excludeWorktreesDir(repoDir); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(repoDir, ".git", "info", "exclude"))
+	if err != nil {
+		t.Fatalf("Failed to read exclude file: %v", err)
+	}
+	if count := strings.Count(string(data), "/.holt-worktrees/"); count != 1 {
+		t.Errorf("Expected exactly one exclude entry, got %d", count)
+	}
+}
+
+// initTestRepo creates a temporary git repository with a single commit
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repoDir := t.TempDir()
+	commands := [][]string{
+		{"git", "init", "-q"},
+		{"git", "config", "user.email", "test@example.com"},
+		{"git", "config", "user.name", "Test"},
+	}
+	for _, args := range commands {
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = repoDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%v failed: %v\n%s", args, err, output)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(repoDir, "README.md"), []byte("test\n"), 0644); err != nil {
+		t.Fatalf("Failed to write README.md: %v", err)
+	}
+	for _, args := range [][]string{{"git", "add", "."}, {"git", "commit", "-q", "-m", "initial"}} {
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = repoDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("%v failed: %v\n%s", args, err, output)
+		}
+	}
+
+	return repoDir
+}
@@ -20,13 +20,16 @@ import (
 type HealthServer struct {
 	server        *http.Server
 	bbClient      *blackboard.Client
-	healthChecker *HealthChecker // M3.9: Optional custom health checker
+	healthChecker *HealthChecker   // M3.9: Optional custom health checker
+	inFlight      InFlightReporter // Optional: reports claims currently executing
 }
 
 // HealthResponse represents the JSON response from the /healthz endpoint.
 type HealthResponse struct {
-	Status string `json:"status"`
-	Error  string `json:"error,omitempty"`
+	Status         string          `json:"status"`
+	Error          string          `json:"error,omitempty"`
+	Concurrency    int             `json:"concurrency,omitempty"`      // Max parallel executions (when reporter set)
+	InFlightClaims []InFlightClaim `json:"in_flight_claims,omitempty"` // Claims currently executing
 }
 
 // NewHealthServer creates a new health check HTTP server.
@@ -56,6 +59,12 @@ func NewHealthServer(bbClient *blackboard.Client, port int) *HealthServer {
 	return hs
 }
 
+// SetInFlightReporter attaches the source of in-flight claim information
+// (normally the pup Engine) so /healthz can report active executions.
+func (hs *HealthServer) SetInFlightReporter(reporter InFlightReporter) {
+	hs.inFlight = reporter
+}
+
 // Start starts the HTTP server in a background goroutine.
 // Returns immediately after the server starts listening.
 // Returns an error if the server fails to start (e.g., port already in use).
@@ -92,7 +101,7 @@ func (hs *HealthServer) Shutdown(ctx context.Context) error {
 // Returns 200 OK if healthy, 503 Service Unavailable otherwise.
 //
 // Response format:
-//   - Success: {"status": "healthy"}
+//   - Success: {"status": "healthy", "concurrency": 2, "in_flight_claims": [...]}
 //   - Failure: {"status": "unhealthy", "error": "connection failed"}
 func (hs *HealthServer) handleHealthz(w http.ResponseWriter, r *http.Request) {
 	var healthy bool
@@ -128,6 +137,12 @@ func (hs *HealthServer) handleHealthz(w http.ResponseWriter, r *http.Request) {
 		statusCode = http.StatusOK
 	}
 
+	// Report in-flight claims regardless of health status
+	if hs.inFlight != nil {
+		response.Concurrency = hs.inFlight.Concurrency()
+		response.InFlightClaims = hs.inFlight.InFlightClaims()
+	}
+
 	// Write JSON response
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -276,3 +276,39 @@ func TestHealthzHandler_ContextTimeout(t *testing.T) {
 	// Should get a response (likely unhealthy due to context cancellation)
 	assert.NotEqual(t, 0, rec.Code)
 }
+
+// stubInFlightReporter is a fixed InFlightReporter for health endpoint tests.
+type stubInFlightReporter struct {
+	claims []InFlightClaim
+}
+
+func (s *stubInFlightReporter) InFlightClaims() []InFlightClaim { return s.claims }
+func (s *stubInFlightReporter) Concurrency() int                { return 3 }
+
+// TestHealthzHandler_InFlightClaims verifies /healthz reports in-flight claims when a reporter is set.
+func TestHealthzHandler_InFlightClaims(t *testing.T) {
+	mr := miniredis.RunT(t)
+	defer mr.Close()
+
+	client, err := blackboard.NewClient(&redis.Options{Addr: mr.Addr()}, "test-instance")
+	require.NoError(t, err)
+	defer client.Close()
+
+	hs := NewHealthServer(client, 8080)
+	hs.SetInFlightReporter(&stubInFlightReporter{claims: []InFlightClaim{
+		{ClaimID: "claim-1", ArtefactID: "artefact-1", StartedAtMs: 1000},
+	}})
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	hs.handleHealthz(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response HealthResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, "healthy", response.Status)
+	assert.Equal(t, 3, response.Concurrency)
+	require.Len(t, response.InFlightClaims, 1)
+	assert.Equal(t, "claim-1", response.InFlightClaims[0].ClaimID)
+}
@@ -0,0 +1,201 @@
+package pup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/dyluth/holt/internal/config"
+)
+
+const (
+	// httpAgentPollInterval is how often readiness is polled during startup
+	httpAgentPollInterval = 250 * time.Millisecond
+
+	// httpAgentStopTimeout is how long the agent gets to exit after SIGTERM
+	httpAgentStopTimeout = 5 * time.Second
+)
+
+// httpAgent is a long-running agent process reached over HTTP (protocol: http).
+// The pup starts it once and POSTs each claim's ToolInput to it, avoiding a
+// process fork per claim for agents that keep models or caches warm.
+type httpAgent struct {
+	settings config.HTTPProtocolConfig
+	baseURL  string
+	client   *http.Client
+
+	cmd  *exec.Cmd
+	done chan struct{} // closed when the agent process exits
+
+	mu      sync.Mutex
+	exitErr error
+}
+
+// startHTTPAgent launches the agent command and waits until its health endpoint responds.
+// The agent is told its port via HOLT_AGENT_PORT and runs in workDir.
+// Returns an error if the process fails to start, exits, or is not ready in time.
+func startHTTPAgent(ctx context.Context, command []string, workDir string, settings config.HTTPProtocolConfig) (*httpAgent, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("command array is empty")
+	}
+
+	startupTimeout, err := time.ParseDuration(settings.StartupTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid startup timeout: %w", err)
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), fmt.Sprintf("HOLT_AGENT_PORT=%d", settings.Port))
+	// Agent logs go to the pup's own output so `holt logs` shows them
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start agent process: %w", err)
+	}
+
+	agent := &httpAgent{
+		settings: settings,
+		baseURL:  fmt.Sprintf("http://127.0.0.1:%d", settings.Port),
+		client:   &http.Client{},
+		cmd:      cmd,
+		done:     make(chan struct{}),
+	}
+
+	go func() {
+		err := cmd.Wait()
+		agent.mu.Lock()
+		agent.exitErr = err
+		agent.mu.Unlock()
+		close(agent.done)
+	}()
+
+	log.Printf("[INFO] Started HTTP agent: command=%v pid=%d port=%d", command, cmd.Process.Pid, settings.Port)
+
+	if err := agent.waitReady(ctx, startupTimeout); err != nil {
+		agent.stop()
+		return nil, err
+	}
+
+	log.Printf("[INFO] HTTP agent ready: %s%s", agent.baseURL, settings.HealthPath)
+	return agent, nil
+}
+
+// waitReady polls the health endpoint until it returns 2xx, the process exits, or the timeout elapses.
+func (a *httpAgent) waitReady(ctx context.Context, timeout time.Duration) error {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(httpAgentPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if a.healthy(ctx) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-a.done:
+			return fmt.Errorf("agent process exited during startup: %v", a.exitError())
+		case <-deadline.C:
+			return fmt.Errorf("agent did not become ready at %s%s within %s", a.baseURL, a.settings.HealthPath, timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// healthy reports whether the agent's health endpoint returns a 2xx status.
+func (a *httpAgent) healthy(ctx context.Context) bool {
+	reqCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, a.baseURL+a.settings.HealthPath, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+// execute POSTs the ToolInput JSON to the agent and returns the response like a subprocess would:
+// the body as stdout, exit code 0 on a 2xx response. Non-2xx responses are failures with the
+// body reported as stderr. The same timeout and output limits as subprocess tools apply.
+func (a *httpAgent) execute(ctx context.Context, inputJSON, workDir, claimID string) (int, string, string, error) {
+	select {
+	case <-a.done:
+		return -1, "", "", fmt.Errorf("agent process is not running: %v", a.exitError())
+	default:
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, toolExecutionTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(execCtx, http.MethodPost, a.baseURL+a.settings.Path, bytes.NewBufferString(inputJSON))
+	if err != nil {
+		return -1, "", "", fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderClaimID, claimID)
+	req.Header.Set(HeaderWorkDir, workDir)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		if execCtx.Err() == context.DeadlineExceeded {
+			return -1, "", "", fmt.Errorf("tool execution timeout (5 minutes)")
+		}
+		return -1, "", "", fmt.Errorf("request to agent failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxOutputSize+1))
+	if err != nil {
+		return -1, "", "", fmt.Errorf("failed to read agent response: %w", err)
+	}
+	if len(body) > maxOutputSize {
+		return -1, string(body[:maxOutputSize]), "", fmt.Errorf("tool output exceeded 10MB limit")
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return -1, "", string(body), fmt.Errorf("agent returned HTTP %d", resp.StatusCode)
+	}
+
+	return 0, string(body), "", nil
+}
+
+// stop terminates the agent process, escalating to SIGKILL if it doesn't exit in time.
+func (a *httpAgent) stop() {
+	select {
+	case <-a.done:
+		return
+	default:
+	}
+
+	_ = a.cmd.Process.Signal(os.Interrupt)
+	select {
+	case <-a.done:
+	case <-time.After(httpAgentStopTimeout):
+		log.Printf("[WARN] HTTP agent did not exit after %s, killing", httpAgentStopTimeout)
+		_ = a.cmd.Process.Kill()
+		<-a.done
+	}
+}
+
+// exitError returns the process exit error, if the process has exited.
+func (a *httpAgent) exitError() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.exitErr
+}
@@ -0,0 +1,182 @@
+package pup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dyluth/holt/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testHTTPAgent returns an httpAgent pointed at an in-process test server
+func testHTTPAgent(t *testing.T, handler http.HandlerFunc) *httpAgent {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	settings := (*config.HTTPProtocolConfig)(nil).WithDefaults()
+	return &httpAgent{
+		settings: settings,
+		baseURL:  server.URL,
+		client:   server.Client(),
+		done:     make(chan struct{}),
+	}
+}
+
+// freePort returns a TCP port that is currently unused on localhost
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func TestHTTPAgentExecute_Success(t *testing.T) {
+	agent := testHTTPAgent(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/execute", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "claim-1", r.Header.Get(HeaderClaimID))
+		assert.Equal(t, "/workspace", r.Header.Get(HeaderWorkDir))
+
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"claim_type":"exclusive"}`, string(body))
+		fmt.Fprint(w, `{"artefact_type":"Result","artefact_payload":"ok","summary":"done"}`)
+	})
+
+	exitCode, stdout, stderr, err := agent.execute(context.Background(), `{"claim_type":"exclusive"}`, "/workspace", "claim-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, exitCode)
+	assert.Empty(t, stderr)
+
+	// The body goes through the same parsing and validation as subprocess stdout
+	engine := &Engine{config: &Config{}}
+	output, err := engine.parseToolOutput(stdout)
+	require.NoError(t, err)
+	assert.Equal(t, "Result", output.ArtefactType)
+}
+
+func TestHTTPAgentExecute_ErrorStatus(t *testing.T) {
+	agent := testHTTPAgent(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "model exploded", http.StatusInternalServerError)
+	})
+
+	exitCode, stdout, stderr, err := agent.execute(context.Background(), `{}`, "/workspace", "claim-1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "HTTP 500")
+	assert.Equal(t, -1, exitCode)
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, "model exploded")
+}
+
+func TestHTTPAgentExecute_InvalidOutput(t *testing.T) {
+	agent := testHTTPAgent(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "not json")
+	})
+
+	_, stdout, _, err := agent.execute(context.Background(), `{}`, "/workspace", "claim-1")
+	require.NoError(t, err)
+
+	engine := &Engine{config: &Config{}}
+	_, err = engine.parseToolOutput(stdout)
+	assert.Error(t, err)
+}
+
+func TestHTTPAgentExecute_ContextCancelled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	agent := testHTTPAgent(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, _, err := agent.execute(ctx, `{}`, "/workspace", "claim-1")
+	assert.Error(t, err)
+}
+
+func TestHTTPAgentExecute_ProcessExited(t *testing.T) {
+	agent := testHTTPAgent(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not be sent to an exited agent")
+	})
+	close(agent.done)
+
+	_, _, _, err := agent.execute(context.Background(), `{}`, "/workspace", "claim-1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not running")
+}
+
+func TestStartHTTPAgent_ExitsDuringStartup(t *testing.T) {
+	settings := (&config.HTTPProtocolConfig{Port: freePort(t), StartupTimeout: "5s"}).WithDefaults()
+
+	_, err := startHTTPAgent(context.Background(), []string{"sh", "-c", "exit 3"}, t.TempDir(), settings)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exited during startup")
+}
+
+func TestStartHTTPAgent_StartupTimeout(t *testing.T) {
+	settings := (&config.HTTPProtocolConfig{Port: freePort(t), StartupTimeout: "300ms"}).WithDefaults()
+
+	_, err := startHTTPAgent(context.Background(), []string{"sleep", "30"}, t.TempDir(), settings)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "did not become ready")
+}
+
+func TestStartHTTPAgent_Ready(t *testing.T) {
+	settings := (&config.HTTPProtocolConfig{Port: freePort(t), StartupTimeout: "10s"}).WithDefaults()
+
+	// Re-run this test binary as the agent (see TestHelperHTTPAgent)
+	command := []string{os.Args[0], "-test.run=^TestHelperHTTPAgent$"}
+	t.Setenv("HOLT_TEST_HTTP_AGENT", "1")
+
+	agent, err := startHTTPAgent(context.Background(), command, t.TempDir(), settings)
+	require.NoError(t, err)
+	defer agent.stop()
+
+	exitCode, stdout, _, err := agent.execute(context.Background(), `{}`, "/workspace", "claim-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, exitCode)
+	assert.Contains(t, stdout, `"artefact_type":"Echo"`)
+
+	agent.stop()
+	select {
+	case <-agent.done:
+	default:
+		t.Fatal("expected agent process to have exited after stop")
+	}
+}
+
+// TestHelperHTTPAgent is not a real test: it acts as a long-running HTTP agent
+// when started by TestStartHTTPAgent_Ready.
+func TestHelperHTTPAgent(t *testing.T) {
+	if os.Getenv("HOLT_TEST_HTTP_AGENT") != "1" {
+		t.Skip("helper process for TestStartHTTPAgent_Ready")
+	}
+
+	port, err := strconv.Atoi(os.Getenv("HOLT_AGENT_PORT"))
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/execute", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"artefact_type":"Echo","artefact_payload":%q,"summary":"echo"}`, r.Header.Get(HeaderClaimID))
+	})
+	_ = http.ListenAndServe(fmt.Sprintf("127.0.0.1:%d", port), mux)
+	os.Exit(0)
+}
@@ -0,0 +1,83 @@
+package pup
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+// InFlightClaim describes a claim currently being executed by this pup.
+// Reported on the /healthz endpoint so operators can see what an agent is doing.
+type InFlightClaim struct {
+	ClaimID     string `json:"claim_id"`
+	ArtefactID  string `json:"artefact_id"`
+	StartedAtMs int64  `json:"started_at_ms"`
+	WorkDir     string `json:"work_dir,omitempty"` // Directory the tool runs in (worktree when isolated)
+}
+
+// InFlightReporter exposes the claims currently executing.
+// Implemented by Engine and consumed by HealthServer.
+type InFlightReporter interface {
+	InFlightClaims() []InFlightClaim
+	Concurrency() int
+}
+
+// trackClaim records a claim as in-flight. Returns a function that removes it.
+func (e *Engine) trackClaim(claim *blackboard.Claim) func() {
+	e.inFlightMu.Lock()
+	defer e.inFlightMu.Unlock()
+
+	if e.inFlight == nil {
+		e.inFlight = make(map[string]*InFlightClaim)
+	}
+	e.inFlight[claim.ID] = &InFlightClaim{
+		ClaimID:     claim.ID,
+		ArtefactID:  claim.ArtefactID,
+		StartedAtMs: time.Now().UnixMilli(),
+	}
+
+	return func() {
+		e.inFlightMu.Lock()
+		defer e.inFlightMu.Unlock()
+		delete(e.inFlight, claim.ID)
+	}
+}
+
+// setClaimWorkDir records the directory a tracked claim's tool runs in.
+func (e *Engine) setClaimWorkDir(claimID, workDir string) {
+	e.inFlightMu.Lock()
+	defer e.inFlightMu.Unlock()
+
+	if entry, ok := e.inFlight[claimID]; ok {
+		entry.WorkDir = workDir
+	}
+}
+
+// InFlightClaims returns a snapshot of the claims currently executing, oldest first.
+func (e *Engine) InFlightClaims() []InFlightClaim {
+	e.inFlightMu.Lock()
+	defer e.inFlightMu.Unlock()
+
+	claims := make([]InFlightClaim, 0, len(e.inFlight))
+	for _, entry := range e.inFlight {
+		claims = append(claims, *entry)
+	}
+
+	sort.Slice(claims, func(i, j int) bool {
+		if claims[i].StartedAtMs != claims[j].StartedAtMs {
+			return claims[i].StartedAtMs < claims[j].StartedAtMs
+		}
+		return claims[i].ClaimID < claims[j].ClaimID
+	})
+
+	return claims
+}
+
+// Concurrency returns the maximum number of claims this pup executes in parallel.
+func (e *Engine) Concurrency() int {
+	if e.config.Concurrency < 1 {
+		return 1
+	}
+	return e.config.Concurrency
+}
@@ -0,0 +1,60 @@
+package pup
+
+import (
+	"testing"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_TrackClaim(t *testing.T) {
+	engine := New(&Config{AgentName: "test-agent", Concurrency: 2}, nil)
+
+	untrackA := engine.trackClaim(&blackboard.Claim{ID: "claim-a", ArtefactID: "artefact-a"})
+	untrackB := engine.trackClaim(&blackboard.Claim{ID: "claim-b", ArtefactID: "artefact-b"})
+	engine.setClaimWorkDir("claim-b", "/workspace/.holt-worktrees/claim-b")
+
+	claims := engine.InFlightClaims()
+	require.Len(t, claims, 2)
+	assert.Equal(t, 2, engine.Concurrency())
+
+	byID := map[string]InFlightClaim{}
+	for _, c := range claims {
+		byID[c.ClaimID] = c
+	}
+	assert.Equal(t, "artefact-a", byID["claim-a"].ArtefactID)
+	assert.Equal(t, "/workspace/.holt-worktrees/claim-b", byID["claim-b"].WorkDir)
+
+	untrackA()
+	claims = engine.InFlightClaims()
+	require.Len(t, claims, 1)
+	assert.Equal(t, "claim-b", claims[0].ClaimID)
+
+	untrackB()
+	assert.Empty(t, engine.InFlightClaims())
+}
+
+func TestEngine_NeedsClaimIsolation(t *testing.T) {
+	tests := []struct {
+		name          string
+		concurrency   int
+		workspaceMode string
+		isolation     string
+		expected      bool
+	}{
+		{"sequential rw", 1, "rw", "", false},
+		{"concurrent ro", 4, "ro", "", false},
+		{"concurrent rw", 4, "rw", "", true},
+		{"default concurrency", 0, "rw", "", false},
+		{"explicit worktree isolation", 1, "rw", "worktree", true},
+		{"worktree isolation on ro workspace", 1, "ro", "worktree", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := New(&Config{Concurrency: tt.concurrency, WorkspaceMode: tt.workspaceMode, WorkspaceIsolation: tt.isolation}, nil)
+			assert.Equal(t, tt.expected, engine.needsClaimIsolation())
+		})
+	}
+}
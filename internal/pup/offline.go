@@ -0,0 +1,90 @@
+package pup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+// ToolRunner executes the agent tool for one ToolInput.
+// Returns values with the same meaning as executeToolSubprocess.
+type ToolRunner func(ctx context.Context, inputJSON string) (int, string, string, error)
+
+// OfflineResult captures every stage of an offline claim execution.
+// Fields are filled in as far as execution got, so callers can report partial results.
+type OfflineResult struct {
+	Input     string
+	ExitCode  int
+	Stdout    string
+	Stderr    string
+	Output    *ToolOutput
+	Artefacts []*blackboard.Artefact
+}
+
+// RunOffline executes a single claim the way executeWork does, but with the tool run by
+// runner and errors returned instead of recorded as Failure artefacts. Used by
+// `holt agent run` against an in-memory blackboard seeded with fixture artefacts.
+// Auto-commit is not applied.
+func (e *Engine) RunOffline(ctx context.Context, claim *blackboard.Claim, targetArtefact *blackboard.Artefact, runner ToolRunner) (*OfflineResult, error) {
+	result := &OfflineResult{ExitCode: -1}
+
+	inputJSON, err := e.prepareToolInput(ctx, claim, targetArtefact)
+	if err != nil {
+		return result, fmt.Errorf("failed to prepare tool input: %w", err)
+	}
+	result.Input = inputJSON
+
+	exitCode, stdout, stderr, err := runner(ctx, inputJSON)
+	result.ExitCode = exitCode
+	result.Stdout = stdout
+	result.Stderr = stderr
+	if err != nil {
+		return result, fmt.Errorf("tool execution failed: %w", err)
+	}
+
+	output, err := e.parseToolOutput(stdout)
+	if err != nil {
+		return result, fmt.Errorf("failed to parse tool output: %w", err)
+	}
+	result.Output = output
+
+	if output.ChildWorkflow != nil {
+		goal, err := e.requestChildWorkflow(ctx, claim, output)
+		if err != nil {
+			return result, fmt.Errorf("child workflow request failed: %w", err)
+		}
+		result.Artefacts = []*blackboard.Artefact{goal}
+		return result, nil
+	}
+
+	artefacts, err := e.createResultArtefacts(ctx, claim, output)
+	if err != nil {
+		return result, fmt.Errorf("artefact creation failed: %w", err)
+	}
+	result.Artefacts = artefacts
+
+	return result, nil
+}
+
+// SubprocessRunner returns a ToolRunner that runs the configured command in workDir,
+// exactly as the pup does for protocol: subprocess.
+func (e *Engine) SubprocessRunner(workDir string) ToolRunner {
+	return func(ctx context.Context, inputJSON string) (int, string, string, error) {
+		return e.executeToolSubprocess(ctx, inputJSON, workDir, nil)
+	}
+}
+
+// SetWorkspace points the engine at a repository other than the /workspace mount.
+// Used by `holt agent run`, where the tool works in the host checkout.
+// Must be called before Start.
+func (e *Engine) SetWorkspace(dir string) {
+	e.workspaceRoot = dir
+}
+
+// SetToolRunner replaces the configured tool protocol with runner for every claim.
+// Used by `holt test` to drive real pups with scripted agent responses.
+// Must be called before Start.
+func (e *Engine) SetToolRunner(runner ToolRunner) {
+	e.toolRunner = runner
+}
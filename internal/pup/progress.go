@@ -0,0 +1,180 @@
+package pup
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+const (
+	// progressFD is the file descriptor tools write progress lines to.
+	// Exposed to tools as HOLT_PROGRESS_FD so scripts can use `echo "..." >&$HOLT_PROGRESS_FD`.
+	progressFD = 3
+
+	// progressInterval is the minimum gap between tool_progress events for one claim.
+	// Lines arriving faster are coalesced: only the latest is published when the gap elapses.
+	progressInterval = 500 * time.Millisecond
+
+	// maxProgressLineSize bounds a single progress line. Longer lines are truncated and the
+	// remainder discarded, so the pipe keeps draining however much a tool writes.
+	maxProgressLineSize = 4096
+)
+
+// progressPublisher republishes tool progress lines as rate-limited tool_progress workflow events.
+type progressPublisher struct {
+	claimID   string
+	agentName string
+	interval  time.Duration
+	publish   func(data map[string]interface{})
+
+	mu         sync.Mutex
+	lastSent   time.Time
+	pending    map[string]interface{}
+	suppressed int
+	timer      *time.Timer
+	closed     bool
+}
+
+// newProgressPublisher creates a publisher that sends tool_progress events for a claim
+// via the blackboard. Publish failures are logged and never affect the tool.
+func (e *Engine) newProgressPublisher(ctx context.Context, claim *blackboard.Claim) *progressPublisher {
+	return &progressPublisher{
+		claimID:   claim.ID,
+		agentName: e.config.AgentName,
+		interval:  progressInterval,
+		publish: func(data map[string]interface{}) {
+			if err := e.bbClient.PublishWorkflowEvent(ctx, "tool_progress", data); err != nil {
+				log.Printf("[WARN] Failed to publish tool_progress event: %v", err)
+			}
+		},
+	}
+}
+
+// consume reads newline-delimited progress from r until EOF, publishing each line.
+// Lines may be plain text or JSON objects like {"message": "...", "percent": 40}.
+func (p *progressPublisher) consume(r io.Reader) {
+	reader := bufio.NewReaderSize(r, maxProgressLineSize)
+
+	for {
+		line, err := readProgressLine(reader)
+		if line = strings.TrimSpace(line); line != "" {
+			p.report(parseProgressLine(line))
+		}
+		if err != nil {
+			if err != io.EOF && !errors.Is(err, os.ErrClosed) {
+				log.Printf("[DEBUG] Progress channel closed: %v", err)
+			}
+			break
+		}
+	}
+
+	p.close()
+}
+
+// readProgressLine returns the next line, cut to maxProgressLineSize. The rest of an
+// over-long line is read and dropped rather than left in the pipe.
+func readProgressLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadSlice('\n')
+	text := string(line)
+
+	for err == bufio.ErrBufferFull {
+		_, err = reader.ReadSlice('\n')
+	}
+	return text, err
+}
+
+// report publishes a progress update now, or defers it if one was sent within the interval.
+func (p *progressPublisher) report(data map[string]interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return
+	}
+
+	data["claim_id"] = p.claimID
+	data["agent_name"] = p.agentName
+
+	if time.Since(p.lastSent) >= p.interval {
+		p.sendLocked(data)
+		return
+	}
+
+	// Coalesce: keep only the latest update until the interval elapses
+	if p.pending != nil {
+		p.suppressed++
+	}
+	p.pending = data
+	if p.timer == nil {
+		p.timer = time.AfterFunc(p.interval-time.Since(p.lastSent), p.flush)
+	}
+}
+
+// flush publishes the pending update, if any.
+func (p *progressPublisher) flush() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.timer = nil
+	if p.pending != nil {
+		p.sendLocked(p.pending)
+	}
+}
+
+// close publishes any pending update and stops further reports.
+func (p *progressPublisher) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return
+	}
+	p.closed = true
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	if p.pending != nil {
+		p.sendLocked(p.pending)
+	}
+}
+
+// sendLocked publishes data; the caller must hold p.mu.
+func (p *progressPublisher) sendLocked(data map[string]interface{}) {
+	if p.suppressed > 0 {
+		data["suppressed"] = p.suppressed
+	}
+	p.publish(data)
+	p.lastSent = time.Now()
+	p.pending = nil
+	p.suppressed = 0
+}
+
+// parseProgressLine converts a progress line into event data.
+// JSON objects keep their message and percent fields; anything else is a plain message.
+func parseProgressLine(line string) map[string]interface{} {
+	if strings.HasPrefix(line, "{") {
+		var structured struct {
+			Message string   `json:"message"`
+			Percent *float64 `json:"percent"`
+		}
+		if err := json.Unmarshal([]byte(line), &structured); err == nil && structured.Message != "" {
+			data := map[string]interface{}{"message": structured.Message}
+			if structured.Percent != nil {
+				data["percent"] = *structured.Percent
+			}
+			return data
+		}
+	}
+
+	return map[string]interface{}{"message": line}
+}
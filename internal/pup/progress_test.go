@@ -0,0 +1,125 @@
+package pup
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingPublisher returns a progressPublisher that records events instead of publishing
+func recordingPublisher(interval time.Duration) (*progressPublisher, func() []map[string]interface{}) {
+	var mu sync.Mutex
+	var events []map[string]interface{}
+
+	p := &progressPublisher{
+		claimID:   "claim-1",
+		agentName: "coder",
+		interval:  interval,
+		publish: func(data map[string]interface{}) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, data)
+		},
+	}
+
+	return p, func() []map[string]interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]map[string]interface{}(nil), events...)
+	}
+}
+
+func TestParseProgressLine(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{"message": "plain text"}, parseProgressLine("plain text"))
+	assert.Equal(t, map[string]interface{}{"message": "compiling", "percent": 40.0},
+		parseProgressLine(`{"message":"compiling","percent":40}`))
+	assert.Equal(t, map[string]interface{}{"message": "{not json"}, parseProgressLine("{not json"))
+}
+
+func TestProgressPublisher_RateLimit(t *testing.T) {
+	p, events := recordingPublisher(time.Hour)
+
+	p.consume(strings.NewReader("first\nsecond\nthird\n\nfourth\n"))
+
+	// First line goes out immediately; the rest are coalesced and flushed on close
+	got := events()
+	require.Len(t, got, 2)
+	assert.Equal(t, "first", got[0]["message"])
+	assert.Equal(t, "claim-1", got[0]["claim_id"])
+	assert.Equal(t, "coder", got[0]["agent_name"])
+	assert.Equal(t, "fourth", got[1]["message"])
+	assert.Equal(t, 2, got[1]["suppressed"])
+}
+
+func TestProgressPublisher_FlushAfterInterval(t *testing.T) {
+	p, events := recordingPublisher(20 * time.Millisecond)
+
+	p.report(map[string]interface{}{"message": "one"})
+	p.report(map[string]interface{}{"message": "two"})
+
+	assert.Eventually(t, func() bool { return len(events()) == 2 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, "two", events()[1]["message"])
+
+	p.close()
+	p.report(map[string]interface{}{"message": "after close"})
+	assert.Len(t, events(), 2)
+}
+
+func TestExecuteToolSubprocess_ProgressChannel(t *testing.T) {
+	engine := New(&Config{
+		AgentName: "coder",
+		Command:   []string{"/bin/sh", "-c", `cat >/dev/null; echo "working" >&$HOLT_PROGRESS_FD; echo '{"artefact_type":"T","artefact_payload":"","summary":"s"}'`},
+	}, nil)
+	p, events := recordingPublisher(time.Hour)
+
+	exitCode, stdout, _, err := engine.executeToolSubprocess(context.Background(), "{}", t.TempDir(), p)
+	require.NoError(t, err)
+	assert.Equal(t, 0, exitCode)
+	assert.Contains(t, stdout, `"artefact_type":"T"`)
+
+	got := events()
+	require.Len(t, got, 1)
+	assert.Equal(t, "working", got[0]["message"])
+}
+
+func TestProgressPublisher_TruncatesLongLines(t *testing.T) {
+	p, events := recordingPublisher(0)
+
+	long := strings.Repeat("x", maxProgressLineSize*3)
+	p.consume(strings.NewReader(long + "\nafter\n"))
+
+	got := events()
+	require.Len(t, got, 2)
+	assert.Len(t, got[0]["message"], maxProgressLineSize)
+	assert.Equal(t, "after", got[1]["message"])
+}
+
+func TestExecuteToolSubprocess_LongProgressLineKeepsDraining(t *testing.T) {
+	// A 5 KB line followed by 128 KB of output must not fill the 64 KB pipe and block the tool
+	engine := New(&Config{
+		AgentName: "coder",
+		Command: []string{"/bin/sh", "-c", `cat >/dev/null
+head -c 5000 /dev/zero | tr '\0' 'a' >&$HOLT_PROGRESS_FD; echo >&$HOLT_PROGRESS_FD
+i=0; while [ $i -lt 2048 ]; do head -c 63 /dev/zero | tr '\0' 'b' >&$HOLT_PROGRESS_FD; echo >&$HOLT_PROGRESS_FD; i=$((i+1)); done
+echo done >&$HOLT_PROGRESS_FD
+echo '{"artefact_type":"T","artefact_payload":"","summary":"s"}'`},
+	}, nil)
+	p, events := recordingPublisher(0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	exitCode, _, _, err := engine.executeToolSubprocess(ctx, "{}", t.TempDir(), p)
+	require.NoError(t, err)
+	assert.Equal(t, 0, exitCode)
+
+	got := events()
+	require.NotEmpty(t, got)
+	assert.Len(t, got[0]["message"], maxProgressLineSize)
+	assert.Equal(t, "done", got[len(got)-1]["message"])
+}
@@ -0,0 +1,53 @@
+package pup
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+)
+
+// requestChildWorkflow starts the child workflow a tool asked for by creating a root
+// GoalDefined artefact that names the requesting claim as its parent. The orchestrator
+// parks the claim when it sees the goal, runs the child to a Terminal or Failure
+// artefact, then grants the claim back to this agent with that result in context.
+//
+// Only exclusive claims can be parked: review and parallel work completes the phase
+// by producing artefacts, and feedback claims must produce a rework version.
+func (e *Engine) requestChildWorkflow(ctx context.Context, claim *blackboard.Claim, output *ToolOutput) (*blackboard.Artefact, error) {
+	if claim.Status != blackboard.ClaimStatusPendingExclusive || claim.GrantedExclusiveAgent != e.config.AgentName {
+		return nil, fmt.Errorf("child workflows can only be requested from an exclusive claim (claim status is %s)", claim.Status)
+	}
+
+	artefactID := uuid.New().String()
+	goal := &blackboard.Artefact{
+		ID:              artefactID,
+		LogicalID:       artefactID,
+		Version:         1,
+		StructuralType:  blackboard.StructuralTypeStandard,
+		Type:            "GoalDefined",
+		Payload:         output.ChildWorkflow.Goal,
+		Summary:         output.Summary,
+		SourceArtefacts: []string{}, // Root of the child workflow; the parent link is ParentClaimID
+		ProducedByRole:  e.config.AgentName,
+		CreatedAtMs:     time.Now().UnixMilli(),
+		ParentClaimID:   claim.ID,
+		ChildAgents:     output.ChildWorkflow.Agents,
+	}
+
+	if err := e.bbClient.CreateArtefact(ctx, goal); err != nil {
+		return nil, fmt.Errorf("failed to create child goal: %w", err)
+	}
+
+	if err := e.bbClient.AddVersionToThread(ctx, artefactID, artefactID, 1); err != nil {
+		log.Printf("[WARN] Failed to add version to thread: logical_id=%s error=%v", artefactID, err)
+	}
+
+	log.Printf("[INFO] Requested child workflow: claim_id=%s child_goal_id=%s agents=%v",
+		claim.ID, artefactID, output.ChildWorkflow.Agents)
+
+	return goal, nil
+}
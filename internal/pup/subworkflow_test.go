@@ -0,0 +1,147 @@
+package pup
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedRunner returns a ToolRunner that records its input and replies with stdout
+func scriptedRunner(stdout string, inputs *[]string) ToolRunner {
+	return func(ctx context.Context, inputJSON string) (int, string, string, error) {
+		*inputs = append(*inputs, inputJSON)
+		return 0, stdout, "", nil
+	}
+}
+
+func newSubWorkflowClaim(t *testing.T, bbClient *blackboard.Client, status blackboard.ClaimStatus, agent string) (*blackboard.Claim, *blackboard.Artefact) {
+	t.Helper()
+	ctx := context.Background()
+
+	target := &blackboard.Artefact{
+		ID:              uuid.New().String(),
+		LogicalID:       uuid.New().String(),
+		Version:         1,
+		StructuralType:  blackboard.StructuralTypeStandard,
+		Type:            "GoalDefined",
+		Payload:         "Add SSO login",
+		SourceArtefacts: []string{},
+		ProducedByRole:  "user",
+		CreatedAtMs:     time.Now().UnixMilli(),
+	}
+	require.NoError(t, bbClient.CreateArtefact(ctx, target))
+
+	claim := &blackboard.Claim{
+		ID:                    uuid.New().String(),
+		ArtefactID:            target.ID,
+		Status:                status,
+		GrantedReviewAgents:   []string{},
+		GrantedParallelAgents: []string{},
+		GrantedExclusiveAgent: agent,
+	}
+	require.NoError(t, bbClient.CreateClaim(ctx, claim))
+	return claim, target
+}
+
+func TestExecuteWork_RequestsChildWorkflow(t *testing.T) {
+	ctx := context.Background()
+	engine, bbClient := setupTestPup(t, "Planner", "Planner")
+	var inputs []string
+	engine.SetToolRunner(scriptedRunner(
+		`{"summary":"need research first","child_workflow":{"goal":"Compare SSO providers","agents":["Researcher"]}}`, &inputs))
+
+	claim, target := newSubWorkflowClaim(t, bbClient, blackboard.ClaimStatusPendingExclusive, "Planner")
+	engine.executeWork(ctx, claim)
+
+	ids, err := bbClient.ScanArtefacts(ctx, "")
+	require.NoError(t, err)
+
+	var goal *blackboard.Artefact
+	for _, id := range ids {
+		artefact, err := bbClient.GetArtefact(ctx, id)
+		require.NoError(t, err)
+		assert.NotEqual(t, blackboard.StructuralTypeFailure, artefact.StructuralType, "unexpected failure: %s", artefact.Payload)
+		if artefact.ID != target.ID {
+			goal = artefact
+		}
+	}
+
+	require.NotNil(t, goal, "child goal should be created")
+	assert.Len(t, ids, 2, "only the child goal should be created")
+	assert.Equal(t, "GoalDefined", goal.Type)
+	assert.Equal(t, "Compare SSO providers", goal.Payload)
+	assert.Equal(t, claim.ID, goal.ParentClaimID)
+	assert.Equal(t, []string{"Researcher"}, goal.ChildAgents)
+	assert.Equal(t, "Planner", goal.ProducedByRole)
+	assert.Empty(t, goal.SourceArtefacts)
+}
+
+func TestExecuteWork_ChildWorkflowRequiresExclusiveClaim(t *testing.T) {
+	ctx := context.Background()
+	engine, bbClient := setupTestPup(t, "Coder", "Coder")
+	var inputs []string
+	engine.SetToolRunner(scriptedRunner(`{"summary":"need research","child_workflow":{"goal":"Research"}}`, &inputs))
+
+	claim, target := newSubWorkflowClaim(t, bbClient, blackboard.ClaimStatusPendingAssignment, "Coder")
+	engine.executeWork(ctx, claim)
+
+	ids, err := bbClient.ScanArtefacts(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	for _, id := range ids {
+		if id == target.ID {
+			continue
+		}
+		failure, err := bbClient.GetArtefact(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, blackboard.StructuralTypeFailure, failure.StructuralType)
+		assert.Contains(t, failure.Payload, "child workflows can only be requested from an exclusive claim")
+	}
+}
+
+func TestPrepareToolInput_ResumedChildWorkflow(t *testing.T) {
+	ctx := context.Background()
+	engine, bbClient := setupTestPup(t, "Planner", "Planner")
+
+	claim, target := newSubWorkflowClaim(t, bbClient, blackboard.ClaimStatusPendingExclusive, "Planner")
+
+	childGoal := &blackboard.Artefact{
+		ID: uuid.New().String(), LogicalID: uuid.New().String(), Version: 1,
+		StructuralType: blackboard.StructuralTypeStandard, Type: "GoalDefined", Payload: "Compare SSO providers",
+		SourceArtefacts: []string{}, ProducedByRole: "Planner", ParentClaimID: claim.ID,
+		CreatedAtMs: time.Now().UnixMilli(),
+	}
+	require.NoError(t, bbClient.CreateArtefact(ctx, childGoal))
+	result := &blackboard.Artefact{
+		ID: uuid.New().String(), LogicalID: uuid.New().String(), Version: 1,
+		StructuralType: blackboard.StructuralTypeTerminal, Type: "Comparison", Payload: "Provider A wins",
+		SourceArtefacts: []string{childGoal.ID}, ProducedByRole: "Researcher",
+		CreatedAtMs: time.Now().UnixMilli() + 1,
+	}
+	require.NoError(t, bbClient.CreateArtefact(ctx, result))
+
+	claim.ChildGoalID = childGoal.ID
+	claim.ChildResultID = result.ID
+
+	inputJSON, err := engine.prepareToolInput(ctx, claim, target)
+	require.NoError(t, err)
+
+	var input ToolInput
+	require.NoError(t, json.Unmarshal([]byte(inputJSON), &input))
+	require.NotNil(t, input.ChildWorkflowResult)
+	assert.Equal(t, result.ID, input.ChildWorkflowResult.ID)
+
+	// The Terminal result is delivered despite the default structural type filter,
+	// alongside the child's history
+	var contextIDs []string
+	for _, artefact := range input.ContextChain {
+		contextIDs = append(contextIDs, artefact.ID)
+	}
+	assert.Equal(t, []string{childGoal.ID, result.ID}, contextIDs)
+}
@@ -255,7 +255,7 @@ func TestAssembleContext_WithAdditionalContextIDs(t *testing.T) {
 	}
 
 	// Call assembleContext with feedback claim
-	contextChain, err := engine.assembleContext(ctx, targetArtefact, feedbackClaim)
+	contextChain, _, err := engine.assembleContext(ctx, targetArtefact, feedbackClaim)
 	require.NoError(t, err)
 
 	// Verify context includes both source artefacts AND additional context
@@ -328,7 +328,7 @@ func TestAssembleContext_RegularClaim_NoAdditionalContext(t *testing.T) {
 	}
 
 	// Call assembleContext with regular claim
-	contextChain, err := engine.assembleContext(ctx, targetArtefact, regularClaim)
+	contextChain, _, err := engine.assembleContext(ctx, targetArtefact, regularClaim)
 	require.NoError(t, err)
 
 	// Verify context only includes source artefacts
@@ -445,3 +445,55 @@ func TestCreateResultArtefact_RegularClaim_NewThread(t *testing.T) {
 	assert.NotEqual(t, targetArtefact.LogicalID, resultArtefact.LogicalID, "Should have new logical_id for new work")
 	assert.Equal(t, "TestArtefact", resultArtefact.Type, "Should use type from tool output")
 }
+
+func TestCreateResultArtefacts_Batch(t *testing.T) {
+	ctx := context.Background()
+	engine, bbClient := setupTestPup(t, "Planner", "Planner")
+
+	targetArtefact := &blackboard.Artefact{
+		ID:              uuid.New().String(),
+		LogicalID:       uuid.New().String(),
+		Version:         1,
+		StructuralType:  blackboard.StructuralTypeStandard,
+		Type:            "GoalDefined",
+		ProducedByRole:  "user",
+		Payload:         "Build a login page",
+		SourceArtefacts: []string{},
+	}
+	require.NoError(t, bbClient.CreateArtefact(ctx, targetArtefact))
+
+	claim := &blackboard.Claim{ID: uuid.New().String(), ArtefactID: targetArtefact.ID, Status: blackboard.ClaimStatusPendingExclusive}
+
+	toolOutput := &ToolOutput{
+		Summary: "Split goal into tasks",
+		Artefacts: []OutputArtefact{
+			{Ref: "plan", ArtefactType: "Plan", ArtefactPayload: "two tasks"},
+			{ArtefactType: "Task", ArtefactPayload: "form", SourceRefs: []string{"plan"}},
+			{ArtefactType: "Task", ArtefactPayload: "backend", SourceRefs: []string{"plan"}, Summary: "Backend task"},
+		},
+	}
+	require.NoError(t, toolOutput.Validate())
+
+	artefacts, err := engine.createResultArtefacts(ctx, claim, toolOutput)
+	require.NoError(t, err)
+	require.Len(t, artefacts, 3)
+
+	plan := artefacts[0]
+	assert.Equal(t, []string{targetArtefact.ID}, plan.SourceArtefacts, "root entries derive from the target")
+	assert.Equal(t, "Split goal into tasks", plan.Summary)
+	for _, task := range artefacts[1:] {
+		assert.Equal(t, []string{plan.ID}, task.SourceArtefacts, "source_refs resolve to batch artefact IDs")
+		assert.Equal(t, "Planner", task.ProducedByRole)
+		assert.Equal(t, 1, task.Version)
+
+		stored, err := bbClient.GetArtefact(ctx, task.ID)
+		require.NoError(t, err)
+		assert.Equal(t, task.Payload, stored.Payload)
+	}
+	assert.Equal(t, "Backend task", artefacts[2].Summary)
+
+	// Feedback claims are rework of a single artefact
+	claim.Status = blackboard.ClaimStatusPendingAssignment
+	_, err = engine.createResultArtefacts(ctx, claim, toolOutput)
+	assert.ErrorContains(t, err, "feedback claims must produce a single artefact")
+}
@@ -0,0 +1,110 @@
+package pup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/dyluth/holt/internal/config"
+	"github.com/dyluth/holt/internal/expr"
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+// whenAllows evaluates the agent's when expression against the target artefact.
+// Returns true if no expression is configured. An expression that fails to evaluate
+// (missing field, type mismatch) does not allow the bid; the failure is published as a
+// when_evaluation_failed workflow event so it is visible in `holt watch` rather than
+// looking like a deliberate ignore.
+func (e *Engine) whenAllows(ctx context.Context, claim *blackboard.Claim, target *blackboard.Artefact) bool {
+	if e.config.When == "" {
+		return true
+	}
+
+	program, err := e.whenProgram()
+	if err == nil {
+		var vars map[string]interface{}
+		vars, err = e.whenVariables(ctx, program, claim, target)
+		if err == nil {
+			var allowed bool
+			allowed, err = program.EvalBool(vars)
+			if err == nil {
+				if !allowed {
+					log.Printf("[DEBUG] when expression is false for artefact %s, ignoring", target.ID)
+				}
+				return allowed
+			}
+		}
+	}
+
+	log.Printf("[WARN] Failed to evaluate when expression for claim_id=%s: %v", claim.ID, err)
+	eventData := map[string]interface{}{
+		"claim_id":      claim.ID,
+		"agent_name":    e.config.AgentName,
+		"artefact_id":   target.ID,
+		"artefact_type": target.Type,
+		"expression":    e.config.When,
+		"error":         err.Error(),
+	}
+	if pubErr := e.bbClient.PublishWorkflowEvent(ctx, "when_evaluation_failed", eventData); pubErr != nil {
+		log.Printf("[WARN] Failed to publish when_evaluation_failed event: %v", pubErr)
+	}
+	return false
+}
+
+// whenProgram compiles the when expression once per engine
+func (e *Engine) whenProgram() (*expr.Program, error) {
+	e.whenOnce.Do(func() {
+		e.when, e.whenErr = expr.Compile(e.config.When, config.WhenVariables...)
+	})
+	return e.when, e.whenErr
+}
+
+// whenVariables builds the expression variables for the target artefact. The context chain
+// is only assembled if the expression refers to it.
+func (e *Engine) whenVariables(ctx context.Context, program *expr.Program, claim *blackboard.Claim, target *blackboard.Artefact) (map[string]interface{}, error) {
+	artefact, err := toExprValue(target)
+	if err != nil {
+		return nil, err
+	}
+
+	// Payloads are usually JSON; anything else is exposed as the raw string
+	var payload interface{}
+	if err := json.Unmarshal([]byte(target.Payload), &payload); err != nil {
+		payload = target.Payload
+	}
+
+	chain := []interface{}{}
+	if program.References("context") {
+		artefacts, _, err := e.assembleContext(ctx, target, claim)
+		if err != nil {
+			return nil, fmt.Errorf("failed to assemble context: %w", err)
+		}
+		for _, a := range artefacts {
+			v, err := toExprValue(a)
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, v)
+		}
+	}
+
+	return map[string]interface{}{
+		"artefact": artefact,
+		"payload":  payload,
+		"context":  chain,
+	}, nil
+}
+
+// toExprValue converts an artefact to the JSON-shaped map expressions see (JSON field names)
+func toExprValue(a *blackboard.Artefact) (map[string]interface{}, error) {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal artefact: %w", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal artefact: %w", err)
+	}
+	return m, nil
+}
@@ -0,0 +1,169 @@
+package pup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bidFor creates the artefact and a claim on it, runs the pup's bid decision and returns the bid
+func bidFor(t *testing.T, engine *Engine, bbClient *blackboard.Client, artefact *blackboard.Artefact) (blackboard.BidType, *blackboard.Claim) {
+	t.Helper()
+	ctx := context.Background()
+
+	require.NoError(t, bbClient.CreateArtefact(ctx, artefact))
+	claim := &blackboard.Claim{
+		ID:                    uuid.New().String(),
+		ArtefactID:            artefact.ID,
+		Status:                blackboard.ClaimStatusPendingReview,
+		GrantedReviewAgents:   []string{},
+		GrantedParallelAgents: []string{},
+	}
+	require.NoError(t, bbClient.CreateClaim(ctx, claim))
+
+	engine.handleClaimEvent(ctx, claim, make(chan *blackboard.Claim, 1))
+
+	bids, err := bbClient.GetAllBids(ctx, claim.ID)
+	require.NoError(t, err)
+	return bids[engine.config.AgentName], claim
+}
+
+func newWhenArtefact(payload string, sources ...string) *blackboard.Artefact {
+	if sources == nil {
+		sources = []string{}
+	}
+	return &blackboard.Artefact{
+		ID:              uuid.New().String(),
+		LogicalID:       uuid.New().String(),
+		Version:         1,
+		StructuralType:  blackboard.StructuralTypeStandard,
+		Type:            "ChangeRequest",
+		Payload:         payload,
+		SourceArtefacts: sources,
+		ProducedByRole:  "Planner",
+		CreatedAtMs:     time.Now().UnixMilli(),
+	}
+}
+
+func TestWhen_RoutesOnPayload(t *testing.T) {
+	engine, bbClient := setupTestPup(t, "SecurityReviewer", "SecurityReviewer")
+	engine.config.BiddingStrategy = blackboard.BidTypeReview
+	engine.config.When = `payload.risk > 7 && artefact.produced_by_role == "Planner"`
+
+	bid, _ := bidFor(t, engine, bbClient, newWhenArtefact(`{"risk": 9}`))
+	assert.Equal(t, blackboard.BidTypeReview, bid)
+
+	bid, _ = bidFor(t, engine, bbClient, newWhenArtefact(`{"risk": 3}`))
+	assert.Equal(t, blackboard.BidTypeIgnore, bid)
+}
+
+func TestWhen_EvaluationErrorPublishesEvent(t *testing.T) {
+	engine, bbClient := setupTestPup(t, "SecurityReviewer", "SecurityReviewer")
+	engine.config.When = `payload.risk > 7`
+
+	sub, err := bbClient.SubscribeWorkflowEvents(context.Background())
+	require.NoError(t, err)
+	defer sub.Close()
+
+	// Not JSON: payload is the raw string, so selecting a field fails
+	bid, claim := bidFor(t, engine, bbClient, newWhenArtefact("plain text"))
+	assert.Equal(t, blackboard.BidTypeIgnore, bid)
+
+	deadline := time.After(time.Second)
+	for {
+		select {
+		case event := <-sub.Events():
+			if event.Event != "when_evaluation_failed" {
+				continue
+			}
+			assert.Equal(t, claim.ID, event.Data["claim_id"])
+			assert.Equal(t, "SecurityReviewer", event.Data["agent_name"])
+			assert.Equal(t, "payload.risk > 7", event.Data["expression"])
+			assert.Contains(t, event.Data["error"], "cannot select field 'risk' from string")
+			return
+		case <-deadline:
+			t.Fatal("timeout waiting for when_evaluation_failed event")
+		}
+	}
+}
+
+func TestWhen_Context(t *testing.T) {
+	engine, bbClient := setupTestPup(t, "SecurityReviewer", "SecurityReviewer")
+	engine.config.When = `size(context) > 0 && context[0].type == "ThreatModel"`
+
+	threatModel := newWhenArtefact(`{}`)
+	threatModel.Type = "ThreatModel"
+	require.NoError(t, bbClient.CreateArtefact(context.Background(), threatModel))
+
+	bid, _ := bidFor(t, engine, bbClient, newWhenArtefact(`{}`, threatModel.ID))
+	assert.Equal(t, blackboard.BidTypeExclusive, bid)
+
+	bid, _ = bidFor(t, engine, bbClient, newWhenArtefact(`{}`))
+	assert.Equal(t, blackboard.BidTypeIgnore, bid)
+}
+
+// Controllers bid for their workers, so they must apply when like any other agent
+func TestWhen_ControllerBid(t *testing.T) {
+	engine, bbClient := setupTestPup(t, "SecurityReviewer", "SecurityReviewer")
+	engine.config.BiddingStrategy = blackboard.BidTypeReview
+	engine.config.When = `payload.risk > 7`
+	ctx := context.Background()
+
+	sub, err := bbClient.SubscribeWorkflowEvents(ctx)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	controllerBid := func(artefact *blackboard.Artefact) blackboard.BidType {
+		require.NoError(t, bbClient.CreateArtefact(ctx, artefact))
+		claim := &blackboard.Claim{
+			ID:                    uuid.New().String(),
+			ArtefactID:            artefact.ID,
+			Status:                blackboard.ClaimStatusPendingReview,
+			GrantedReviewAgents:   []string{},
+			GrantedParallelAgents: []string{},
+		}
+		require.NoError(t, bbClient.CreateClaim(ctx, claim))
+
+		submitControllerBid(ctx, engine, claim)
+
+		bids, err := bbClient.GetAllBids(ctx, claim.ID)
+		require.NoError(t, err)
+		return bids["SecurityReviewer"]
+	}
+
+	assert.Equal(t, blackboard.BidTypeReview, controllerBid(newWhenArtefact(`{"risk": 9}`)))
+	assert.Equal(t, blackboard.BidTypeIgnore, controllerBid(newWhenArtefact(`{"risk": 3}`)))
+	assert.Equal(t, blackboard.BidTypeIgnore, controllerBid(newWhenArtefact("plain text")))
+
+	deadline := time.After(time.Second)
+	for {
+		select {
+		case event := <-sub.Events():
+			if event.Event == "when_evaluation_failed" {
+				assert.Equal(t, "SecurityReviewer", event.Data["agent_name"])
+				return
+			}
+		case <-deadline:
+			t.Fatal("timeout waiting for when_evaluation_failed event")
+		}
+	}
+}
+
+func TestConfigValidate_When(t *testing.T) {
+	cfg := &Config{
+		InstanceName:    "test",
+		AgentName:       "Reviewer",
+		RedisURL:        "redis://localhost:6379",
+		Command:         []string{"/app/run.sh"},
+		BiddingStrategy: blackboard.BidTypeReview,
+		When:            "payload.risk >",
+	}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid HOLT_AGENT_WHEN expression")
+}
@@ -0,0 +1,188 @@
+package pup
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+const (
+	// worktreesDirName is the directory under the workspace holding per-claim git worktrees
+	worktreesDirName = ".holt-worktrees"
+)
+
+// commitHashPattern matches abbreviated or full hexadecimal git object names
+var commitHashPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// workspace returns the root of the mounted workspace.
+// Tests override workspaceRoot to point at a temporary repository.
+func (e *Engine) workspace() string {
+	if e.workspaceRoot != "" {
+		return e.workspaceRoot
+	}
+	return workspaceDir
+}
+
+// needsClaimIsolation reports whether claims must run in their own worktree.
+// Worktree isolation requires a writable workspace and is enabled either explicitly
+// (workspace.isolation: worktree) or implicitly when executing claims concurrently,
+// since concurrent tools would otherwise trample each other's checkouts.
+func (e *Engine) needsClaimIsolation() bool {
+	if e.config.WorkspaceMode != "rw" {
+		return false
+	}
+	return e.config.WorkspaceIsolation == "worktree" || e.Concurrency() > 1
+}
+
+// prepareClaimWorkspace returns the directory the tool should run in for a claim,
+// plus a cleanup function that must be called once execution has finished.
+// For isolated claims a detached worktree is created at the target artefact's commit
+// when its payload is a commit hash, or at the workspace HEAD otherwise.
+// Without isolation the shared workspace is used and cleanup is a no-op.
+func (e *Engine) prepareClaimWorkspace(claim *blackboard.Claim, targetArtefact *blackboard.Artefact) (string, func(), error) {
+	if !e.needsClaimIsolation() {
+		return e.workspace(), func() {}, nil
+	}
+
+	repoDir := e.workspace()
+	worktreePath := claimWorktreePath(repoDir, e.config.AgentName, claim.ID)
+	if err := os.MkdirAll(filepath.Dir(worktreePath), 0755); err != nil {
+		return "", nil, fmt.Errorf("failed to create worktrees directory: %w", err)
+	}
+	e.excludeOnce.Do(func() {
+		if err := excludeWorktreesDir(repoDir); err != nil {
+			log.Printf("[WARN] Failed to add %s to git exclude: %v", worktreesDirName, err)
+		}
+	})
+
+	// A previous crash may have left a worktree behind for this very claim
+	if _, err := os.Stat(worktreePath); err == nil {
+		log.Printf("[WARN] Removing leftover worktree for claim %s", claim.ID)
+		if err := removeWorktree(repoDir, worktreePath); err != nil {
+			return "", nil, fmt.Errorf("failed to remove leftover worktree: %w", err)
+		}
+	}
+
+	ref := worktreeRef(repoDir, targetArtefact)
+	if err := createWorktree(repoDir, worktreePath, ref); err != nil {
+		return "", nil, fmt.Errorf("failed to create worktree for claim %s: %w", claim.ID, err)
+	}
+
+	log.Printf("[INFO] Created isolated worktree for claim %s at %s: %s", claim.ID, ref, worktreePath)
+
+	cleanup := func() {
+		if err := removeWorktree(repoDir, worktreePath); err != nil {
+			log.Printf("[WARN] Failed to remove worktree %s: %v", worktreePath, err)
+			return
+		}
+		log.Printf("[DEBUG] Removed worktree for claim %s", claim.ID)
+	}
+
+	return worktreePath, cleanup, nil
+}
+
+// worktreeRef picks the ref a claim's worktree is checked out at.
+// Uses the target artefact's payload when it names a commit in the repository,
+// otherwise falls back to HEAD.
+func worktreeRef(repoDir string, targetArtefact *blackboard.Artefact) string {
+	if targetArtefact == nil {
+		return "HEAD"
+	}
+
+	payload := strings.TrimSpace(targetArtefact.Payload)
+	if !commitHashPattern.MatchString(payload) {
+		return "HEAD"
+	}
+	if err := commitExistsIn(repoDir, payload); err != nil {
+		log.Printf("[DEBUG] Payload %s looks like a commit hash but is not in the repository, using HEAD", payload)
+		return "HEAD"
+	}
+
+	return payload
+}
+
+// cleanupStaleWorktrees removes worktrees left behind by this agent after a crash.
+// Called once at engine startup, before any claims execute, so every worktree in
+// this agent's directory is by definition orphaned. Other agents' directories are
+// never touched, even when their names share a prefix with this one.
+func (e *Engine) cleanupStaleWorktrees() {
+	if e.config.WorkspaceMode != "rw" {
+		return
+	}
+
+	repoDir := e.workspace()
+	agentDir := agentWorktreesDir(repoDir, e.config.AgentName)
+	entries, err := os.ReadDir(agentDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[WARN] Failed to scan worktrees directory: %v", err)
+		}
+		return
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		worktreePath := filepath.Join(agentDir, entry.Name())
+		if err := removeWorktree(repoDir, worktreePath); err != nil {
+			log.Printf("[WARN] Failed to remove stale worktree %s: %v", worktreePath, err)
+			continue
+		}
+		removed++
+	}
+
+	if removed > 0 {
+		log.Printf("[INFO] Removed %d stale worktree(s) from previous run", removed)
+	}
+}
+
+// agentWorktreesDir returns the directory holding one agent's claim worktrees.
+func agentWorktreesDir(repoDir, agentName string) string {
+	return filepath.Join(repoDir, worktreesDirName, agentName)
+}
+
+// claimWorktreePath returns the worktree location for a claim.
+// Pattern: {workspace}/.holt-worktrees/{agent_name}/{claim_id}
+func claimWorktreePath(repoDir, agentName, claimID string) string {
+	return filepath.Join(agentWorktreesDir(repoDir, agentName), claimID)
+}
+
+// excludeWorktreesDir adds the worktrees directory to .git/info/exclude so
+// per-claim worktrees never show up as untracked files in the main workspace.
+func excludeWorktreesDir(repoDir string) error {
+	excludePath := filepath.Join(repoDir, ".git", "info", "exclude")
+	entry := "/" + worktreesDirName + "/"
+
+	existing, err := os.ReadFile(excludePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, line := range strings.Split(string(existing), "\n") {
+		if strings.TrimSpace(line) == entry {
+			return nil
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(excludePath), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(excludePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		entry = "\n" + entry
+	}
+	_, err = f.WriteString(entry + "\n")
+	return err
+}
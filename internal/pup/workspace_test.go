@@ -0,0 +1,168 @@
+package pup
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWorktreeRef verifies commit-hash payloads select the checkout ref
+func TestWorktreeRef(t *testing.T) {
+	repoDir := initTestRepo(t)
+	head := gitOutput(t, repoDir, "rev-parse", "HEAD")
+
+	tests := []struct {
+		name     string
+		artefact *blackboard.Artefact
+		expected string
+	}{
+		{"nil artefact", nil, "HEAD"},
+		{"non-hash payload", &blackboard.Artefact{Payload: "build a thing"}, "HEAD"},
+		{"full commit hash", &blackboard.Artefact{Payload: head}, head},
+		{"abbreviated commit hash", &blackboard.Artefact{Payload: head[:8]}, head[:8]},
+		{"unknown commit hash", &blackboard.Artefact{Payload: "deadbeefdeadbeef"}, "HEAD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, worktreeRef(repoDir, tt.artefact))
+		})
+	}
+}
+
+// TestPrepareClaimWorkspace_ChecksOutTargetCommit verifies the worktree is created at the
+// target artefact's commit and removed by cleanup
+func TestPrepareClaimWorkspace_ChecksOutTargetCommit(t *testing.T) {
+	repoDir := initTestRepo(t)
+	firstCommit := gitOutput(t, repoDir, "rev-parse", "HEAD")
+
+	// Advance HEAD so the worktree must be at the older commit to lack this file
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "later.txt"), []byte("later\n"), 0644))
+	gitOutput(t, repoDir, "add", ".")
+	gitOutput(t, repoDir, "commit", "-q", "-m", "later")
+
+	engine := New(&Config{AgentName: "coder", WorkspaceMode: "rw", WorkspaceIsolation: "worktree"}, nil)
+	engine.workspaceRoot = repoDir
+
+	claim := &blackboard.Claim{ID: "claim-1"}
+	workDir, cleanup, err := engine.prepareClaimWorkspace(claim, &blackboard.Artefact{Payload: firstCommit})
+	require.NoError(t, err)
+
+	assert.Equal(t, claimWorktreePath(repoDir, "coder", "claim-1"), workDir)
+	assert.Equal(t, firstCommit, gitOutput(t, workDir, "rev-parse", "HEAD"))
+	_, err = os.Stat(filepath.Join(workDir, "later.txt"))
+	assert.True(t, os.IsNotExist(err), "worktree should be checked out at the target commit")
+
+	cleanup()
+	_, err = os.Stat(workDir)
+	assert.True(t, os.IsNotExist(err), "worktree should be removed after cleanup")
+}
+
+// TestPrepareClaimWorkspace_ConcurrentClaimsExcludeOnce verifies parallel claims add the
+// worktrees directory to git's exclude file exactly once
+func TestPrepareClaimWorkspace_ConcurrentClaimsExcludeOnce(t *testing.T) {
+	repoDir := initTestRepo(t)
+	head := gitOutput(t, repoDir, "rev-parse", "HEAD")
+
+	engine := New(&Config{AgentName: "coder", WorkspaceMode: "rw", WorkspaceIsolation: "worktree"}, nil)
+	engine.workspaceRoot = repoDir
+
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	cleanups := make([]func(), 4)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			claim := &blackboard.Claim{ID: fmt.Sprintf("claim-%d", i)}
+			_, cleanups[i], errs[i] = engine.prepareClaimWorkspace(claim, &blackboard.Artefact{Payload: head})
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		require.NoError(t, err)
+		cleanups[i]()
+	}
+
+	exclude, err := os.ReadFile(filepath.Join(repoDir, ".git", "info", "exclude"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(exclude), worktreesDirName))
+}
+
+// TestPrepareClaimWorkspace_NoIsolation verifies the shared workspace is used by default
+func TestPrepareClaimWorkspace_NoIsolation(t *testing.T) {
+	engine := New(&Config{AgentName: "coder", WorkspaceMode: "rw"}, nil)
+	engine.workspaceRoot = t.TempDir()
+
+	workDir, cleanup, err := engine.prepareClaimWorkspace(&blackboard.Claim{ID: "claim-1"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, engine.workspaceRoot, workDir)
+	cleanup()
+}
+
+// TestCleanupStaleWorktrees verifies only this agent's leftover worktrees are removed at startup
+func TestCleanupStaleWorktrees(t *testing.T) {
+	repoDir := initTestRepo(t)
+
+	own := claimWorktreePath(repoDir, "coder", "claim-1")
+	other := claimWorktreePath(repoDir, "reviewer", "claim-2")
+	for _, path := range []string{own, other} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, createWorktree(repoDir, path, "HEAD"))
+	}
+
+	engine := New(&Config{AgentName: "coder", WorkspaceMode: "rw"}, nil)
+	engine.workspaceRoot = repoDir
+	engine.cleanupStaleWorktrees()
+
+	_, err := os.Stat(own)
+	assert.True(t, os.IsNotExist(err), "own stale worktree should be removed")
+	_, err = os.Stat(other)
+	assert.NoError(t, err, "other agents' worktrees should be left alone")
+
+	worktrees := gitOutput(t, repoDir, "worktree", "list")
+	assert.NotContains(t, worktrees, own)
+}
+
+// TestCleanupStaleWorktrees_SharedPrefix verifies a role is not mistaken for the
+// start of a longer hyphenated role name, e.g. "Code" and "Code-Reviewer"
+func TestCleanupStaleWorktrees_SharedPrefix(t *testing.T) {
+	repoDir := initTestRepo(t)
+
+	own := claimWorktreePath(repoDir, "Code", "claim-1")
+	live := claimWorktreePath(repoDir, "Code-Reviewer", "claim-2")
+	for _, path := range []string{own, live} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, createWorktree(repoDir, path, "HEAD"))
+	}
+
+	engine := New(&Config{AgentName: "Code", WorkspaceMode: "rw"}, nil)
+	engine.workspaceRoot = repoDir
+	engine.cleanupStaleWorktrees()
+
+	_, err := os.Stat(own)
+	assert.True(t, os.IsNotExist(err), "own stale worktree should be removed")
+	_, err = os.Stat(live)
+	assert.NoError(t, err, "Code-Reviewer's worktree should be left alone")
+	assert.Contains(t, gitOutput(t, repoDir, "worktree", "list"), live)
+}
+
+// gitOutput runs a git command in dir and returns its trimmed output
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %v failed: %s", args, output)
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,128 @@
+// Package redact removes secret values from text before it leaves a container:
+// log output, Failure payloads and anything else that reaches the blackboard.
+package redact
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Placeholder replaces each occurrence of a secret value
+const Placeholder = "[REDACTED]"
+
+const (
+	// minValueLength guards against redacting short values that would match everywhere
+	minValueLength = 4
+
+	// minLineLength is the shortest line of a multi-line secret (e.g. a PEM key)
+	// that is redacted on its own, so partial output is still covered
+	minLineLength = 8
+)
+
+// Redactor replaces secret values in text. The zero value and nil redact nothing.
+type Redactor struct {
+	values []string // Longest first, so overlapping values are fully replaced
+}
+
+// New creates a Redactor for the given secret values. Surrounding whitespace is
+// ignored, and each line of a multi-line value is also redacted on its own.
+func New(values ...string) *Redactor {
+	seen := map[string]bool{}
+	add := func(v string, minLength int) {
+		v = strings.TrimSpace(v)
+		if len(v) >= minLength {
+			seen[v] = true
+		}
+	}
+	for _, value := range values {
+		add(value, minValueLength)
+		if strings.Contains(strings.TrimSpace(value), "\n") {
+			for _, line := range strings.Split(value, "\n") {
+				add(line, minLineLength)
+			}
+		}
+	}
+
+	r := &Redactor{values: make([]string, 0, len(seen))}
+	for v := range seen {
+		r.values = append(r.values, v)
+	}
+	sort.Slice(r.values, func(i, j int) bool {
+		if len(r.values[i]) != len(r.values[j]) {
+			return len(r.values[i]) > len(r.values[j])
+		}
+		return r.values[i] < r.values[j]
+	})
+	return r
+}
+
+// FromDir creates a Redactor for every regular file in dir, such as the
+// secrets mounted at /run/secrets. A missing dir yields an empty Redactor.
+func FromDir(dir string) (*Redactor, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return New(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets directory: %w", err)
+	}
+
+	var values []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read secret %s: %w", entry.Name(), err)
+		}
+		values = append(values, string(data))
+	}
+	return New(values...), nil
+}
+
+// Len returns how many distinct values are redacted
+func (r *Redactor) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.values)
+}
+
+// String returns s with every secret value replaced by Placeholder
+func (r *Redactor) String(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, v := range r.values {
+		if strings.Contains(s, v) {
+			s = strings.ReplaceAll(s, v, Placeholder)
+		}
+	}
+	return s
+}
+
+// Writer wraps w so everything written through it is redacted. Each Write is
+// redacted on its own, which suits writers fed whole lines such as a log.Logger.
+func (r *Redactor) Writer(w io.Writer) io.Writer {
+	if r.Len() == 0 {
+		return w
+	}
+	return &writer{redactor: r, w: w}
+}
+
+type writer struct {
+	redactor *Redactor
+	w        io.Writer
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.w, w.redactor.String(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
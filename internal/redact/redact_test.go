@@ -0,0 +1,67 @@
+package redact
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactor_String(t *testing.T) {
+	r := New("sk-live-12345\n", "abc", "  ")
+
+	assert.Equal(t, 1, r.Len(), "short and blank values are ignored")
+	assert.Equal(t, "key=[REDACTED] again [REDACTED]", r.String("key=sk-live-12345 again sk-live-12345"))
+	assert.Equal(t, "abc stays", r.String("abc stays"))
+}
+
+func TestRedactor_LongestValueFirst(t *testing.T) {
+	r := New("token", "token-extended")
+
+	assert.Equal(t, "[REDACTED] and [REDACTED]", r.String("token-extended and token"))
+}
+
+func TestRedactor_MultiLineSecret(t *testing.T) {
+	pem := "-----BEGIN KEY-----\nMIIBVwIBADANBgkq\n-----END KEY-----\n"
+	r := New(pem)
+
+	assert.Equal(t, "[REDACTED]", r.String(pem[:len(pem)-1]))
+	assert.Equal(t, "line: [REDACTED]", r.String("line: MIIBVwIBADANBgkq"))
+}
+
+func TestRedactor_NilIsNoOp(t *testing.T) {
+	var r *Redactor
+
+	assert.Equal(t, 0, r.Len())
+	assert.Equal(t, "secret", r.String("secret"))
+
+	var buf bytes.Buffer
+	assert.Same(t, &buf, r.Writer(&buf))
+}
+
+func TestRedactor_Writer(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New(New("hunter2-password").Writer(&buf), "", 0)
+
+	logger.Printf("connecting with hunter2-password")
+
+	assert.Equal(t, "connecting with [REDACTED]\n", buf.String())
+}
+
+func TestFromDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "api_key"), []byte("sk-test-987654\n"), 0600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "nested"), 0700))
+
+	r, err := FromDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "[REDACTED]", r.String("sk-test-987654"))
+
+	missing, err := FromDir(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, missing.Len())
+}
@@ -0,0 +1,140 @@
+package scenario
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+// check evaluates a scenario's expectations against a run's result
+func check(s *Scenario, result *Result) []string {
+	var failures []string
+
+	if result.TimedOut {
+		failures = append(failures, "workflow did not settle before the timeout")
+	}
+
+	// The goal is submitted by the runner, so expectations cover what agents produced
+	produced := result.Artefacts
+	if len(produced) > 0 && produced[0].Type == "GoalDefined" && produced[0].ProducedByRole == "user" {
+		produced = produced[1:]
+	}
+
+	failures = append(failures, checkSequence(s.Expect.Artefacts, produced, s.Expect.Exhaustive)...)
+
+	if s.Expect.Terminal != nil {
+		switch {
+		case result.Terminal == nil:
+			failures = append(failures, fmt.Sprintf("expected workflow to end with %s, but no Terminal or Failure artefact was created", s.Expect.Terminal))
+		case !s.Expect.Terminal.matches(result.Terminal):
+			failures = append(failures, fmt.Sprintf("expected workflow to end with %s, got %s", s.Expect.Terminal, describe(result.Terminal)))
+		}
+	}
+
+	return failures
+}
+
+// checkSequence verifies expected matchers appear in order. With exhaustive set the
+// produced artefacts must match the expected list one-to-one.
+func checkSequence(expected []ArtefactMatcher, produced []*blackboard.Artefact, exhaustive bool) []string {
+	if exhaustive {
+		var failures []string
+		for i := 0; i < len(expected) || i < len(produced); i++ {
+			switch {
+			case i >= len(produced):
+				failures = append(failures, fmt.Sprintf("artefact %d: expected %s, but only %d artefacts were created", i+1, &expected[i], len(produced)))
+			case i >= len(expected):
+				failures = append(failures, fmt.Sprintf("artefact %d: unexpected %s", i+1, describe(produced[i])))
+			case !expected[i].matches(produced[i]):
+				failures = append(failures, fmt.Sprintf("artefact %d: expected %s, got %s", i+1, &expected[i], describe(produced[i])))
+			}
+		}
+		return failures
+	}
+
+	next := 0
+	for i := range expected {
+		found := false
+		for next < len(produced) {
+			artefact := produced[next]
+			next++
+			if expected[i].matches(artefact) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return []string{fmt.Sprintf("expected artefact %d (%s) was not created in order; created: %s",
+				i+1, &expected[i], describeAll(produced))}
+		}
+	}
+	return nil
+}
+
+// matches reports whether an artefact satisfies every set field of the matcher
+func (m *ArtefactMatcher) matches(artefact *blackboard.Artefact) bool {
+	if m.Type != "" && m.Type != artefact.Type {
+		return false
+	}
+	if m.StructuralType != "" && m.StructuralType != string(artefact.StructuralType) {
+		return false
+	}
+	if m.ProducedBy != "" && m.ProducedBy != artefact.ProducedByRole {
+		return false
+	}
+	if m.Payload != "" && m.Payload != artefact.Payload {
+		return false
+	}
+	if m.PayloadContains != "" && !strings.Contains(artefact.Payload, m.PayloadContains) {
+		return false
+	}
+	if m.Version != 0 && m.Version != artefact.Version {
+		return false
+	}
+	return true
+}
+
+// String describes the matcher for failure messages
+func (m *ArtefactMatcher) String() string {
+	var parts []string
+	if m.Type != "" {
+		parts = append(parts, "type="+m.Type)
+	}
+	if m.StructuralType != "" {
+		parts = append(parts, "structural_type="+m.StructuralType)
+	}
+	if m.ProducedBy != "" {
+		parts = append(parts, "produced_by="+m.ProducedBy)
+	}
+	if m.Payload != "" {
+		parts = append(parts, fmt.Sprintf("payload=%q", m.Payload))
+	}
+	if m.PayloadContains != "" {
+		parts = append(parts, fmt.Sprintf("payload_contains=%q", m.PayloadContains))
+	}
+	if m.Version != 0 {
+		parts = append(parts, fmt.Sprintf("version=%d", m.Version))
+	}
+	if len(parts) == 0 {
+		return "any artefact"
+	}
+	return "{" + strings.Join(parts, " ") + "}"
+}
+
+// describe summarises an artefact for failure messages
+func describe(artefact *blackboard.Artefact) string {
+	return fmt.Sprintf("%s/%s v%d by %s", artefact.Type, artefact.StructuralType, artefact.Version, artefact.ProducedByRole)
+}
+
+// describeAll summarises a sequence of artefacts
+func describeAll(artefacts []*blackboard.Artefact) string {
+	if len(artefacts) == 0 {
+		return "(none)"
+	}
+	descriptions := make([]string, len(artefacts))
+	for i, artefact := range artefacts {
+		descriptions[i] = describe(artefact)
+	}
+	return strings.Join(descriptions, ", ")
+}
@@ -0,0 +1,275 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/dyluth/holt/internal/config"
+	"github.com/dyluth/holt/internal/orchestrator"
+	"github.com/dyluth/holt/internal/pup"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// testInstanceName namespaces the in-memory blackboard
+const testInstanceName = "holt-test"
+
+// subscriptionWait bounds how long to wait for components to subscribe before submitting the goal
+const subscriptionWait = 5 * time.Second
+
+// Result is the outcome of running a scenario.
+type Result struct {
+	Scenario *Scenario
+
+	// Artefacts are all artefacts created during the run in creation order, goal first
+	Artefacts []*blackboard.Artefact
+
+	// Terminal is the last Terminal or Failure artefact created (nil if none)
+	Terminal *blackboard.Artefact
+
+	// TimedOut is true if the blackboard never settled within the timeout
+	TimedOut bool
+
+	Duration time.Duration
+
+	// Failures lists every expectation that was not met
+	Failures []string
+}
+
+// Passed reports whether every expectation was met.
+func (r *Result) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// Options controls a scenario run.
+type Options struct {
+	// Verbose keeps orchestrator and pup logs (written to the standard logger)
+	Verbose bool
+}
+
+// Run executes a scenario against cfg: the orchestrator engine and one pup per agent run
+// in-process on an in-memory blackboard, the goal is submitted, and the run ends when no
+// artefact has been created for the settle period (or on timeout). Expectations are then
+// checked and recorded in Result.Failures.
+//
+// Controller agents are run as ordinary agents executing their worker's command, since
+// worker containers cannot be launched in-process.
+func Run(ctx context.Context, s *Scenario, cfg *config.HoltConfig, opts Options) (*Result, error) {
+	if err := s.checkRoles(cfg); err != nil {
+		return nil, err
+	}
+	timeout, _ := s.timeout()
+	settle, _ := s.settle()
+
+	// Route the Redis client's internal logging through the standard logger so that
+	// connection teardown noise is silenced along with everything else
+	redis.SetLogger(stdLogger{})
+	if !opts.Verbose {
+		logWriter := log.Writer()
+		log.SetOutput(io.Discard)
+		defer log.SetOutput(logWriter)
+	}
+
+	server, err := miniredis.Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start in-memory blackboard: %w", err)
+	}
+	defer server.Close()
+
+	bbClient, err := blackboard.NewClient(&redis.Options{Addr: server.Addr()}, testInstanceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blackboard client: %w", err)
+	}
+	defer bbClient.Close()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Subscribe before anything runs so every artefact is observed
+	subscription, err := bbClient.SubscribeArtefactEvents(runCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to artefact events: %w", err)
+	}
+	defer subscription.Close()
+
+	testConfig := inProcessConfig(cfg)
+
+	var wg sync.WaitGroup
+	engine := orchestrator.NewEngine(bbClient, testInstanceName, testConfig, nil)
+	engine.DisableHealthServer()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := engine.Run(runCtx); err != nil {
+			log.Printf("[holt test] Orchestrator error: %v", err)
+		}
+	}()
+
+	for role, agent := range testConfig.Agents {
+		agentPup := pup.New(pupConfig(role, agent), bbClient)
+		agentPup.SetToolRunner(newScript(role, s.Responses[role]).runner())
+		wg.Add(1)
+		go func(role string) {
+			defer wg.Done()
+			if err := agentPup.Start(runCtx); err != nil {
+				log.Printf("[holt test] Agent %s error: %v", role, err)
+			}
+		}(role)
+	}
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	if err := waitForSubscribers(runCtx, server, testConfig); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	if err := submitGoal(runCtx, bbClient, s.Goal); err != nil {
+		return nil, err
+	}
+
+	result := &Result{Scenario: s}
+	result.TimedOut = collect(runCtx, subscription, timeout, settle, result)
+	result.Duration = time.Since(start)
+	result.Failures = check(s, result)
+
+	return result, nil
+}
+
+// stdLogger adapts the standard logger to the Redis client's logging interface
+type stdLogger struct{}
+
+func (stdLogger) Printf(_ context.Context, format string, v ...interface{}) {
+	log.Printf("[redis] "+format, v...)
+}
+
+// collect records artefact events until the blackboard is idle for settle or timeout elapses.
+// Returns true on timeout.
+func collect(ctx context.Context, subscription *blackboard.Subscription, timeout, settle time.Duration, result *Result) bool {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	idle := time.NewTimer(settle)
+	defer idle.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-deadline.C:
+			return true
+		case <-idle.C:
+			return false
+		case artefact, ok := <-subscription.Events():
+			if !ok {
+				return false
+			}
+			result.Artefacts = append(result.Artefacts, artefact)
+			if artefact.StructuralType == blackboard.StructuralTypeTerminal || artefact.StructuralType == blackboard.StructuralTypeFailure {
+				result.Terminal = artefact
+			}
+			if !idle.Stop() {
+				<-idle.C
+			}
+			idle.Reset(settle)
+		case <-subscription.Errors():
+		}
+	}
+}
+
+// waitForSubscribers blocks until the orchestrator and every pup are subscribed,
+// so the goal and the claims it produces are not published into the void
+func waitForSubscribers(ctx context.Context, server *miniredis.Miniredis, cfg *config.HoltConfig) error {
+	want := map[string]int{
+		blackboard.ArtefactEventsChannel(testInstanceName): 2, // this runner and the orchestrator
+	}
+	if len(cfg.Agents) > 0 {
+		want[blackboard.ClaimEventsChannel(testInstanceName)] = len(cfg.Agents)
+	}
+	for role := range cfg.Agents {
+		want[blackboard.AgentEventsChannel(testInstanceName, role)] = 1
+	}
+
+	deadline := time.Now().Add(subscriptionWait)
+	for {
+		ready := true
+		for channel, count := range want {
+			if server.PubSubNumSub(channel)[channel] < count {
+				ready = false
+				break
+			}
+		}
+		if ready {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for orchestrator and agents to start")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// submitGoal creates the GoalDefined artefact exactly as `holt forage` does
+func submitGoal(ctx context.Context, bbClient *blackboard.Client, goal string) error {
+	artefact := &blackboard.Artefact{
+		ID:              uuid.New().String(),
+		LogicalID:       uuid.New().String(),
+		Version:         1,
+		StructuralType:  blackboard.StructuralTypeStandard,
+		Type:            "GoalDefined",
+		Payload:         goal,
+		SourceArtefacts: []string{},
+		ProducedByRole:  "user",
+	}
+	if err := bbClient.CreateArtefact(ctx, artefact); err != nil {
+		return fmt.Errorf("failed to create goal artefact: %w", err)
+	}
+	return nil
+}
+
+// inProcessConfig returns a copy of cfg with controllers flattened into ordinary agents
+func inProcessConfig(cfg *config.HoltConfig) *config.HoltConfig {
+	copied := *cfg
+	copied.Agents = make(map[string]config.Agent, len(cfg.Agents))
+	for role, agent := range cfg.Agents {
+		if agent.Mode == "controller" && agent.Worker != nil {
+			agent.Mode = ""
+			agent.Image = agent.Worker.Image
+			agent.Command = agent.Worker.Command
+			agent.Workspace = agent.Worker.Workspace
+			agent.Worker = nil
+		}
+		copied.Agents[role] = agent
+	}
+	return &copied
+}
+
+// pupConfig builds the configuration for an in-process pup. Tool execution is scripted,
+// so workspace isolation, auto-commit and commit validation are disabled.
+func pupConfig(role string, agent config.Agent) *pup.Config {
+	return &pup.Config{
+		InstanceName:         testInstanceName,
+		AgentName:            role,
+		RedisURL:             "in-memory",
+		Command:              agent.Command,
+		BiddingStrategy:      blackboard.BidType(agent.BiddingStrategy),
+		BidScript:            agent.BidScript,
+		Consumes:             agent.Consumes,
+		When:                 agent.When,
+		WorkspaceMode:        "ro",
+		Context:              agent.Context,
+		SkipCommitValidation: true,
+	}
+}
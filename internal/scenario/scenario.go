@@ -0,0 +1,254 @@
+// Package scenario runs workflow scenarios for `holt test`: a goal is submitted to the
+// real orchestrator engine, agents are real pups whose tool output is scripted, and the
+// resulting artefacts are checked against expectations. Everything runs in-process
+// against an in-memory blackboard, so no Docker or Redis is needed.
+package scenario
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dyluth/holt/internal/config"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"gopkg.in/yaml.v3"
+)
+
+// Default timing for scenario runs
+const (
+	DefaultTimeout = 30 * time.Second
+	DefaultSettle  = 500 * time.Millisecond
+)
+
+// Scenario describes one workflow test.
+//
+// Example:
+//
+//	name: design then build
+//	goal: "Build a CLI"
+//	responses:
+//	  Designer:
+//	    GoalDefined:
+//	      - artefact_type: DesignSpec
+//	        artefact_payload: "# Spec"
+//	        summary: Designed the CLI
+//	  Coder:
+//	    DesignSpec:
+//	      - artefact_type: Done
+//	        summary: Built it
+//	        structural_type: Terminal
+//	expect:
+//	  artefacts:
+//	    - type: DesignSpec
+//	      produced_by: Designer
+//	  terminal:
+//	    type: Done
+type Scenario struct {
+	// Name identifies the scenario in output (defaults to the file name)
+	Name string `yaml:"name"`
+
+	// Config is the holt.yml to test, relative to the scenario file (default: ./holt.yml)
+	Config string `yaml:"config"`
+
+	// Goal is the GoalDefined payload submitted to start the workflow
+	Goal string `yaml:"goal"`
+
+	// Timeout bounds the whole run, e.g. "30s"
+	Timeout string `yaml:"timeout"`
+
+	// Settle is how long the blackboard must be idle before the run is considered finished
+	Settle string `yaml:"settle"`
+
+	// Responses scripts agent output: role -> target artefact type (glob) -> responses.
+	// Responses are used in order; the last one repeats once the list is exhausted.
+	Responses map[string]map[string][]Response `yaml:"responses"`
+
+	// Expect holds the assertions checked once the run finishes
+	Expect Expectations `yaml:"expect"`
+
+	// path is the file the scenario was loaded from
+	path string
+}
+
+// Response is one scripted tool execution. It mirrors ToolOutput, plus fields to
+// simulate tool failures.
+type Response struct {
+	ArtefactType    string           `yaml:"artefact_type" json:"artefact_type,omitempty"`
+	ArtefactPayload string           `yaml:"artefact_payload" json:"artefact_payload,omitempty"`
+	Summary         string           `yaml:"summary" json:"summary,omitempty"`
+	StructuralType  string           `yaml:"structural_type" json:"structural_type,omitempty"`
+	Artefacts       []OutputArtefact `yaml:"artefacts" json:"artefacts,omitempty"`
+
+	// ExitCode simulates a tool exiting non-zero (the pup creates a Failure artefact)
+	ExitCode int `yaml:"exit_code" json:"-"`
+
+	// Stderr is reported as the tool's stderr
+	Stderr string `yaml:"stderr" json:"-"`
+
+	// Stdout, when set, is written verbatim instead of the ToolOutput fields
+	// (e.g. to test handling of malformed output)
+	Stdout string `yaml:"stdout" json:"-"`
+}
+
+// OutputArtefact is one entry of a scripted batch output.
+type OutputArtefact struct {
+	Ref             string   `yaml:"ref" json:"ref,omitempty"`
+	ArtefactType    string   `yaml:"artefact_type" json:"artefact_type"`
+	ArtefactPayload string   `yaml:"artefact_payload" json:"artefact_payload"`
+	Summary         string   `yaml:"summary" json:"summary,omitempty"`
+	StructuralType  string   `yaml:"structural_type" json:"structural_type,omitempty"`
+	SourceRefs      []string `yaml:"source_refs" json:"source_refs,omitempty"`
+}
+
+// Expectations are checked against the artefacts created during the run.
+type Expectations struct {
+	// Artefacts must appear in this order (other artefacts may be interleaved unless Exhaustive)
+	Artefacts []ArtefactMatcher `yaml:"artefacts"`
+
+	// Exhaustive requires the created artefacts (excluding the goal) to be exactly Artefacts
+	Exhaustive bool `yaml:"exhaustive"`
+
+	// Terminal matches the Terminal or Failure artefact the workflow ended with
+	Terminal *ArtefactMatcher `yaml:"terminal"`
+}
+
+// ArtefactMatcher matches artefacts by any combination of fields. Empty fields match anything.
+type ArtefactMatcher struct {
+	Type            string `yaml:"type"`
+	StructuralType  string `yaml:"structural_type"`
+	ProducedBy      string `yaml:"produced_by"`
+	Payload         string `yaml:"payload"`
+	PayloadContains string `yaml:"payload_contains"`
+	Version         int    `yaml:"version"`
+}
+
+// Load reads and validates a scenario file.
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+
+	var s Scenario
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&s); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+	}
+	s.path = path
+
+	if s.Name == "" {
+		s.Name = filepath.Base(path)
+	}
+
+	if err := s.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid scenario %s: %w", path, err)
+	}
+
+	return &s, nil
+}
+
+// Validate checks the scenario's own fields (not its references into holt.yml).
+func (s *Scenario) Validate() error {
+	if s.Goal == "" {
+		return fmt.Errorf("goal is required")
+	}
+	if _, err := s.timeout(); err != nil {
+		return err
+	}
+	if _, err := s.settle(); err != nil {
+		return err
+	}
+
+	for role, byType := range s.Responses {
+		for artefactType, responses := range byType {
+			if len(responses) == 0 {
+				return fmt.Errorf("responses.%s.%s: at least one response is required", role, artefactType)
+			}
+		}
+	}
+
+	for i, matcher := range s.Expect.Artefacts {
+		if err := matcher.validate(); err != nil {
+			return fmt.Errorf("expect.artefacts[%d]: %w", i, err)
+		}
+	}
+	if s.Expect.Terminal != nil {
+		if err := s.Expect.Terminal.validate(); err != nil {
+			return fmt.Errorf("expect.terminal: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ConfigPath returns the holt.yml path for the scenario.
+func (s *Scenario) ConfigPath() string {
+	if s.Config == "" {
+		return "holt.yml"
+	}
+	if filepath.IsAbs(s.Config) || s.path == "" {
+		return s.Config
+	}
+	return filepath.Join(filepath.Dir(s.path), s.Config)
+}
+
+// checkRoles verifies every scripted role exists in the holt.yml under test
+func (s *Scenario) checkRoles(cfg *config.HoltConfig) error {
+	for role := range s.Responses {
+		if _, ok := cfg.Agents[role]; !ok {
+			return fmt.Errorf("responses: agent '%s' is not defined in %s", role, s.ConfigPath())
+		}
+	}
+	return nil
+}
+
+// timeout returns the parsed run timeout
+func (s *Scenario) timeout() (time.Duration, error) {
+	return parseDuration("timeout", s.Timeout, DefaultTimeout)
+}
+
+// settle returns the parsed idle period
+func (s *Scenario) settle() (time.Duration, error) {
+	return parseDuration("settle", s.Settle, DefaultSettle)
+}
+
+// parseDuration parses an optional positive duration field
+func parseDuration(field, value string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s %q (must be a positive duration like '10s')", field, value)
+	}
+	return d, nil
+}
+
+// stdout renders the response as the tool's stdout
+func (r *Response) stdout() (string, error) {
+	if r.Stdout != "" {
+		return r.Stdout, nil
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal scripted response: %w", err)
+	}
+	return string(data), nil
+}
+
+// validate checks a matcher's fields
+func (m *ArtefactMatcher) validate() error {
+	if m.StructuralType != "" {
+		if err := blackboard.StructuralType(m.StructuralType).Validate(); err != nil {
+			return err
+		}
+	}
+	if m.Version < 0 {
+		return fmt.Errorf("version must be positive")
+	}
+	return nil
+}
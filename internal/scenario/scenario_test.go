@@ -0,0 +1,263 @@
+package scenario
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dyluth/holt/internal/config"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pipelineConfig = `version: "1.0"
+agents:
+  Designer:
+    image: designer:latest
+    command: ["/app/run.sh"]
+    bidding_strategy: exclusive
+    consumes: ["GoalDefined"]
+  Coder:
+    image: coder:latest
+    command: ["/app/run.sh"]
+    bidding_strategy: exclusive
+    consumes: ["DesignSpec"]
+`
+
+// writeScenario writes holt.yml and a scenario into a temp dir and loads both.
+// The scenario's config defaults to the holt.yml beside it.
+func writeScenario(t *testing.T, scenarioYAML string) (*Scenario, *config.HoltConfig) {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "holt.yml"), []byte(pipelineConfig), 0644))
+	if !strings.Contains(scenarioYAML, "config:") {
+		scenarioYAML = "config: holt.yml\n" + scenarioYAML
+	}
+	path := filepath.Join(dir, "scenario.yml")
+	require.NoError(t, os.WriteFile(path, []byte(scenarioYAML), 0644))
+
+	s, err := Load(path)
+	require.NoError(t, err)
+	cfg, err := config.Load(s.ConfigPath())
+	require.NoError(t, err)
+	return s, cfg
+}
+
+func runScenario(t *testing.T, scenarioYAML string) *Result {
+	t.Helper()
+	s, cfg := writeScenario(t, scenarioYAML)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	result, err := Run(ctx, s, cfg, Options{})
+	require.NoError(t, err)
+	return result
+}
+
+func TestRun_Passes(t *testing.T) {
+	result := runScenario(t, `
+name: design then build
+goal: "Build a CLI"
+settle: 300ms
+responses:
+  Designer:
+    GoalDefined:
+      - artefact_type: DesignSpec
+        artefact_payload: "# Spec"
+        summary: Designed the CLI
+  Coder:
+    DesignSpec:
+      - artefact_type: Done
+        artefact_payload: built
+        summary: Built it
+        structural_type: Terminal
+expect:
+  exhaustive: true
+  artefacts:
+    - type: DesignSpec
+      produced_by: Designer
+      payload: "# Spec"
+    - type: Done
+      structural_type: Terminal
+  terminal:
+    type: Done
+`)
+
+	assert.True(t, result.Passed(), "failures: %v", result.Failures)
+	assert.False(t, result.TimedOut)
+	require.Len(t, result.Artefacts, 3)
+	assert.Equal(t, "GoalDefined", result.Artefacts[0].Type)
+	require.NotNil(t, result.Terminal)
+	assert.Equal(t, "Done", result.Terminal.Type)
+}
+
+func TestRun_ReportsUnmetExpectations(t *testing.T) {
+	result := runScenario(t, `
+goal: "Build a CLI"
+settle: 300ms
+responses:
+  Designer:
+    GoalDefined:
+      - artefact_type: DesignSpec
+        artefact_payload: "# Spec"
+        summary: Designed the CLI
+  Coder:
+    DesignSpec:
+      - artefact_type: Done
+        artefact_payload: built
+        summary: Built it
+        structural_type: Terminal
+expect:
+  artefacts:
+    - type: TestReport
+  terminal:
+    type: Released
+`)
+
+	assert.False(t, result.Passed())
+	require.Len(t, result.Failures, 2)
+	assert.Contains(t, result.Failures[0], "type=TestReport")
+	assert.Contains(t, result.Failures[1], "expected workflow to end with {type=Released}")
+}
+
+func TestRun_ExitCodeCreatesFailure(t *testing.T) {
+	result := runScenario(t, `
+goal: "Build a CLI"
+settle: 300ms
+responses:
+  Designer:
+    GoalDefined:
+      - exit_code: 2
+        stderr: "design tool crashed"
+expect:
+  exhaustive: true
+  artefacts:
+    - structural_type: Failure
+      produced_by: Designer
+      payload_contains: "design tool crashed"
+  terminal:
+    structural_type: Failure
+`)
+
+	assert.True(t, result.Passed(), "failures: %v", result.Failures)
+}
+
+func TestRun_UnknownRole(t *testing.T) {
+	s, cfg := writeScenario(t, `
+goal: "Build a CLI"
+responses:
+  Tester:
+    "*":
+      - artefact_type: Done
+        artefact_payload: ok
+        summary: ok
+`)
+
+	_, err := Run(context.Background(), s, cfg, Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "agent 'Tester' is not defined")
+}
+
+func TestLoad_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"missing goal", "name: x\n", "goal is required"},
+		{"unknown field", "goal: g\nexpected: []\n", "field expected not found"},
+		{"bad timeout", "goal: g\ntimeout: soon\n", "invalid timeout"},
+		{"negative settle", "goal: g\nsettle: -1s\n", "invalid settle"},
+		{"empty responses", "goal: g\nresponses:\n  Coder:\n    GoalDefined: []\n", "responses.Coder.GoalDefined"},
+		{"bad structural type", "goal: g\nexpect:\n  terminal:\n    structural_type: Finished\n", "expect.terminal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "scenario.yml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.yaml), 0644))
+
+			_, err := Load(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "smoke.yml")
+	require.NoError(t, os.WriteFile(path, []byte("goal: g\nconfig: ../holt.yml\n"), 0644))
+
+	s, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "smoke.yml", s.Name)
+	assert.Equal(t, filepath.Join(filepath.Dir(dir), "holt.yml"), s.ConfigPath())
+
+	timeout, _ := s.timeout()
+	settle, _ := s.settle()
+	assert.Equal(t, DefaultTimeout, timeout)
+	assert.Equal(t, DefaultSettle, settle)
+
+	assert.Equal(t, "holt.yml", (&Scenario{}).ConfigPath())
+}
+
+func TestScript_Next(t *testing.T) {
+	s := newScript("Coder", map[string][]Response{
+		"DesignSpec": {{ArtefactType: "First"}, {ArtefactType: "Second"}},
+		"Review*":    {{ArtefactType: "Fix"}},
+	})
+
+	first, err := s.next("DesignSpec")
+	require.NoError(t, err)
+	second, err := s.next("DesignSpec")
+	require.NoError(t, err)
+	repeated, err := s.next("DesignSpec")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"First", "Second", "Second"},
+		[]string{first.ArtefactType, second.ArtefactType, repeated.ArtefactType})
+
+	glob, err := s.next("ReviewFeedback")
+	require.NoError(t, err)
+	assert.Equal(t, "Fix", glob.ArtefactType)
+
+	_, err = s.next("GoalDefined")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no scripted response for agent 'Coder' on artefact type 'GoalDefined'")
+}
+
+func TestCheckSequence(t *testing.T) {
+	produced := []*blackboard.Artefact{
+		{Type: "DesignSpec", ProducedByRole: "Designer", Version: 1, StructuralType: blackboard.StructuralTypeStandard},
+		{Type: "Review", ProducedByRole: "Reviewer", Version: 1, StructuralType: blackboard.StructuralTypeReview},
+		{Type: "Done", ProducedByRole: "Coder", Version: 1, StructuralType: blackboard.StructuralTypeTerminal},
+	}
+
+	t.Run("subsequence in order", func(t *testing.T) {
+		failures := checkSequence([]ArtefactMatcher{{Type: "DesignSpec"}, {Type: "Done"}}, produced, false)
+		assert.Empty(t, failures)
+	})
+
+	t.Run("out of order", func(t *testing.T) {
+		failures := checkSequence([]ArtefactMatcher{{Type: "Done"}, {Type: "DesignSpec"}}, produced, false)
+		require.Len(t, failures, 1)
+		assert.Contains(t, failures[0], "expected artefact 2 ({type=DesignSpec})")
+	})
+
+	t.Run("exhaustive reports extra artefacts", func(t *testing.T) {
+		failures := checkSequence([]ArtefactMatcher{{Type: "DesignSpec"}, {ProducedBy: "Reviewer"}}, produced, true)
+		require.Len(t, failures, 1)
+		assert.Equal(t, "artefact 3: unexpected Done/Terminal v1 by Coder", failures[0])
+	})
+
+	t.Run("exhaustive reports missing artefacts", func(t *testing.T) {
+		failures := checkSequence([]ArtefactMatcher{{}, {}, {}, {Type: "Released"}}, produced, true)
+		require.Len(t, failures, 1)
+		assert.Contains(t, failures[0], "only 3 artefacts were created")
+	})
+}
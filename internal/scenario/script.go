@@ -0,0 +1,101 @@
+package scenario
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/dyluth/holt/internal/config"
+	"github.com/dyluth/holt/internal/pup"
+)
+
+// script serves scripted responses for one agent role
+type script struct {
+	role string
+
+	mu        sync.Mutex
+	responses map[string][]Response // target type glob -> responses
+	used      map[string]int        // target type glob -> responses consumed
+}
+
+// newScript returns the script for role (which may have no responses)
+func newScript(role string, responses map[string][]Response) *script {
+	return &script{
+		role:      role,
+		responses: responses,
+		used:      make(map[string]int),
+	}
+}
+
+// runner returns a pup ToolRunner that answers each claim from the script
+func (s *script) runner() pup.ToolRunner {
+	return func(ctx context.Context, inputJSON string) (int, string, string, error) {
+		var input struct {
+			TargetArtefact struct {
+				Type string `json:"type"`
+			} `json:"target_artefact"`
+		}
+		if err := json.Unmarshal([]byte(inputJSON), &input); err != nil {
+			return -1, "", "", fmt.Errorf("failed to parse tool input: %w", err)
+		}
+
+		response, err := s.next(input.TargetArtefact.Type)
+		if err != nil {
+			return -1, "", "", err
+		}
+
+		if response.ExitCode != 0 {
+			return response.ExitCode, response.Stdout, response.Stderr,
+				fmt.Errorf("process exited with code %d", response.ExitCode)
+		}
+
+		stdout, err := response.stdout()
+		if err != nil {
+			return -1, "", "", err
+		}
+		return 0, stdout, response.Stderr, nil
+	}
+}
+
+// next returns the next response for a target artefact type.
+// Exact type keys take precedence over globs; globs are tried in name order.
+func (s *script) next(targetType string) (Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.matchKey(targetType)
+	if !ok {
+		return Response{}, fmt.Errorf("no scripted response for agent '%s' on artefact type '%s'", s.role, targetType)
+	}
+
+	responses := s.responses[key]
+	index := s.used[key]
+	if index >= len(responses) {
+		index = len(responses) - 1
+	}
+	s.used[key]++
+
+	return responses[index], nil
+}
+
+// matchKey finds the response key for a target type
+func (s *script) matchKey(targetType string) (string, bool) {
+	if _, ok := s.responses[targetType]; ok {
+		return targetType, true
+	}
+
+	keys := make([]string, 0, len(s.responses))
+	for key := range s.responses {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if config.MatchArtefactType([]string{key}, targetType) {
+			return key, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,51 @@
+package top
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/dyluth/holt/internal/pup"
+)
+
+// DockerHealthProbe returns a HealthProbe that fetches the pup's /healthz from inside
+// the agent container with docker exec, as `holt up` does (the port is not published).
+func DockerHealthProbe(cli *client.Client) HealthProbe {
+	return func(ctx context.Context, containerID string) (*pup.HealthResponse, error) {
+		exec, err := cli.ContainerExecCreate(ctx, containerID, types.ExecConfig{
+			Cmd:          []string{"wget", "-q", "-O-", "http://localhost:8080/healthz"},
+			AttachStdout: true,
+			AttachStderr: true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create exec: %w", err)
+		}
+
+		attach, err := cli.ContainerExecAttach(ctx, exec.ID, types.ExecStartCheck{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to start exec: %w", err)
+		}
+		defer attach.Close()
+
+		var stdout, stderr bytes.Buffer
+		if _, err := stdcopy.StdCopy(&stdout, &stderr, attach.Reader); err != nil {
+			return nil, fmt.Errorf("failed to read health response: %w", err)
+		}
+
+		// /healthz answers 503 with a JSON body when unhealthy, which wget reports as a
+		// failure without printing the body; a decodable body is preferred when present
+		var resp pup.HealthResponse
+		if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+			inspect, inspectErr := cli.ContainerExecInspect(ctx, exec.ID)
+			if inspectErr == nil && inspect.ExitCode != 0 {
+				return nil, fmt.Errorf("health check failed (exit code %d)", inspect.ExitCode)
+			}
+			return nil, fmt.Errorf("invalid health response: %w", err)
+		}
+		return &resp, nil
+	}
+}
@@ -0,0 +1,224 @@
+package top
+
+// key is a decoded keypress
+type key int
+
+const (
+	keyNone key = iota
+	keyUp
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyTab
+	keyEnter
+	keyBack
+	keyRefresh
+	keyQuit
+)
+
+// parseKeys decodes raw terminal input into keypresses.
+// A lone ESC is treated as "back"; arrow and page keys arrive as CSI sequences.
+func parseKeys(buf []byte) []key {
+	var keys []key
+	for i := 0; i < len(buf); i++ {
+		switch b := buf[i]; b {
+		case 0x1b:
+			if i+2 < len(buf) && buf[i+1] == '[' {
+				switch buf[i+2] {
+				case 'A':
+					keys = append(keys, keyUp)
+					i += 2
+					continue
+				case 'B':
+					keys = append(keys, keyDown)
+					i += 2
+					continue
+				case '5', '6':
+					if i+3 < len(buf) && buf[i+3] == '~' {
+						if buf[i+2] == '5' {
+							keys = append(keys, keyPageUp)
+						} else {
+							keys = append(keys, keyPageDown)
+						}
+						i += 3
+						continue
+					}
+				}
+				// Unhandled sequence: skip the CSI introducer
+				i += 2
+				continue
+			}
+			keys = append(keys, keyBack)
+		case 'k':
+			keys = append(keys, keyUp)
+		case 'j':
+			keys = append(keys, keyDown)
+		case '\t':
+			keys = append(keys, keyTab)
+		case '\r', '\n':
+			keys = append(keys, keyEnter)
+		case 0x7f, 0x08, 'b':
+			keys = append(keys, keyBack)
+		case 'r':
+			keys = append(keys, keyRefresh)
+		case 'q', 0x03: // q or Ctrl+C
+			keys = append(keys, keyQuit)
+		}
+	}
+	return keys
+}
+
+// pane identifies the selectable list with focus on the dashboard
+type pane int
+
+const (
+	paneClaims pane = iota
+	paneArtefacts
+)
+
+// detail is a drill-down view (an artefact or a claim) replacing the dashboard
+type detail struct {
+	title  string
+	lines  []string
+	scroll int
+}
+
+// command is what the event loop must do in response to a keypress
+type command struct {
+	quit         bool
+	refresh      bool
+	openArtefact string // Artefact ID to show in a detail view
+	openClaim    string // Claim ID to show in a detail view
+}
+
+// model is the dashboard's UI state. Selections are tracked by ID so they
+// survive refreshes that reorder or remove rows.
+type model struct {
+	snap   *Snapshot
+	err    error // Last refresh error, shown in the status line
+	focus  pane
+	detail *detail
+
+	selectedClaim    string
+	selectedArtefact string
+}
+
+// claimRows returns the selectable claims: active claims followed by queued ones
+func (m *model) claimRows() []ClaimRow {
+	if m.snap == nil {
+		return nil
+	}
+	rows := make([]ClaimRow, 0, len(m.snap.Claims)+len(m.snap.Queued))
+	rows = append(rows, m.snap.Claims...)
+	return append(rows, m.snap.Queued...)
+}
+
+// claimIndex returns the index of the selected claim, defaulting to the first row
+func (m *model) claimIndex() int {
+	for i, row := range m.claimRows() {
+		if row.Claim.ID == m.selectedClaim {
+			return i
+		}
+	}
+	return 0
+}
+
+// artefactIndex returns the index of the selected artefact, defaulting to the newest
+func (m *model) artefactIndex() int {
+	if m.snap == nil {
+		return 0
+	}
+	for i, artefact := range m.snap.Artefacts {
+		if artefact.ID == m.selectedArtefact {
+			return i
+		}
+	}
+	return 0
+}
+
+// setSnapshot replaces the displayed data
+func (m *model) setSnapshot(snap *Snapshot) {
+	m.snap = snap
+	m.err = nil
+}
+
+// handleKey applies a keypress and returns the resulting command.
+// pageSize is the number of lines a page key scrolls in a detail view.
+func (m *model) handleKey(k key, pageSize int) command {
+	if k == keyQuit {
+		return command{quit: true}
+	}
+	if k == keyRefresh {
+		return command{refresh: true}
+	}
+
+	if m.detail != nil {
+		switch k {
+		case keyBack, keyEnter:
+			m.detail = nil
+		case keyUp:
+			m.scrollDetail(-1)
+		case keyDown:
+			m.scrollDetail(1)
+		case keyPageUp:
+			m.scrollDetail(-pageSize)
+		case keyPageDown:
+			m.scrollDetail(pageSize)
+		}
+		return command{}
+	}
+
+	switch k {
+	case keyTab:
+		if m.focus == paneClaims {
+			m.focus = paneArtefacts
+		} else {
+			m.focus = paneClaims
+		}
+	case keyUp, keyPageUp:
+		m.moveSelection(-1)
+	case keyDown, keyPageDown:
+		m.moveSelection(1)
+	case keyEnter:
+		if m.focus == paneClaims {
+			if rows := m.claimRows(); len(rows) > 0 {
+				return command{openClaim: rows[m.claimIndex()].Claim.ID}
+			}
+		} else if m.snap != nil && len(m.snap.Artefacts) > 0 {
+			return command{openArtefact: m.snap.Artefacts[m.artefactIndex()].ID}
+		}
+	}
+	return command{}
+}
+
+// moveSelection moves the focused pane's selection by delta rows, clamped to the list
+func (m *model) moveSelection(delta int) {
+	if m.focus == paneClaims {
+		rows := m.claimRows()
+		if len(rows) == 0 {
+			return
+		}
+		m.selectedClaim = rows[clamp(m.claimIndex()+delta, len(rows))].Claim.ID
+		return
+	}
+	if m.snap == nil || len(m.snap.Artefacts) == 0 {
+		return
+	}
+	m.selectedArtefact = m.snap.Artefacts[clamp(m.artefactIndex()+delta, len(m.snap.Artefacts))].ID
+}
+
+// scrollDetail scrolls the detail view by delta lines
+func (m *model) scrollDetail(delta int) {
+	m.detail.scroll = clamp(m.detail.scroll+delta, len(m.detail.lines))
+}
+
+// clamp limits i to [0, n)
+func clamp(i, n int) int {
+	if i >= n {
+		i = n - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	return i
+}
@@ -0,0 +1,88 @@
+package top
+
+import (
+	"testing"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeys(t *testing.T) {
+	assert.Equal(t, []key{keyUp, keyDown}, parseKeys([]byte("\x1b[A\x1b[B")))
+	assert.Equal(t, []key{keyPageUp, keyPageDown}, parseKeys([]byte("\x1b[5~\x1b[6~")))
+	assert.Equal(t, []key{keyBack}, parseKeys([]byte("\x1b")))
+	assert.Equal(t, []key{keyUp, keyDown, keyTab, keyEnter, keyRefresh, keyQuit}, parseKeys([]byte("kj\t\rrq")))
+	assert.Equal(t, []key{keyQuit}, parseKeys([]byte{0x03}))
+	assert.Empty(t, parseKeys([]byte("\x1b[Cx")), "unhandled sequences and keys are ignored")
+}
+
+func testSnapshot() *Snapshot {
+	claim := func(id string) ClaimRow {
+		return ClaimRow{Claim: &blackboard.Claim{ID: id}, Phase: "exclusive"}
+	}
+	return &Snapshot{
+		Instance: "test",
+		Claims:   []ClaimRow{claim("claim-a"), claim("claim-b")},
+		Queued:   []ClaimRow{claim("claim-q")},
+		Artefacts: []*blackboard.Artefact{
+			{ID: "artefact-1", Type: "CodeCommit"},
+			{ID: "artefact-2", Type: "GoalDefined"},
+		},
+	}
+}
+
+func TestModel_Navigation(t *testing.T) {
+	m := &model{}
+	m.setSnapshot(testSnapshot())
+
+	// Claims pane: moves across active and queued claims, clamped at the end
+	assert.Equal(t, command{openClaim: "claim-a"}, m.handleKey(keyEnter, 10))
+	m.handleKey(keyDown, 10)
+	m.handleKey(keyDown, 10)
+	m.handleKey(keyDown, 10)
+	assert.Equal(t, command{openClaim: "claim-q"}, m.handleKey(keyEnter, 10))
+	m.handleKey(keyUp, 10)
+	assert.Equal(t, "claim-b", m.selectedClaim)
+
+	// Artefacts pane
+	m.handleKey(keyTab, 10)
+	m.handleKey(keyDown, 10)
+	assert.Equal(t, command{openArtefact: "artefact-2"}, m.handleKey(keyEnter, 10))
+
+	assert.Equal(t, command{quit: true}, m.handleKey(keyQuit, 10))
+	assert.Equal(t, command{refresh: true}, m.handleKey(keyRefresh, 10))
+}
+
+func TestModel_SelectionSurvivesRefresh(t *testing.T) {
+	m := &model{}
+	m.setSnapshot(testSnapshot())
+	m.handleKey(keyDown, 10)
+	require.Equal(t, "claim-b", m.selectedClaim)
+
+	// claim-a finished; claim-b is now the first row but stays selected
+	snap := testSnapshot()
+	snap.Claims = snap.Claims[1:]
+	m.setSnapshot(snap)
+	assert.Equal(t, 0, m.claimIndex())
+	assert.Equal(t, command{openClaim: "claim-b"}, m.handleKey(keyEnter, 10))
+}
+
+func TestModel_Detail(t *testing.T) {
+	m := &model{}
+	m.setSnapshot(testSnapshot())
+	m.detail = &detail{title: "Artefact", lines: []string{"1", "2", "3", "4", "5"}}
+
+	m.handleKey(keyPageDown, 3)
+	assert.Equal(t, 3, m.detail.scroll)
+	m.handleKey(keyPageDown, 3)
+	assert.Equal(t, 4, m.detail.scroll, "scroll is clamped to the last line")
+	m.handleKey(keyUp, 3)
+	assert.Equal(t, 3, m.detail.scroll)
+
+	// Selection keys scroll the detail rather than moving the dashboard selection
+	assert.Empty(t, m.selectedClaim)
+
+	m.handleKey(keyBack, 3)
+	assert.Nil(t, m.detail)
+}
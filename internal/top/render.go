@@ -0,0 +1,274 @@
+package top
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// style is how a rendered line is painted
+type style int
+
+const (
+	styleNormal style = iota
+	styleTitle
+	styleHeader
+	styleSelected
+	styleWarn
+	styleDim
+)
+
+// ansi escape sequences for each style
+var styleCodes = map[style]string{
+	styleTitle:    "\x1b[1;36m",
+	styleHeader:   "\x1b[1m",
+	styleSelected: "\x1b[7m",
+	styleWarn:     "\x1b[31m",
+	styleDim:      "\x1b[2m",
+}
+
+// line is a single rendered screen line
+type line struct {
+	text  string
+	style style
+}
+
+// maxQueuedRows caps the grant queue section so it cannot crowd out the feed
+const maxQueuedRows = 5
+
+// render lays out the current view for a width x height terminal
+func render(m *model, width, height int) []line {
+	var lines []line
+	if m.detail != nil {
+		lines = renderDetail(m.detail, height)
+	} else {
+		lines = renderDashboard(m, height)
+	}
+
+	for i := range lines {
+		lines[i].text = truncate(lines[i].text, width)
+	}
+	return lines
+}
+
+// renderDashboard lays out the agents, claims, grant queue and artefact feed
+func renderDashboard(m *model, height int) []line {
+	var lines []line
+	add := func(s style, format string, args ...interface{}) {
+		lines = append(lines, line{text: fmt.Sprintf(format, args...), style: s})
+	}
+
+	snap := m.snap
+	if snap == nil {
+		add(styleTitle, "holt top")
+		add(styleDim, "Loading...")
+		return lines
+	}
+
+	add(styleTitle, "holt top - instance: %s   updated %s", snap.Instance, snap.TakenAt.Format("15:04:05"))
+	add(styleNormal, "agents %d   claims %d   queued %d   workers %d   artefacts %d",
+		len(snap.Agents), len(snap.Claims), len(snap.Queued), snap.Workers, len(snap.Artefacts))
+	if m.err != nil {
+		add(styleWarn, "refresh failed: %v", m.err)
+	}
+
+	// Agents
+	add(styleNormal, "")
+	add(styleHeader, "AGENTS")
+	add(styleDim, "  %-20s %-9s %-10s %-9s %s", "ROLE", "STATE", "HEALTH", "IN-FLIGHT", "WORKERS")
+	if len(snap.Agents) == 0 {
+		add(styleDim, "  (no agent containers)")
+	}
+	for _, agent := range snap.Agents {
+		s := styleNormal
+		if agent.State != "running" || agent.Health == "unhealthy" {
+			s = styleWarn
+		}
+		inFlight := "-"
+		if agent.Health == "healthy" || agent.Concurrency > 0 {
+			inFlight = fmt.Sprintf("%d", agent.InFlight)
+			if agent.Concurrency > 0 {
+				inFlight = fmt.Sprintf("%d/%d", agent.InFlight, agent.Concurrency)
+			}
+		}
+		text := fmt.Sprintf("  %-20s %-9s %-10s %-9s %d", agent.Role, agent.State, agent.Health, inFlight, agent.Workers)
+		if agent.HealthError != "" {
+			text += "   " + agent.HealthError
+		}
+		add(s, "%s", text)
+	}
+
+	// Claims and grant queue share one selection
+	selectedClaim := m.claimIndex()
+	footer := 2 // blank line + key help
+	remaining := height - len(lines) - footer
+
+	// Split what is left between claims and the artefact feed
+	queued := snap.Queued
+	if len(queued) > maxQueuedRows {
+		queued = queued[:maxQueuedRows]
+	}
+	claimBudget := (remaining - 6) / 2 // section headers for claims, queue and feed
+	if claimBudget < 1 {
+		claimBudget = 1
+	}
+
+	add(styleNormal, "")
+	add(styleHeader, "ACTIVE CLAIMS")
+	add(styleDim, "  %-8s %-10s %8s  %-24s %s", "CLAIM", "PHASE", "ELAPSED", "ARTEFACT", "AGENTS")
+	if len(snap.Claims) == 0 {
+		add(styleDim, "  (none)")
+	}
+	start, end := window(len(snap.Claims), claimBudget, selectedClaim)
+	for i := start; i < end; i++ {
+		row := snap.Claims[i]
+		text := fmt.Sprintf("%-8s %-10s %8s  %-24s %s", shortID(row.Claim.ID), row.Phase,
+			formatElapsed(row.Elapsed), row.ArtefactType, strings.Join(row.Agents, ","))
+		lines = append(lines, selectable(text, m.focus == paneClaims, i == selectedClaim))
+	}
+
+	if len(queued) > 0 {
+		add(styleNormal, "")
+		add(styleHeader, "GRANT QUEUE")
+		for i, row := range queued {
+			idx := len(snap.Claims) + i
+			text := fmt.Sprintf("%-8s %-20s waiting %s", shortID(row.Claim.ID), strings.Join(row.Agents, ","), formatElapsed(row.Elapsed))
+			lines = append(lines, selectable(text, m.focus == paneClaims, idx == selectedClaim))
+		}
+	}
+
+	// Artefact feed fills the rest of the screen
+	add(styleNormal, "")
+	add(styleHeader, "ARTEFACTS (newest first)")
+	feedBudget := height - len(lines) - footer
+	if feedBudget < 1 {
+		feedBudget = 1
+	}
+	if len(snap.Artefacts) == 0 {
+		add(styleDim, "  (none)")
+	}
+	selectedArtefact := m.artefactIndex()
+	start, end = window(len(snap.Artefacts), feedBudget, selectedArtefact)
+	for i := start; i < end; i++ {
+		artefact := snap.Artefacts[i]
+		text := fmt.Sprintf("%-8s %s  %-9s %-24s %-14s %s", shortID(artefact.ID),
+			time.UnixMilli(artefact.CreatedAtMs).Format("15:04:05"), artefact.StructuralType,
+			artefact.Type, artefact.ProducedByRole, oneLine(artefact.Payload))
+		lines = append(lines, selectable(text, m.focus == paneArtefacts, i == selectedArtefact))
+	}
+
+	for len(lines) < height-1 {
+		add(styleNormal, "")
+	}
+	add(styleDim, "[tab] switch pane  [up/down] select  [enter] details  [r] refresh  [q] quit")
+	return lines
+}
+
+// renderDetail lays out a scrolled drill-down view
+func renderDetail(d *detail, height int) []line {
+	lines := []line{{text: d.title, style: styleTitle}, {}}
+
+	body := height - 3 // title, blank, key help
+	if body < 1 {
+		body = 1
+	}
+	end := d.scroll + body
+	if end > len(d.lines) {
+		end = len(d.lines)
+	}
+	for _, text := range d.lines[d.scroll:end] {
+		lines = append(lines, line{text: text})
+	}
+
+	for len(lines) < height-1 {
+		lines = append(lines, line{})
+	}
+	lines = append(lines, line{text: "[up/down/pgup/pgdn] scroll  [esc] back  [q] quit", style: styleDim})
+	return lines
+}
+
+// selectable renders a list row with a selection marker, highlighted when its pane has focus
+func selectable(text string, focused, selected bool) line {
+	if !selected {
+		return line{text: "  " + text}
+	}
+	if focused {
+		return line{text: "> " + text, style: styleSelected}
+	}
+	return line{text: "> " + text}
+}
+
+// window returns the [start, end) range of n rows that fits size rows and shows selected
+func window(n, size, selected int) (int, int) {
+	if n <= size {
+		return 0, n
+	}
+	start := selected - size + 1
+	if start < 0 {
+		start = 0
+	}
+	return start, start + size
+}
+
+// paint renders lines as a full-screen frame, clearing leftovers from the previous one
+func paint(lines []line) string {
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for i, l := range lines {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		if code, ok := styleCodes[l.style]; ok {
+			b.WriteString(code)
+			b.WriteString(l.text)
+			b.WriteString("\x1b[0m")
+		} else {
+			b.WriteString(l.text)
+		}
+		b.WriteString("\x1b[K")
+	}
+	b.WriteString("\x1b[J")
+	return b.String()
+}
+
+// truncate shortens s to at most width runes
+func truncate(s string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	if width == 1 {
+		return string(runes[:1])
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// oneLine collapses a payload onto a single line for the feed
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// shortID returns the first 8 characters of an ID, as hoard displays them
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// formatElapsed renders a duration compactly: 45s, 3m12s, 2h05m
+func formatElapsed(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
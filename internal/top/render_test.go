@@ -0,0 +1,118 @@
+package top
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func texts(lines []line) string {
+	var parts []string
+	for _, l := range lines {
+		parts = append(parts, l.text)
+	}
+	return strings.Join(parts, "\n")
+}
+
+func TestRender_Dashboard(t *testing.T) {
+	snap := &Snapshot{
+		Instance: "prod",
+		TakenAt:  time.Date(2025, 1, 1, 14, 3, 22, 0, time.Local),
+		Agents: []AgentRow{
+			{Role: "coder", State: "running", Health: "healthy", Concurrency: 2, InFlight: 1, Workers: 1},
+			{Role: "tester", State: "running", Health: "unhealthy", HealthError: "connection refused"},
+		},
+		Claims: []ClaimRow{{
+			Claim:        &blackboard.Claim{ID: "1a2b3c4d-0000-0000-0000-000000000000"},
+			ArtefactType: "GoalDefined",
+			Phase:        "exclusive",
+			Elapsed:      72 * time.Second,
+			Agents:       []string{"coder"},
+		}},
+		Queued: []ClaimRow{{
+			Claim:   &blackboard.Claim{ID: "5e6f7a8b-0000-0000-0000-000000000000"},
+			Elapsed: 45 * time.Second,
+			Agents:  []string{"coder"},
+		}},
+		Workers: 1,
+		Artefacts: []*blackboard.Artefact{{
+			ID:             "9f8e7d6c-0000-0000-0000-000000000000",
+			StructuralType: blackboard.StructuralTypeStandard,
+			Type:           "CodeCommit",
+			ProducedByRole: "coder",
+			Payload:        "abc123\nsecond line",
+		}},
+	}
+	m := &model{}
+	m.setSnapshot(snap)
+
+	lines := render(m, 200, 30)
+	require.Len(t, lines, 30, "the frame fills the terminal")
+	out := texts(lines)
+
+	assert.Contains(t, out, "holt top - instance: prod   updated 14:03:22")
+	assert.Contains(t, out, "agents 2   claims 1   queued 1   workers 1   artefacts 1")
+	assert.Contains(t, out, "coder                running   healthy    1/2       1")
+	assert.Contains(t, out, "unhealthy  -         0   connection refused")
+	assert.Contains(t, out, "> 1a2b3c4d exclusive     1m12s  GoalDefined              coder")
+	assert.Contains(t, out, "  5e6f7a8b coder                waiting 45s")
+	assert.Contains(t, out, "9f8e7d6c", "artefact feed")
+	assert.Contains(t, out, "abc123 second line", "payloads are collapsed onto one line")
+	assert.Contains(t, lines[len(lines)-1].text, "[q] quit")
+
+	// The unhealthy agent and the focused selection are highlighted
+	for _, l := range lines {
+		if strings.Contains(l.text, "tester") {
+			assert.Equal(t, styleWarn, l.style)
+		}
+		if strings.HasPrefix(l.text, "> 1a2b3c4d") {
+			assert.Equal(t, styleSelected, l.style)
+		}
+	}
+}
+
+func TestRender_TruncatesToWidth(t *testing.T) {
+	m := &model{}
+	m.setSnapshot(&Snapshot{Instance: "a-very-long-instance-name"})
+	for _, l := range render(m, 20, 10) {
+		assert.LessOrEqual(t, len([]rune(l.text)), 20)
+	}
+}
+
+func TestRender_Detail(t *testing.T) {
+	m := &model{detail: &detail{title: "Artefact abc", lines: []string{"{", `  "id": "abc"`, "}"}, scroll: 1}}
+	lines := render(m, 80, 10)
+	require.Len(t, lines, 10)
+	assert.Equal(t, "Artefact abc", lines[0].text)
+	assert.Equal(t, `  "id": "abc"`, lines[2].text)
+	assert.Equal(t, "}", lines[3].text)
+}
+
+func TestWindow(t *testing.T) {
+	start, end := window(3, 5, 0)
+	assert.Equal(t, [2]int{0, 3}, [2]int{start, end})
+
+	start, end = window(10, 4, 2)
+	assert.Equal(t, [2]int{0, 4}, [2]int{start, end})
+
+	start, end = window(10, 4, 7)
+	assert.Equal(t, [2]int{4, 8}, [2]int{start, end}, "the selection stays visible")
+}
+
+func TestFormatElapsed(t *testing.T) {
+	assert.Equal(t, "-", formatElapsed(0))
+	assert.Equal(t, "45s", formatElapsed(45*time.Second))
+	assert.Equal(t, "3m12s", formatElapsed(192*time.Second))
+	assert.Equal(t, "2h05m", formatElapsed(2*time.Hour+5*time.Minute))
+}
+
+func TestPaint(t *testing.T) {
+	frame := paint([]line{{text: "title", style: styleTitle}, {text: "plain"}})
+	assert.True(t, strings.HasPrefix(frame, "\x1b[H"))
+	assert.Contains(t, frame, "\x1b[1;36mtitle\x1b[0m\x1b[K\r\nplain\x1b[K")
+	assert.True(t, strings.HasSuffix(frame, "\x1b[J"))
+}
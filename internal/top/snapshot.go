@@ -0,0 +1,293 @@
+// Package top implements `holt top`, an interactive terminal dashboard showing an
+// instance's agents, active claims, grant queues, workers and artefact feed.
+package top
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	dockerpkg "github.com/dyluth/holt/internal/docker"
+	"github.com/dyluth/holt/internal/pup"
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+// DefaultArtefactLimit is how many of the newest artefacts the feed keeps.
+const DefaultArtefactLimit = 50
+
+// healthTimeout bounds a single agent health probe so one stuck agent cannot stall a refresh
+const healthTimeout = 3 * time.Second
+
+// activeClaimStatuses are the claim states shown on the dashboard
+var activeClaimStatuses = []string{
+	string(blackboard.ClaimStatusPendingReview),
+	string(blackboard.ClaimStatusPendingParallel),
+	string(blackboard.ClaimStatusPendingExclusive),
+	string(blackboard.ClaimStatusPendingAssignment),
+	string(blackboard.ClaimStatusAwaitingChild),
+}
+
+// DockerAPI is the subset of the Docker client used to find agent and worker containers.
+type DockerAPI interface {
+	ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error)
+}
+
+// HealthProbe fetches an agent's /healthz response from inside its container.
+type HealthProbe func(ctx context.Context, containerID string) (*pup.HealthResponse, error)
+
+// Snapshot is one refresh of the dashboard's data.
+type Snapshot struct {
+	Instance  string
+	TakenAt   time.Time
+	Agents    []AgentRow
+	Claims    []ClaimRow             // Active claims, oldest first
+	Queued    []ClaimRow             // Claims paused in a grant queue, in grant order
+	Workers   int                    // Running worker containers across all roles
+	Artefacts []*blackboard.Artefact // Newest first
+}
+
+// AgentRow is an agent container with its health.
+type AgentRow struct {
+	Role          string
+	ContainerID   string
+	ContainerName string
+	State         string // Docker state: running, exited, ...
+	Health        string // healthy, unhealthy or unknown
+	HealthError   string
+	Concurrency   int // Max parallel executions reported by /healthz (0 if not reported)
+	InFlight      int // Claims currently executing in the agent
+	Workers       int // Running worker containers for this role
+}
+
+// ClaimRow is an active claim with its phase timing.
+type ClaimRow struct {
+	Claim        *blackboard.Claim
+	ArtefactType string
+	Phase        string
+	Elapsed      time.Duration // Time in the current phase (zero if unknown)
+	Agents       []string      // Agents granted in the current phase, or queued for
+}
+
+// Collector gathers dashboard snapshots for one instance. Artefacts are cached
+// between refreshes so each refresh only fetches artefacts created since the last.
+type Collector struct {
+	docker        DockerAPI
+	bbClient      *blackboard.Client
+	instanceName  string
+	health        HealthProbe
+	artefactLimit int
+
+	artefacts map[string]*blackboard.Artefact
+}
+
+// NewCollector creates a Collector. health may be nil, in which case agent health is reported as unknown.
+func NewCollector(docker DockerAPI, bbClient *blackboard.Client, instanceName string, health HealthProbe) *Collector {
+	return &Collector{
+		docker:        docker,
+		bbClient:      bbClient,
+		instanceName:  instanceName,
+		health:        health,
+		artefactLimit: DefaultArtefactLimit,
+		artefacts:     make(map[string]*blackboard.Artefact),
+	}
+}
+
+// Collect takes a fresh snapshot.
+func (c *Collector) Collect(ctx context.Context) (*Snapshot, error) {
+	snap := &Snapshot{Instance: c.instanceName, TakenAt: time.Now()}
+
+	if err := c.collectContainers(ctx, snap); err != nil {
+		return nil, err
+	}
+	if err := c.collectArtefacts(ctx, snap); err != nil {
+		return nil, err
+	}
+	if err := c.collectClaims(ctx, snap); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// collectContainers lists agent and worker containers and probes agent health in parallel
+func (c *Collector) collectContainers(ctx context.Context, snap *Snapshot) error {
+	args := filters.NewArgs()
+	args.Add("label", fmt.Sprintf("%s=%s", dockerpkg.LabelInstanceName, c.instanceName))
+	containers, err := c.docker.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: args})
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	workersByRole := make(map[string]int)
+	for _, ctr := range containers {
+		switch ctr.Labels[dockerpkg.LabelComponent] {
+		case "worker":
+			if ctr.State == "running" {
+				workersByRole[ctr.Labels[dockerpkg.LabelAgentRole]]++
+				snap.Workers++
+			}
+		case "agent":
+			row := AgentRow{
+				Role:        ctr.Labels[dockerpkg.LabelAgentRole],
+				ContainerID: ctr.ID,
+				State:       ctr.State,
+				Health:      "unknown",
+			}
+			if len(ctr.Names) > 0 {
+				row.ContainerName = strings.TrimPrefix(ctr.Names[0], "/")
+			}
+			if row.Role == "" {
+				row.Role = ctr.Labels[dockerpkg.LabelAgentName]
+			}
+			snap.Agents = append(snap.Agents, row)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := range snap.Agents {
+		agent := &snap.Agents[i]
+		agent.Workers = workersByRole[agent.Role]
+		if agent.State != "running" || c.health == nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, healthTimeout)
+			defer cancel()
+			resp, err := c.health(probeCtx, agent.ContainerID)
+			if err != nil {
+				agent.Health = "unhealthy"
+				agent.HealthError = err.Error()
+				return
+			}
+			agent.Health = resp.Status
+			agent.HealthError = resp.Error
+			agent.Concurrency = resp.Concurrency
+			agent.InFlight = len(resp.InFlightClaims)
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(snap.Agents, func(i, j int) bool { return snap.Agents[i].Role < snap.Agents[j].Role })
+	return nil
+}
+
+// collectArtefacts fetches artefacts not yet cached and keeps the newest for the feed
+func (c *Collector) collectArtefacts(ctx context.Context, snap *Snapshot) error {
+	ids, err := c.bbClient.ScanArtefacts(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to scan artefacts: %w", err)
+	}
+
+	for _, id := range ids {
+		if _, ok := c.artefacts[id]; ok {
+			continue
+		}
+		artefact, err := c.bbClient.GetArtefact(ctx, id)
+		if err != nil {
+			continue // Skip malformed artefacts, as hoard does
+		}
+		c.artefacts[id] = artefact
+	}
+
+	feed := make([]*blackboard.Artefact, 0, len(c.artefacts))
+	for _, artefact := range c.artefacts {
+		feed = append(feed, artefact)
+	}
+	sort.Slice(feed, func(i, j int) bool { return feed[i].CreatedAtMs > feed[j].CreatedAtMs })
+
+	if len(feed) > c.artefactLimit {
+		feed = feed[:c.artefactLimit]
+	}
+	snap.Artefacts = feed
+	return nil
+}
+
+// collectClaims loads active claims, splitting off those paused in a grant queue
+func (c *Collector) collectClaims(ctx context.Context, snap *Snapshot) error {
+	claims, err := c.bbClient.GetClaimsByStatus(ctx, activeClaimStatuses)
+	if err != nil {
+		return fmt.Errorf("failed to load claims: %w", err)
+	}
+
+	for _, claim := range claims {
+		row := ClaimRow{
+			Claim: claim,
+			Phase: claimPhase(claim.Status),
+		}
+		if artefact := c.artefactFor(ctx, claim.ArtefactID); artefact != nil {
+			row.ArtefactType = artefact.Type
+		}
+
+		if claim.GrantQueue != nil {
+			row.Agents = []string{claim.GrantQueue.AgentName}
+			row.Elapsed = elapsedSince(snap.TakenAt, claim.GrantQueue.PausedAtMs)
+			snap.Queued = append(snap.Queued, row)
+			continue
+		}
+
+		if claim.PhaseState != nil {
+			row.Agents = claim.PhaseState.GrantedAgents
+			row.Elapsed = elapsedSince(snap.TakenAt, claim.PhaseState.StartTimeMs)
+		}
+		if len(row.Agents) == 0 && claim.GrantedExclusiveAgent != "" {
+			row.Agents = []string{claim.GrantedExclusiveAgent}
+		}
+		snap.Claims = append(snap.Claims, row)
+	}
+
+	// Longest-running first surfaces stuck claims; the queue is shown in grant (FIFO) order
+	sort.SliceStable(snap.Claims, func(i, j int) bool { return snap.Claims[i].Elapsed > snap.Claims[j].Elapsed })
+	sort.SliceStable(snap.Queued, func(i, j int) bool {
+		return snap.Queued[i].Claim.GrantQueue.PausedAtMs < snap.Queued[j].Claim.GrantQueue.PausedAtMs
+	})
+	return nil
+}
+
+// artefactFor returns a claim's artefact from the cache, fetching it if needed
+func (c *Collector) artefactFor(ctx context.Context, artefactID string) *blackboard.Artefact {
+	if artefact, ok := c.artefacts[artefactID]; ok {
+		return artefact
+	}
+	artefact, err := c.bbClient.GetArtefact(ctx, artefactID)
+	if err != nil {
+		return nil
+	}
+	c.artefacts[artefactID] = artefact
+	return artefact
+}
+
+// claimPhase maps a claim status to the phase name shown on the dashboard
+func claimPhase(status blackboard.ClaimStatus) string {
+	switch status {
+	case blackboard.ClaimStatusPendingReview:
+		return "review"
+	case blackboard.ClaimStatusPendingParallel:
+		return "parallel"
+	case blackboard.ClaimStatusPendingExclusive:
+		return "exclusive"
+	case blackboard.ClaimStatusPendingAssignment:
+		return "assignment"
+	case blackboard.ClaimStatusAwaitingChild:
+		return "child"
+	default:
+		return string(status)
+	}
+}
+
+// elapsedSince returns the time from a millisecond timestamp to now (zero if unset)
+func elapsedSince(now time.Time, startMs int64) time.Duration {
+	if startMs <= 0 {
+		return 0
+	}
+	elapsed := now.Sub(time.UnixMilli(startMs))
+	if elapsed < 0 {
+		return 0
+	}
+	return elapsed
+}
@@ -0,0 +1,179 @@
+package top
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/docker/docker/api/types"
+	dockerpkg "github.com/dyluth/holt/internal/docker"
+	"github.com/dyluth/holt/internal/pup"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDocker struct {
+	containers []types.Container
+}
+
+func (f *fakeDocker) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	return f.containers, nil
+}
+
+func container(id, component, role, state string) types.Container {
+	return types.Container{
+		ID:    id,
+		Names: []string{"/holt-test-" + id},
+		State: state,
+		Labels: map[string]string{
+			dockerpkg.LabelInstanceName: "test",
+			dockerpkg.LabelComponent:    component,
+			dockerpkg.LabelAgentRole:    role,
+		},
+	}
+}
+
+func setupBlackboard(t *testing.T) *blackboard.Client {
+	mr := miniredis.RunT(t)
+	bbClient, err := blackboard.NewClient(&redis.Options{Addr: mr.Addr()}, "test")
+	require.NoError(t, err)
+	t.Cleanup(func() { bbClient.Close() })
+	return bbClient
+}
+
+func createArtefact(t *testing.T, bbClient *blackboard.Client, artefactType string, createdAt time.Time) *blackboard.Artefact {
+	artefact := &blackboard.Artefact{
+		ID:              uuid.New().String(),
+		LogicalID:       uuid.New().String(),
+		Version:         1,
+		StructuralType:  blackboard.StructuralTypeStandard,
+		Type:            artefactType,
+		Payload:         "payload",
+		SourceArtefacts: []string{},
+		ProducedByRole:  "user",
+		CreatedAtMs:     createdAt.UnixMilli(),
+	}
+	require.NoError(t, bbClient.CreateArtefact(context.Background(), artefact))
+	return artefact
+}
+
+func TestCollector_Collect(t *testing.T) {
+	ctx := context.Background()
+	bbClient := setupBlackboard(t)
+	now := time.Now()
+
+	goal := createArtefact(t, bbClient, "GoalDefined", now.Add(-3*time.Minute))
+	design := createArtefact(t, bbClient, "DesignSpec", now.Add(-1*time.Minute))
+	code := createArtefact(t, bbClient, "CodeCommit", now.Add(-30*time.Second))
+
+	// Exclusive claim in its phase for two minutes
+	active := &blackboard.Claim{
+		ID:                    uuid.New().String(),
+		ArtefactID:            goal.ID,
+		Status:                blackboard.ClaimStatusPendingExclusive,
+		GrantedExclusiveAgent: "coder",
+		PhaseState: &blackboard.PhaseState{
+			Current:       "exclusive",
+			GrantedAgents: []string{"coder"},
+			StartTimeMs:   now.Add(-2 * time.Minute).UnixMilli(),
+		},
+	}
+	// Claim paused waiting for a worker slot
+	queued := &blackboard.Claim{
+		ID:         uuid.New().String(),
+		ArtefactID: design.ID,
+		Status:     blackboard.ClaimStatusPendingExclusive,
+		GrantQueue: &blackboard.GrantQueue{AgentName: "coder", PausedAtMs: now.Add(-45 * time.Second).UnixMilli()},
+	}
+	// Finished claims are not shown
+	done := &blackboard.Claim{
+		ID:         uuid.New().String(),
+		ArtefactID: code.ID,
+		Status:     blackboard.ClaimStatusComplete,
+	}
+	for _, claim := range []*blackboard.Claim{active, queued, done} {
+		require.NoError(t, bbClient.CreateClaim(ctx, claim))
+	}
+
+	docker := &fakeDocker{containers: []types.Container{
+		container("coder", "agent", "coder", "running"),
+		container("tester", "agent", "tester", "running"),
+		container("reviewer", "agent", "reviewer", "exited"),
+		container("w1", "worker", "coder", "running"),
+		container("w2", "worker", "coder", "exited"),
+		container("redis", "redis", "", "running"),
+	}}
+	health := func(ctx context.Context, containerID string) (*pup.HealthResponse, error) {
+		if containerID == "tester" {
+			return nil, errors.New("connection refused")
+		}
+		return &pup.HealthResponse{Status: "healthy", Concurrency: 2, InFlightClaims: []pup.InFlightClaim{{ClaimID: active.ID}}}, nil
+	}
+
+	collector := NewCollector(docker, bbClient, "test", health)
+	snap, err := collector.Collect(ctx)
+	require.NoError(t, err)
+
+	t.Run("agents with health and workers", func(t *testing.T) {
+		require.Len(t, snap.Agents, 3)
+		coder, reviewer, tester := snap.Agents[0], snap.Agents[1], snap.Agents[2]
+
+		assert.Equal(t, "coder", coder.Role)
+		assert.Equal(t, "healthy", coder.Health)
+		assert.Equal(t, 2, coder.Concurrency)
+		assert.Equal(t, 1, coder.InFlight)
+		assert.Equal(t, 1, coder.Workers, "only running workers are counted")
+
+		assert.Equal(t, "reviewer", reviewer.Role)
+		assert.Equal(t, "unknown", reviewer.Health, "stopped agents are not probed")
+
+		assert.Equal(t, "unhealthy", tester.Health)
+		assert.Equal(t, "connection refused", tester.HealthError)
+
+		assert.Equal(t, 1, snap.Workers)
+	})
+
+	t.Run("claims by phase and grant queue", func(t *testing.T) {
+		require.Len(t, snap.Claims, 1)
+		assert.Equal(t, active.ID, snap.Claims[0].Claim.ID)
+		assert.Equal(t, "exclusive", snap.Claims[0].Phase)
+		assert.Equal(t, "GoalDefined", snap.Claims[0].ArtefactType)
+		assert.Equal(t, []string{"coder"}, snap.Claims[0].Agents)
+		assert.InDelta(t, (2 * time.Minute).Seconds(), snap.Claims[0].Elapsed.Seconds(), 5)
+
+		require.Len(t, snap.Queued, 1)
+		assert.Equal(t, queued.ID, snap.Queued[0].Claim.ID)
+		assert.Equal(t, []string{"coder"}, snap.Queued[0].Agents)
+		assert.InDelta(t, 45, snap.Queued[0].Elapsed.Seconds(), 5)
+	})
+
+	t.Run("artefact feed newest first", func(t *testing.T) {
+		require.Len(t, snap.Artefacts, 3)
+		assert.Equal(t, code.ID, snap.Artefacts[0].ID)
+		assert.Equal(t, goal.ID, snap.Artefacts[2].ID)
+	})
+
+	t.Run("feed picks up new artefacts and respects the limit", func(t *testing.T) {
+		collector.artefactLimit = 2
+		latest := createArtefact(t, bbClient, "TestResult", now)
+
+		snap, err := collector.Collect(ctx)
+		require.NoError(t, err)
+		require.Len(t, snap.Artefacts, 2)
+		assert.Equal(t, latest.ID, snap.Artefacts[0].ID)
+		assert.Equal(t, code.ID, snap.Artefacts[1].ID)
+	})
+}
+
+func TestClaimPhase(t *testing.T) {
+	assert.Equal(t, "review", claimPhase(blackboard.ClaimStatusPendingReview))
+	assert.Equal(t, "parallel", claimPhase(blackboard.ClaimStatusPendingParallel))
+	assert.Equal(t, "exclusive", claimPhase(blackboard.ClaimStatusPendingExclusive))
+	assert.Equal(t, "assignment", claimPhase(blackboard.ClaimStatusPendingAssignment))
+	assert.Equal(t, "child", claimPhase(blackboard.ClaimStatusAwaitingChild))
+}
@@ -0,0 +1,202 @@
+package top
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dyluth/holt/internal/hoard"
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+// DefaultInterval is how often the dashboard refreshes.
+const DefaultInterval = 2 * time.Second
+
+// ErrNotTerminal is returned by Run when stdin or stdout is not an interactive terminal.
+var ErrNotTerminal = errors.New("holt top requires an interactive terminal")
+
+// Run shows the dashboard on the terminal until the user quits or ctx is cancelled.
+// The dashboard refreshes every interval and immediately when an artefact is created.
+func Run(ctx context.Context, collector *Collector, bbClient *blackboard.Client, interval time.Duration) error {
+	in, out := os.Stdin, os.Stdout
+	if !isTerminal(int(in.Fd())) || !isTerminal(int(out.Fd())) {
+		return ErrNotTerminal
+	}
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	term, err := makeRaw(int(in.Fd()))
+	if err != nil {
+		return fmt.Errorf("failed to enter raw terminal mode: %w", err)
+	}
+	defer term.restore()
+
+	// Alternate screen with hidden cursor, restored on exit
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	keys := make(chan []byte)
+	go readInput(in, keys)
+
+	resize := make(chan os.Signal, 1)
+	signal.Notify(resize, syscall.SIGWINCH)
+	defer signal.Stop(resize)
+
+	// New artefacts trigger an immediate refresh; the ticker covers everything else
+	var artefactEvents <-chan *blackboard.Artefact
+	if sub, err := bbClient.SubscribeArtefactEvents(ctx); err == nil {
+		defer sub.Close()
+		artefactEvents = sub.Events()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	type result struct {
+		snap *Snapshot
+		err  error
+	}
+	results := make(chan result, 1)
+	refreshing := false
+	refresh := func() {
+		if refreshing {
+			return
+		}
+		refreshing = true
+		go func() {
+			snap, err := collector.Collect(ctx)
+			results <- result{snap: snap, err: err}
+		}()
+	}
+
+	m := &model{}
+	draw := func() {
+		width, height := term.size()
+		fmt.Fprint(out, paint(render(m, width, height)))
+	}
+
+	refresh()
+	draw()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case r := <-results:
+			refreshing = false
+			if r.err != nil {
+				m.err = r.err
+			} else {
+				m.setSnapshot(r.snap)
+			}
+		case <-ticker.C:
+			refresh()
+			continue
+		case _, ok := <-artefactEvents:
+			if !ok {
+				// Subscription dropped: stop selecting on the closed channel, the ticker takes over
+				artefactEvents = nil
+				continue
+			}
+			refresh()
+			continue
+		case <-resize:
+		case buf, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			_, height := term.size()
+			for _, k := range parseKeys(buf) {
+				cmd := m.handleKey(k, height-3)
+				switch {
+				case cmd.quit:
+					return nil
+				case cmd.refresh:
+					refresh()
+				case cmd.openArtefact != "":
+					m.detail = artefactDetail(ctx, bbClient, cmd.openArtefact)
+				case cmd.openClaim != "":
+					m.detail = claimDetail(ctx, bbClient, cmd.openClaim)
+				}
+			}
+		}
+		draw()
+	}
+}
+
+// readInput forwards raw terminal input until it cannot be read
+func readInput(in *os.File, keys chan<- []byte) {
+	defer close(keys)
+	buf := make([]byte, 64)
+	for {
+		n, err := in.Read(buf)
+		if err != nil {
+			return
+		}
+		chunk := make([]byte, n)
+		copy(chunk, buf[:n])
+		keys <- chunk
+	}
+}
+
+// artefactDetail shows an artefact as `holt hoard <id>` would
+func artefactDetail(ctx context.Context, bbClient *blackboard.Client, artefactID string) *detail {
+	d := &detail{title: fmt.Sprintf("Artefact %s", artefactID)}
+
+	var buf bytes.Buffer
+	if err := hoard.GetArtefact(ctx, bbClient, artefactID, &buf); err != nil {
+		d.lines = []string{fmt.Sprintf("Failed to load artefact: %v", err)}
+		return d
+	}
+	d.lines = strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	return d
+}
+
+// claimDetail shows a claim with its bids and the artefact it is for
+func claimDetail(ctx context.Context, bbClient *blackboard.Client, claimID string) *detail {
+	d := &detail{title: fmt.Sprintf("Claim %s", claimID)}
+
+	claim, err := bbClient.GetClaim(ctx, claimID)
+	if err != nil {
+		d.lines = []string{fmt.Sprintf("Failed to load claim: %v", err)}
+		return d
+	}
+
+	if artefact, err := bbClient.GetArtefact(ctx, claim.ArtefactID); err == nil {
+		d.lines = append(d.lines,
+			fmt.Sprintf("Artefact: %s (%s, %s, by %s)", artefact.ID, artefact.Type, artefact.StructuralType, artefact.ProducedByRole),
+			"")
+	}
+
+	if bids, err := bbClient.GetAllBids(ctx, claimID); err == nil && len(bids) > 0 {
+		agents := make([]string, 0, len(bids))
+		for agent := range bids {
+			agents = append(agents, agent)
+		}
+		sort.Strings(agents)
+		d.lines = append(d.lines, "Bids:")
+		for _, agent := range agents {
+			d.lines = append(d.lines, fmt.Sprintf("  %-20s %s", agent, bids[agent]))
+		}
+		d.lines = append(d.lines, "")
+	}
+
+	data, err := json.MarshalIndent(claim, "", "  ")
+	if err != nil {
+		d.lines = append(d.lines, fmt.Sprintf("Failed to format claim: %v", err))
+		return d
+	}
+	d.lines = append(d.lines, strings.Split(string(data), "\n")...)
+	return d
+}
@@ -52,6 +52,62 @@ func TestNewWorkflowEvents(t *testing.T) {
 			},
 			expected: "🔄 Rework Assigned: to=Writer for claim ghi78901-1234-1234-1234-123456789012 (iteration 2)",
 		},
+		{
+			name: "workflow_bid_rejected",
+			event: &blackboard.WorkflowEvent{
+				Event: "workflow_bid_rejected",
+				Data: map[string]interface{}{
+					"claim_id":      "abc123",
+					"agent_name":    "Coder",
+					"bid_type":      "exclusive",
+					"artefact_type": "GoalDefined",
+					"reason":        "no workflow stage lets Coder bid 'exclusive' on GoalDefined produced by user",
+				},
+			},
+			expected: "🚧 Bid rejected by workflow: agent=Coder, claim=abc123: no workflow stage lets Coder bid 'exclusive' on GoalDefined produced by user",
+		},
+		{
+			name: "when_evaluation_failed",
+			event: &blackboard.WorkflowEvent{
+				Event: "when_evaluation_failed",
+				Data: map[string]interface{}{
+					"claim_id":      "abc123",
+					"agent_name":    "SecurityReviewer",
+					"artefact_type": "ChangeRequest",
+					"expression":    "payload.risk > 7",
+					"error":         "no such key: risk",
+				},
+			},
+			expected: "⚠️  when expression failed: agent=SecurityReviewer, claim=abc123: no such key: risk",
+		},
+		{
+			name: "child_workflow_started",
+			event: &blackboard.WorkflowEvent{
+				Event: "child_workflow_started",
+				Data: map[string]interface{}{
+					"claim_id":      "abc123",
+					"child_goal_id": "def456",
+					"agent_name":    "Planner",
+					"goal":          "Compare OAuth2 libraries",
+				},
+			},
+			expected: `🪆 Child workflow started: agent=Planner, claim=abc123, goal="Compare OAuth2 libraries"`,
+		},
+		{
+			name: "child_workflow_completed",
+			event: &blackboard.WorkflowEvent{
+				Event: "child_workflow_completed",
+				Data: map[string]interface{}{
+					"claim_id":        "abc123",
+					"child_goal_id":   "def456",
+					"agent_name":      "Planner",
+					"result_id":       "ghi789",
+					"result_type":     "Comparison",
+					"structural_type": "Terminal",
+				},
+			},
+			expected: "🪆 Child workflow finished (Terminal): resuming claim=abc123 for agent=Planner, result=ghi789",
+		},
 		{
 			name: "artefact_reworked",
 			event: &blackboard.WorkflowEvent{
@@ -82,6 +138,56 @@ func TestNewWorkflowEvents(t *testing.T) {
 			},
 			expected: "🔄 Artefact Reworked (v3): by=Writer, type=RecipeYAML, id=jkl34567-1234-1234-1234-123456789012",
 		},
+		{
+			name: "claim_retried",
+			event: &blackboard.WorkflowEvent{
+				Event: "claim_retried",
+				Data: map[string]interface{}{
+					"request_id": "req-1",
+					"claim_id":   "new123",
+					"retry_of":   "old456",
+					"agent_role": "Coder",
+				},
+			},
+			expected: "🔁 Claim retried: claim=new123 retries old456 (to=Coder)",
+		},
+		{
+			name: "claim_skipped",
+			event: &blackboard.WorkflowEvent{
+				Event: "claim_skipped",
+				Data: map[string]interface{}{
+					"request_id":  "req-2",
+					"claim_id":    "old456",
+					"artefact_id": "art789",
+				},
+			},
+			expected: "⏭️  Claim skipped: claim=old456 resolved by user with artefact art789",
+		},
+		{
+			name: "tool_progress",
+			event: &blackboard.WorkflowEvent{
+				Event: "tool_progress",
+				Data: map[string]interface{}{
+					"claim_id":   "claim-123",
+					"agent_name": "Coder",
+					"message":    "Running tests",
+				},
+			},
+			expected: "💬 Progress: agent=Coder, claim=claim-123: Running tests",
+		},
+		{
+			name: "tool_progress with percent",
+			event: &blackboard.WorkflowEvent{
+				Event: "tool_progress",
+				Data: map[string]interface{}{
+					"claim_id":   "claim-123",
+					"agent_name": "Coder",
+					"message":    "Compiling",
+					"percent":    float64(40),
+				},
+			},
+			expected: "💬 Progress: agent=Coder, claim=claim-123: Compiling (40%)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -552,7 +552,9 @@ func streamWithSubscriptions(ctx context.Context, client *blackboard.Client, for
 			}
 
 			// Check for Terminal artefact if exitOnCompletion is enabled
-			if exitOnCompletion && artefact.StructuralType == blackboard.StructuralTypeTerminal {
+			// A child workflow's Terminal resumes its parent rather than ending the run
+			if exitOnCompletion && artefact.StructuralType == blackboard.StructuralTypeTerminal &&
+				!isChildWorkflowArtefact(ctx, client, artefact) {
 				return nil // Clean exit
 			}
 
@@ -586,6 +588,37 @@ func streamWithSubscriptions(ctx context.Context, client *blackboard.Client, for
 	}
 }
 
+// maxChildWorkflowAncestry bounds the ancestry walk in isChildWorkflowArtefact
+const maxChildWorkflowAncestry = 1000
+
+// isChildWorkflowArtefact reports whether the artefact descends from a child workflow's
+// goal (a root artefact with a parent claim). Lookup failures count as not a child.
+func isChildWorkflowArtefact(ctx context.Context, client *blackboard.Client, artefact *blackboard.Artefact) bool {
+	visited := make(map[string]bool)
+	queue := []*blackboard.Artefact{artefact}
+
+	for len(queue) > 0 && len(visited) < maxChildWorkflowAncestry {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current.ParentClaimID != "" {
+			return true
+		}
+
+		for _, sourceID := range current.SourceArtefacts {
+			if visited[sourceID] {
+				continue
+			}
+			visited[sourceID] = true
+			if source, err := client.GetArtefact(ctx, sourceID); err == nil {
+				queue = append(queue, source)
+			}
+		}
+	}
+
+	return false
+}
+
 // reconnectWithRetry attempts to reconnect to Redis with retries
 func reconnectWithRetry(ctx context.Context, client *blackboard.Client, retryInterval time.Duration) error {
 	ticker := time.NewTicker(retryInterval)
@@ -728,6 +761,77 @@ func (f *defaultFormatter) FormatWorkflow(event *blackboard.WorkflowEvent, times
 			timestamp, newVersion, producedByRole, artefactType, newArtefactID)
 		return err
 
+	case "workflow_bid_rejected":
+		agentName, _ := event.Data["agent_name"].(string)
+		claimID, _ := event.Data["claim_id"].(string)
+		reason, _ := event.Data["reason"].(string)
+
+		_, err := fmt.Fprintf(f.writer, "[%s] 🚧 Bid rejected by workflow: agent=%s, claim=%s: %s\n",
+			timestamp, agentName, claimID, reason)
+		return err
+
+	case "child_workflow_started":
+		agentName, _ := event.Data["agent_name"].(string)
+		claimID, _ := event.Data["claim_id"].(string)
+		goal, _ := event.Data["goal"].(string)
+
+		_, err := fmt.Fprintf(f.writer, "[%s] 🪆 Child workflow started: agent=%s, claim=%s, goal=%q\n",
+			timestamp, agentName, claimID, goal)
+		return err
+
+	case "child_workflow_completed":
+		agentName, _ := event.Data["agent_name"].(string)
+		claimID, _ := event.Data["claim_id"].(string)
+		resultID, _ := event.Data["result_id"].(string)
+		structuralType, _ := event.Data["structural_type"].(string)
+
+		_, err := fmt.Fprintf(f.writer, "[%s] 🪆 Child workflow finished (%s): resuming claim=%s for agent=%s, result=%s\n",
+			timestamp, structuralType, claimID, agentName, resultID)
+		return err
+
+	case "claim_retried":
+		claimID, _ := event.Data["claim_id"].(string)
+		retryOf, _ := event.Data["retry_of"].(string)
+		agentRole, _ := event.Data["agent_role"].(string)
+		if agentRole == "" {
+			agentRole = "bidding"
+		}
+
+		_, err := fmt.Fprintf(f.writer, "[%s] 🔁 Claim retried: claim=%s retries %s (to=%s)\n",
+			timestamp, claimID, retryOf, agentRole)
+		return err
+
+	case "claim_skipped":
+		claimID, _ := event.Data["claim_id"].(string)
+		artefactID, _ := event.Data["artefact_id"].(string)
+
+		_, err := fmt.Fprintf(f.writer, "[%s] ⏭️  Claim skipped: claim=%s resolved by user with artefact %s\n",
+			timestamp, claimID, artefactID)
+		return err
+
+	case "when_evaluation_failed":
+		agentName, _ := event.Data["agent_name"].(string)
+		claimID, _ := event.Data["claim_id"].(string)
+		errMsg, _ := event.Data["error"].(string)
+
+		_, err := fmt.Fprintf(f.writer, "[%s] ⚠️  when expression failed: agent=%s, claim=%s: %s\n",
+			timestamp, agentName, claimID, errMsg)
+		return err
+
+	case "tool_progress":
+		agentName, _ := event.Data["agent_name"].(string)
+		claimID, _ := event.Data["claim_id"].(string)
+		message, _ := event.Data["message"].(string)
+
+		// Percent is optional; JSON numbers unmarshal as float64
+		if percent, ok := event.Data["percent"].(float64); ok {
+			message = fmt.Sprintf("%s (%.0f%%)", message, percent)
+		}
+
+		_, err := fmt.Fprintf(f.writer, "[%s] 💬 Progress: agent=%s, claim=%s: %s\n",
+			timestamp, agentName, claimID, message)
+		return err
+
 	default:
 		_, err := fmt.Fprintf(f.writer, "[%s] ❓ Unknown event: %s\n", timestamp, event.Event)
 		return err
@@ -0,0 +1,173 @@
+// Package agentsdktest runs agentsdk handlers against fixture artefacts, the way the
+// pup would, so agents can be unit tested without Redis, Docker or an orchestrator.
+//
+//	func TestCoder(t *testing.T) {
+//		goal := agentsdktest.NewArtefact("GoalDefined", "hello.txt")
+//		h := agentsdktest.New(t, goal)
+//
+//		output := h.Run(coder.Handle)
+//		assert.Equal(t, "CodeCommit", output.ArtefactType)
+//		assert.True(t, h.Workspace().Exists("hello.txt"))
+//	}
+package agentsdktest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dyluth/holt/pkg/agentsdk"
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/google/uuid"
+)
+
+// Harness builds a ToolInput from fixtures and runs handlers against it.
+type Harness struct {
+	t testing.TB
+
+	// ClaimType is sent as claim_type (default "exclusive")
+	ClaimType string
+
+	// Target is the claim's target artefact
+	Target *blackboard.Artefact
+
+	// ContextChain is delivered as context_chain, oldest first
+	ContextChain []*blackboard.Artefact
+
+	// WorkDir is the workspace handed to the handler (default: a fresh temp dir)
+	WorkDir string
+}
+
+// New returns a harness for a claim on target with an empty context chain.
+func New(t testing.TB, target *blackboard.Artefact) *Harness {
+	t.Helper()
+	return &Harness{
+		t:         t,
+		ClaimType: "exclusive",
+		Target:    target,
+		WorkDir:   t.TempDir(),
+	}
+}
+
+// WithContext appends artefacts to the context chain (oldest first) and returns the harness.
+func (h *Harness) WithContext(artefacts ...*blackboard.Artefact) *Harness {
+	h.ContextChain = append(h.ContextChain, artefacts...)
+	return h
+}
+
+// Workspace returns the workspace the handler runs in, for seeding files and checking results.
+func (h *Harness) Workspace() agentsdk.Workspace {
+	return agentsdk.Workspace{Dir: h.WorkDir}
+}
+
+// Input returns the ToolInput the handler will receive, after a JSON round trip
+// so handlers see exactly what the pup would send.
+func (h *Harness) Input() *agentsdk.Input {
+	h.t.Helper()
+
+	chain := h.ContextChain
+	if chain == nil {
+		chain = []*blackboard.Artefact{}
+	}
+	data, err := json.Marshal(agentsdk.Input{
+		ClaimType:      h.ClaimType,
+		TargetArtefact: h.Target,
+		ContextChain:   chain,
+	})
+	if err != nil {
+		h.t.Fatalf("failed to marshal tool input: %v", err)
+	}
+
+	var in agentsdk.Input
+	if err := json.Unmarshal(data, &in); err != nil {
+		h.t.Fatalf("failed to unmarshal tool input: %v", err)
+	}
+	in.WorkDir = h.WorkDir
+	return &in
+}
+
+// Run runs handler and returns its validated output, failing the test on a handler
+// error or an output the pup would reject.
+func (h *Harness) Run(handler agentsdk.Handler) *agentsdk.Output {
+	h.t.Helper()
+
+	output, err := h.RunErr(handler)
+	if err != nil {
+		h.t.Fatalf("handler failed: %v", err)
+	}
+	return output
+}
+
+// RunErr runs handler and returns its output or error. Like the pup, a nil or
+// invalid output is an error, and the output is round-tripped through JSON.
+func (h *Harness) RunErr(handler agentsdk.Handler) (*agentsdk.Output, error) {
+	h.t.Helper()
+
+	output, err := handler(context.Background(), h.Input())
+	if err != nil {
+		return nil, err
+	}
+	if output == nil {
+		return nil, fmt.Errorf("handler returned no output")
+	}
+	if err := output.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid tool output: %w", err)
+	}
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tool output: %w", err)
+	}
+	var decoded agentsdk.Output
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tool output: %w", err)
+	}
+	return &decoded, nil
+}
+
+// NewArtefact returns a version 1 Standard artefact produced by "user", with fresh IDs.
+func NewArtefact(artefactType, payload string) *blackboard.Artefact {
+	id := uuid.New().String()
+	return &blackboard.Artefact{
+		ID:              id,
+		LogicalID:       id,
+		Version:         1,
+		StructuralType:  blackboard.StructuralTypeStandard,
+		Type:            artefactType,
+		Payload:         payload,
+		SourceArtefacts: []string{},
+		ProducedByRole:  "user",
+		CreatedAtMs:     time.Now().UnixMilli(),
+	}
+}
+
+// DerivedArtefact returns a new artefact produced by role from the given sources.
+func DerivedArtefact(artefactType, payload, role string, sources ...*blackboard.Artefact) *blackboard.Artefact {
+	artefact := NewArtefact(artefactType, payload)
+	artefact.ProducedByRole = role
+	for _, source := range sources {
+		artefact.SourceArtefacts = append(artefact.SourceArtefacts, source.ID)
+		if source.CreatedAtMs >= artefact.CreatedAtMs {
+			artefact.CreatedAtMs = source.CreatedAtMs + 1
+		}
+	}
+	return artefact
+}
+
+// LoadArtefact reads an artefact fixture from a JSON file (as printed by `holt hoard <id>`).
+func LoadArtefact(t testing.TB, path string) *blackboard.Artefact {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read artefact fixture: %v", err)
+	}
+	var artefact blackboard.Artefact
+	if err := json.Unmarshal(data, &artefact); err != nil {
+		t.Fatalf("failed to parse artefact fixture %s: %v", path, err)
+	}
+	return &artefact
+}
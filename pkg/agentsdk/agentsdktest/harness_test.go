@@ -0,0 +1,67 @@
+package agentsdktest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dyluth/holt/pkg/agentsdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHarness_Run(t *testing.T) {
+	goal := NewArtefact("GoalDefined", "hello.txt")
+	spec := DerivedArtefact("DesignSpec", "say hello", "designer", goal)
+	h := New(t, DerivedArtefact("Task", "write it", "planner", spec)).WithContext(goal, spec)
+
+	output := h.Run(func(ctx context.Context, in *agentsdk.Input) (*agentsdk.Output, error) {
+		assert.Equal(t, "exclusive", in.ClaimType)
+		require.Len(t, in.Ancestors(in.TargetArtefact), 2)
+
+		filename := in.Latest("GoalDefined").Payload
+		if err := in.Workspace().WriteFile(filename, []byte(in.Latest("DesignSpec").Payload)); err != nil {
+			return nil, err
+		}
+		return agentsdk.NewOutput("CodeCommit", "", "wrote "+filename), nil
+	})
+
+	assert.Equal(t, "CodeCommit", output.ArtefactType)
+	data, err := h.Workspace().ReadFile("hello.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "say hello", string(data))
+}
+
+func TestHarness_RunErr(t *testing.T) {
+	h := New(t, NewArtefact("GoalDefined", "x"))
+
+	_, err := h.RunErr(func(ctx context.Context, in *agentsdk.Input) (*agentsdk.Output, error) {
+		return nil, fmt.Errorf("boom")
+	})
+	assert.EqualError(t, err, "boom")
+
+	_, err = h.RunErr(func(ctx context.Context, in *agentsdk.Input) (*agentsdk.Output, error) {
+		return &agentsdk.Output{ArtefactType: "Result"}, nil
+	})
+	assert.ErrorContains(t, err, "invalid tool output")
+}
+
+func TestDerivedArtefact(t *testing.T) {
+	goal := NewArtefact("GoalDefined", "x")
+	derived := DerivedArtefact("Spec", "y", "designer", goal)
+
+	assert.Equal(t, []string{goal.ID}, derived.SourceArtefacts)
+	assert.Equal(t, "designer", derived.ProducedByRole)
+	assert.Greater(t, derived.CreatedAtMs, goal.CreatedAtMs)
+}
+
+func TestLoadArtefact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goal.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"id":"a1","type":"GoalDefined","payload":"hi","structural_type":"Standard"}`), 0644))
+
+	artefact := LoadArtefact(t, path)
+	assert.Equal(t, "a1", artefact.ID)
+	assert.Equal(t, "hi", artefact.Payload)
+}
@@ -0,0 +1,104 @@
+package agentsdk
+
+import (
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+// Artefacts returns the context chain followed by the target artefact, oldest first.
+func (in *Input) Artefacts() []*blackboard.Artefact {
+	all := make([]*blackboard.Artefact, 0, len(in.ContextChain)+1)
+	all = append(all, in.ContextChain...)
+	if in.TargetArtefact != nil {
+		all = append(all, in.TargetArtefact)
+	}
+	return all
+}
+
+// OfType returns the context artefacts of the given domain type, oldest first.
+// The target artefact is not included.
+func (in *Input) OfType(artefactType string) []*blackboard.Artefact {
+	var matches []*blackboard.Artefact
+	for _, artefact := range in.ContextChain {
+		if artefact.Type == artefactType {
+			matches = append(matches, artefact)
+		}
+	}
+	return matches
+}
+
+// Latest returns the most recent context artefact of the given domain type, or nil.
+// The target artefact is not included.
+func (in *Input) Latest(artefactType string) *blackboard.Artefact {
+	matches := in.OfType(artefactType)
+	if len(matches) == 0 {
+		return nil
+	}
+	return matches[len(matches)-1]
+}
+
+// OfStructuralType returns the context artefacts with the given structural type, oldest first.
+func (in *Input) OfStructuralType(structuralType blackboard.StructuralType) []*blackboard.Artefact {
+	var matches []*blackboard.Artefact
+	for _, artefact := range in.ContextChain {
+		if artefact.StructuralType == structuralType {
+			matches = append(matches, artefact)
+		}
+	}
+	return matches
+}
+
+// Feedback returns Review artefacts in the context chain. A non-empty result means
+// the target is being reworked after review rejection.
+func (in *Input) Feedback() []*blackboard.Artefact {
+	return in.OfStructuralType(blackboard.StructuralTypeReview)
+}
+
+// Find returns the artefact with the given ID from the target and context chain, or nil.
+func (in *Input) Find(id string) *blackboard.Artefact {
+	for _, artefact := range in.Artefacts() {
+		if artefact.ID == id {
+			return artefact
+		}
+	}
+	return nil
+}
+
+// Sources returns the direct source artefacts of artefact that are present in the input.
+// Sources outside the delivered context (e.g. beyond max_depth) are skipped.
+func (in *Input) Sources(artefact *blackboard.Artefact) []*blackboard.Artefact {
+	var sources []*blackboard.Artefact
+	for _, id := range artefact.SourceArtefacts {
+		if source := in.Find(id); source != nil {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+// Ancestors walks source_artefacts from artefact breadth-first and returns every
+// ancestor present in the input, nearest first. Each artefact is returned once.
+func (in *Input) Ancestors(artefact *blackboard.Artefact) []*blackboard.Artefact {
+	var ancestors []*blackboard.Artefact
+	seen := map[string]bool{artefact.ID: true}
+	queue := []*blackboard.Artefact{artefact}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, source := range in.Sources(current) {
+			if seen[source.ID] {
+				continue
+			}
+			seen[source.ID] = true
+			ancestors = append(ancestors, source)
+			queue = append(queue, source)
+		}
+	}
+
+	return ancestors
+}
+
+// Truncated reports whether context assembly left anything out of the context chain.
+func (in *Input) Truncated() bool {
+	return in.ContextTruncation != nil
+}
@@ -0,0 +1,58 @@
+package agentsdk
+
+import (
+	"testing"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testChain builds goal -> spec(v1) -> review -> spec(v2, target) style input
+func testChain() *Input {
+	goal := &blackboard.Artefact{ID: "goal", Type: "GoalDefined", StructuralType: blackboard.StructuralTypeStandard}
+	spec := &blackboard.Artefact{ID: "spec", Type: "DesignSpec", StructuralType: blackboard.StructuralTypeStandard, SourceArtefacts: []string{"goal"}}
+	review := &blackboard.Artefact{ID: "review", Type: "Review", StructuralType: blackboard.StructuralTypeReview, SourceArtefacts: []string{"spec"}}
+	spec2 := &blackboard.Artefact{ID: "spec2", Type: "DesignSpec", StructuralType: blackboard.StructuralTypeStandard, SourceArtefacts: []string{"spec", "review"}}
+	target := &blackboard.Artefact{ID: "code", Type: "CodeCommit", SourceArtefacts: []string{"spec2", "missing"}}
+
+	return &Input{
+		TargetArtefact: target,
+		ContextChain:   []*blackboard.Artefact{goal, spec, review, spec2},
+	}
+}
+
+func TestInput_ContextHelpers(t *testing.T) {
+	in := testChain()
+
+	assert.Len(t, in.Artefacts(), 5)
+	assert.Equal(t, "code", in.Artefacts()[4].ID)
+
+	specs := in.OfType("DesignSpec")
+	require.Len(t, specs, 2)
+	assert.Equal(t, "spec", specs[0].ID)
+	assert.Equal(t, "spec2", in.Latest("DesignSpec").ID)
+	assert.Nil(t, in.Latest("CodeCommit"), "target is not part of the context chain")
+
+	feedback := in.Feedback()
+	require.Len(t, feedback, 1)
+	assert.Equal(t, "review", feedback[0].ID)
+
+	assert.Equal(t, "goal", in.Find("goal").ID)
+	assert.Equal(t, "code", in.Find("code").ID)
+	assert.Nil(t, in.Find("missing"))
+
+	sources := in.Sources(in.TargetArtefact)
+	require.Len(t, sources, 1, "sources outside the input are skipped")
+	assert.Equal(t, "spec2", sources[0].ID)
+
+	var ancestorIDs []string
+	for _, ancestor := range in.Ancestors(in.TargetArtefact) {
+		ancestorIDs = append(ancestorIDs, ancestor.ID)
+	}
+	assert.Equal(t, []string{"spec2", "spec", "review", "goal"}, ancestorIDs)
+
+	assert.False(t, in.Truncated())
+	in.ContextTruncation = &ContextTruncation{DepthLimitReached: true}
+	assert.True(t, in.Truncated())
+}
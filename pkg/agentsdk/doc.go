@@ -0,0 +1,36 @@
+// Package agentsdk provides typed helpers for writing Holt agent tools in Go.
+//
+// # Overview
+//
+// Agent tools are driven by the pup through a JSON contract: a ToolInput describing
+// the granted claim (the target artefact plus its context chain), and a ToolOutput
+// describing the artefact(s) the tool produced. This package exposes that contract as
+// Go types so agents don't have to parse JSON by hand.
+//
+// # Writing an Agent
+//
+// An agent is a Handler. Main runs it with the subprocess protocol (ToolInput on stdin,
+// ToolOutput on stdout); ListenAndServe runs it as a long-running agent for protocol: http.
+//
+//	func main() {
+//		agentsdk.Main(func(ctx context.Context, in *agentsdk.Input) (*agentsdk.Output, error) {
+//			spec := in.Latest("DesignSpec")
+//			if spec == nil {
+//				return agentsdk.Question("Which design should I implement?"), nil
+//			}
+//
+//			ws := in.Workspace()
+//			if err := ws.WriteFile("main.go", render(spec.Payload)); err != nil {
+//				return nil, err
+//			}
+//			return agentsdk.NewOutput("CodeCommit", "", "Implemented design"), nil
+//		})
+//	}
+//
+// A Handler error becomes a Failure artefact (the tool exits non-zero, or the HTTP
+// agent responds with a 500). Use Failure to report an expected failure explicitly.
+//
+// # Testing
+//
+// Package agentsdktest runs a Handler against fixture artefacts the same way the pup would.
+package agentsdk
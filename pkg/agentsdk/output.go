@@ -0,0 +1,91 @@
+package agentsdk
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+// NewOutput returns a Standard output with the given type, payload and summary.
+func NewOutput(artefactType, payload, summary string) *Output {
+	return &Output{
+		ArtefactType:    artefactType,
+		ArtefactPayload: payload,
+		Summary:         summary,
+	}
+}
+
+// Terminal returns an output that completes the workflow.
+func Terminal(artefactType, payload, summary string) *Output {
+	output := NewOutput(artefactType, payload, summary)
+	output.StructuralType = string(blackboard.StructuralTypeTerminal)
+	return output
+}
+
+// Approve returns a Review output approving the target artefact.
+// The orchestrator treats an empty JSON object payload as approval.
+func Approve(summary string) *Output {
+	return &Output{
+		ArtefactType:    "Review",
+		ArtefactPayload: "{}",
+		Summary:         summary,
+		StructuralType:  string(blackboard.StructuralTypeReview),
+	}
+}
+
+// Reject returns a Review output carrying feedback. Feedback is marshalled to JSON
+// (strings become JSON strings), so any non-empty value is treated as a rejection
+// and delivered to the producing agent in its feedback context.
+func Reject(feedback interface{}, summary string) (*Output, error) {
+	payload, err := json.Marshal(feedback)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal review feedback: %w", err)
+	}
+	return &Output{
+		ArtefactType:    "Review",
+		ArtefactPayload: string(payload),
+		Summary:         summary,
+		StructuralType:  string(blackboard.StructuralTypeReview),
+	}, nil
+}
+
+// Question returns a Question output that escalates to a human.
+// The question text is the payload; `holt questions` shows it for answering.
+func Question(question string) *Output {
+	return &Output{
+		ArtefactType:    "Question",
+		ArtefactPayload: question,
+		Summary:         question,
+		StructuralType:  string(blackboard.StructuralTypeQuestion),
+	}
+}
+
+// Failure returns a Failure output, terminating the claim with the given reason.
+func Failure(reason string) *Output {
+	return &Output{
+		ArtefactType:    "Failure",
+		ArtefactPayload: reason,
+		Summary:         reason,
+		StructuralType:  string(blackboard.StructuralTypeFailure),
+	}
+}
+
+// Batch returns an output that fans out into several artefacts.
+func Batch(summary string, artefacts ...OutputArtefact) *Output {
+	return &Output{
+		Summary:   summary,
+		Artefacts: artefacts,
+	}
+}
+
+// RequestChildWorkflow returns an output that parks the claim while a child workflow runs
+// for goal, optionally restricted to the given agent roles. The agent is invoked again
+// for the same claim once the child ends, with the Terminal or Failure artefact that
+// ended it in Input.ChildWorkflowResult.
+func RequestChildWorkflow(goal, summary string, agents ...string) *Output {
+	return &Output{
+		Summary:       summary,
+		ChildWorkflow: &ChildWorkflow{Goal: goal, Agents: agents},
+	}
+}
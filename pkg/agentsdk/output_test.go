@@ -0,0 +1,46 @@
+package agentsdk
+
+import (
+	"testing"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputHelpers(t *testing.T) {
+	approve := Approve("looks good")
+	assert.Equal(t, "{}", approve.ArtefactPayload)
+	assert.Equal(t, string(blackboard.StructuralTypeReview), approve.StructuralType)
+	assert.NoError(t, approve.Validate())
+
+	reject, err := Reject(map[string]string{"issue": "missing tests"}, "needs work")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"issue":"missing tests"}`, reject.ArtefactPayload)
+	assert.Equal(t, string(blackboard.StructuralTypeReview), reject.StructuralType)
+
+	rejectText, err := Reject("add tests", "needs work")
+	require.NoError(t, err)
+	assert.Equal(t, `"add tests"`, rejectText.ArtefactPayload)
+
+	question := Question("Which database?")
+	assert.Equal(t, "Which database?", question.ArtefactPayload)
+	assert.Equal(t, string(blackboard.StructuralTypeQuestion), question.StructuralType)
+	assert.NoError(t, question.Validate())
+
+	failure := Failure("no network")
+	assert.Equal(t, string(blackboard.StructuralTypeFailure), failure.StructuralType)
+	assert.NoError(t, failure.Validate())
+
+	terminal := Terminal("Report", "all done", "finished")
+	assert.Equal(t, string(blackboard.StructuralTypeTerminal), terminal.StructuralType)
+
+	batch := Batch("split", OutputArtefact{Ref: "plan", ArtefactType: "Plan"}, OutputArtefact{ArtefactType: "Task", SourceRefs: []string{"plan"}})
+	assert.NoError(t, batch.Validate())
+	assert.Len(t, batch.Artefacts, 2)
+
+	child := RequestChildWorkflow("Compare OAuth2 libraries", "need research", "Researcher")
+	assert.NoError(t, child.Validate())
+	assert.Equal(t, []string{"Researcher"}, child.ChildWorkflow.Agents)
+	assert.Empty(t, child.ArtefactType)
+}
@@ -0,0 +1,169 @@
+package agentsdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+)
+
+// Headers sent by the pup with each protocol: http request
+const (
+	HeaderClaimID = "X-Holt-Claim-Id"
+	HeaderWorkDir = "X-Holt-Work-Dir"
+)
+
+// Default endpoints the pup uses for protocol: http (see the agent's http settings)
+const (
+	DefaultExecutePath = "/execute"
+	DefaultHealthPath  = "/healthz"
+)
+
+// Handler performs the work for one claim.
+// Returning an error produces a Failure artefact containing the error text.
+type Handler func(ctx context.Context, in *Input) (*Output, error)
+
+// Main runs handler with the subprocess protocol and exits: ToolInput is read from
+// stdin and ToolOutput written to stdout. Errors are written to stderr with exit code 1.
+func Main(handler Handler) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := Execute(ctx, os.Stdin, os.Stdout, handler); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		stop()
+		os.Exit(1)
+	}
+}
+
+// Execute reads one ToolInput from r, runs handler and writes the validated ToolOutput to w.
+// WorkDir is set to the process working directory, which the pup sets for each claim.
+func Execute(ctx context.Context, r io.Reader, w io.Writer, handler Handler) error {
+	var in Input
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return fmt.Errorf("failed to parse tool input: %w", err)
+	}
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to determine working directory: %w", err)
+	}
+	in.WorkDir = workDir
+
+	output, err := run(ctx, &in, handler)
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(output)
+}
+
+// run invokes handler and validates its output
+func run(ctx context.Context, in *Input, handler Handler) (*Output, error) {
+	output, err := handler(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	if output == nil {
+		return nil, fmt.Errorf("handler returned no output")
+	}
+	if err := output.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid tool output: %w", err)
+	}
+	return output, nil
+}
+
+// ExecuteHandler returns an http.Handler that runs handler for each POSTed ToolInput.
+// WorkDir is taken from the X-Holt-Work-Dir header. Handler errors become 500 responses,
+// which the pup turns into Failure artefacts.
+func ExecuteHandler(handler Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var in Input
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			http.Error(w, fmt.Sprintf("failed to parse tool input: %v", err), http.StatusBadRequest)
+			return
+		}
+		in.WorkDir = r.Header.Get(HeaderWorkDir)
+
+		output, err := run(r.Context(), &in, handler)
+		if err != nil {
+			log.Printf("claim %s failed: %v", r.Header.Get(HeaderClaimID), err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(output)
+	})
+}
+
+// HTTPHandler serves handler on DefaultExecutePath with a health check on DefaultHealthPath.
+// Agents with custom http paths can mount ExecuteHandler on their own mux instead.
+func HTTPHandler(handler Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(DefaultExecutePath, ExecuteHandler(handler))
+	mux.HandleFunc(DefaultHealthPath, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
+
+// ListenAndServe runs handler as a protocol: http agent on the port given by
+// HOLT_AGENT_PORT (default 9000), listening on localhost only.
+func ListenAndServe(handler Handler) error {
+	port := 9000
+	if value := os.Getenv("HOLT_AGENT_PORT"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid HOLT_AGENT_PORT %q: %w", value, err)
+		}
+		port = parsed
+	}
+
+	return http.ListenAndServe(fmt.Sprintf("127.0.0.1:%d", port), HTTPHandler(handler))
+}
+
+var (
+	progressOnce   sync.Once
+	progressWriter io.Writer
+)
+
+// Progress reports progress to `holt watch` via HOLT_PROGRESS_FD.
+// A no-op when the channel is unavailable (e.g. protocol: http or in tests).
+// Percent is omitted when negative.
+func Progress(message string, percent float64) {
+	progressOnce.Do(func() {
+		fd, err := strconv.Atoi(os.Getenv("HOLT_PROGRESS_FD"))
+		if err != nil {
+			return
+		}
+		if file := os.NewFile(uintptr(fd), "holt-progress"); file != nil {
+			progressWriter = file
+		}
+	})
+	if progressWriter == nil {
+		return
+	}
+
+	event := map[string]interface{}{"message": message}
+	if percent >= 0 {
+		event["percent"] = percent
+	}
+	line, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	_, _ = progressWriter.Write(append(line, '\n'))
+}
@@ -0,0 +1,94 @@
+package agentsdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testInputJSON = `{"claim_type":"exclusive","target_artefact":{"id":"t1","type":"GoalDefined","payload":"hello"},"context_chain":[]}`
+
+// echoHandler returns the target payload as a Result artefact
+func echoHandler(ctx context.Context, in *Input) (*Output, error) {
+	return NewOutput("Result", in.TargetArtefact.Payload, "echoed "+in.WorkDir), nil
+}
+
+func TestExecute(t *testing.T) {
+	var stdout bytes.Buffer
+	require.NoError(t, Execute(context.Background(), strings.NewReader(testInputJSON), &stdout, echoHandler))
+
+	var output Output
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	assert.Equal(t, "Result", output.ArtefactType)
+	assert.Equal(t, "hello", output.ArtefactPayload)
+
+	wd, _ := os.Getwd()
+	assert.Equal(t, "echoed "+wd, output.Summary)
+}
+
+func TestExecute_Errors(t *testing.T) {
+	var stdout bytes.Buffer
+
+	err := Execute(context.Background(), strings.NewReader("not json"), &stdout, echoHandler)
+	assert.ErrorContains(t, err, "failed to parse tool input")
+
+	err = Execute(context.Background(), strings.NewReader(testInputJSON), &stdout, func(ctx context.Context, in *Input) (*Output, error) {
+		return nil, fmt.Errorf("boom")
+	})
+	assert.EqualError(t, err, "boom")
+
+	err = Execute(context.Background(), strings.NewReader(testInputJSON), &stdout, func(ctx context.Context, in *Input) (*Output, error) {
+		return &Output{ArtefactType: "Result"}, nil
+	})
+	assert.ErrorContains(t, err, "invalid tool output")
+
+	assert.Empty(t, stdout.String(), "nothing is written on failure")
+}
+
+func TestHTTPHandler(t *testing.T) {
+	server := httptest.NewServer(HTTPHandler(echoHandler))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + DefaultHealthPath)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+DefaultExecutePath, strings.NewReader(testInputJSON))
+	req.Header.Set(HeaderWorkDir, "/workspace/.holt-worktrees/coder-c1")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var output Output
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&output))
+	assert.Equal(t, "hello", output.ArtefactPayload)
+	assert.Equal(t, "echoed /workspace/.holt-worktrees/coder-c1", output.Summary)
+}
+
+func TestHTTPHandler_HandlerError(t *testing.T) {
+	server := httptest.NewServer(HTTPHandler(func(ctx context.Context, in *Input) (*Output, error) {
+		return nil, fmt.Errorf("model unavailable")
+	}))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+DefaultExecutePath, "application/json", strings.NewReader(testInputJSON))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	resp2, err := http.Get(server.URL + DefaultExecutePath)
+	require.NoError(t, err)
+	resp2.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp2.StatusCode)
+}
@@ -0,0 +1,222 @@
+package agentsdk
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dyluth/holt/pkg/blackboard"
+)
+
+// This file is the single definition of the ToolInput/ToolOutput contract: the pup
+// (internal/pup) uses these types for the JSON it sends and the output it validates.
+
+// Input is the ToolInput the pup sends for each granted claim.
+// The JSON contract is documented in docs/agent-development.md.
+type Input struct {
+	// ClaimType is the type of claim granted ("exclusive", "claim", "review")
+	ClaimType string `json:"claim_type"`
+
+	// TargetArtefact is the artefact the claim is for
+	TargetArtefact *blackboard.Artefact `json:"target_artefact"`
+
+	// ContextChain holds ancestor (and optionally sibling) artefacts, oldest first
+	ContextChain []*blackboard.Artefact `json:"context_chain"`
+
+	// ContextTruncation reports what context assembly left out (nil if nothing)
+	ContextTruncation *ContextTruncation `json:"context_truncation,omitempty"`
+
+	// ChildWorkflowResult is the Terminal or Failure artefact that ended the child
+	// workflow this claim requested (nil unless the claim was resumed)
+	ChildWorkflowResult *blackboard.Artefact `json:"child_workflow_result,omitempty"`
+
+	// WorkDir is the directory the agent should work in. Not part of the JSON contract:
+	// the runners set it from the process working directory (subprocess) or the
+	// X-Holt-Work-Dir header (http).
+	WorkDir string `json:"-"`
+}
+
+// ContextTruncation describes how the context chain was cut down to fit the
+// agent's context settings (max_depth and max_bytes).
+type ContextTruncation struct {
+	DepthLimitReached     bool     `json:"depth_limit_reached,omitempty"`
+	MaxDepth              int      `json:"max_depth"`
+	DroppedArtefactIDs    []string `json:"dropped_artefact_ids,omitempty"`
+	SummarisedArtefactIDs []string `json:"summarised_artefact_ids,omitempty"`
+	PayloadBytes          int      `json:"payload_bytes"`
+	MaxPayloadBytes       int      `json:"max_payload_bytes,omitempty"`
+}
+
+// IsEmpty reports whether nothing was actually left out or condensed
+func (t *ContextTruncation) IsEmpty() bool {
+	return !t.DepthLimitReached && len(t.DroppedArtefactIDs) == 0 && len(t.SummarisedArtefactIDs) == 0
+}
+
+// Output is the ToolOutput an agent returns.
+// Exactly one of the single-artefact fields, Artefacts (a batch) or ChildWorkflow is set.
+type Output struct {
+	ArtefactType    string           `json:"artefact_type,omitempty"`
+	ArtefactPayload string           `json:"artefact_payload,omitempty"`
+	Summary         string           `json:"summary"`
+	StructuralType  string           `json:"structural_type,omitempty"`
+	Artefacts       []OutputArtefact `json:"artefacts,omitempty"`
+	ChildWorkflow   *ChildWorkflow   `json:"child_workflow,omitempty"`
+}
+
+// ChildWorkflow asks the orchestrator to run a child workflow before this claim completes.
+// Agents optionally restricts the child to those agent roles.
+type ChildWorkflow struct {
+	Goal   string   `json:"goal"`
+	Agents []string `json:"agents,omitempty"`
+}
+
+// OutputArtefact is one entry in a batch Output.
+type OutputArtefact struct {
+	// Ref is an optional batch-local name other entries can use in SourceRefs
+	Ref             string   `json:"ref,omitempty"`
+	ArtefactType    string   `json:"artefact_type"`
+	ArtefactPayload string   `json:"artefact_payload"`
+	Summary         string   `json:"summary,omitempty"`
+	StructuralType  string   `json:"structural_type,omitempty"`
+	SourceRefs      []string `json:"source_refs,omitempty"` // Entries without source refs derive from the claim's target
+}
+
+// Validate checks the output against the ToolOutput contract. The pup runs the same check
+// on every output it receives, so calling it in the agent (and its tests) surfaces mistakes
+// before they become a Failure artefact.
+func (o *Output) Validate() error {
+	if o.ChildWorkflow != nil {
+		return o.validateChildWorkflow()
+	}
+
+	if o.IsBatch() {
+		return o.validateBatch()
+	}
+
+	if o.ArtefactType == "" {
+		return fmt.Errorf("artefact_type is required and cannot be empty")
+	}
+	if o.Summary == "" {
+		return fmt.Errorf("summary is required and cannot be empty")
+	}
+
+	// ArtefactPayload may be empty; StructuralType defaults to Standard
+	return validateStructuralType(o.StructuralType)
+}
+
+// IsBatch reports whether the output carries an artefacts array.
+func (o *Output) IsBatch() bool {
+	return len(o.Artefacts) > 0
+}
+
+// validateBatch checks a batch output: single-artefact fields must be absent, every
+// entry must be valid, refs must be unique, and source_refs may only name earlier
+// entries (which rules out cycles and gives a creation order).
+func (o *Output) validateBatch() error {
+	if o.ArtefactType != "" || o.ArtefactPayload != "" || o.StructuralType != "" {
+		return fmt.Errorf("artefacts cannot be combined with artefact_type, artefact_payload or structural_type")
+	}
+	if o.Summary == "" {
+		return fmt.Errorf("summary is required and cannot be empty")
+	}
+
+	refs := make(map[string]bool)
+	for i, entry := range o.Artefacts {
+		if entry.ArtefactType == "" {
+			return fmt.Errorf("artefacts[%d]: artefact_type is required and cannot be empty", i)
+		}
+		if err := validateStructuralType(entry.StructuralType); err != nil {
+			return fmt.Errorf("artefacts[%d]: %w", i, err)
+		}
+		for _, sourceRef := range entry.SourceRefs {
+			if !refs[sourceRef] {
+				return fmt.Errorf("artefacts[%d]: source_ref %q does not name an earlier artefact in the batch", i, sourceRef)
+			}
+		}
+		if entry.Ref != "" {
+			if refs[entry.Ref] {
+				return fmt.Errorf("artefacts[%d]: duplicate ref %q", i, entry.Ref)
+			}
+			refs[entry.Ref] = true
+		}
+	}
+
+	return nil
+}
+
+// validateChildWorkflow checks a child workflow request: it replaces the artefact
+// fields entirely, and needs a goal and a summary.
+func (o *Output) validateChildWorkflow() error {
+	if o.ArtefactType != "" || o.ArtefactPayload != "" || o.StructuralType != "" || o.IsBatch() {
+		return fmt.Errorf("child_workflow cannot be combined with artefact_type, artefact_payload, structural_type or artefacts")
+	}
+	if o.Summary == "" {
+		return fmt.Errorf("summary is required and cannot be empty")
+	}
+	if strings.TrimSpace(o.ChildWorkflow.Goal) == "" {
+		return fmt.Errorf("child_workflow.goal is required and cannot be empty")
+	}
+	for i, agent := range o.ChildWorkflow.Agents {
+		if agent == "" {
+			return fmt.Errorf("child_workflow.agents[%d]: agent role cannot be empty", i)
+		}
+	}
+	return nil
+}
+
+// validateStructuralType checks an optional structural type
+func validateStructuralType(structuralType string) error {
+	if structuralType == "" {
+		return nil
+	}
+	if err := blackboard.StructuralType(structuralType).Validate(); err != nil {
+		return fmt.Errorf("invalid structural_type: %w", err)
+	}
+	return nil
+}
+
+// Entries returns the artefacts described by the output as batch entries.
+// A single-artefact output is returned as a one-entry batch; batch entries
+// without a summary inherit the output's.
+func (o *Output) Entries() []OutputArtefact {
+	if !o.IsBatch() {
+		return []OutputArtefact{{
+			ArtefactType:    o.ArtefactType,
+			ArtefactPayload: o.ArtefactPayload,
+			Summary:         o.Summary,
+			StructuralType:  o.StructuralType,
+		}}
+	}
+
+	entries := make([]OutputArtefact, len(o.Artefacts))
+	for i, entry := range o.Artefacts {
+		if entry.Summary == "" {
+			entry.Summary = o.Summary
+		}
+		entries[i] = entry
+	}
+	return entries
+}
+
+// GetStructuralType returns the structural type of the artefact the output produces.
+// An explicit structural_type wins; otherwise artefact type "Review" maps to Review
+// and everything else is Standard.
+func (o *Output) GetStructuralType() blackboard.StructuralType {
+	return structuralTypeFor(o.ArtefactType, o.StructuralType)
+}
+
+// GetStructuralType returns the structural type of a batch entry, with the same
+// defaults as Output.GetStructuralType.
+func (a *OutputArtefact) GetStructuralType() blackboard.StructuralType {
+	return structuralTypeFor(a.ArtefactType, a.StructuralType)
+}
+
+// structuralTypeFor applies the structural type defaults
+func structuralTypeFor(artefactType, structuralType string) blackboard.StructuralType {
+	if structuralType != "" {
+		return blackboard.StructuralType(structuralType)
+	}
+	if artefactType == "Review" {
+		return blackboard.StructuralTypeReview
+	}
+	return blackboard.StructuralTypeStandard
+}
@@ -0,0 +1,66 @@
+package agentsdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutput_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		output      Output
+		expectError string
+	}{
+		{name: "single", output: Output{ArtefactType: "CodeCommit", Summary: "done"}},
+		{name: "missing summary", output: Output{ArtefactType: "CodeCommit"}, expectError: "summary is required"},
+		{name: "missing type", output: Output{Summary: "done"}, expectError: "artefact_type is required"},
+		{name: "bad structural type", output: Output{ArtefactType: "X", Summary: "done", StructuralType: "Bogus"}, expectError: "invalid structural_type"},
+		{
+			name: "batch",
+			output: Output{Summary: "split", Artefacts: []OutputArtefact{
+				{Ref: "plan", ArtefactType: "Plan"},
+				{ArtefactType: "Task", SourceRefs: []string{"plan"}},
+			}},
+		},
+		{
+			name:        "batch mixed with single fields",
+			output:      Output{ArtefactType: "Plan", Summary: "split", Artefacts: []OutputArtefact{{ArtefactType: "Task"}}},
+			expectError: "cannot be combined",
+		},
+		{
+			name:        "batch forward ref",
+			output:      Output{Summary: "split", Artefacts: []OutputArtefact{{ArtefactType: "Task", SourceRefs: []string{"plan"}}, {Ref: "plan", ArtefactType: "Plan"}}},
+			expectError: "does not name an earlier artefact",
+		},
+		{
+			name:        "batch duplicate ref",
+			output:      Output{Summary: "split", Artefacts: []OutputArtefact{{Ref: "a", ArtefactType: "Task"}, {Ref: "a", ArtefactType: "Task"}}},
+			expectError: "duplicate ref",
+		},
+		{name: "child workflow", output: Output{Summary: "need research", ChildWorkflow: &ChildWorkflow{Goal: "Compare libraries", Agents: []string{"Researcher"}}}},
+		{
+			name:        "child workflow mixed with single fields",
+			output:      Output{ArtefactType: "Plan", Summary: "need research", ChildWorkflow: &ChildWorkflow{Goal: "Compare libraries"}},
+			expectError: "child_workflow cannot be combined",
+		},
+		{
+			name:        "child workflow missing goal",
+			output:      Output{Summary: "need research", ChildWorkflow: &ChildWorkflow{Goal: " "}},
+			expectError: "child_workflow.goal is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.output.Validate()
+			if tt.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package agentsdk
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Workspace gives path-safe file access within the agent's working directory.
+// Relative paths are resolved against Dir and may not escape it.
+type Workspace struct {
+	Dir string
+}
+
+// Workspace returns the workspace for this claim (see Input.WorkDir).
+func (in *Input) Workspace() Workspace {
+	return Workspace{Dir: in.WorkDir}
+}
+
+// Path resolves a workspace-relative path, rejecting absolute paths and paths
+// that would escape the workspace.
+func (w Workspace) Path(rel string) (string, error) {
+	if filepath.IsAbs(rel) {
+		return "", fmt.Errorf("path %q must be relative to the workspace", rel)
+	}
+	cleaned := filepath.Clean(rel)
+	if cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q escapes the workspace", rel)
+	}
+	return filepath.Join(w.Dir, cleaned), nil
+}
+
+// ReadFile reads a workspace file.
+func (w Workspace) ReadFile(rel string) ([]byte, error) {
+	path, err := w.Path(rel)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+// WriteFile writes a workspace file, creating parent directories as needed.
+// Fails if the workspace is mounted read-only.
+func (w Workspace) WriteFile(rel string, data []byte) error {
+	path, err := w.Path(rel)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", rel, err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Exists reports whether a workspace file or directory exists.
+func (w Workspace) Exists(rel string) bool {
+	path, err := w.Path(rel)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// Glob returns workspace-relative paths matching pattern (filepath.Match syntax).
+func (w Workspace) Glob(pattern string) ([]string, error) {
+	if _, err := w.Path(pattern); err != nil {
+		return nil, err
+	}
+	matches, err := filepath.Glob(filepath.Join(w.Dir, pattern))
+	if err != nil {
+		return nil, err
+	}
+	for i, match := range matches {
+		rel, err := filepath.Rel(w.Dir, match)
+		if err != nil {
+			return nil, err
+		}
+		matches[i] = rel
+	}
+	return matches, nil
+}
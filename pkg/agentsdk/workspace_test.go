@@ -0,0 +1,36 @@
+package agentsdk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	ws := (&Input{WorkDir: dir}).Workspace()
+
+	require.NoError(t, ws.WriteFile("src/main.go", []byte("package main\n")))
+	assert.FileExists(t, filepath.Join(dir, "src", "main.go"))
+	assert.True(t, ws.Exists("src/main.go"))
+	assert.False(t, ws.Exists("src/other.go"))
+
+	data, err := ws.ReadFile("src/main.go")
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n", string(data))
+
+	matches, err := ws.Glob("src/*.go")
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("src", "main.go")}, matches)
+
+	_, err = ws.ReadFile("../outside")
+	assert.ErrorContains(t, err, "escapes the workspace")
+	assert.ErrorContains(t, ws.WriteFile("/etc/passwd", nil), "must be relative")
+	assert.False(t, ws.Exists("../"+filepath.Base(dir)))
+
+	_, err = os.Stat(filepath.Join(filepath.Dir(dir), "outside"))
+	assert.True(t, os.IsNotExist(err))
+}
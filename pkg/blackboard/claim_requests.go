@@ -0,0 +1,45 @@
+package blackboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Claim request actions
+const (
+	// ClaimActionRetry creates a fresh claim on a terminated claim's artefact
+	ClaimActionRetry = "retry"
+	// ClaimActionSkip resolves a terminated claim with a human-supplied artefact
+	ClaimActionSkip = "skip"
+)
+
+// ClaimRequest asks the orchestrator to intervene on a terminated claim. Claims are
+// only written by the leading orchestrator, so holt retry and holt skip publish a
+// request and wait for the orchestrator's workflow event carrying the same ID.
+type ClaimRequest struct {
+	ID      string `json:"id"`       // Echoed as request_id in the resulting workflow event
+	Action  string `json:"action"`   // ClaimActionRetry or ClaimActionSkip
+	ClaimID string `json:"claim_id"` // The terminated claim
+
+	// Retry
+	AgentRole string `json:"agent_role,omitempty"` // Grant straight to this agent instead of bidding
+	NoContext bool   `json:"no_context,omitempty"` // Do not pass the earlier Failure artefacts to the agent
+
+	// Skip
+	ArtefactType string `json:"artefact_type,omitempty"`
+	Payload      string `json:"payload,omitempty"`
+	Terminal     bool   `json:"terminal,omitempty"` // The supplied artefact ends the workflow
+}
+
+// PublishClaimRequest sends a claim request to the orchestrator.
+func (c *Client) PublishClaimRequest(ctx context.Context, request *ClaimRequest) error {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal claim request: %w", err)
+	}
+	if err := c.rdb.Publish(ctx, ClaimRequestsChannel(c.instanceName), data).Err(); err != nil {
+		return fmt.Errorf("failed to publish claim request: %w", err)
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -18,6 +19,10 @@ import (
 type Client struct {
 	rdb          *redis.Client
 	instanceName string
+
+	// fencingToken, when non-zero, is stamped on every claim write and checked
+	// against the instance's latest fencing token (see SetFencingToken)
+	fencingToken atomic.Int64
 }
 
 // NewClient creates a new blackboard client for the specified instance.
@@ -61,7 +66,8 @@ func (c *Client) RedisClient() *redis.Client {
 // Validates the artefact before writing. Returns error if validation fails or Redis operation fails.
 // Publishes full artefact JSON to holt:{instance}:artefact_events after successful write.
 //
-// The artefact is stored as a Redis hash at holt:{instance}:artefact:{id}.
+// The artefact is stored as a Redis hash at holt:{instance}:artefact:{id}, together with
+// its result index entries if it is a Terminal or Failure (see indexResult).
 // This method is idempotent - writing the same artefact twice is safe.
 func (c *Client) CreateArtefact(ctx context.Context, a *Artefact) error {
 	// M3.9: Auto-populate CreatedAtMs if not set
@@ -81,8 +87,12 @@ func (c *Client) CreateArtefact(ctx context.Context, a *Artefact) error {
 	}
 
 	// Write to Redis
-	key := ArtefactKey(c.instanceName, a.ID)
-	if err := c.rdb.HSet(ctx, key, hash).Err(); err != nil {
+	_, err = c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, ArtefactKey(c.instanceName, a.ID), hash)
+		c.indexResult(ctx, pipe, a)
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("failed to write artefact to Redis: %w", err)
 	}
 
@@ -100,6 +110,61 @@ func (c *Client) CreateArtefact(ctx context.Context, a *Artefact) error {
 	return nil
 }
 
+// CreateArtefacts writes a batch of artefacts atomically, adds each to its version
+// thread and publishes their events, all in a single MULTI/EXEC transaction.
+// Subscribers therefore never observe a partial batch: either every artefact exists
+// before the first event is delivered, or none is written.
+// Events are published in slice order, so artefacts should precede those derived from them.
+func (c *Client) CreateArtefacts(ctx context.Context, artefacts []*Artefact) error {
+	now := time.Now().UnixMilli()
+	hashes := make([]map[string]interface{}, len(artefacts))
+	events := make([][]byte, len(artefacts))
+
+	// Validate and serialise everything up front so nothing is written on error
+	for i, a := range artefacts {
+		if a.CreatedAtMs == 0 {
+			a.CreatedAtMs = now
+		}
+
+		if err := a.Validate(); err != nil {
+			return fmt.Errorf("invalid artefact at index %d: %w", i, err)
+		}
+
+		hash, err := ArtefactToHash(a)
+		if err != nil {
+			return fmt.Errorf("failed to serialize artefact at index %d: %w", i, err)
+		}
+		hashes[i] = hash
+
+		artefactJSON, err := json.Marshal(a)
+		if err != nil {
+			return fmt.Errorf("failed to marshal artefact at index %d for event: %w", i, err)
+		}
+		events[i] = artefactJSON
+	}
+
+	channel := ArtefactEventsChannel(c.instanceName)
+	_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, a := range artefacts {
+			pipe.HSet(ctx, ArtefactKey(c.instanceName, a.ID), hashes[i])
+			pipe.ZAdd(ctx, ThreadKey(c.instanceName, a.LogicalID), redis.Z{
+				Score:  ThreadScore(a.Version),
+				Member: a.ID,
+			})
+			c.indexResult(ctx, pipe, a)
+		}
+		for _, event := range events {
+			pipe.Publish(ctx, channel, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write artefact batch to Redis: %w", err)
+	}
+
+	return nil
+}
+
 // GetArtefact retrieves an artefact by ID.
 // Returns (nil, redis.Nil) if the artefact doesn't exist.
 // Use IsNotFound() to check for not-found errors.
@@ -175,12 +240,17 @@ func (c *Client) ScanArtefacts(ctx context.Context, prefix string) ([]string, er
 // Validates the claim before writing.
 // Publishes full claim JSON to holt:{instance}:claim_events after successful write.
 // Also creates an index mapping artefact_id to claim_id for idempotency checks.
+// Fenced like UpdateClaim when a fencing token is set.
 func (c *Client) CreateClaim(ctx context.Context, claim *Claim) error {
 	// Validate claim
 	if err := claim.Validate(); err != nil {
 		return fmt.Errorf("invalid claim: %w", err)
 	}
 
+	if token := c.fencingToken.Load(); token > 0 {
+		claim.FencingToken = token
+	}
+
 	// Convert to Redis hash
 	hash, err := ClaimToHash(claim)
 	if err != nil {
@@ -188,8 +258,7 @@ func (c *Client) CreateClaim(ctx context.Context, claim *Claim) error {
 	}
 
 	// Write to Redis
-	key := ClaimKey(c.instanceName, claim.ID)
-	if err := c.rdb.HSet(ctx, key, hash).Err(); err != nil {
+	if err := c.writeClaimHash(ctx, claim.ID, hash); err != nil {
 		return fmt.Errorf("failed to write claim to Redis: %w", err)
 	}
 
@@ -240,7 +309,8 @@ func (c *Client) GetClaim(ctx context.Context, claimID string) (*Claim, error) {
 
 // UpdateClaim replaces an existing claim with new data (full HMSET replacement).
 // Used by orchestrator to update status and granted agents as claim progresses through phases.
-// Validates the claim before writing.
+// Validates the claim before writing. If a fencing token is set, the write is rejected with
+// ErrStaleFencingToken once a newer leader has been elected.
 //
 // Note: This performs a full replacement of all fields. The claim will be created if it doesn't exist.
 func (c *Client) UpdateClaim(ctx context.Context, claim *Claim) error {
@@ -249,6 +319,10 @@ func (c *Client) UpdateClaim(ctx context.Context, claim *Claim) error {
 		return fmt.Errorf("invalid claim: %w", err)
 	}
 
+	if token := c.fencingToken.Load(); token > 0 {
+		claim.FencingToken = token
+	}
+
 	// Convert to Redis hash
 	hash, err := ClaimToHash(claim)
 	if err != nil {
@@ -256,8 +330,7 @@ func (c *Client) UpdateClaim(ctx context.Context, claim *Claim) error {
 	}
 
 	// Write to Redis (full replacement)
-	key := ClaimKey(c.instanceName, claim.ID)
-	if err := c.rdb.HSet(ctx, key, hash).Err(); err != nil {
+	if err := c.writeClaimHash(ctx, claim.ID, hash); err != nil {
 		return fmt.Errorf("failed to update claim in Redis: %w", err)
 	}
 
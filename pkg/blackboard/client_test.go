@@ -134,6 +134,69 @@ func TestCreateArtefact(t *testing.T) {
 	})
 }
 
+func TestCreateArtefacts(t *testing.T) {
+	client, _ := setupTestClient(t)
+	ctx := context.Background()
+
+	newArtefact := func(artefactType string, sources ...string) *Artefact {
+		id := uuid.New().String()
+		return &Artefact{
+			ID:              id,
+			LogicalID:       id,
+			Version:         1,
+			StructuralType:  StructuralTypeStandard,
+			Type:            artefactType,
+			ProducedByRole:  "planner",
+			Payload:         artefactType + " payload",
+			SourceArtefacts: sources,
+		}
+	}
+
+	t.Run("writes batch, threads and events in order", func(t *testing.T) {
+		sub, err := client.SubscribeArtefactEvents(ctx)
+		require.NoError(t, err)
+		defer sub.Close()
+
+		plan := newArtefact("Plan")
+		task := newArtefact("Task", plan.ID)
+		require.NoError(t, client.CreateArtefacts(ctx, []*Artefact{plan, task}))
+
+		for _, expected := range []*Artefact{plan, task} {
+			retrieved, err := client.GetArtefact(ctx, expected.ID)
+			require.NoError(t, err)
+			assert.Equal(t, expected.Type, retrieved.Type)
+			assert.NotZero(t, retrieved.CreatedAtMs)
+
+			latestID, version, err := client.GetLatestVersion(ctx, expected.LogicalID)
+			require.NoError(t, err)
+			assert.Equal(t, expected.ID, latestID)
+			assert.Equal(t, 1, version)
+		}
+
+		for _, expected := range []*Artefact{plan, task} {
+			select {
+			case received := <-sub.Events():
+				assert.Equal(t, expected.ID, received.ID)
+			case <-time.After(1 * time.Second):
+				t.Fatal("timeout waiting for artefact event")
+			}
+		}
+	})
+
+	t.Run("writes nothing when any artefact is invalid", func(t *testing.T) {
+		valid := newArtefact("Task")
+		invalid := newArtefact("")
+
+		err := client.CreateArtefacts(ctx, []*Artefact{valid, invalid})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "index 1")
+
+		exists, err := client.ArtefactExists(ctx, valid.ID)
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
+
 func TestGetArtefact(t *testing.T) {
 	client, _ := setupTestClient(t)
 	ctx := context.Background()
@@ -0,0 +1,93 @@
+package blackboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// InstanceConfig is the effective holt.yml a running instance uses. holt up stores
+// revision 1 and each holt reload stores the next revision. The orchestrator adopts
+// the stored configuration when it starts leading, so reloads survive restarts and
+// failover.
+type InstanceConfig struct {
+	Revision  int                 `json:"revision"`             // Incremented by each save
+	Profile   string              `json:"profile,omitempty"`    // Profile overlay the config was loaded with
+	Config    string              `json:"config"`               // Effective holt.yml as YAML (overlay merged, variables expanded)
+	WorkerEnv map[string][]string `json:"worker_env,omitempty"` // Resolved environment for each controller's workers
+	SavedAtMs int64               `json:"saved_at_ms"`          // When the revision was stored (Unix milliseconds)
+}
+
+// GetInstanceConfig returns the stored instance configuration.
+// Returns redis.Nil (check with IsNotFound) if none has been stored.
+func (c *Client) GetInstanceConfig(ctx context.Context) (*InstanceConfig, error) {
+	data, err := c.rdb.Get(ctx, InstanceConfigKey(c.instanceName)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read instance config: %w", err)
+	}
+
+	var instanceConfig InstanceConfig
+	if err := json.Unmarshal([]byte(data), &instanceConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal instance config: %w", err)
+	}
+	return &instanceConfig, nil
+}
+
+// SaveInstanceConfig stores cfg as the next revision, setting its Revision and
+// SavedAtMs. The read-increment-write is optimistic: a concurrent save makes it fail
+// rather than reuse a revision number.
+func (c *Client) SaveInstanceConfig(ctx context.Context, cfg *InstanceConfig) error {
+	key := InstanceConfigKey(c.instanceName)
+
+	err := c.rdb.Watch(ctx, func(tx *redis.Tx) error {
+		revision := 0
+		current, err := tx.Get(ctx, key).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return fmt.Errorf("failed to read instance config: %w", err)
+		}
+		if err == nil {
+			var stored InstanceConfig
+			if err := json.Unmarshal([]byte(current), &stored); err == nil {
+				revision = stored.Revision
+			}
+		}
+
+		next := *cfg
+		next.Revision = revision + 1
+		next.SavedAtMs = time.Now().UnixMilli()
+		data, err := json.Marshal(&next)
+		if err != nil {
+			return fmt.Errorf("failed to marshal instance config: %w", err)
+		}
+
+		if _, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, data, 0)
+			return nil
+		}); err != nil {
+			return err
+		}
+		cfg.Revision = next.Revision
+		cfg.SavedAtMs = next.SavedAtMs
+		return nil
+	}, key)
+	if err != nil {
+		return fmt.Errorf("failed to save instance config: %w", err)
+	}
+	return nil
+}
+
+// PublishConfigRevision announces a newly saved configuration revision to the orchestrator.
+func (c *Client) PublishConfigRevision(ctx context.Context, revision int) error {
+	if err := c.rdb.Publish(ctx, ConfigEventsChannel(c.instanceName), strconv.Itoa(revision)).Err(); err != nil {
+		return fmt.Errorf("failed to publish config revision: %w", err)
+	}
+	return nil
+}
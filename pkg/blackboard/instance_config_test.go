@@ -0,0 +1,55 @@
+package blackboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndGetInstanceConfig(t *testing.T) {
+	client, _ := setupTestClient(t)
+	ctx := context.Background()
+
+	_, err := client.GetInstanceConfig(ctx)
+	assert.True(t, IsNotFound(err))
+
+	first := &InstanceConfig{Config: "version: \"1.0\"\n", Profile: "ci"}
+	require.NoError(t, client.SaveInstanceConfig(ctx, first))
+	assert.Equal(t, 1, first.Revision)
+	assert.NotZero(t, first.SavedAtMs)
+
+	second := &InstanceConfig{
+		Config:    "version: \"1.0\"\nagents: {}\n",
+		WorkerEnv: map[string][]string{"Builder": {"MODEL=small"}},
+	}
+	require.NoError(t, client.SaveInstanceConfig(ctx, second))
+	assert.Equal(t, 2, second.Revision)
+
+	stored, err := client.GetInstanceConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored.Revision)
+	assert.Equal(t, second.Config, stored.Config)
+	assert.Empty(t, stored.Profile)
+	assert.Equal(t, []string{"MODEL=small"}, stored.WorkerEnv["Builder"])
+}
+
+func TestPublishConfigRevision(t *testing.T) {
+	client, _ := setupTestClient(t)
+	ctx := context.Background()
+
+	sub, err := client.SubscribeRawChannel(ctx, ConfigEventsChannel("test-instance"))
+	require.NoError(t, err)
+	defer sub.Close()
+
+	require.NoError(t, client.PublishConfigRevision(ctx, 3))
+
+	select {
+	case message := <-sub.Messages():
+		assert.Equal(t, "3", message)
+	case <-time.After(1 * time.Second):
+		t.Fatal("timeout waiting for config revision")
+	}
+}
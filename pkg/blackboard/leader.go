@@ -0,0 +1,174 @@
+package blackboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrStaleFencingToken is returned by claim writes made with a fencing token older than
+// the instance's latest one, i.e. by an orchestrator that has lost leadership.
+var ErrStaleFencingToken = errors.New("stale fencing token: a newer orchestrator leader has been elected")
+
+// staleFencingReply is the Lua error prefix mapped to ErrStaleFencingToken
+const staleFencingReply = "STALE_FENCING_TOKEN"
+
+// LeaderLease describes the orchestrator replica currently holding the leader lease.
+type LeaderLease struct {
+	HolderID     string `json:"holder_id"`      // Orchestrator replica identifier
+	AcquiredAtMs int64  `json:"acquired_at_ms"` // When leadership was acquired (Unix milliseconds)
+	FencingToken int64  `json:"fencing_token"`  // Monotonic token issued on acquisition
+
+	// value is the raw lease value, compared on renewal and release
+	value string
+}
+
+// acquireLeaseScript sets the lease only if no leader holds it (SET NX PX) and,
+// on success, issues the next fencing token.
+// KEYS[1]=leader key, KEYS[2]=fencing key, ARGV[1]=lease value, ARGV[2]=ttl ms
+var acquireLeaseScript = redis.NewScript(`
+if not redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+	return 0
+end
+return redis.call('INCR', KEYS[2])
+`)
+
+// renewLeaseScript extends the lease TTL if it is still held by the caller.
+// KEYS[1]=leader key, ARGV[1]=lease value, ARGV[2]=ttl ms
+var renewLeaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseLeaseScript deletes the lease if it is still held by the caller.
+// KEYS[1]=leader key, ARGV[1]=lease value
+var releaseLeaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// fencedHSetScript writes a claim hash unless a newer fencing token has been issued.
+// KEYS[1]=claim key, KEYS[2]=fencing key, ARGV[1]=writer token, ARGV[2..]=field/value pairs
+var fencedHSetScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[2]) or '0')
+if current > tonumber(ARGV[1]) then
+	return redis.error_reply('` + staleFencingReply + ` current=' .. current)
+end
+return redis.call('HSET', KEYS[1], unpack(ARGV, 2))
+`)
+
+// AcquireLeadership attempts to take the orchestrator leader lease for holderID.
+// Returns (nil, nil) if another replica currently holds it.
+func (c *Client) AcquireLeadership(ctx context.Context, holderID string, ttl time.Duration) (*LeaderLease, error) {
+	lease := &LeaderLease{
+		HolderID:     holderID,
+		AcquiredAtMs: time.Now().UnixMilli(),
+	}
+	value, err := json.Marshal(lease)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal leader lease: %w", err)
+	}
+	lease.value = string(value)
+
+	keys := []string{OrchestratorLeaderKey(c.instanceName), OrchestratorFencingKey(c.instanceName)}
+	token, err := acquireLeaseScript.Run(ctx, c.rdb, keys, lease.value, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire leader lease: %w", err)
+	}
+	if token == 0 {
+		return nil, nil
+	}
+
+	lease.FencingToken = token
+	return lease, nil
+}
+
+// RenewLeadership extends the lease TTL. Returns false if the lease has expired or
+// been taken by another replica, in which case the caller is no longer leader.
+func (c *Client) RenewLeadership(ctx context.Context, lease *LeaderLease, ttl time.Duration) (bool, error) {
+	renewed, err := renewLeaseScript.Run(ctx, c.rdb, []string{OrchestratorLeaderKey(c.instanceName)},
+		lease.value, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return false, fmt.Errorf("failed to renew leader lease: %w", err)
+	}
+	return renewed == 1, nil
+}
+
+// ReleaseLeadership gives up the lease so a standby can take over immediately.
+// Releasing a lease that is no longer held is a no-op.
+func (c *Client) ReleaseLeadership(ctx context.Context, lease *LeaderLease) error {
+	if err := releaseLeaseScript.Run(ctx, c.rdb, []string{OrchestratorLeaderKey(c.instanceName)}, lease.value).Err(); err != nil {
+		return fmt.Errorf("failed to release leader lease: %w", err)
+	}
+	return nil
+}
+
+// GetLeader returns the current leader lease.
+// Returns (nil, redis.Nil) if no orchestrator holds the lease.
+func (c *Client) GetLeader(ctx context.Context) (*LeaderLease, error) {
+	value, err := c.rdb.Get(ctx, OrchestratorLeaderKey(c.instanceName)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, redis.Nil
+		}
+		return nil, fmt.Errorf("failed to read leader lease: %w", err)
+	}
+
+	lease := &LeaderLease{value: value}
+	if err := json.Unmarshal([]byte(value), lease); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal leader lease: %w", err)
+	}
+
+	// The counter only advances on acquisition, so it is the current leader's token
+	token, err := c.rdb.Get(ctx, OrchestratorFencingKey(c.instanceName)).Result()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to read fencing token: %w", err)
+	}
+	lease.FencingToken, _ = strconv.ParseInt(token, 10, 64)
+
+	return lease, nil
+}
+
+// SetFencingToken makes every subsequent CreateClaim and UpdateClaim on this client stamp
+// the claim with token and fail with ErrStaleFencingToken if a newer token has been issued.
+// A token of 0 disables fencing.
+func (c *Client) SetFencingToken(token int64) {
+	c.fencingToken.Store(token)
+}
+
+// FencingToken returns the token set by SetFencingToken (0 if fencing is disabled).
+func (c *Client) FencingToken() int64 {
+	return c.fencingToken.Load()
+}
+
+// writeClaimHash writes a claim hash, fenced by the client's token if one is set
+func (c *Client) writeClaimHash(ctx context.Context, claimID string, hash map[string]interface{}) error {
+	key := ClaimKey(c.instanceName, claimID)
+
+	token := c.fencingToken.Load()
+	if token == 0 {
+		return c.rdb.HSet(ctx, key, hash).Err()
+	}
+
+	args := make([]interface{}, 0, 1+2*len(hash))
+	args = append(args, token)
+	for field, value := range hash {
+		args = append(args, field, value)
+	}
+
+	err := fencedHSetScript.Run(ctx, c.rdb, []string{key, OrchestratorFencingKey(c.instanceName)}, args...).Err()
+	if err != nil && strings.HasPrefix(err.Error(), staleFencingReply) {
+		return fmt.Errorf("%w (%s)", ErrStaleFencingToken, err.Error())
+	}
+	return err
+}
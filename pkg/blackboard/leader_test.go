@@ -0,0 +1,137 @@
+package blackboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireLeadership(t *testing.T) {
+	client, mr := setupTestClient(t)
+	ctx := context.Background()
+
+	t.Run("first replica acquires with token 1", func(t *testing.T) {
+		lease, err := client.AcquireLeadership(ctx, "orch-a", 10*time.Second)
+		require.NoError(t, err)
+		require.NotNil(t, lease)
+		assert.Equal(t, "orch-a", lease.HolderID)
+		assert.Equal(t, int64(1), lease.FencingToken)
+		assert.Greater(t, mr.TTL(OrchestratorLeaderKey("test-instance")), time.Duration(0))
+	})
+
+	t.Run("second replica is refused while the lease is held", func(t *testing.T) {
+		lease, err := client.AcquireLeadership(ctx, "orch-b", 10*time.Second)
+		require.NoError(t, err)
+		assert.Nil(t, lease)
+
+		// A refused attempt must not advance the fencing token
+		token, err := mr.Get(OrchestratorFencingKey("test-instance"))
+		require.NoError(t, err)
+		assert.Equal(t, "1", token)
+	})
+
+	t.Run("takeover after expiry issues a higher token", func(t *testing.T) {
+		mr.FastForward(11 * time.Second)
+
+		lease, err := client.AcquireLeadership(ctx, "orch-b", 10*time.Second)
+		require.NoError(t, err)
+		require.NotNil(t, lease)
+		assert.Equal(t, int64(2), lease.FencingToken)
+
+		leader, err := client.GetLeader(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "orch-b", leader.HolderID)
+		assert.Equal(t, int64(2), leader.FencingToken)
+	})
+}
+
+func TestRenewAndReleaseLeadership(t *testing.T) {
+	client, mr := setupTestClient(t)
+	ctx := context.Background()
+
+	lease, err := client.AcquireLeadership(ctx, "orch-a", 10*time.Second)
+	require.NoError(t, err)
+	require.NotNil(t, lease)
+
+	mr.FastForward(8 * time.Second)
+	renewed, err := client.RenewLeadership(ctx, lease, 10*time.Second)
+	require.NoError(t, err)
+	assert.True(t, renewed)
+
+	// Still held after the original TTL because of the renewal
+	mr.FastForward(8 * time.Second)
+	leader, err := client.GetLeader(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "orch-a", leader.HolderID)
+
+	// Once expired and taken over, the old holder can neither renew nor release
+	mr.FastForward(11 * time.Second)
+	other, err := client.AcquireLeadership(ctx, "orch-b", 10*time.Second)
+	require.NoError(t, err)
+	require.NotNil(t, other)
+
+	renewed, err = client.RenewLeadership(ctx, lease, 10*time.Second)
+	require.NoError(t, err)
+	assert.False(t, renewed)
+
+	require.NoError(t, client.ReleaseLeadership(ctx, lease))
+	leader, err = client.GetLeader(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "orch-b", leader.HolderID)
+
+	// The holder's own release frees the lease
+	require.NoError(t, client.ReleaseLeadership(ctx, other))
+	_, err = client.GetLeader(ctx)
+	assert.ErrorIs(t, err, redis.Nil)
+}
+
+func TestFencedClaimWrites(t *testing.T) {
+	client, mr := setupTestClient(t)
+	ctx := context.Background()
+
+	lease, err := client.AcquireLeadership(ctx, "orch-a", 10*time.Second)
+	require.NoError(t, err)
+	client.SetFencingToken(lease.FencingToken)
+
+	claim := &Claim{
+		ID:                    uuid.New().String(),
+		ArtefactID:            uuid.New().String(),
+		Status:                ClaimStatusPendingReview,
+		GrantedReviewAgents:   []string{},
+		GrantedParallelAgents: []string{},
+	}
+	require.NoError(t, client.CreateClaim(ctx, claim))
+
+	stored, err := client.GetClaim(ctx, claim.ID)
+	require.NoError(t, err)
+	assert.Equal(t, lease.FencingToken, stored.FencingToken)
+
+	// A standby takes over: the old leader's writes are now rejected
+	mr.FastForward(11 * time.Second)
+	newClient, err := NewClient(&redis.Options{Addr: mr.Addr()}, "test-instance")
+	require.NoError(t, err)
+	defer newClient.Close()
+	newLease, err := newClient.AcquireLeadership(ctx, "orch-b", 10*time.Second)
+	require.NoError(t, err)
+	newClient.SetFencingToken(newLease.FencingToken)
+
+	claim.Status = ClaimStatusComplete
+	err = client.UpdateClaim(ctx, claim)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrStaleFencingToken)
+
+	stored, err = client.GetClaim(ctx, claim.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ClaimStatusPendingReview, stored.Status)
+
+	// The new leader's write succeeds and is stamped with its token
+	require.NoError(t, newClient.UpdateClaim(ctx, stored))
+	stored, err = newClient.GetClaim(ctx, claim.ID)
+	require.NoError(t, err)
+	assert.Equal(t, newLease.FencingToken, stored.FencingToken)
+}
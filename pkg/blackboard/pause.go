@@ -0,0 +1,218 @@
+package blackboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pause state fields
+const (
+	// PauseFieldInstance is set by holt pause: no artefact is processed until resume
+	PauseFieldInstance = "instance"
+	// PauseFieldDrain is set by holt drain: existing claims run to completion but no new claims are created
+	PauseFieldDrain = "drain"
+
+	pauseWorkflowPrefix = "workflow:"
+)
+
+// PauseMessageChanged is published on PauseEventsChannel whenever the pause state changes.
+const PauseMessageChanged = "changed"
+
+// PauseState is what the orchestrator is currently holding back.
+// Times are the Unix milliseconds each pause was set; zero means not set.
+type PauseState struct {
+	InstanceSinceMs int64            `json:"instance_since_ms,omitempty"`
+	DrainSinceMs    int64            `json:"drain_since_ms,omitempty"`
+	Workflows       map[string]int64 `json:"workflows,omitempty"` // root artefact ID -> paused since
+}
+
+// IsActive reports whether anything is paused or draining.
+func (s *PauseState) IsActive() bool {
+	return s.InstanceSinceMs != 0 || s.DrainSinceMs != 0 || len(s.Workflows) > 0
+}
+
+// HoldsWorkflow reports whether artefacts in the workflow rooted at rootID are held back,
+// either because the whole instance is paused or because the workflow itself is.
+func (s *PauseState) HoldsWorkflow(rootID string) bool {
+	if s.InstanceSinceMs != 0 {
+		return true
+	}
+	_, paused := s.Workflows[rootID]
+	return paused
+}
+
+// PausedWorkflows returns the paused workflow root IDs, sorted.
+func (s *PauseState) PausedWorkflows() []string {
+	roots := make([]string, 0, len(s.Workflows))
+	for root := range s.Workflows {
+		roots = append(roots, root)
+	}
+	sort.Strings(roots)
+	return roots
+}
+
+// DeferredArtefact is an artefact event the orchestrator held back while paused.
+type DeferredArtefact struct {
+	ArtefactID   string `json:"artefact_id"`
+	WorkflowID   string `json:"workflow_id"`          // Root artefact of the workflow it belongs to
+	ClaimOnly    bool   `json:"claim_only,omitempty"` // Phase tracking already ran; only claim creation was held (drain)
+	DeferredAtMs int64  `json:"deferred_at_ms"`
+}
+
+// GetPauseState returns the instance's pause state. An instance that was never
+// paused has an empty state.
+func (c *Client) GetPauseState(ctx context.Context) (*PauseState, error) {
+	fields, err := c.rdb.HGetAll(ctx, PauseKey(c.instanceName)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pause state: %w", err)
+	}
+
+	state := &PauseState{}
+	for field, value := range fields {
+		since, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pause state field '%s': %w", field, err)
+		}
+
+		switch {
+		case field == PauseFieldInstance:
+			state.InstanceSinceMs = since
+		case field == PauseFieldDrain:
+			state.DrainSinceMs = since
+		case strings.HasPrefix(field, pauseWorkflowPrefix):
+			if state.Workflows == nil {
+				state.Workflows = make(map[string]int64)
+			}
+			state.Workflows[strings.TrimPrefix(field, pauseWorkflowPrefix)] = since
+		}
+	}
+	return state, nil
+}
+
+// PauseInstance stops the orchestrator processing artefacts until ResumeAll.
+func (c *Client) PauseInstance(ctx context.Context) error {
+	return c.setPauseField(ctx, PauseFieldInstance)
+}
+
+// PauseWorkflow stops the orchestrator processing artefacts in the workflow rooted at
+// rootID until ResumeWorkflow or ResumeAll.
+func (c *Client) PauseWorkflow(ctx context.Context, rootID string) error {
+	return c.setPauseField(ctx, pauseWorkflowPrefix+rootID)
+}
+
+// StartDrain stops the orchestrator creating new claims while existing claims finish.
+func (c *Client) StartDrain(ctx context.Context) error {
+	return c.setPauseField(ctx, PauseFieldDrain)
+}
+
+// ResumeAll clears every pause, workflow pause and drain.
+func (c *Client) ResumeAll(ctx context.Context) error {
+	if err := c.rdb.Del(ctx, PauseKey(c.instanceName)).Err(); err != nil {
+		return fmt.Errorf("failed to clear pause state: %w", err)
+	}
+	return c.publishPauseChanged(ctx)
+}
+
+// ResumeWorkflow clears the pause on the workflow rooted at rootID.
+// An instance-wide pause or drain stays in place.
+func (c *Client) ResumeWorkflow(ctx context.Context, rootID string) error {
+	if err := c.rdb.HDel(ctx, PauseKey(c.instanceName), pauseWorkflowPrefix+rootID).Err(); err != nil {
+		return fmt.Errorf("failed to clear workflow pause: %w", err)
+	}
+	return c.publishPauseChanged(ctx)
+}
+
+// setPauseField records a pause (keeping the original time if already set) and notifies the orchestrator.
+func (c *Client) setPauseField(ctx context.Context, field string) error {
+	if err := c.rdb.HSetNX(ctx, PauseKey(c.instanceName), field, time.Now().UnixMilli()).Err(); err != nil {
+		return fmt.Errorf("failed to set pause state: %w", err)
+	}
+	return c.publishPauseChanged(ctx)
+}
+
+func (c *Client) publishPauseChanged(ctx context.Context) error {
+	if err := c.rdb.Publish(ctx, PauseEventsChannel(c.instanceName), PauseMessageChanged).Err(); err != nil {
+		return fmt.Errorf("failed to publish pause change: %w", err)
+	}
+	return nil
+}
+
+// AppendDeferredArtefact adds an artefact to the end of the deferred list.
+func (c *Client) AppendDeferredArtefact(ctx context.Context, deferred DeferredArtefact) error {
+	data, err := json.Marshal(deferred)
+	if err != nil {
+		return fmt.Errorf("failed to marshal deferred artefact: %w", err)
+	}
+	if err := c.rdb.RPush(ctx, DeferredArtefactsKey(c.instanceName), data).Err(); err != nil {
+		return fmt.Errorf("failed to store deferred artefact: %w", err)
+	}
+	return nil
+}
+
+// GetDeferredArtefacts returns the deferred artefacts, oldest first.
+func (c *Client) GetDeferredArtefacts(ctx context.Context) ([]DeferredArtefact, error) {
+	entries, err := c.rdb.LRange(ctx, DeferredArtefactsKey(c.instanceName), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deferred artefacts: %w", err)
+	}
+
+	deferred := make([]DeferredArtefact, 0, len(entries))
+	for _, entry := range entries {
+		var d DeferredArtefact
+		if err := json.Unmarshal([]byte(entry), &d); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal deferred artefact: %w", err)
+		}
+		deferred = append(deferred, d)
+	}
+	return deferred, nil
+}
+
+// RemoveDeferredArtefact removes an entry from the deferred list once it has been replayed.
+func (c *Client) RemoveDeferredArtefact(ctx context.Context, deferred DeferredArtefact) error {
+	data, err := json.Marshal(deferred)
+	if err != nil {
+		return fmt.Errorf("failed to marshal deferred artefact: %w", err)
+	}
+	if err := c.rdb.LRem(ctx, DeferredArtefactsKey(c.instanceName), 1, data).Err(); err != nil {
+		return fmt.Errorf("failed to remove deferred artefact: %w", err)
+	}
+	return nil
+}
+
+// WorkflowRoot returns the ID of the artefact that started the workflow containing
+// artefactID. It follows the first source artefact back to an artefact with none, and
+// crosses from a child workflow's goal to the claim that requested it, so pausing a
+// workflow also holds its sub-workflows.
+func (c *Client) WorkflowRoot(ctx context.Context, artefactID string) (string, error) {
+	visited := make(map[string]bool)
+	current := artefactID
+	for {
+		if visited[current] {
+			return "", fmt.Errorf("artefact %s has cyclic lineage", artefactID)
+		}
+		visited[current] = true
+
+		artefact, err := c.GetArtefact(ctx, current)
+		if err != nil {
+			return "", err
+		}
+
+		switch {
+		case len(artefact.SourceArtefacts) > 0:
+			current = artefact.SourceArtefacts[0]
+		case artefact.ParentClaimID != "":
+			parent, err := c.GetClaim(ctx, artefact.ParentClaimID)
+			if err != nil {
+				return "", fmt.Errorf("failed to read parent claim %s: %w", artefact.ParentClaimID, err)
+			}
+			current = parent.ArtefactID
+		default:
+			return current, nil
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package blackboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPauseState(t *testing.T) {
+	client, _ := setupTestClient(t)
+	ctx := context.Background()
+
+	state, err := client.GetPauseState(ctx)
+	require.NoError(t, err)
+	assert.False(t, state.IsActive())
+
+	sub, err := client.SubscribeRawChannel(ctx, PauseEventsChannel("test-instance"))
+	require.NoError(t, err)
+	defer sub.Close()
+
+	require.NoError(t, client.PauseWorkflow(ctx, "root-b"))
+	require.NoError(t, client.PauseWorkflow(ctx, "root-a"))
+	require.NoError(t, client.StartDrain(ctx))
+
+	select {
+	case message := <-sub.Messages():
+		assert.Equal(t, PauseMessageChanged, message)
+	case <-time.After(1 * time.Second):
+		t.Fatal("timeout waiting for pause event")
+	}
+
+	state, err = client.GetPauseState(ctx)
+	require.NoError(t, err)
+	assert.True(t, state.IsActive())
+	assert.Zero(t, state.InstanceSinceMs)
+	assert.NotZero(t, state.DrainSinceMs)
+	assert.Equal(t, []string{"root-a", "root-b"}, state.PausedWorkflows())
+	assert.True(t, state.HoldsWorkflow("root-a"))
+	assert.False(t, state.HoldsWorkflow("root-c"))
+
+	require.NoError(t, client.PauseInstance(ctx))
+	state, err = client.GetPauseState(ctx)
+	require.NoError(t, err)
+	assert.True(t, state.HoldsWorkflow("root-c"))
+
+	require.NoError(t, client.ResumeWorkflow(ctx, "root-a"))
+	state, err = client.GetPauseState(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"root-b"}, state.PausedWorkflows())
+	assert.NotZero(t, state.InstanceSinceMs)
+
+	require.NoError(t, client.ResumeAll(ctx))
+	state, err = client.GetPauseState(ctx)
+	require.NoError(t, err)
+	assert.False(t, state.IsActive())
+}
+
+func TestDeferredArtefacts(t *testing.T) {
+	client, _ := setupTestClient(t)
+	ctx := context.Background()
+
+	first := DeferredArtefact{ArtefactID: "a1", WorkflowID: "root", DeferredAtMs: 1}
+	second := DeferredArtefact{ArtefactID: "a2", WorkflowID: "root", ClaimOnly: true, DeferredAtMs: 2}
+	require.NoError(t, client.AppendDeferredArtefact(ctx, first))
+	require.NoError(t, client.AppendDeferredArtefact(ctx, second))
+
+	deferred, err := client.GetDeferredArtefacts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []DeferredArtefact{first, second}, deferred)
+
+	require.NoError(t, client.RemoveDeferredArtefact(ctx, first))
+	deferred, err = client.GetDeferredArtefacts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []DeferredArtefact{second}, deferred)
+}
+
+func TestWorkflowRoot(t *testing.T) {
+	client, _ := setupTestClient(t)
+	ctx := context.Background()
+
+	create := func(sources []string, parentClaimID string) string {
+		id := uuid.New().String()
+		require.NoError(t, client.CreateArtefact(ctx, &Artefact{
+			ID:              id,
+			LogicalID:       id,
+			Version:         1,
+			StructuralType:  StructuralTypeStandard,
+			Type:            "Work",
+			Payload:         "work",
+			SourceArtefacts: sources,
+			ProducedByRole:  "user",
+			ParentClaimID:   parentClaimID,
+		}))
+		return id
+	}
+
+	goal := create([]string{}, "")
+	design := create([]string{goal}, "")
+	designClaim := &Claim{
+		ID:                    uuid.New().String(),
+		ArtefactID:            design,
+		Status:                ClaimStatusAwaitingChild,
+		GrantedReviewAgents:   []string{},
+		GrantedParallelAgents: []string{},
+	}
+	require.NoError(t, client.CreateClaim(ctx, designClaim))
+	childGoal := create([]string{}, designClaim.ID)
+	childWork := create([]string{childGoal}, "")
+
+	for _, id := range []string{goal, design, childGoal, childWork} {
+		root, err := client.WorkflowRoot(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, goal, root)
+	}
+
+	_, err := client.WorkflowRoot(ctx, uuid.New().String())
+	assert.True(t, IsNotFound(err))
+}
@@ -0,0 +1,49 @@
+package blackboard
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// indexResult queues the index entries for a Terminal or Failure artefact, so results can
+// be found without scanning every artefact. Other artefacts are not indexed.
+func (c *Client) indexResult(ctx context.Context, pipe redis.Pipeliner, a *Artefact) {
+	if a.StructuralType != StructuralTypeTerminal && a.StructuralType != StructuralTypeFailure {
+		return
+	}
+
+	member := redis.Z{Score: float64(a.CreatedAtMs), Member: a.ID}
+	pipe.ZAdd(ctx, ResultsKey(c.instanceName), member)
+
+	if a.StructuralType == StructuralTypeFailure {
+		for _, sourceID := range a.SourceArtefacts {
+			pipe.ZAdd(ctx, FailuresBySourceKey(c.instanceName, sourceID), member)
+		}
+	}
+}
+
+// GetFailuresBySource returns the IDs of the Failure artefacts derived from an artefact,
+// oldest first.
+func (c *Client) GetFailuresBySource(ctx context.Context, artefactID string) ([]string, error) {
+	ids, err := c.rdb.ZRange(ctx, FailuresBySourceKey(c.instanceName, artefactID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read failures of artefact %s: %w", artefactID, err)
+	}
+	return ids, nil
+}
+
+// GetResultsSince returns the IDs of the Terminal and Failure artefacts created at or after
+// sinceMs (Unix milliseconds), oldest first.
+func (c *Client) GetResultsSince(ctx context.Context, sinceMs int64) ([]string, error) {
+	ids, err := c.rdb.ZRangeByScore(ctx, ResultsKey(c.instanceName), &redis.ZRangeBy{
+		Min: strconv.FormatInt(sinceMs, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results index: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,56 @@
+package blackboard
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newResultTestArtefact(structuralType StructuralType, createdAtMs int64, sources ...string) *Artefact {
+	return &Artefact{
+		ID:              uuid.New().String(),
+		LogicalID:       uuid.New().String(),
+		Version:         1,
+		StructuralType:  structuralType,
+		Type:            "TestType",
+		Payload:         "payload",
+		SourceArtefacts: sources,
+		ProducedByRole:  "test-agent",
+		CreatedAtMs:     createdAtMs,
+	}
+}
+
+func TestResultIndexes(t *testing.T) {
+	client, _ := setupTestClient(t)
+	ctx := context.Background()
+
+	target := newResultTestArtefact(StructuralTypeStandard, 1000)
+	require.NoError(t, client.CreateArtefact(ctx, target))
+
+	later := newResultTestArtefact(StructuralTypeFailure, 3000, target.ID)
+	earlier := newResultTestArtefact(StructuralTypeFailure, 2000, target.ID)
+	terminal := newResultTestArtefact(StructuralTypeTerminal, 4000, target.ID)
+	require.NoError(t, client.CreateArtefact(ctx, later))
+	require.NoError(t, client.CreateArtefacts(ctx, []*Artefact{earlier, terminal}))
+
+	// Only Failures are indexed by source, oldest first
+	failures, err := client.GetFailuresBySource(ctx, target.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{earlier.ID, later.ID}, failures)
+
+	failures, err = client.GetFailuresBySource(ctx, uuid.New().String())
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+
+	// Terminals and Failures are indexed by creation time; Standard artefacts are not
+	results, err := client.GetResultsSince(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{earlier.ID, later.ID, terminal.ID}, results)
+
+	results, err = client.GetResultsSince(ctx, 3000)
+	require.NoError(t, err)
+	assert.Equal(t, []string{later.ID, terminal.ID}, results)
+}
@@ -73,3 +73,81 @@ func WorkflowEventsChannel(instanceName string) string {
 func AgentImagesKey(instanceName string) string {
 	return fmt.Sprintf("holt:%s:agent_images", instanceName)
 }
+
+// OrchestratorLeaderKey returns the Redis key for the orchestrator leader lease.
+// Holds the current leader's lease (set with NX and a TTL, renewed while leading).
+// Pattern: holt:{instance_name}:orchestrator:leader
+func OrchestratorLeaderKey(instanceName string) string {
+	return fmt.Sprintf("holt:%s:orchestrator:leader", instanceName)
+}
+
+// OrchestratorFencingKey returns the Redis key for the orchestrator fencing token counter.
+// Incremented each time a leader is elected; claim writes carrying an older token are rejected.
+// Pattern: holt:{instance_name}:orchestrator:fencing_token
+func OrchestratorFencingKey(instanceName string) string {
+	return fmt.Sprintf("holt:%s:orchestrator:fencing_token", instanceName)
+}
+
+// InstanceConfigKey returns the Redis key for the instance's running configuration.
+// Holds a JSON-encoded InstanceConfig written by holt up and replaced by holt reload.
+// Pattern: holt:{instance_name}:config
+func InstanceConfigKey(instanceName string) string {
+	return fmt.Sprintf("holt:%s:config", instanceName)
+}
+
+// ConfigEventsChannel returns the Pub/Sub channel announcing new configuration revisions.
+// Messages are the revision number; the orchestrator re-reads InstanceConfigKey on each.
+// Pattern: holt:{instance_name}:config_events
+func ConfigEventsChannel(instanceName string) string {
+	return fmt.Sprintf("holt:%s:config_events", instanceName)
+}
+
+// PauseKey returns the Redis key for the instance's pause state.
+// A hash whose fields are PauseFieldInstance, PauseFieldDrain or "workflow:{root_artefact_id}",
+// each holding the Unix millisecond time it was set.
+// Pattern: holt:{instance_name}:pause
+func PauseKey(instanceName string) string {
+	return fmt.Sprintf("holt:%s:pause", instanceName)
+}
+
+// DeferredArtefactsKey returns the Redis key for artefacts held back while paused.
+// A list of JSON-encoded DeferredArtefact entries, replayed in order on resume.
+// Pattern: holt:{instance_name}:deferred_artefacts
+func DeferredArtefactsKey(instanceName string) string {
+	return fmt.Sprintf("holt:%s:deferred_artefacts", instanceName)
+}
+
+// PauseEventsChannel returns the Pub/Sub channel announcing pause state changes.
+// The orchestrator re-reads PauseKey on each message and replays artefacts that are no longer held.
+// Pattern: holt:{instance_name}:pause_events
+func PauseEventsChannel(instanceName string) string {
+	return fmt.Sprintf("holt:%s:pause_events", instanceName)
+}
+
+// ClaimRequestsChannel returns the Pub/Sub channel for manual claim interventions.
+// Messages are JSON-encoded ClaimRequest entries from holt retry and holt skip.
+// Pattern: holt:{instance_name}:claim_requests
+func ClaimRequestsChannel(instanceName string) string {
+	return fmt.Sprintf("holt:%s:claim_requests", instanceName)
+}
+
+// WorkerLogsKey returns the Redis key for the persisted worker log list.
+// Each entry is a JSON-encoded WorkerLog captured before the worker container is removed.
+// Pattern: holt:{instance_name}:worker_logs
+func WorkerLogsKey(instanceName string) string {
+	return fmt.Sprintf("holt:%s:worker_logs", instanceName)
+}
+
+// FailuresBySourceKey returns the Redis key for the Failure artefacts derived from an artefact.
+// A ZSET of Failure artefact IDs scored by creation time (Unix milliseconds).
+// Pattern: holt:{instance_name}:failures_by_source:{artefact_id}
+func FailuresBySourceKey(instanceName, artefactID string) string {
+	return fmt.Sprintf("holt:%s:failures_by_source:%s", instanceName, artefactID)
+}
+
+// ResultsKey returns the Redis key for the index of Terminal and Failure artefacts.
+// A ZSET of artefact IDs scored by creation time (Unix milliseconds).
+// Pattern: holt:{instance_name}:results
+func ResultsKey(instanceName string) string {
+	return fmt.Sprintf("holt:%s:results", instanceName)
+}
@@ -257,3 +257,13 @@ func TestKeyFormatsWithSpecialCharacters(t *testing.T) {
 		})
 	}
 }
+
+// TestWorkerLogsKey tests worker log list key generation
+func TestWorkerLogsKey(t *testing.T) {
+	key := WorkerLogsKey("default-1")
+
+	expected := "holt:default-1:worker_logs"
+	if key != expected {
+		t.Errorf("WorkerLogsKey() = %q, expected %q", key, expected)
+	}
+}
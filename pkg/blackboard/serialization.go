@@ -31,6 +31,17 @@ func ArtefactToHash(a *Artefact) (map[string]interface{}, error) {
 		"source_artefacts": string(sourceArtefactsJSON),
 		"produced_by_role": a.ProducedByRole,
 		"created_at_ms":    a.CreatedAtMs, // M3.9
+		"summary":          a.Summary,
+		"parent_claim_id":  a.ParentClaimID,
+	}
+
+	// Sub-workflows: child agent subset, only stored when set
+	if len(a.ChildAgents) > 0 {
+		childAgentsJSON, err := json.Marshal(a.ChildAgents)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal child_agents: %w", err)
+		}
+		hash["child_agents"] = string(childAgentsJSON)
 	}
 
 	return hash, nil
@@ -58,6 +69,13 @@ func HashToArtefact(hash map[string]string) (*Artefact, error) {
 		sourceArtefacts = []string{}
 	}
 
+	var childAgents []string
+	if childAgentsJSON := hash["child_agents"]; childAgentsJSON != "" {
+		if err := json.Unmarshal([]byte(childAgentsJSON), &childAgents); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal child_agents: %w", err)
+		}
+	}
+
 	// M3.9: Parse created_at_ms
 	createdAtMs, _ := strconv.ParseInt(hash["created_at_ms"], 10, 64)
 
@@ -71,6 +89,9 @@ func HashToArtefact(hash map[string]string) (*Artefact, error) {
 		SourceArtefacts: sourceArtefacts,
 		ProducedByRole:  hash["produced_by_role"],
 		CreatedAtMs:     createdAtMs, // M3.9
+		Summary:         hash["summary"],
+		ParentClaimID:   hash["parent_claim_id"],
+		ChildAgents:     childAgents,
 	}
 
 	return artefact, nil
@@ -137,6 +158,17 @@ func ClaimToHash(c *Claim) (map[string]interface{}, error) {
 	// M3.9: Agent version auditing
 	hash["granted_agent_image_id"] = c.GrantedAgentImageID
 
+	// Orchestrator HA: fencing token of the writing leader
+	hash["fencing_token"] = c.FencingToken
+
+	// Sub-workflows
+	hash["child_goal_id"] = c.ChildGoalID
+	hash["child_result_id"] = c.ChildResultID
+
+	// Manual intervention
+	hash["retry_of"] = c.RetryOf
+	hash["skipped_with"] = c.SkippedWith
+
 	return hash, nil
 }
 
@@ -199,6 +231,7 @@ func HashToClaim(hash map[string]string) (*Claim, error) {
 	// M3.5: Parse grant tracking fields
 	lastGrantTime, _ := strconv.ParseInt(hash["last_grant_time"], 10, 64)
 	artefactExpected, _ := strconv.ParseBool(hash["artefact_expected"])
+	fencingToken, _ := strconv.ParseInt(hash["fencing_token"], 10, 64)
 
 	claim := &Claim{
 		ID:                    hash["id"],
@@ -215,6 +248,11 @@ func HashToClaim(hash map[string]string) (*Claim, error) {
 		LastGrantTime:         lastGrantTime,         // M3.5
 		ArtefactExpected:      artefactExpected,      // M3.5
 		GrantedAgentImageID:   hash["granted_agent_image_id"], // M3.9
+		FencingToken:          fencingToken,
+		ChildGoalID:           hash["child_goal_id"],
+		ChildResultID:         hash["child_result_id"],
+		RetryOf:               hash["retry_of"],
+		SkippedWith:           hash["skipped_with"],
 	}
 
 	return claim, nil
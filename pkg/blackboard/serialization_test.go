@@ -20,6 +20,7 @@ func TestArtefactRoundTrip(t *testing.T) {
 			ProducedByRole:  "test-agent",
 		Payload:         "abc123def",
 		SourceArtefacts: []string{uuid.New().String(), uuid.New().String()},
+		Summary:         "Implemented login",
 	}
 
 	// Convert to hash
@@ -428,6 +429,71 @@ func TestClaimRoundTrip_M3_5_NoOptionalFields(t *testing.T) {
 	}
 }
 
+// TestClaimRoundTrip_ManualIntervention tests that holt retry and holt skip links survive serialization
+func TestClaimRoundTrip_ManualIntervention(t *testing.T) {
+	original := &Claim{
+		ID:                    uuid.New().String(),
+		ArtefactID:            uuid.New().String(),
+		Status:                ClaimStatusComplete,
+		GrantedReviewAgents:   []string{},
+		GrantedParallelAgents: []string{},
+		AdditionalContextIDs:  []string{},
+		RetryOf:               uuid.New().String(),
+		SkippedWith:           uuid.New().String(),
+	}
+
+	hash, err := ClaimToHash(original)
+	if err != nil {
+		t.Fatalf("ClaimToHash failed: %v", err)
+	}
+
+	stringHash := make(map[string]string)
+	for k, v := range hash {
+		stringHash[k] = toString(v)
+	}
+
+	result, err := HashToClaim(stringHash)
+	if err != nil {
+		t.Fatalf("HashToClaim failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(original, result) {
+		t.Errorf("round-trip with retry and skip links failed:\noriginal: %+v\nresult:   %+v", original, result)
+	}
+}
+
+// TestClaimRoundTrip_FencingToken tests that the leader fencing token survives serialization
+func TestClaimRoundTrip_FencingToken(t *testing.T) {
+	original := &Claim{
+		ID:                    uuid.New().String(),
+		ArtefactID:            uuid.New().String(),
+		Status:                ClaimStatusPendingExclusive,
+		GrantedReviewAgents:   []string{},
+		GrantedParallelAgents: []string{},
+		AdditionalContextIDs:  []string{},
+		FencingToken:          42,
+	}
+
+	hash, err := ClaimToHash(original)
+	if err != nil {
+		t.Fatalf("ClaimToHash failed: %v", err)
+	}
+
+	stringHash := make(map[string]string)
+	for k, v := range hash {
+		stringHash[k] = toString(v)
+	}
+
+	result, err := HashToClaim(stringHash)
+	if err != nil {
+		t.Fatalf("HashToClaim failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(original, result) {
+		t.Errorf("round-trip with fencing token failed:\noriginal: %+v\nresult:   %+v", original, result)
+	}
+}
+
 // TestHashToClaim_M3_5_MalformedPhaseState tests that malformed phase state JSON fails gracefully
 func TestHashToClaim_M3_5_MalformedPhaseState(t *testing.T) {
 	hash := map[string]string{
@@ -476,6 +542,66 @@ func TestHashToClaim_M3_5_MalformedGrantQueue(t *testing.T) {
 	}
 }
 
+// TestSubWorkflowFieldsRoundTrip tests the sub-workflow fields on artefacts and claims
+func TestSubWorkflowFieldsRoundTrip(t *testing.T) {
+	artefact := &Artefact{
+		ID:              uuid.New().String(),
+		LogicalID:       uuid.New().String(),
+		Version:         1,
+		StructuralType:  StructuralTypeStandard,
+		Type:            "GoalDefined",
+		Payload:         "Research the auth library options",
+		SourceArtefacts: []string{},
+		ProducedByRole:  "Planner",
+		ParentClaimID:   uuid.New().String(),
+		ChildAgents:     []string{"Researcher", "Summariser"},
+	}
+
+	hash, err := ArtefactToHash(artefact)
+	if err != nil {
+		t.Fatalf("ArtefactToHash failed: %v", err)
+	}
+	stringHash := make(map[string]string)
+	for k, v := range hash {
+		stringHash[k] = toString(v)
+	}
+	resultArtefact, err := HashToArtefact(stringHash)
+	if err != nil {
+		t.Fatalf("HashToArtefact failed: %v", err)
+	}
+	if !reflect.DeepEqual(artefact, resultArtefact) {
+		t.Errorf("artefact round-trip failed:\noriginal: %+v\nresult:   %+v", artefact, resultArtefact)
+	}
+
+	claim := &Claim{
+		ID:                    uuid.New().String(),
+		ArtefactID:            uuid.New().String(),
+		Status:                ClaimStatusAwaitingChild,
+		GrantedReviewAgents:   []string{},
+		GrantedParallelAgents: []string{},
+		GrantedExclusiveAgent: "planner",
+		AdditionalContextIDs:  []string{},
+		ChildGoalID:           artefact.ID,
+		ChildResultID:         uuid.New().String(),
+	}
+
+	claimHash, err := ClaimToHash(claim)
+	if err != nil {
+		t.Fatalf("ClaimToHash failed: %v", err)
+	}
+	stringHash = make(map[string]string)
+	for k, v := range claimHash {
+		stringHash[k] = toString(v)
+	}
+	resultClaim, err := HashToClaim(stringHash)
+	if err != nil {
+		t.Fatalf("HashToClaim failed: %v", err)
+	}
+	if !reflect.DeepEqual(claim, resultClaim) {
+		t.Errorf("claim round-trip failed:\noriginal: %+v\nresult:   %+v", claim, resultClaim)
+	}
+}
+
 // Helper function to convert interface{} to string (simulates Redis storage)
 func toString(v interface{}) string {
 	switch val := v.(type) {
@@ -8,6 +8,7 @@
 package blackboard
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -26,6 +27,11 @@ type Artefact struct {
 	SourceArtefacts []string       `json:"source_artefacts"` // Array of artefact UUIDs this was derived from
 	ProducedByRole  string         `json:"produced_by_role"` // Agent's role from holt.yml or "user"
 	CreatedAtMs     int64          `json:"created_at_ms"`    // M3.9: Unix timestamp in milliseconds when artefact was created
+	Summary         string         `json:"summary,omitempty"` // Producing tool's human-readable summary (used to condense context)
+
+	// Sub-workflows: set on a child workflow's root GoalDefined artefact
+	ParentClaimID string   `json:"parent_claim_id,omitempty"` // Claim parked until the child workflow finishes
+	ChildAgents   []string `json:"child_agents,omitempty"`    // Roles allowed to bid in the child workflow (empty = all)
 }
 
 // StructuralType defines the role an artefact plays in the orchestration flow.
@@ -80,6 +86,18 @@ type Claim struct {
 
 	// M3.9: Agent version auditing
 	GrantedAgentImageID string `json:"granted_agent_image_id,omitempty"` // Docker image ID of agent that was granted this claim
+
+	// Orchestrator HA: fencing token of the leader that last wrote this claim
+	FencingToken int64 `json:"fencing_token,omitempty"`
+
+	// Sub-workflows: the child goal this claim is parked on, and the child's result once finished
+	ChildGoalID   string `json:"child_goal_id,omitempty"`
+	ChildResultID string `json:"child_result_id,omitempty"`
+
+	// Manual intervention: the terminated claim this claim retries (holt retry), and the
+	// human-supplied artefact that resolved this claim instead (holt skip)
+	RetryOf     string `json:"retry_of,omitempty"`
+	SkippedWith string `json:"skipped_with,omitempty"`
 }
 
 // ClaimStatus defines the lifecycle state of a claim.
@@ -99,6 +117,9 @@ const (
 	// ClaimStatusPendingAssignment indicates a feedback claim with pre-assigned agent (M3.3)
 	ClaimStatusPendingAssignment ClaimStatus = "pending_assignment"
 
+	// ClaimStatusAwaitingChild indicates an exclusive claim parked on a child workflow
+	ClaimStatusAwaitingChild ClaimStatus = "awaiting_child"
+
 	// ClaimStatusComplete indicates the claim has been successfully processed
 	ClaimStatusComplete ClaimStatus = "complete"
 
@@ -223,7 +244,7 @@ func (cs ClaimStatus) Validate() error {
 	switch cs {
 	case ClaimStatusPendingReview, ClaimStatusPendingParallel,
 		ClaimStatusPendingExclusive, ClaimStatusPendingAssignment,
-		ClaimStatusComplete, ClaimStatusTerminated:
+		ClaimStatusAwaitingChild, ClaimStatusComplete, ClaimStatusTerminated:
 		return nil
 	default:
 		return fmt.Errorf("unknown claim status: %q", cs)
@@ -240,6 +261,39 @@ func (bt BidType) Validate() error {
 	}
 }
 
+// IsReviewApproval checks if a Review artefact's payload represents approval.
+// Approval is indicated by an empty JSON object {} or empty JSON array [].
+// Any other content (including invalid JSON) is treated as feedback.
+//
+// Edge cases:
+//   - "{}" → approval (empty object)
+//   - "[]" → approval (empty array)
+//   - "{\"issue\": \"fix this\"}" → feedback (non-empty object)
+//   - "[\"problem\"]" → feedback (non-empty array)
+//   - "" → feedback (empty string, not JSON)
+//   - "true" → feedback (JSON boolean, not object/array)
+//   - "42" → feedback (JSON number)
+//   - Invalid JSON → feedback
+func IsReviewApproval(payload string) bool {
+	// Attempt to parse as JSON
+	var jsonData interface{}
+	err := json.Unmarshal([]byte(payload), &jsonData)
+	if err != nil {
+		// Not valid JSON → feedback
+		return false
+	}
+
+	// Check if empty object or empty array
+	switch v := jsonData.(type) {
+	case map[string]interface{}:
+		return len(v) == 0 // {} = approval
+	case []interface{}:
+		return len(v) == 0 // [] = approval
+	default:
+		return false // Any other JSON type = feedback
+	}
+}
+
 // isValidUUID checks if a string is a valid UUID format.
 func isValidUUID(s string) bool {
 	_, err := uuid.Parse(s)
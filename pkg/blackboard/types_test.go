@@ -276,6 +276,7 @@ func TestClaimStatusValidate_AllValid(t *testing.T) {
 		ClaimStatusPendingReview,
 		ClaimStatusPendingParallel,
 		ClaimStatusPendingExclusive,
+		ClaimStatusAwaitingChild,
 		ClaimStatusComplete,
 		ClaimStatusTerminated,
 	}
@@ -348,3 +349,16 @@ func TestIsValidUUID(t *testing.T) {
 		})
 	}
 }
+
+func TestIsReviewApproval(t *testing.T) {
+	for _, payload := range []string{"{}", "[]", " { } ", "[\n]"} {
+		if !IsReviewApproval(payload) {
+			t.Errorf("IsReviewApproval(%q) = false, expected approval", payload)
+		}
+	}
+	for _, payload := range []string{"", `{"issue": "fix this"}`, `["problem"]`, "true", "42", "not json"} {
+		if IsReviewApproval(payload) {
+			t.Errorf("IsReviewApproval(%q) = true, expected feedback", payload)
+		}
+	}
+}
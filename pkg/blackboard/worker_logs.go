@@ -0,0 +1,76 @@
+package blackboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// MaxWorkerLogBytes caps the output stored for a single worker; older output is dropped first.
+const MaxWorkerLogBytes = 256 * 1024
+
+// MaxWorkerLogs caps how many worker logs an instance keeps; the oldest are trimmed first.
+const MaxWorkerLogs = 500
+
+// WorkerLog is the captured output of an ephemeral worker container, persisted so
+// `holt logs` can still show it after the container has been removed.
+type WorkerLog struct {
+	ClaimID       string `json:"claim_id"`            // Claim the worker executed
+	Role          string `json:"role"`                // Agent role the worker ran for
+	ContainerName string `json:"container_name"`      // Worker container name
+	ExitCode      int    `json:"exit_code"`           // Container exit code (-1 if the wait for exit failed)
+	StartedAtMs   int64  `json:"started_at_ms"`       // When the worker was launched (Unix milliseconds)
+	FinishedAtMs  int64  `json:"finished_at_ms"`      // When the worker exited (Unix milliseconds)
+	Output        string `json:"output"`              // Docker log lines, each prefixed with an RFC3339Nano timestamp
+	Truncated     bool   `json:"truncated,omitempty"` // Whether older output was dropped to fit MaxWorkerLogBytes
+}
+
+// SaveWorkerLog appends a worker's captured output to the instance's worker log list.
+// Output longer than MaxWorkerLogBytes is cut at a line boundary, keeping the tail.
+func (c *Client) SaveWorkerLog(ctx context.Context, workerLog *WorkerLog) error {
+	entry := *workerLog
+	if len(entry.Output) > MaxWorkerLogBytes {
+		tail := entry.Output[len(entry.Output)-MaxWorkerLogBytes:]
+		for i := 0; i < len(tail); i++ {
+			if tail[i] == '\n' {
+				tail = tail[i+1:]
+				break
+			}
+		}
+		entry.Output = tail
+		entry.Truncated = true
+	}
+
+	data, err := json.Marshal(&entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal worker log: %w", err)
+	}
+
+	key := WorkerLogsKey(c.instanceName)
+	pipe := c.rdb.TxPipeline()
+	pipe.RPush(ctx, key, data)
+	pipe.LTrim(ctx, key, -MaxWorkerLogs, -1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save worker log for claim %s: %w", workerLog.ClaimID, err)
+	}
+	return nil
+}
+
+// GetWorkerLogs returns the persisted worker logs, oldest first.
+// Entries that cannot be decoded are skipped.
+func (c *Client) GetWorkerLogs(ctx context.Context) ([]*WorkerLog, error) {
+	entries, err := c.rdb.LRange(ctx, WorkerLogsKey(c.instanceName), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read worker logs: %w", err)
+	}
+
+	logs := make([]*WorkerLog, 0, len(entries))
+	for _, entry := range entries {
+		var workerLog WorkerLog
+		if err := json.Unmarshal([]byte(entry), &workerLog); err != nil {
+			continue
+		}
+		logs = append(logs, &workerLog)
+	}
+	return logs, nil
+}
@@ -0,0 +1,77 @@
+package blackboard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndGetWorkerLogs(t *testing.T) {
+	client, _ := setupTestClient(t)
+	ctx := context.Background()
+
+	logs, err := client.GetWorkerLogs(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, logs)
+
+	require.NoError(t, client.SaveWorkerLog(ctx, &WorkerLog{
+		ClaimID:       "claim-1",
+		Role:          "coder",
+		ContainerName: "holt-test-coder-worker-claim-1",
+		ExitCode:      1,
+		Output:        "2025-01-01T00:00:00.000000000Z starting\n",
+	}))
+	require.NoError(t, client.SaveWorkerLog(ctx, &WorkerLog{ClaimID: "claim-2", Role: "tester"}))
+
+	logs, err = client.GetWorkerLogs(ctx)
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	assert.Equal(t, "claim-1", logs[0].ClaimID)
+	assert.Equal(t, "coder", logs[0].Role)
+	assert.Equal(t, 1, logs[0].ExitCode)
+	assert.Contains(t, logs[0].Output, "starting")
+	assert.False(t, logs[0].Truncated)
+	assert.Equal(t, "claim-2", logs[1].ClaimID)
+}
+
+func TestSaveWorkerLog_TruncatesKeepingTail(t *testing.T) {
+	client, _ := setupTestClient(t)
+	ctx := context.Background()
+
+	var output strings.Builder
+	for i := 0; output.Len() <= MaxWorkerLogBytes; i++ {
+		fmt.Fprintf(&output, "2025-01-01T00:00:00.000000000Z line %d\n", i)
+	}
+	original := output.String()
+
+	workerLog := &WorkerLog{ClaimID: "claim-1", Output: original}
+	require.NoError(t, client.SaveWorkerLog(ctx, workerLog))
+	assert.Equal(t, original, workerLog.Output, "caller's struct must not be modified")
+
+	logs, err := client.GetWorkerLogs(ctx)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.True(t, logs[0].Truncated)
+	assert.LessOrEqual(t, len(logs[0].Output), MaxWorkerLogBytes)
+	assert.True(t, strings.HasSuffix(original, logs[0].Output))
+	assert.True(t, strings.HasPrefix(logs[0].Output, "2025-"), "truncation should cut at a line boundary")
+}
+
+func TestSaveWorkerLog_TrimsOldestEntries(t *testing.T) {
+	client, _ := setupTestClient(t)
+	ctx := context.Background()
+
+	for i := 0; i < MaxWorkerLogs+5; i++ {
+		require.NoError(t, client.SaveWorkerLog(ctx, &WorkerLog{ClaimID: fmt.Sprintf("claim-%d", i)}))
+	}
+
+	logs, err := client.GetWorkerLogs(ctx)
+	require.NoError(t, err)
+	require.Len(t, logs, MaxWorkerLogs)
+	assert.Equal(t, "claim-5", logs[0].ClaimID)
+	assert.Equal(t, fmt.Sprintf("claim-%d", MaxWorkerLogs+4), logs[len(logs)-1].ClaimID)
+}